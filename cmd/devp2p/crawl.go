@@ -37,6 +37,7 @@ type crawler struct {
 
 	// settings
 	revalidateInterval time.Duration
+	handshake          func(*enode.Node) *nodeHandshake // optional RLPx handshake
 	mu                 sync.RWMutex
 }
 
@@ -207,6 +208,9 @@ func (c *crawler) updateNode(n *enode.Node) int {
 			status = nodeAdded
 		}
 		node.LastResponse = node.LastCheck
+		if c.handshake != nil {
+			node.Handshake = c.handshake(nn)
+		}
 	}
 	// Store/update node in output set.
 	c.mu.Lock()
@@ -0,0 +1,245 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/ethereum/go-ethereum/cmd/devp2p/internal/ethtest"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/rlpx"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	// handshakeTimeout is the time limit for the whole handshake with a node.
+	handshakeTimeout = 10 * time.Second
+
+	// handshakeMaxMessages is the number of messages read while waiting for
+	// the status message.
+	handshakeMaxMessages = 16
+
+	// Devp2p message codes and protocol length, see p2p/peer.go.
+	handshakeMsg = 0x00
+	discMsg      = 0x01
+	pingMsg      = 0x02
+	pongMsg      = 0x03
+	baseProtoLen = 16
+)
+
+// nodeHandshake is the result of the RLPx and eth handshake with a node.
+type nodeHandshake struct {
+	Time      time.Time `json:"time"`
+	Reachable bool      `json:"reachable"`       // whether the RLPx handshake succeeded
+	Error     string    `json:"error,omitempty"` // reason why the handshake did not complete
+
+	// Devp2p handshake.
+	Client string   `json:"client,omitempty"`
+	Caps   []string `json:"caps,omitempty"`
+
+	// Status message of the eth protocol.
+	EthVersion uint32        `json:"ethVersion,omitempty"`
+	NetworkID  uint64        `json:"networkID,omitempty"`
+	Genesis    *common.Hash  `json:"genesis,omitempty"`
+	ForkHash   hexutil.Bytes `json:"forkHash,omitempty"`
+	ForkNext   uint64        `json:"forkNext,omitempty"`
+	Head       *common.Hash  `json:"head,omitempty"`
+	HeadNumber uint64        `json:"headNumber,omitempty"` // only known for eth/69
+}
+
+// forkID returns the fork ID from the status message.
+func (h *nodeHandshake) forkID() (forkid.ID, bool) {
+	if h == nil || len(h.ForkHash) != 4 {
+		return forkid.ID{}, false
+	}
+	return forkid.ID{Hash: [4]byte(h.ForkHash), Next: h.ForkNext}, true
+}
+
+// handshaker performs handshakes with crawled nodes.
+type handshaker struct {
+	key  *ecdsa.PrivateKey
+	caps []p2p.Cap
+}
+
+func newHandshaker() *handshaker {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		exit(err)
+	}
+	h := &handshaker{key: key}
+	for _, v := range eth.ProtocolVersions {
+		h.caps = append(h.caps, p2p.Cap{Name: eth.ProtocolName, Version: v})
+	}
+	return h
+}
+
+// handshake connects to the node and reads its devp2p hello and eth status
+// messages. The crawler doesn't send a status message of its own, it disconnects
+// as soon as the remote status has been received.
+func (h *handshaker) handshake(n *enode.Node) *nodeHandshake {
+	result := &nodeHandshake{Time: truncNow()}
+	if err := h.run(n, result); err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func (h *handshaker) run(n *enode.Node, result *nodeHandshake) error {
+	endpoint, ok := n.TCPEndpoint()
+	if !ok {
+		return errors.New("node has no TCP endpoint")
+	}
+	fd, err := net.DialTimeout("tcp", endpoint.String(), handshakeTimeout)
+	if err != nil {
+		return err
+	}
+	fd.SetDeadline(time.Now().Add(handshakeTimeout))
+	conn := rlpx.NewConn(fd, n.Pubkey())
+	defer conn.Close()
+	if _, err := conn.Handshake(h.key); err != nil {
+		return err
+	}
+	result.Reachable = true
+
+	// Exchange the devp2p hello.
+	hello := &ethtest.Hello{
+		Version: 5,
+		Name:    "devp2p-crawler",
+		Caps:    h.caps,
+		ID:      crypto.FromECDSAPub(&h.key.PublicKey)[1:],
+	}
+	if err := writeMsg(conn, handshakeMsg, hello); err != nil {
+		return err
+	}
+	code, data, _, err := conn.Read()
+	if err != nil {
+		return err
+	}
+	switch code {
+	case handshakeMsg:
+	case discMsg:
+		return decodeDisconnect(data)
+	default:
+		return fmt.Errorf("expected hello, got message code %d", code)
+	}
+	var their ethtest.Hello
+	if err := rlp.DecodeBytes(data, &their); err != nil {
+		return fmt.Errorf("invalid hello: %v", err)
+	}
+	result.Client = their.Name
+	for _, cap := range their.Caps {
+		result.Caps = append(result.Caps, cap.String())
+	}
+	conn.SetSnappy(their.Version >= 5)
+
+	version := negotiateEth(h.caps, their.Caps)
+	if version == 0 {
+		writeMsg(conn, discMsg, []p2p.DiscReason{p2p.DiscUselessPeer})
+		return errors.New("no matching eth version")
+	}
+	defer writeMsg(conn, discMsg, []p2p.DiscReason{p2p.DiscQuitting})
+
+	// Wait for the status message, answering pings. The eth protocol is the
+	// only shared protocol, so its messages start right after the base protocol.
+	for i := 0; i < handshakeMaxMessages; i++ {
+		code, data, _, err := conn.Read()
+		if err != nil {
+			return err
+		}
+		switch code {
+		case pingMsg:
+			writeMsg(conn, pongMsg, []any{})
+		case discMsg:
+			return decodeDisconnect(data)
+		case baseProtoLen + eth.StatusMsg:
+			return decodeStatus(version, data, result)
+		}
+	}
+	return errors.New("no status message received")
+}
+
+// decodeStatus stores the content of an eth status message in the result.
+func decodeStatus(version uint, data []byte, result *nodeHandshake) error {
+	var (
+		genesis common.Hash
+		head    common.Hash
+		fork    forkid.ID
+	)
+	if version >= eth.ETH69 {
+		var status eth.StatusPacket69
+		if err := rlp.DecodeBytes(data, &status); err != nil {
+			return fmt.Errorf("invalid status: %v", err)
+		}
+		result.EthVersion = status.ProtocolVersion
+		result.NetworkID = status.NetworkID
+		result.HeadNumber = status.LatestBlock
+		genesis, head, fork = status.Genesis, status.LatestBlockHash, status.ForkID
+	} else {
+		var status eth.StatusPacket68
+		if err := rlp.DecodeBytes(data, &status); err != nil {
+			return fmt.Errorf("invalid status: %v", err)
+		}
+		result.EthVersion = status.ProtocolVersion
+		result.NetworkID = status.NetworkID
+		genesis, head, fork = status.Genesis, status.Head, status.ForkID
+	}
+	result.Genesis = &genesis
+	result.Head = &head
+	result.ForkHash = fork.Hash[:]
+	result.ForkNext = fork.Next
+	return nil
+}
+
+// negotiateEth returns the highest eth version supported by both sides.
+func negotiateEth(ours, theirs []p2p.Cap) uint {
+	var version uint
+	for _, our := range ours {
+		for _, their := range theirs {
+			if our == their && our.Version > version {
+				version = our.Version
+			}
+		}
+	}
+	return version
+}
+
+func writeMsg(conn *rlpx.Conn, code uint64, msg any) error {
+	data, err := rlp.EncodeToBytes(msg)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(code, data)
+	return err
+}
+
+func decodeDisconnect(data []byte) error {
+	var reason []p2p.DiscReason
+	if rlp.DecodeBytes(data, &reason); len(reason) == 0 {
+		return errors.New("invalid disconnect message")
+	}
+	return fmt.Errorf("disconnected: %v", reason[0])
+}
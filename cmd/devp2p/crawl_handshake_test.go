@@ -0,0 +1,103 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestDecodeStatus(t *testing.T) {
+	var (
+		genesis = common.HexToHash("0x01")
+		head    = common.HexToHash("0x02")
+		fork    = forkid.ID{Hash: [4]byte{0xde, 0xad, 0xbe, 0xef}, Next: 1000}
+	)
+	status68, _ := rlp.EncodeToBytes(&eth.StatusPacket68{
+		ProtocolVersion: eth.ETH68,
+		NetworkID:       5,
+		TD:              big.NewInt(100),
+		Head:            head,
+		Genesis:         genesis,
+		ForkID:          fork,
+	})
+	status69, _ := rlp.EncodeToBytes(&eth.StatusPacket69{
+		ProtocolVersion: eth.ETH69,
+		NetworkID:       5,
+		Genesis:         genesis,
+		ForkID:          fork,
+		EarliestBlock:   10,
+		LatestBlock:     20,
+		LatestBlockHash: head,
+	})
+	tests := []struct {
+		version uint
+		data    []byte
+		number  uint64
+	}{
+		{eth.ETH68, status68, 0},
+		{eth.ETH69, status69, 20},
+	}
+	for _, test := range tests {
+		var result nodeHandshake
+		if err := decodeStatus(test.version, test.data, &result); err != nil {
+			t.Fatalf("eth/%d: decode failed: %v", test.version, err)
+		}
+		if result.EthVersion != uint32(test.version) || result.NetworkID != 5 || result.HeadNumber != test.number {
+			t.Errorf("eth/%d: wrong status fields %+v", test.version, result)
+		}
+		if result.Genesis == nil || *result.Genesis != genesis || result.Head == nil || *result.Head != head {
+			t.Errorf("eth/%d: wrong genesis or head %v %v", test.version, result.Genesis, result.Head)
+		}
+		if id, ok := result.forkID(); !ok || id != fork {
+			t.Errorf("eth/%d: wrong fork ID %v", test.version, id)
+		}
+	}
+	// The message format depends on the negotiated version.
+	var result nodeHandshake
+	if err := decodeStatus(eth.ETH69, status68, &result); err == nil {
+		t.Error("eth/68 status accepted for eth/69")
+	}
+	if err := decodeStatus(eth.ETH68, []byte{0x01}, &result); err == nil {
+		t.Error("invalid status accepted")
+	}
+}
+
+func TestNegotiateEth(t *testing.T) {
+	ours := []p2p.Cap{{Name: "eth", Version: 69}, {Name: "eth", Version: 68}}
+	tests := []struct {
+		theirs []p2p.Cap
+		want   uint
+	}{
+		{[]p2p.Cap{{Name: "eth", Version: 68}, {Name: "eth", Version: 69}, {Name: "snap", Version: 1}}, 69},
+		{[]p2p.Cap{{Name: "eth", Version: 68}}, 68},
+		{[]p2p.Cap{{Name: "eth", Version: 67}, {Name: "eth", Version: 70}}, 0},
+		{[]p2p.Cap{{Name: "snap", Version: 69}}, 0},
+		{nil, 0},
+	}
+	for _, test := range tests {
+		if v := negotiateEth(ours, test.theirs); v != test.want {
+			t.Errorf("negotiateEth(%v) = %d, want %d", test.theirs, v, test.want)
+		}
+	}
+}
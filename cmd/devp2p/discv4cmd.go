@@ -91,7 +91,7 @@ var (
 		Name:   "crawl",
 		Usage:  "Updates a nodes.json file with random nodes found in the DHT",
 		Action: discv4Crawl,
-		Flags:  slices.Concat(discoveryNodeFlags, []cli.Flag{crawlTimeoutFlag, crawlParallelismFlag}),
+		Flags:  slices.Concat(discoveryNodeFlags, []cli.Flag{crawlTimeoutFlag, crawlParallelismFlag, crawlHandshakeFlag}),
 	}
 	discv4TestCommand = &cli.Command{
 		Name:   "test",
@@ -138,6 +138,10 @@ var (
 		Usage: "How many parallel discoveries to attempt.",
 		Value: 16,
 	}
+	crawlHandshakeFlag = &cli.BoolFlag{
+		Name:  "handshake",
+		Usage: "Performs an RLPx and eth handshake with responding nodes to record client and chain information.",
+	}
 	remoteEnodeFlag = &cli.StringFlag{
 		Name:    "remote",
 		Usage:   "Enode of the remote node under test",
@@ -264,6 +268,9 @@ func discv4Crawl(ctx *cli.Context) error {
 		return err
 	}
 	c.revalidateInterval = 10 * time.Minute
+	if ctx.Bool(crawlHandshakeFlag.Name) {
+		c.handshake = newHandshaker().handshake
+	}
 	output := c.run(ctx.Duration(crawlTimeoutFlag.Name), ctx.Int(crawlParallelismFlag.Name))
 	writeNodesJSON(nodesFile, output)
 	return nil
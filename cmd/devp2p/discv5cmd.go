@@ -58,6 +58,7 @@ var (
 		Action: discv5Crawl,
 		Flags: slices.Concat(discoveryNodeFlags, []cli.Flag{
 			crawlTimeoutFlag,
+			crawlHandshakeFlag,
 		}),
 	}
 	discv5TestCommand = &cli.Command{
@@ -115,6 +116,9 @@ func discv5Crawl(ctx *cli.Context) error {
 		return err
 	}
 	c.revalidateInterval = 10 * time.Minute
+	if ctx.Bool(crawlHandshakeFlag.Name) {
+		c.handshake = newHandshaker().handshake
+	}
 	output := c.run(ctx.Duration(crawlTimeoutFlag.Name), ctx.Int(crawlParallelismFlag.Name))
 	writeNodesJSON(nodesFile, output)
 	return nil
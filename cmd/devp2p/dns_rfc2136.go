@@ -0,0 +1,529 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// rfc2136MessageSizeLimit is the size limit of update messages. Messages sent
+	// over TCP can be up to 64kB, the rest is reserved for the header, zone and
+	// TSIG record.
+	rfc2136MessageSizeLimit = 60000
+	rfc2136DefaultTimeout   = 30 * time.Second
+
+	opcodeUpdate dnsmessage.OpCode = 5
+)
+
+var rfc2136Errors = map[dnsmessage.RCode]string{
+	6:  "YXDOMAIN",
+	7:  "YXRRSET",
+	8:  "NXRRSET",
+	9:  "NOTAUTH",
+	10: "NOTZONE",
+}
+
+var (
+	rfc2136ServerFlag = &cli.StringFlag{
+		Name:  "server",
+		Usage: "Address of the authoritative DNS server (host[:port])",
+	}
+	rfc2136ZoneFlag = &cli.StringFlag{
+		Name:  "zone",
+		Usage: "DNS zone containing the tree (default: found via SOA query)",
+	}
+	rfc2136KeyNameFlag = &cli.StringFlag{
+		Name:  "tsig-key",
+		Usage: "Name of the TSIG key",
+	}
+	rfc2136KeySecretFlag = &cli.StringFlag{
+		Name:    "tsig-secret",
+		Usage:   "Base64-encoded TSIG secret",
+		EnvVars: []string{"TSIG_SECRET"},
+	}
+	rfc2136KeyAlgorithmFlag = &cli.StringFlag{
+		Name:  "tsig-algorithm",
+		Usage: "TSIG algorithm (hmac-sha1, hmac-sha224, hmac-sha256, hmac-sha384, hmac-sha512)",
+		Value: "hmac-sha256",
+	}
+	rfc2136DryRunFlag = &cli.BoolFlag{
+		Name:  "dry-run",
+		Usage: "Print the changes instead of applying them",
+	}
+)
+
+// rfc2136Client deploys trees to a DNS server supporting dynamic updates.
+type rfc2136Client struct {
+	server  string
+	zone    string
+	key     *tsigKey
+	timeout time.Duration
+	dryRun  bool
+	out     io.Writer // dry run output
+}
+
+// rfc2136Change is a change of the TXT records at a name.
+type rfc2136Change struct {
+	action string // "CREATE", "UPDATE" or "DELETE"
+	name   string
+	ttl    uint32
+	value  string // new value, empty for deletions
+}
+
+// newRFC2136Client sets up a dynamic update client from command line flags.
+func newRFC2136Client(ctx *cli.Context) *rfc2136Client {
+	server := ctx.String(rfc2136ServerFlag.Name)
+	if server == "" {
+		exit(errors.New("need DNS server address to proceed"))
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	c := &rfc2136Client{
+		server:  server,
+		zone:    canonicalName(ctx.String(rfc2136ZoneFlag.Name)),
+		timeout: ctx.Duration(dnsTimeoutFlag.Name),
+		dryRun:  ctx.Bool(rfc2136DryRunFlag.Name),
+		out:     os.Stdout,
+	}
+	if c.timeout == 0 {
+		c.timeout = rfc2136DefaultTimeout
+	}
+	if name := ctx.String(rfc2136KeyNameFlag.Name); name != "" {
+		key, err := newTSIGKey(name, ctx.String(rfc2136KeyAlgorithmFlag.Name), ctx.String(rfc2136KeySecretFlag.Name))
+		if err != nil {
+			exit(err)
+		}
+		c.key = key
+	} else {
+		log.Warn("No TSIG key configured, sending unsigned requests")
+	}
+	return c
+}
+
+// deploy uploads the given tree to the DNS server.
+func (c *rfc2136Client) deploy(name string, t *dnsdisc.Tree) error {
+	name = canonicalName(name)
+	if err := c.checkZone(name); err != nil {
+		return err
+	}
+
+	// Compute DNS changes.
+	existing, err := c.collectRecords(name)
+	if err != nil {
+		return err
+	}
+	records := t.ToTXT(name)
+	changes := computeRFC2136Changes(name, records, existing)
+
+	// Submit to server.
+	return c.submitChanges(changes)
+}
+
+// checkZone verifies zone information for the given domain.
+func (c *rfc2136Client) checkZone(name string) (err error) {
+	if c.zone == "" {
+		c.zone, err = c.findZone(name)
+		return err
+	}
+	if !isSubdomain(name, c.zone) {
+		return fmt.Errorf("%s is not in zone %s", name, c.zone)
+	}
+	return nil
+}
+
+// findZone queries the SOA record of the given domain to find its zone. The
+// server answers with the SOA record of the zone in the answer section if name
+// is the zone apex, and in the authority section otherwise.
+func (c *rfc2136Client) findZone(name string) (string, error) {
+	log.Info(fmt.Sprintf("Finding zone of %s", name))
+	msg, err := newDNSQuery(name, dnsmessage.TypeSOA)
+	if err != nil {
+		return "", err
+	}
+	var zone string
+	err = c.roundTrip(msg, func(h dnsmessage.Header, p *dnsmessage.Parser) (bool, error) {
+		if h.RCode != dnsmessage.RCodeNameError {
+			if err := rcodeError(h.RCode); err != nil {
+				return false, err
+			}
+		}
+		if err := p.SkipAllQuestions(); err != nil {
+			return false, err
+		}
+		answers, err := p.AllAnswers()
+		if err != nil {
+			return false, err
+		}
+		authorities, err := p.AllAuthorities()
+		if err != nil {
+			return false, err
+		}
+		for _, rr := range append(answers, authorities...) {
+			if rr.Header.Type == dnsmessage.TypeSOA && isSubdomain(name, rr.Header.Name.String()) {
+				zone = canonicalName(rr.Header.Name.String())
+				return true, nil
+			}
+		}
+		return false, errors.New("can't find zone of " + name)
+	})
+	return zone, err
+}
+
+// collectRecords collects all TXT records below the given name by transferring
+// the zone.
+func (c *rfc2136Client) collectRecords(name string) (map[string]recordSet, error) {
+	log.Info("Loading existing TXT records", "name", name, "zone", c.zone, "server", c.server)
+	msg, err := newDNSQuery(c.zone, dnsmessage.TypeAXFR)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		existing = make(map[string]recordSet)
+		soas     int
+	)
+	err = c.roundTrip(msg, func(h dnsmessage.Header, p *dnsmessage.Parser) (bool, error) {
+		if err := rcodeError(h.RCode); err != nil {
+			return false, err
+		}
+		if err := p.SkipAllQuestions(); err != nil {
+			return false, err
+		}
+		for {
+			rh, err := p.AnswerHeader()
+			if err == dnsmessage.ErrSectionDone {
+				break
+			} else if err != nil {
+				return false, err
+			}
+			// The transfer starts and ends with the SOA record of the zone.
+			switch {
+			case rh.Type == dnsmessage.TypeSOA:
+				soas++
+				err = p.SkipAnswer()
+			case rh.Type == dnsmessage.TypeTXT && isSubdomain(rh.Name.String(), name):
+				var txt dnsmessage.TXTResource
+				if txt, err = p.TXTResource(); err == nil {
+					path := canonicalName(rh.Name.String())
+					set := existing[path]
+					set.ttl = int64(rh.TTL)
+					set.values = append(set.values, strings.Join(txt.TXT, ""))
+					existing[path] = set
+				}
+			default:
+				err = p.SkipAnswer()
+			}
+			if err != nil {
+				return false, err
+			}
+		}
+		if soas == 0 {
+			return false, errors.New("zone transfer did not start with SOA record")
+		}
+		return soas >= 2, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("zone transfer failed: %v", err)
+	}
+	log.Info("Loaded existing TXT records", "name", name, "zone", c.zone, "records", len(existing))
+	return existing, nil
+}
+
+// computeRFC2136Changes creates DNS changes for the given set of DNS discovery
+// records. The 'existing' arg is the set of records that already exist on the server.
+func computeRFC2136Changes(name string, records map[string]string, existing map[string]recordSet) []rfc2136Change {
+	// Convert all names to lowercase.
+	lrecords := make(map[string]string, len(records))
+	for name, r := range records {
+		lrecords[strings.ToLower(name)] = r
+	}
+	records = lrecords
+
+	var (
+		changes []rfc2136Change
+		inserts int
+		updates int
+		skips   int
+		deletes int
+	)
+	for path, newValue := range records {
+		prev, exists := existing[path]
+
+		// Assign TTL.
+		ttl := uint32(rootTTL)
+		if path != name {
+			ttl = uint32(treeNodeTTL)
+		}
+
+		switch {
+		case !exists:
+			log.Debug(fmt.Sprintf("Creating %s = %q", path, newValue))
+			changes = append(changes, rfc2136Change{"CREATE", path, ttl, newValue})
+			inserts++
+		case len(prev.values) != 1 || prev.values[0] != newValue || prev.ttl != int64(ttl):
+			log.Info(fmt.Sprintf("Updating %s from %q to %q", path, strings.Join(prev.values, ""), newValue))
+			changes = append(changes, rfc2136Change{"UPDATE", path, ttl, newValue})
+			updates++
+		default:
+			log.Debug(fmt.Sprintf("Skipping %s = %q", path, newValue))
+			skips++
+		}
+	}
+
+	// Iterate over the old records and delete anything stale.
+	for path, set := range existing {
+		if _, ok := records[path]; ok {
+			continue
+		}
+		log.Debug(fmt.Sprintf("Deleting %s = %q", path, strings.Join(set.values, "")))
+		changes = append(changes, rfc2136Change{action: "DELETE", name: path})
+		deletes++
+	}
+
+	log.Info("Computed DNS changes",
+		"changes", len(changes),
+		"inserts", inserts,
+		"skips", skips,
+		"deleted", deletes,
+		"updates", updates)
+	// Ensure leaf records are added before the root changes, and stale
+	// records are removed last.
+	score := map[string]int{"CREATE": 1, "UPDATE": 2, "DELETE": 3}
+	slices.SortFunc(changes, func(a, b rfc2136Change) int {
+		return cmp.Or(cmp.Compare(score[a.action], score[b.action]), strings.Compare(a.name, b.name))
+	})
+	return changes
+}
+
+// submitChanges sends the given changes to the server as update messages.
+func (c *rfc2136Client) submitChanges(changes []rfc2136Change) error {
+	if len(changes) == 0 {
+		log.Info("No DNS changes needed")
+		return nil
+	}
+	if c.dryRun {
+		for _, ch := range changes {
+			if ch.action == "DELETE" {
+				fmt.Fprintf(c.out, "%s %s\n", ch.action, ch.name)
+			} else {
+				fmt.Fprintf(c.out, "%s %s %d %q\n", ch.action, ch.name, ch.ttl, ch.value)
+			}
+		}
+		return nil
+	}
+
+	batches := splitRFC2136Changes(changes, rfc2136MessageSizeLimit)
+	for i, batch := range batches {
+		log.Info(fmt.Sprintf("Submitting %d changes to %s (%d/%d)", len(batch), c.server, i+1, len(batches)))
+		msg, err := c.newUpdate(batch)
+		if err != nil {
+			return err
+		}
+		err = c.roundTrip(msg, func(h dnsmessage.Header, p *dnsmessage.Parser) (bool, error) {
+			return true, rcodeError(h.RCode)
+		})
+		if err != nil {
+			return fmt.Errorf("update failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// newUpdate creates an update message applying the given changes. Records are
+// replaced by deleting the TXT record set and adding the new record in the same
+// message, which the server applies atomically.
+func (c *rfc2136Client) newUpdate(changes []rfc2136Change) ([]byte, error) {
+	zone, err := dnsName(c.zone)
+	if err != nil {
+		return nil, err
+	}
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: uint16(rand.Uint32()), OpCode: opcodeUpdate})
+	b.EnableCompression()
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: zone, Type: dnsmessage.TypeSOA, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	if err := b.StartAuthorities(); err != nil {
+		return nil, err
+	}
+	for _, ch := range changes {
+		name, err := dnsName(ch.name)
+		if err != nil {
+			return nil, err
+		}
+		if ch.action != "CREATE" {
+			h := dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassANY}
+			if err := b.UnknownResource(h, dnsmessage.UnknownResource{Type: dnsmessage.TypeTXT}); err != nil {
+				return nil, err
+			}
+		}
+		if ch.action != "DELETE" {
+			h := dnsmessage.ResourceHeader{Name: name, Class: dnsmessage.ClassINET, TTL: ch.ttl}
+			if err := b.TXTResource(h, dnsmessage.TXTResource{TXT: txtStrings(ch.value)}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b.Finish()
+}
+
+// roundTrip sends a message to the server over TCP and passes the response
+// messages to handle until it returns true. Requests are signed and responses
+// are verified if a TSIG key is configured.
+func (c *rfc2136Client) roundTrip(msg []byte, handle func(dnsmessage.Header, *dnsmessage.Parser) (bool, error)) error {
+	conn, err := net.DialTimeout("tcp", c.server, c.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(c.timeout))
+
+	var verifier *tsigVerifier
+	if c.key != nil {
+		var mac []byte
+		msg, mac = c.key.sign(msg, nil, false, time.Now())
+		verifier = c.key.newVerifier(mac)
+	}
+	if err := writeTCPMessage(conn, msg); err != nil {
+		return err
+	}
+	id := binary.BigEndian.Uint16(msg)
+	for {
+		resp, err := readTCPMessage(conn)
+		if err != nil {
+			return err
+		}
+		var p dnsmessage.Parser
+		h, err := p.Start(resp)
+		if err != nil {
+			return err
+		}
+		if h.ID != id || !h.Response {
+			return errors.New("unexpected DNS message")
+		}
+		if verifier != nil {
+			if err := verifier.verify(resp, time.Now()); err != nil {
+				if rerr := rcodeError(h.RCode); rerr != nil {
+					return fmt.Errorf("%v (%v)", rerr, err)
+				}
+				return err
+			}
+		}
+		done, err := handle(h, &p)
+		if err != nil {
+			return err
+		}
+		if done {
+			if verifier != nil && !verifier.complete() {
+				return errors.New("last response message is not signed")
+			}
+			return nil
+		}
+	}
+}
+
+// splitRFC2136Changes splits up changes such that each update message stays
+// below the given size.
+func splitRFC2136Changes(changes []rfc2136Change, sizeLimit int) [][]rfc2136Change {
+	var (
+		batches   [][]rfc2136Change
+		batchSize int
+	)
+	for _, ch := range changes {
+		size := rfc2136ChangeSize(ch)
+		if len(batches) == 0 || batchSize+size > sizeLimit {
+			batches = append(batches, nil)
+			batchSize = 0
+		}
+		batches[len(batches)-1] = append(batches[len(batches)-1], ch)
+		batchSize += size
+	}
+	return batches
+}
+
+// rfc2136ChangeSize returns the maximum encoded size of the records of a change.
+func rfc2136ChangeSize(ch rfc2136Change) int {
+	rrsize := len(ch.name) + 2 + 10 // name, type, class, TTL and RDATA length
+	size := 0
+	if ch.action != "CREATE" {
+		size += rrsize
+	}
+	if ch.action != "DELETE" {
+		size += rrsize + len(ch.value) + len(txtStrings(ch.value))
+	}
+	return size
+}
+
+// newDNSQuery creates a query message.
+func newDNSQuery(name string, qtype dnsmessage.Type) ([]byte, error) {
+	qname, err := dnsName(name)
+	if err != nil {
+		return nil, err
+	}
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: uint16(rand.Uint32())})
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+// dnsName converts a domain name to its absolute form.
+func dnsName(name string) (dnsmessage.Name, error) {
+	return dnsmessage.NewName(canonicalName(name) + ".")
+}
+
+// txtStrings splits a TXT record value into character strings of at most 255 bytes.
+func txtStrings(value string) []string {
+	var result []string
+	for len(value) > 255 {
+		result = append(result, value[:255])
+		value = value[255:]
+	}
+	return append(result, value)
+}
+
+// rcodeError returns an error for unsuccessful response codes.
+func rcodeError(code dnsmessage.RCode) error {
+	if code == dnsmessage.RCodeSuccess {
+		return nil
+	}
+	if s, ok := rfc2136Errors[code]; ok {
+		return fmt.Errorf("server returned %s", s)
+	}
+	return fmt.Errorf("server returned %v", code)
+}
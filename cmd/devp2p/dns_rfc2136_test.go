@@ -0,0 +1,337 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"maps"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const testTSIGSecret = "c2VjcmV0LWtleS1mb3ItdGVzdGluZy10c2lnLXVwZGF0ZXM="
+
+// fakeDNSServer is a minimal authoritative DNS server for a single zone. It
+// answers SOA queries and zone transfers, and applies dynamic updates.
+type fakeDNSServer struct {
+	t    *testing.T
+	zone string
+	key  *tsigKey
+	ln   net.Listener
+
+	mu      sync.Mutex
+	records map[string][]fakeTXT
+	updates int
+}
+
+type fakeTXT struct {
+	ttl   uint32
+	value string
+}
+
+func newFakeDNSServer(t *testing.T, zone string, records map[string][]fakeTXT) *fakeDNSServer {
+	key, err := newTSIGKey("update-key", "hmac-sha256", testTSIGSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeDNSServer{t: t, zone: zone, key: key, ln: ln, records: records}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeDNSServer) client() *rfc2136Client {
+	return &rfc2136Client{server: s.ln.Addr().String(), key: s.key, timeout: 5 * time.Second}
+}
+
+func (s *fakeDNSServer) snapshot() (map[string][]fakeTXT, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return maps.Clone(s.records), s.updates
+}
+
+func (s *fakeDNSServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			if err := s.handle(conn); err != nil {
+				s.t.Log("fake DNS server error:", err)
+			}
+		}()
+	}
+}
+
+func (s *fakeDNSServer) handle(conn net.Conn) error {
+	req, err := readTCPMessage(conn)
+	if err != nil {
+		return err
+	}
+	stripped, rec, err := splitTSIG(req)
+	if err != nil {
+		return err
+	}
+	var p dnsmessage.Parser
+	h, err := p.Start(stripped)
+	if err != nil {
+		return err
+	}
+	q, err := p.Question()
+	if err != nil {
+		return err
+	}
+	if rec == nil || !hmac.Equal(rec.mac, s.key.mac(nil, stripped, rec.variables(s.key.name))) {
+		resp := s.response(h, q, 9, nil)
+		return writeTCPMessage(conn, resp)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case h.OpCode == opcodeUpdate:
+		p.SkipAllQuestions()
+		if err := p.SkipAllAnswers(); err != nil {
+			return err
+		}
+		if err := s.applyUpdate(&p); err != nil {
+			return err
+		}
+		s.updates++
+		resp, _ := s.key.sign(s.response(h, q, 0, nil), rec.mac, false, time.Now())
+		return writeTCPMessage(conn, resp)
+
+	case q.Type == dnsmessage.TypeSOA:
+		resp, _ := s.key.sign(s.response(h, q, 0, nil), rec.mac, false, time.Now())
+		return writeTCPMessage(conn, resp)
+
+	case q.Type == dnsmessage.TypeAXFR:
+		// Transfer the zone in two messages, the second one signed with timers only.
+		names := slices.Sorted(maps.Keys(s.records))
+		first := s.response(h, q, 0, func(b *dnsmessage.Builder) { s.addRecords(b, names[:len(names)/2], true) })
+		second := s.response(h, q, 0, func(b *dnsmessage.Builder) { s.addRecords(b, names[len(names)/2:], false) })
+		resp, mac := s.key.sign(first, rec.mac, false, time.Now())
+		if err := writeTCPMessage(conn, resp); err != nil {
+			return err
+		}
+		resp, _ = s.key.sign(second, mac, true, time.Now())
+		return writeTCPMessage(conn, resp)
+	}
+	return nil
+}
+
+func (s *fakeDNSServer) applyUpdate(p *dnsmessage.Parser) error {
+	for {
+		h, err := p.AuthorityHeader()
+		if err == dnsmessage.ErrSectionDone {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		name := canonicalName(h.Name.String())
+		switch h.Class {
+		case dnsmessage.ClassANY:
+			delete(s.records, name)
+			err = p.SkipAuthority()
+		case dnsmessage.ClassINET:
+			var txt dnsmessage.TXTResource
+			if txt, err = p.TXTResource(); err == nil {
+				s.records[name] = append(s.records[name], fakeTXT{h.TTL, strings.Join(txt.TXT, "")})
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// response creates a response message. Answers are added by the optional
+// answers function, other responses carry the SOA record in the authority section.
+func (s *fakeDNSServer) response(req dnsmessage.Header, q dnsmessage.Question, rcode dnsmessage.RCode, answers func(*dnsmessage.Builder)) []byte {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: req.ID, Response: true, OpCode: req.OpCode, Authoritative: true, RCode: rcode})
+	b.EnableCompression()
+	b.StartQuestions()
+	b.Question(q)
+	if answers != nil {
+		b.StartAnswers()
+		answers(&b)
+	} else if rcode == 0 && req.OpCode == 0 {
+		b.StartAuthorities()
+		s.addSOA(&b)
+	}
+	msg, err := b.Finish()
+	if err != nil {
+		s.t.Error(err)
+	}
+	return msg
+}
+
+func (s *fakeDNSServer) addRecords(b *dnsmessage.Builder, names []string, first bool) {
+	if first {
+		s.addSOA(b)
+	}
+	for _, name := range names {
+		for _, rec := range s.records[name] {
+			h := dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name + "."), Class: dnsmessage.ClassINET, TTL: rec.ttl}
+			b.TXTResource(h, dnsmessage.TXTResource{TXT: txtStrings(rec.value)})
+		}
+	}
+	if !first {
+		s.addSOA(b)
+	}
+}
+
+func (s *fakeDNSServer) addSOA(b *dnsmessage.Builder) {
+	h := dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(s.zone + "."), Class: dnsmessage.ClassINET, TTL: 3600}
+	b.SOAResource(h, dnsmessage.SOAResource{
+		NS:      dnsmessage.MustNewName("ns." + s.zone + "."),
+		MBox:    dnsmessage.MustNewName("admin." + s.zone + "."),
+		Serial:  1,
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		MinTTL:  60,
+	})
+}
+
+func makeTestTree(t *testing.T, seq uint, count int) *dnsdisc.Tree {
+	nodes := make([]*enode.Node, count)
+	for i := range nodes {
+		key, _ := crypto.GenerateKey()
+		var r enr.Record
+		r.Set(enr.IPv4{127, 0, 0, byte(i)})
+		r.Set(enr.TCP(30303))
+		if err := enode.SignV4(&r, key); err != nil {
+			t.Fatal(err)
+		}
+		n, err := enode.New(enode.ValidSchemes, &r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes[i] = n
+	}
+	tree, err := dnsdisc.MakeTree(seq, nodes, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := crypto.GenerateKey()
+	if _, err := tree.Sign(key, "nodes.example.org"); err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func TestRFC2136Deploy(t *testing.T) {
+	srv := newFakeDNSServer(t, "example.org", map[string][]fakeTXT{
+		"other.example.org":       {{300, "unrelated"}},
+		"nodes.example.org":       {{rootTTL, "enrtree-root:v1 old"}},
+		"stale.nodes.example.org": {{treeNodeTTL, "enrtree-branch:"}},
+	})
+	tree := makeTestTree(t, 1, 40)
+
+	// The zone is found via SOA query.
+	c := srv.client()
+	if err := c.deploy("nodes.example.org", tree); err != nil {
+		t.Fatal(err)
+	}
+	if c.zone != "example.org" {
+		t.Errorf("wrong zone %q", c.zone)
+	}
+	records, updates := srv.snapshot()
+	if updates == 0 {
+		t.Fatal("no updates received")
+	}
+	if _, ok := records["stale.nodes.example.org"]; ok {
+		t.Error("stale record not deleted")
+	}
+	if _, ok := records["other.example.org"]; !ok {
+		t.Error("unrelated record deleted")
+	}
+	want := tree.ToTXT("nodes.example.org")
+	for name, value := range want {
+		have := records[strings.ToLower(name)]
+		if len(have) != 1 || have[0].value != value {
+			t.Errorf("wrong records at %s: %v", name, have)
+		}
+	}
+	if len(records) != len(want)+1 {
+		t.Errorf("wrong number of records %d, want %d", len(records), len(want)+1)
+	}
+	if ttl := records["nodes.example.org"][0].ttl; ttl != rootTTL {
+		t.Errorf("wrong root TTL %d", ttl)
+	}
+
+	// Deploying the same tree again doesn't change anything.
+	if err := c.deploy("nodes.example.org", tree); err != nil {
+		t.Fatal(err)
+	}
+	if _, n := srv.snapshot(); n != updates {
+		t.Errorf("unchanged tree sent %d updates", n-updates)
+	}
+
+	// A dry run prints the root update without applying it.
+	var out bytes.Buffer
+	c.dryRun, c.out = true, &out
+	if err := c.deploy("nodes.example.org", makeTestTree(t, 2, 40)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "UPDATE nodes.example.org 1800") {
+		t.Errorf("dry run output misses root update:\n%s", out.String())
+	}
+	if after, n := srv.snapshot(); n != updates || !maps.EqualFunc(after, records, slices.Equal) {
+		t.Error("dry run changed records")
+	}
+}
+
+func TestRFC2136WrongKey(t *testing.T) {
+	srv := newFakeDNSServer(t, "example.org", map[string][]fakeTXT{})
+	c := srv.client()
+	c.zone = "example.org"
+	c.key, _ = newTSIGKey("update-key", "hmac-sha256", "d3Jvbmc=")
+	err := c.deploy("nodes.example.org", makeTestTree(t, 1, 3))
+	if err == nil || !strings.Contains(err.Error(), "NOTAUTH") {
+		t.Fatalf("wrong error: %v", err)
+	}
+	if records, n := srv.snapshot(); n != 0 || len(records) != 0 {
+		t.Error("records changed")
+	}
+}
+
+func TestTXTStrings(t *testing.T) {
+	value := strings.Repeat("a", 600)
+	parts := txtStrings(value)
+	if len(parts) != 3 || len(parts[0]) != 255 || strings.Join(parts, "") != value {
+		t.Errorf("wrong split: %d parts", len(parts))
+	}
+}
@@ -0,0 +1,421 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// This file implements TSIG (RFC 8945) message authentication for the RFC 2136
+// deployer, along with the bits of DNS wire format handling it needs.
+
+const (
+	typeTSIG         dnsmessage.Type = 250
+	tsigFudge                        = 300 // seconds
+	tsigMaxUnsigned                  = 99  // unsigned messages allowed between signed ones
+	dnsHeaderLen                     = 12
+	dnsMaxMessageLen                 = 0xffff
+)
+
+var tsigAlgorithms = map[string]func() hash.Hash{
+	"hmac-sha1":   sha1.New,
+	"hmac-sha224": sha256.New224,
+	"hmac-sha256": sha256.New,
+	"hmac-sha384": sha512.New384,
+	"hmac-sha512": sha512.New,
+}
+
+var tsigErrors = map[uint16]string{
+	16: "BADSIG",
+	17: "BADKEY",
+	18: "BADTIME",
+	22: "BADTRUNC",
+}
+
+var errMalformedDNS = errors.New("malformed DNS message")
+
+// tsigKey is a shared secret used to sign DNS messages.
+type tsigKey struct {
+	name      string // lower case, without trailing dot
+	algorithm string // lower case, without trailing dot
+	secret    []byte
+	hash      func() hash.Hash
+}
+
+// newTSIGKey creates a key from its name, algorithm and base64-encoded secret.
+func newTSIGKey(name, algorithm, secret string) (*tsigKey, error) {
+	algorithm = canonicalName(algorithm)
+	h, ok := tsigAlgorithms[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported TSIG algorithm %q", algorithm)
+	}
+	s, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TSIG secret: %v", err)
+	}
+	if len(s) == 0 {
+		return nil, errors.New("empty TSIG secret")
+	}
+	return &tsigKey{name: canonicalName(name), algorithm: algorithm, secret: s, hash: h}, nil
+}
+
+// mac computes the MAC over the given data. prevMAC is the MAC of the request or
+// of the previous signed message when computing the MAC of a response, and nil
+// for requests.
+func (k *tsigKey) mac(prevMAC []byte, data ...[]byte) []byte {
+	h := hmac.New(k.hash, k.secret)
+	if prevMAC != nil {
+		h.Write(binary.BigEndian.AppendUint16(nil, uint16(len(prevMAC))))
+		h.Write(prevMAC)
+	}
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// sign appends a TSIG record to msg. Responses are signed with the MAC of the
+// request as prevMAC. Subsequent messages of a multi-message response only
+// cover the timers instead of all TSIG variables. It returns the signed message
+// and its MAC.
+func (k *tsigKey) sign(msg, prevMAC []byte, timersOnly bool, now time.Time) (signed, mac []byte) {
+	rec := &tsigRecord{
+		algorithm: k.algorithm,
+		time:      uint64(now.Unix()),
+		fudge:     tsigFudge,
+		origID:    binary.BigEndian.Uint16(msg),
+	}
+	if timersOnly {
+		rec.mac = k.mac(prevMAC, msg, rec.appendTimers(nil))
+	} else {
+		rec.mac = k.mac(prevMAC, msg, rec.variables(k.name))
+	}
+	signed = rec.append(slices.Clone(msg), k.name)
+	binary.BigEndian.PutUint16(signed[10:], binary.BigEndian.Uint16(msg[10:])+1)
+	return signed, rec.mac
+}
+
+// tsigVerifier checks the signatures of the messages in a response.
+type tsigVerifier struct {
+	key      *tsigKey
+	prevMAC  []byte // MAC of the request or the last signed response message
+	first    bool   // whether no response message has been verified yet
+	pending  []byte // unsigned messages received since the last signed one
+	unsigned int
+}
+
+func (k *tsigKey) newVerifier(requestMAC []byte) *tsigVerifier {
+	return &tsigVerifier{key: k, prevMAC: requestMAC, first: true}
+}
+
+// verify checks the TSIG record of a response message. In multi-message
+// responses, messages may be unsigned. They are covered by the MAC of the next
+// signed message.
+func (v *tsigVerifier) verify(msg []byte, now time.Time) error {
+	stripped, rec, err := splitTSIG(msg)
+	if err != nil {
+		return err
+	}
+	if rec == nil {
+		if v.first {
+			return errors.New("response is not signed")
+		}
+		if v.unsigned++; v.unsigned > tsigMaxUnsigned {
+			return errors.New("too many unsigned response messages")
+		}
+		v.pending = append(v.pending, stripped...)
+		return nil
+	}
+	if rec.error != 0 {
+		return fmt.Errorf("TSIG error %s", tsigErrorString(rec.error))
+	}
+	if rec.algorithm != v.key.algorithm {
+		return fmt.Errorf("response signed with %s, want %s", rec.algorithm, v.key.algorithm)
+	}
+	var mac []byte
+	if v.first {
+		mac = v.key.mac(v.prevMAC, v.pending, stripped, rec.variables(v.key.name))
+	} else {
+		mac = v.key.mac(v.prevMAC, v.pending, stripped, rec.appendTimers(nil))
+	}
+	if !hmac.Equal(mac, rec.mac) {
+		return errors.New("invalid TSIG signature on response")
+	}
+	if d := now.Unix() - int64(rec.time); d > int64(rec.fudge) || -d > int64(rec.fudge) {
+		return errors.New("TSIG time of response out of range")
+	}
+	v.prevMAC, v.first, v.pending, v.unsigned = rec.mac, false, nil, 0
+	return nil
+}
+
+// complete reports whether all received messages are covered by a signature.
+func (v *tsigVerifier) complete() bool {
+	return v.unsigned == 0
+}
+
+// tsigRecord is the content of a TSIG resource record.
+type tsigRecord struct {
+	algorithm string
+	time      uint64 // 48 bits
+	fudge     uint16
+	mac       []byte
+	origID    uint16
+	error     uint16
+	other     []byte
+}
+
+// variables encodes the TSIG variables covered by the MAC.
+func (r *tsigRecord) variables(keyName string) []byte {
+	b := appendDNSName(nil, keyName)
+	b = binary.BigEndian.AppendUint16(b, uint16(dnsmessage.ClassANY))
+	b = binary.BigEndian.AppendUint32(b, 0)
+	b = appendDNSName(b, r.algorithm)
+	b = r.appendTimers(b)
+	b = binary.BigEndian.AppendUint16(b, r.error)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(r.other)))
+	return append(b, r.other...)
+}
+
+func (r *tsigRecord) appendTimers(b []byte) []byte {
+	b = append(b, byte(r.time>>40), byte(r.time>>32), byte(r.time>>24), byte(r.time>>16), byte(r.time>>8), byte(r.time))
+	return binary.BigEndian.AppendUint16(b, r.fudge)
+}
+
+// append encodes the record and appends it to b.
+func (r *tsigRecord) append(b []byte, keyName string) []byte {
+	b = appendDNSName(b, keyName)
+	b = binary.BigEndian.AppendUint16(b, uint16(typeTSIG))
+	b = binary.BigEndian.AppendUint16(b, uint16(dnsmessage.ClassANY))
+	b = binary.BigEndian.AppendUint32(b, 0)
+	lenOffset := len(b)
+	b = append(b, 0, 0)
+	b = appendDNSName(b, r.algorithm)
+	b = r.appendTimers(b)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(r.mac)))
+	b = append(b, r.mac...)
+	b = binary.BigEndian.AppendUint16(b, r.origID)
+	b = binary.BigEndian.AppendUint16(b, r.error)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(r.other)))
+	b = append(b, r.other...)
+	binary.BigEndian.PutUint16(b[lenOffset:], uint16(len(b)-lenOffset-2))
+	return b
+}
+
+// parseTSIG decodes the RDATA of a TSIG record.
+func parseTSIG(rdata []byte) (*tsigRecord, error) {
+	var (
+		r   tsigRecord
+		err error
+		off int
+	)
+	if r.algorithm, off, err = readDNSName(rdata, 0); err != nil {
+		return nil, err
+	}
+	if off+10 > len(rdata) {
+		return nil, errMalformedDNS
+	}
+	for _, b := range rdata[off : off+6] {
+		r.time = r.time<<8 | uint64(b)
+	}
+	r.fudge = binary.BigEndian.Uint16(rdata[off+6:])
+	macLen := int(binary.BigEndian.Uint16(rdata[off+8:]))
+	off += 10
+	if off+macLen+6 > len(rdata) {
+		return nil, errMalformedDNS
+	}
+	r.mac = rdata[off : off+macLen]
+	off += macLen
+	r.origID = binary.BigEndian.Uint16(rdata[off:])
+	r.error = binary.BigEndian.Uint16(rdata[off+2:])
+	otherLen := int(binary.BigEndian.Uint16(rdata[off+4:]))
+	off += 6
+	if off+otherLen != len(rdata) {
+		return nil, errMalformedDNS
+	}
+	r.other = rdata[off:]
+	return &r, nil
+}
+
+// splitTSIG removes the TSIG record from the end of a message. It returns the
+// message as it was before signing, and the TSIG record. If the message is not
+// signed, it is returned unchanged along with a nil record.
+func splitTSIG(msg []byte) ([]byte, *tsigRecord, error) {
+	if len(msg) < dnsHeaderLen {
+		return nil, nil, errMalformedDNS
+	}
+	var (
+		qdcount = int(binary.BigEndian.Uint16(msg[4:]))
+		ancount = int(binary.BigEndian.Uint16(msg[6:]))
+		nscount = int(binary.BigEndian.Uint16(msg[8:]))
+		arcount = int(binary.BigEndian.Uint16(msg[10:]))
+		off     = dnsHeaderLen
+		err     error
+	)
+	if arcount == 0 {
+		return msg, nil, nil
+	}
+	for i := 0; i < qdcount; i++ {
+		if off, err = skipDNSName(msg, off); err != nil {
+			return nil, nil, err
+		}
+		off += 4
+	}
+	for i := 0; i < ancount+nscount+arcount-1; i++ {
+		if off, err = skipDNSResource(msg, off); err != nil {
+			return nil, nil, err
+		}
+	}
+	// The TSIG record is always the last record of the message.
+	start := off
+	if off, err = skipDNSName(msg, off); err != nil {
+		return nil, nil, err
+	}
+	if off+10 > len(msg) {
+		return nil, nil, errMalformedDNS
+	}
+	if dnsmessage.Type(binary.BigEndian.Uint16(msg[off:])) != typeTSIG {
+		return msg, nil, nil
+	}
+	rdlen := int(binary.BigEndian.Uint16(msg[off+8:]))
+	if off+10+rdlen != len(msg) {
+		return nil, nil, errMalformedDNS
+	}
+	rec, err := parseTSIG(msg[off+10:])
+	if err != nil {
+		return nil, nil, err
+	}
+	stripped := slices.Clone(msg[:start])
+	binary.BigEndian.PutUint16(stripped, rec.origID)
+	binary.BigEndian.PutUint16(stripped[10:], uint16(arcount-1))
+	return stripped, rec, nil
+}
+
+// skipDNSName returns the offset after the possibly compressed name at off.
+func skipDNSName(msg []byte, off int) (int, error) {
+	for {
+		if off >= len(msg) {
+			return 0, errMalformedDNS
+		}
+		switch l := int(msg[off]); {
+		case l == 0:
+			return off + 1, nil
+		case l&0xC0 == 0xC0:
+			if off+2 > len(msg) {
+				return 0, errMalformedDNS
+			}
+			return off + 2, nil
+		case l&0xC0 != 0:
+			return 0, errMalformedDNS
+		default:
+			off += 1 + l
+		}
+	}
+}
+
+// skipDNSResource returns the offset after the resource record at off.
+func skipDNSResource(msg []byte, off int) (int, error) {
+	off, err := skipDNSName(msg, off)
+	if err != nil {
+		return 0, err
+	}
+	if off+10 > len(msg) {
+		return 0, errMalformedDNS
+	}
+	off += 10 + int(binary.BigEndian.Uint16(msg[off+8:]))
+	if off > len(msg) {
+		return 0, errMalformedDNS
+	}
+	return off, nil
+}
+
+// readDNSName decodes an uncompressed name.
+func readDNSName(b []byte, off int) (string, int, error) {
+	var labels []string
+	for {
+		if off >= len(b) {
+			return "", 0, errMalformedDNS
+		}
+		l := int(b[off])
+		if l == 0 {
+			return strings.ToLower(strings.Join(labels, ".")), off + 1, nil
+		}
+		if l > 63 || off+1+l > len(b) {
+			return "", 0, errMalformedDNS
+		}
+		labels = append(labels, string(b[off+1:off+1+l]))
+		off += 1 + l
+	}
+}
+
+// appendDNSName appends the uncompressed canonical wire encoding of name to b.
+func appendDNSName(b []byte, name string) []byte {
+	if name = canonicalName(name); name != "" {
+		for _, label := range strings.Split(name, ".") {
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+	}
+	return append(b, 0)
+}
+
+// canonicalName converts a domain name to lower case and removes the trailing dot.
+func canonicalName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func tsigErrorString(code uint16) string {
+	if s, ok := tsigErrors[code]; ok {
+		return s
+	}
+	return fmt.Sprint(code)
+}
+
+// writeTCPMessage writes a length-prefixed DNS message.
+func writeTCPMessage(w io.Writer, msg []byte) error {
+	if len(msg) > dnsMaxMessageLen {
+		return errors.New("DNS message too large")
+	}
+	_, err := w.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(msg))), msg...))
+	return err
+}
+
+// readTCPMessage reads a length-prefixed DNS message.
+func readTCPMessage(r io.Reader) ([]byte, error) {
+	var size [2]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	msg := make([]byte, binary.BigEndian.Uint16(size[:]))
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
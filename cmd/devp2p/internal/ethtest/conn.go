@@ -73,6 +73,17 @@ func (s *Suite) dialAs(key *ecdsa.PrivateKey) (*Conn, error) {
 	return &conn, nil
 }
 
+// dial69 creates a connection which only offers the eth/69 capability.
+func (s *Suite) dial69() (*Conn, error) {
+	conn, err := s.dial()
+	if err != nil {
+		return nil, fmt.Errorf("dial failed: %v", err)
+	}
+	conn.caps = []p2p.Cap{{Name: "eth", Version: eth.ETH69}}
+	conn.ourHighestProtoVersion = eth.ETH69
+	return conn, nil
+}
+
 // dialSnap creates a connection with snap/1 capability.
 func (s *Suite) dialSnap() (*Conn, error) {
 	conn, err := s.dial()
@@ -113,7 +124,7 @@ func (c *Conn) ReadMsg(proto Proto, code uint64, msg any) error {
 		if err != nil {
 			return err
 		}
-		if protoOffset(proto)+code == got {
+		if c.protoOffset(proto)+code == got {
 			return rlp.DecodeBytes(data, msg)
 		}
 	}
@@ -126,7 +137,7 @@ func (c *Conn) Write(proto Proto, code uint64, msg any) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.Conn.Write(protoOffset(proto)+code, payload)
+	_, err = c.Conn.Write(c.protoOffset(proto)+code, payload)
 	return err
 }
 
@@ -142,7 +153,7 @@ func (c *Conn) ReadEth() (any, error) {
 			c.Write(baseProto, pongMsg, []byte{})
 			continue
 		}
-		if getProto(code) != ethProto {
+		if c.getProto(code) != ethProto {
 			// Read until eth message.
 			continue
 		}
@@ -151,7 +162,11 @@ func (c *Conn) ReadEth() (any, error) {
 		var msg any
 		switch int(code) {
 		case eth.StatusMsg:
-			msg = new(eth.StatusPacket)
+			if c.negotiatedProtoVersion >= eth.ETH69 {
+				msg = new(eth.StatusPacket69)
+			} else {
+				msg = new(eth.StatusPacket68)
+			}
 		case eth.GetBlockHeadersMsg:
 			msg = new(eth.GetBlockHeadersPacket)
 		case eth.BlockHeadersMsg:
@@ -172,6 +187,16 @@ func (c *Conn) ReadEth() (any, error) {
 			msg = new(eth.GetPooledTransactionsPacket)
 		case eth.PooledTransactionsMsg:
 			msg = new(eth.PooledTransactionsPacket)
+		case eth.GetReceiptsMsg:
+			msg = new(eth.GetReceiptsPacket)
+		case eth.ReceiptsMsg:
+			if c.negotiatedProtoVersion >= eth.ETH69 {
+				msg = new(eth.ReceiptsPacket69)
+			} else {
+				msg = new(eth.ReceiptsPacket)
+			}
+		case eth.BlockRangeUpdateMsg:
+			msg = new(eth.BlockRangeUpdatePacket)
 		default:
 			panic(fmt.Sprintf("unhandled eth msg code %d", code))
 		}
@@ -190,11 +215,11 @@ func (c *Conn) ReadSnap() (any, error) {
 		if err != nil {
 			return nil, err
 		}
-		if getProto(code) != snapProto {
+		if c.getProto(code) != snapProto {
 			// Read until snap message.
 			continue
 		}
-		code -= baseProtoLen + ethProtoLen
+		code -= baseProtoLen + c.ethProtoLen()
 
 		var msg any
 		switch int(code) {
@@ -226,7 +251,7 @@ func (c *Conn) ReadSnap() (any, error) {
 
 // peer performs both the protocol handshake and the status message
 // exchange with the node in order to peer with it.
-func (c *Conn) peer(chain *Chain, status *eth.StatusPacket) error {
+func (c *Conn) peer(chain *Chain, status eth.Packet) error {
 	if err := c.handshake(); err != nil {
 		return fmt.Errorf("handshake failed: %v", err)
 	}
@@ -298,8 +323,10 @@ func (c *Conn) negotiateEthProtocol(caps []p2p.Cap) {
 	c.negotiatedSnapProtoVersion = highestSnapVersion
 }
 
-// statusExchange performs a `Status` message exchange with the given node.
-func (c *Conn) statusExchange(chain *Chain, status *eth.StatusPacket) error {
+// statusExchange performs a `Status` message exchange with the given node. If
+// status is nil, a valid status message matching the negotiated eth version is
+// sent.
+func (c *Conn) statusExchange(chain *Chain, status eth.Packet) error {
 loop:
 	for {
 		code, data, err := c.Read()
@@ -307,23 +334,14 @@ loop:
 			return fmt.Errorf("failed to read from connection: %w", err)
 		}
 		switch code {
-		case eth.StatusMsg + protoOffset(ethProto):
-			msg := new(eth.StatusPacket)
-			if err := rlp.DecodeBytes(data, &msg); err != nil {
-				return fmt.Errorf("error decoding status packet: %w", err)
-			}
-			if have, want := msg.Head, chain.blocks[chain.Len()-1].Hash(); have != want {
-				return fmt.Errorf("wrong head block in status, want:  %#x (block %d) have %#x",
-					want, chain.blocks[chain.Len()-1].NumberU64(), have)
-			}
-			if have, want := msg.TD.Cmp(chain.TD()), 0; have != want {
-				return fmt.Errorf("wrong TD in status: have %v want %v", have, want)
-			}
-			if have, want := msg.ForkID, chain.ForkID(); !reflect.DeepEqual(have, want) {
-				return fmt.Errorf("wrong fork ID in status: have %v, want %v", have, want)
+		case eth.StatusMsg + c.protoOffset(ethProto):
+			if c.negotiatedProtoVersion >= eth.ETH69 {
+				err = c.checkStatus69(chain, data)
+			} else {
+				err = c.checkStatus68(chain, data)
 			}
-			if have, want := msg.ProtocolVersion, c.ourHighestProtoVersion; have != uint32(want) {
-				return fmt.Errorf("wrong protocol version: have %v, want %v", have, want)
+			if err != nil {
+				return err
 			}
 			break loop
 		case discMsg:
@@ -346,13 +364,26 @@ loop:
 	}
 	if status == nil {
 		// default status message
-		status = &eth.StatusPacket{
-			ProtocolVersion: uint32(c.negotiatedProtoVersion),
-			NetworkID:       chain.config.ChainID.Uint64(),
-			TD:              chain.TD(),
-			Head:            chain.blocks[chain.Len()-1].Hash(),
-			Genesis:         chain.blocks[0].Hash(),
-			ForkID:          chain.ForkID(),
+		head := chain.Head()
+		if c.negotiatedProtoVersion >= eth.ETH69 {
+			status = &eth.StatusPacket69{
+				ProtocolVersion: uint32(c.negotiatedProtoVersion),
+				NetworkID:       chain.config.ChainID.Uint64(),
+				Genesis:         chain.blocks[0].Hash(),
+				ForkID:          chain.ForkID(),
+				EarliestBlock:   0,
+				LatestBlock:     head.NumberU64(),
+				LatestBlockHash: head.Hash(),
+			}
+		} else {
+			status = &eth.StatusPacket68{
+				ProtocolVersion: uint32(c.negotiatedProtoVersion),
+				NetworkID:       chain.config.ChainID.Uint64(),
+				TD:              chain.TD(),
+				Head:            head.Hash(),
+				Genesis:         chain.blocks[0].Hash(),
+				ForkID:          chain.ForkID(),
+			}
 		}
 	}
 	if err := c.Write(ethProto, eth.StatusMsg, status); err != nil {
@@ -360,3 +391,51 @@ loop:
 	}
 	return nil
 }
+
+// checkStatus68 validates an eth/68 status message received from the node.
+func (c *Conn) checkStatus68(chain *Chain, data []byte) error {
+	msg := new(eth.StatusPacket68)
+	if err := rlp.DecodeBytes(data, &msg); err != nil {
+		return fmt.Errorf("error decoding status packet: %w", err)
+	}
+	if have, want := msg.Head, chain.blocks[chain.Len()-1].Hash(); have != want {
+		return fmt.Errorf("wrong head block in status, want:  %#x (block %d) have %#x",
+			want, chain.blocks[chain.Len()-1].NumberU64(), have)
+	}
+	if have, want := msg.TD.Cmp(chain.TD()), 0; have != want {
+		return fmt.Errorf("wrong TD in status: have %v want %v", have, want)
+	}
+	if have, want := msg.ForkID, chain.ForkID(); !reflect.DeepEqual(have, want) {
+		return fmt.Errorf("wrong fork ID in status: have %v, want %v", have, want)
+	}
+	if have, want := msg.ProtocolVersion, c.ourHighestProtoVersion; have != uint32(want) {
+		return fmt.Errorf("wrong protocol version: have %v, want %v", have, want)
+	}
+	return nil
+}
+
+// checkStatus69 validates an eth/69 status message received from the node.
+func (c *Conn) checkStatus69(chain *Chain, data []byte) error {
+	msg := new(eth.StatusPacket69)
+	if err := rlp.DecodeBytes(data, &msg); err != nil {
+		return fmt.Errorf("error decoding status packet: %w", err)
+	}
+	head := chain.Head()
+	if have, want := msg.LatestBlockHash, head.Hash(); have != want {
+		return fmt.Errorf("wrong latest block in status, want:  %#x (block %d) have %#x",
+			want, head.NumberU64(), have)
+	}
+	if have, want := msg.LatestBlock, head.NumberU64(); have != want {
+		return fmt.Errorf("wrong latest block number in status: have %d, want %d", have, want)
+	}
+	if msg.EarliestBlock > msg.LatestBlock {
+		return fmt.Errorf("invalid block range in status: earliest %d > latest %d", msg.EarliestBlock, msg.LatestBlock)
+	}
+	if have, want := msg.ForkID, chain.ForkID(); !reflect.DeepEqual(have, want) {
+		return fmt.Errorf("wrong fork ID in status: have %v, want %v", have, want)
+	}
+	if have, want := msg.ProtocolVersion, c.ourHighestProtoVersion; have != uint32(want) {
+		return fmt.Errorf("wrong protocol version: have %v, want %v", have, want)
+	}
+	return nil
+}
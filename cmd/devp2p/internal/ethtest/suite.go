@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/internal/utesting"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
 )
 
@@ -81,6 +82,12 @@ func (s *Suite) EthTests() []utesting.Test {
 		{Name: "InvalidTxs", Fn: s.TestInvalidTxs},
 		{Name: "NewPooledTxs", Fn: s.TestNewPooledTxs},
 		{Name: "BlobViolations", Fn: s.TestBlobViolations},
+		// eth/69
+		{Name: "Status69", Fn: s.TestStatus69},
+		{Name: "GetReceipts69", Fn: s.TestGetReceipts69},
+		{Name: "BlockRangeUpdate", Fn: s.TestBlockRangeUpdate},
+		{Name: "InvalidBlockRangeUpdate", Fn: s.TestInvalidBlockRangeUpdate},
+		{Name: "NewBlock69", Fn: s.TestNewBlock69},
 	}
 }
 
@@ -107,6 +114,23 @@ func (s *Suite) TestStatus(t *utesting.T) {
 	}
 }
 
+func (s *Suite) TestStatus69(t *utesting.T) {
+	t.Log(`This test performs an eth/69 protocol handshake, checking the block range
+announced in the node's Status message.`)
+
+	conn, err := s.dial69()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+	if conn.negotiatedProtoVersion != eth.ETH69 {
+		t.Fatalf("wrong protocol version negotiated: have %d, want %d", conn.negotiatedProtoVersion, eth.ETH69)
+	}
+}
+
 // headersMatch returns whether the received headers match the given request
 func headersMatch(expected []*types.Header, headers []*types.Header) bool {
 	return reflect.DeepEqual(expected, headers)
@@ -465,7 +489,7 @@ func (s *Suite) TestMaliciousStatus(t *utesting.T) {
 		t.Fatalf("handshake failed: %v", err)
 	}
 	// Create status with large total difficulty.
-	status := &eth.StatusPacket{
+	status := &eth.StatusPacket68{
 		ProtocolVersion: uint32(conn.negotiatedProtoVersion),
 		NetworkID:       s.chain.config.ChainID.Uint64(),
 		TD:              new(big.Int).SetBytes(randBuf(2048)),
@@ -849,7 +873,7 @@ func (s *Suite) TestBlobViolations(t *utesting.T) {
 		if code, _, err := conn.Read(); err != nil {
 			t.Fatalf("expected disconnect on blob violation, got err: %v", err)
 		} else if code != discMsg {
-			if code == protoOffset(ethProto)+eth.NewPooledTransactionHashesMsg {
+			if code == conn.protoOffset(ethProto)+eth.NewPooledTransactionHashesMsg {
 				// sometimes we'll get a blob transaction hashes announcement before the disconnect
 				// because blob transactions are scheduled to be fetched right away.
 				if code, _, err = conn.Read(); err != nil {
@@ -863,3 +887,161 @@ func (s *Suite) TestBlobViolations(t *utesting.T) {
 		conn.Close()
 	}
 }
+
+func (s *Suite) TestGetReceipts69(t *utesting.T) {
+	t.Log(`This test requests block receipts over eth/69, where receipts are sent
+without their bloom filter, and checks them against the receipt roots of the blocks.`)
+
+	conn, err := s.dial69()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+	// Request the receipts of the most recent blocks containing transactions.
+	var blocks []*types.Block
+	for i := s.chain.Len() - 1; i > 0 && len(blocks) < 16; i-- {
+		if block := s.chain.GetBlock(i); len(block.Transactions()) > 0 {
+			blocks = append(blocks, block)
+		}
+	}
+	if len(blocks) == 0 {
+		t.Fatalf("test chain has no blocks with transactions")
+	}
+	req := &eth.GetReceiptsPacket{RequestId: 66}
+	for _, block := range blocks {
+		req.GetReceiptsRequest = append(req.GetReceiptsRequest, block.Hash())
+	}
+	if err := conn.Write(ethProto, eth.GetReceiptsMsg, req); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	resp := new(eth.ReceiptsPacket69)
+	if err := conn.ReadMsg(ethProto, eth.ReceiptsMsg, resp); err != nil {
+		t.Fatalf("error reading msg: %v", err)
+	}
+	if got, want := resp.RequestId, req.RequestId; got != want {
+		t.Fatalf("unexpected request id in response: got %d, want %d", got, want)
+	}
+	receipts, err := resp.Unpack()
+	if err != nil {
+		t.Fatalf("invalid receipts in response: %v", err)
+	}
+	if len(receipts) != len(blocks) {
+		t.Fatalf("wrong number of receipt lists: have %d, want %d", len(receipts), len(blocks))
+	}
+	for i, block := range blocks {
+		if have, want := types.DeriveSha(types.Receipts(receipts[i]), trie.NewStackTrie(nil)), block.ReceiptHash(); have != want {
+			t.Fatalf("receipts of block %d don't match receipt root: have %v, want %v", block.NumberU64(), have, want)
+		}
+	}
+}
+
+func (s *Suite) TestBlockRangeUpdate(t *utesting.T) {
+	t.Log(`This test sends a valid eth/69 BlockRangeUpdate message and checks that
+the node keeps serving requests.`)
+
+	conn, err := s.dial69()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+	head := s.chain.Head()
+	update := &eth.BlockRangeUpdatePacket{
+		EarliestBlock:   1,
+		LatestBlock:     head.NumberU64(),
+		LatestBlockHash: head.Hash(),
+	}
+	if err := conn.Write(ethProto, eth.BlockRangeUpdateMsg, update); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	// The connection must stay usable after the update.
+	req := &eth.GetBlockHeadersPacket{
+		RequestId: 44,
+		GetBlockHeadersRequest: &eth.GetBlockHeadersRequest{
+			Origin: eth.HashOrNumber{Hash: head.Hash()},
+			Amount: 1,
+		},
+	}
+	if err := conn.Write(ethProto, eth.GetBlockHeadersMsg, req); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	headers := new(eth.BlockHeadersPacket)
+	if err := conn.ReadMsg(ethProto, eth.BlockHeadersMsg, headers); err != nil {
+		t.Fatalf("error reading msg: %v", err)
+	}
+	if got, want := headers.RequestId, req.RequestId; got != want {
+		t.Fatalf("unexpected request id in response: got %d, want %d", got, want)
+	}
+	if len(headers.BlockHeadersRequest) != 1 || headers.BlockHeadersRequest[0].Hash() != head.Hash() {
+		t.Fatalf("wrong headers in response: %v", headers.BlockHeadersRequest)
+	}
+}
+
+func (s *Suite) TestInvalidBlockRangeUpdate(t *utesting.T) {
+	t.Log(`This test sends an eth/69 BlockRangeUpdate message with the earliest block
+after the latest block and expects a disconnect.`)
+
+	conn, err := s.dial69()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+	head := s.chain.Head()
+	update := &eth.BlockRangeUpdatePacket{
+		EarliestBlock:   head.NumberU64() + 1,
+		LatestBlock:     head.NumberU64(),
+		LatestBlockHash: head.Hash(),
+	}
+	if err := conn.Write(ethProto, eth.BlockRangeUpdateMsg, update); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	expectDisconnect(t, conn)
+}
+
+func (s *Suite) TestNewBlock69(t *utesting.T) {
+	t.Log(`This test sends a NewBlock message, which is not part of eth/69, and
+expects a disconnect.`)
+
+	conn, err := s.dial69()
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.peer(s.chain, nil); err != nil {
+		t.Fatalf("peering failed: %v", err)
+	}
+	block := &eth.NewBlockPacket{Block: s.chain.Head(), TD: s.chain.TD()}
+	if err := conn.Write(ethProto, eth.NewBlockMsg, block); err != nil {
+		t.Fatalf("could not write to connection: %v", err)
+	}
+	expectDisconnect(t, conn)
+}
+
+// expectDisconnect reads from the connection until the node disconnects,
+// failing the test if anything else happens.
+func expectDisconnect(t *utesting.T, conn *Conn) {
+	for {
+		code, _, err := conn.Read()
+		if err != nil {
+			t.Fatalf("expected disconnect, got err: %v", err)
+		}
+		switch {
+		case code == discMsg:
+			return
+		case code == pingMsg:
+			conn.Write(baseProto, pongMsg, []byte{})
+		case conn.getProto(code) == ethProto && code-baseProtoLen == eth.BlockRangeUpdateMsg:
+			// Range updates can be announced by the node at any time.
+		default:
+			t.Fatalf("expected disconnect, got msg code: %d", code)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	nodesetStatsCommand = &cli.Command{
+		Name:      "stats",
+		Usage:     "Summarises client diversity, fork readiness and networks of a crawled node set",
+		Action:    nodesetStats,
+		ArgsUsage: "<nodes.json>",
+		Flags:     []cli.Flag{statsNetworkFlag},
+	}
+	statsNetworkFlag = &cli.StringFlag{
+		Name:  "network",
+		Usage: "Network used to check fork readiness (mainnet/sepolia/holesky)",
+		Value: "mainnet",
+	}
+)
+
+// countMap counts occurrences of string values.
+type countMap map[string]int
+
+// print shows the counts with their share of total, most common first.
+func (c countMap) print(title string, total int) {
+	if len(c) == 0 {
+		return
+	}
+	keys := slices.SortedFunc(maps.Keys(c), func(a, b string) int {
+		return cmp.Or(cmp.Compare(c[b], c[a]), strings.Compare(a, b))
+	})
+	width := 0
+	for _, key := range keys {
+		width = max(width, len(key))
+	}
+	fmt.Printf("\n%s:\n", title)
+	for _, key := range keys {
+		fmt.Printf("%s%s: %d (%.1f%%)\n", strings.Repeat(" ", width-len(key)+1), key, c[key], 100*float64(c[key])/float64(total))
+	}
+}
+
+func nodesetStats(ctx *cli.Context) error {
+	if ctx.NArg() < 1 {
+		return errors.New("need nodes file as argument")
+	}
+	config, genesis, err := ethNetwork(ctx.String(statsNetworkFlag.Name))
+	if err != nil {
+		return err
+	}
+	var (
+		ns      = loadNodesJSON(ctx.Args().First())
+		current = forkid.NewID(config, genesis, math.MaxUint64, uint64(time.Now().Unix()))
+		filter  = forkid.NewStaticFilter(config, genesis)
+		stats   = newNodesetSummary(ns, current, filter)
+	)
+	fmt.Printf("Set contains %d nodes, %d with handshake results, %d reachable, %d with eth status.\n", stats.total, stats.checked, stats.reachable, stats.withStatus)
+	stats.clients.print("Clients", stats.reachable)
+	stats.versions.print("Client versions", stats.reachable)
+	stats.protocols.print("Negotiated eth versions", stats.withStatus)
+	stats.networks.print("Network IDs", stats.withStatus)
+	stats.families.print("Address families", stats.total)
+	stats.forks.print("Fork IDs", stats.withFork)
+	stats.readiness.print(fmt.Sprintf("Fork readiness (%s, current fork ID %#x/%d)", ctx.String(statsNetworkFlag.Name), current.Hash, current.Next), stats.withFork)
+	return nil
+}
+
+// nodesetSummary holds the statistics of a node set.
+type nodesetSummary struct {
+	total      int // nodes in the set
+	checked    int // nodes with handshake results
+	reachable  int // nodes which completed the RLPx handshake
+	withStatus int // nodes which sent an eth status message
+	withFork   int // nodes with a known fork ID
+
+	clients   countMap
+	versions  countMap
+	protocols countMap
+	networks  countMap
+	forks     countMap
+	readiness countMap
+	families  countMap
+}
+
+// newNodesetSummary aggregates the statistics of a node set. The fork readiness
+// of nodes is checked against the current fork ID of the network.
+func newNodesetSummary(ns nodeSet, current forkid.ID, filter forkid.Filter) *nodesetSummary {
+	s := &nodesetSummary{
+		total:     len(ns),
+		clients:   make(countMap),
+		versions:  make(countMap),
+		protocols: make(countMap),
+		networks:  make(countMap),
+		forks:     make(countMap),
+		readiness: make(countMap),
+		families:  make(countMap),
+	}
+	for _, n := range ns {
+		if ip := n.N.IPAddr(); ip.Is4() {
+			s.families["IPv4"]++
+		} else if ip.Is6() {
+			s.families["IPv6"]++
+		}
+
+		// Prefer the fork ID from the status message, since the node record
+		// may be outdated.
+		id, ok := n.Handshake.forkID()
+		if !ok {
+			id, ok = enrForkID(n.N)
+		}
+		if ok {
+			s.withFork++
+			s.forks[fmt.Sprintf("%#x/%d", id.Hash, id.Next)]++
+			s.readiness[forkReadiness(current, filter, id)]++
+		}
+
+		h := n.Handshake
+		if h == nil {
+			continue
+		}
+		s.checked++
+		if !h.Reachable {
+			continue
+		}
+		s.reachable++
+		if h.Client != "" {
+			name, version := parseClientName(h.Client)
+			s.clients[name]++
+			s.versions[name+"/"+version]++
+		}
+		if h.EthVersion != 0 {
+			s.withStatus++
+			s.protocols[fmt.Sprintf("eth/%d", h.EthVersion)]++
+			s.networks[fmt.Sprint(h.NetworkID)]++
+		}
+	}
+	return s
+}
+
+// forkReadiness classifies a remote fork ID against the current fork ID of
+// the network.
+func forkReadiness(current forkid.ID, filter forkid.Filter, id forkid.ID) string {
+	switch {
+	case filter(id) != nil:
+		return "incompatible"
+	case id == current && current.Next == 0:
+		return "current"
+	case id == current:
+		return "ready for next fork"
+	case id.Hash == current.Hash:
+		return "not ready for next fork"
+	default:
+		return "on other fork"
+	}
+}
+
+// parseClientName splits a client name like "Geth/v1.15.0-stable/linux-amd64/go1.23"
+// into the lower-case client name and the version without build metadata.
+func parseClientName(s string) (name, version string) {
+	parts := strings.Split(s, "/")
+	name = strings.ToLower(parts[0])
+	version = "unknown"
+	for _, p := range parts[1:] {
+		// Some clients put an identity before the version.
+		if strings.HasPrefix(p, "v") && len(p) > 1 && p[1] >= '0' && p[1] <= '9' {
+			version, _, _ = strings.Cut(p, "-")
+			version, _, _ = strings.Cut(version, "+")
+			break
+		}
+	}
+	return name, version
+}
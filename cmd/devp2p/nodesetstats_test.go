@@ -0,0 +1,129 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"maps"
+	"math"
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/forkid"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// newStatsTestNode creates a node record, with an eth entry if fork is non-nil.
+func newStatsTestNode(t *testing.T, ip net.IP, fork *forkid.ID) *enode.Node {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var r enr.Record
+	r.Set(enr.IP(ip))
+	if fork != nil {
+		r.Set(enr.WithEntry("eth", struct {
+			ForkID forkid.ID
+			Tail   []rlp.RawValue `rlp:"tail"`
+		}{ForkID: *fork}))
+	}
+	if err := enode.SignV4(&r, key); err != nil {
+		t.Fatal(err)
+	}
+	n, err := enode.New(enode.ValidSchemes, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestNodesetSummary(t *testing.T) {
+	config, genesis, err := ethNetwork("mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		current = forkid.NewID(config, genesis, math.MaxUint64, math.MaxUint64)
+		filter  = forkid.NewStaticFilter(config, genesis)
+		stale   = forkid.ID{Hash: [4]byte{0xde, 0xad, 0xbe, 0xef}}
+		ns      = make(nodeSet)
+	)
+	add := func(n *enode.Node, h *nodeHandshake) {
+		ns[n.ID()] = nodeJSON{N: n, Handshake: h}
+	}
+	// Reachable with status, the fork ID of the status overrides the record.
+	add(newStatsTestNode(t, net.IP{10, 0, 0, 1}, &stale), &nodeHandshake{
+		Reachable:  true,
+		Client:     "Geth/v1.15.0-stable-abcdef/linux-amd64/go1.23",
+		EthVersion: 69,
+		NetworkID:  1,
+		ForkHash:   current.Hash[:],
+		ForkNext:   current.Next,
+	})
+	add(newStatsTestNode(t, net.ParseIP("2001:db8::1"), nil), &nodeHandshake{
+		Reachable:  true,
+		Client:     "Nethermind/v1.30.1+1234/linux-x64/dotnet9",
+		EthVersion: 68,
+		NetworkID:  1,
+		ForkHash:   stale.Hash[:],
+	})
+	// Reachable without status.
+	add(newStatsTestNode(t, net.IP{10, 0, 0, 3}, nil), &nodeHandshake{Reachable: true, Client: "geth/v1.14.0"})
+	// Unreachable, the fork ID of the record is used.
+	add(newStatsTestNode(t, net.IP{10, 0, 0, 4}, &current), &nodeHandshake{Error: "timeout"})
+	// Not checked.
+	add(newStatsTestNode(t, net.IP{10, 0, 0, 5}, nil), nil)
+
+	s := newNodesetSummary(ns, current, filter)
+	if s.total != 5 || s.checked != 4 || s.reachable != 3 || s.withStatus != 2 || s.withFork != 3 {
+		t.Errorf("wrong counts: total %d, checked %d, reachable %d, status %d, fork %d", s.total, s.checked, s.reachable, s.withStatus, s.withFork)
+	}
+	checkCounts := func(name string, have, want countMap) {
+		t.Helper()
+		if !maps.Equal(have, want) {
+			t.Errorf("wrong %s: %v, want %v", name, have, want)
+		}
+	}
+	checkCounts("clients", s.clients, countMap{"geth": 2, "nethermind": 1})
+	checkCounts("versions", s.versions, countMap{"geth/v1.15.0": 1, "geth/v1.14.0": 1, "nethermind/v1.30.1": 1})
+	checkCounts("protocols", s.protocols, countMap{"eth/69": 1, "eth/68": 1})
+	checkCounts("networks", s.networks, countMap{"1": 2})
+	checkCounts("families", s.families, countMap{"IPv4": 4, "IPv6": 1})
+	checkCounts("readiness", s.readiness, countMap{"current": 2, "incompatible": 1})
+}
+
+func TestParseClientName(t *testing.T) {
+	tests := []struct {
+		input         string
+		name, version string
+	}{
+		{"Geth/v1.15.0-stable-abcdef/linux-amd64/go1.23", "geth", "v1.15.0"},
+		{"Geth/myidentity/v1.14.12-stable/linux-amd64/go1.23", "geth", "v1.14.12"},
+		{"erigon/v2.60.1+abc/linux/go1.22", "erigon", "v2.60.1"},
+		{"besu", "besu", "unknown"},
+	}
+	for _, test := range tests {
+		name, version := parseClientName(test.input)
+		if name != test.name || version != test.version {
+			t.Errorf("parseClientName(%q) = %q, %q, want %q, %q", test.input, name, version, test.name, test.version)
+		}
+	}
+}
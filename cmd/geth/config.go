@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"math/big"
 	"os"
 	"reflect"
 	"runtime"
@@ -104,11 +105,19 @@ type ethstatsConfig struct {
 	URL string `toml:",omitempty"`
 }
 
+// logConfig holds the logging settings of the config file. Flags take precedence
+// over them.
+type logConfig struct {
+	Verbosity *int   `toml:",omitempty"`
+	Vmodule   string `toml:",omitempty"`
+}
+
 type gethConfig struct {
 	Eth      ethconfig.Config
 	Node     node.Config
 	Ethstats ethstatsConfig
 	Metrics  metrics.Config
+	Log      logConfig
 }
 
 func loadConfig(file string, cfg *gethConfig) error {
@@ -137,15 +146,32 @@ func defaultNodeConfig() node.Config {
 	return cfg
 }
 
-// loadBaseConfig loads the gethConfig based on the given command line
-// parameters and config file.
-func loadBaseConfig(ctx *cli.Context) gethConfig {
-	// Load defaults.
+// defaultGethConfig returns the default configuration, which the config file is
+// applied on top of. Shared pointers of the defaults are copied, so decoding the
+// file doesn't modify them.
+func defaultGethConfig() gethConfig {
 	cfg := gethConfig{
 		Eth:     ethconfig.Defaults,
 		Node:    defaultNodeConfig(),
 		Metrics: metrics.DefaultConfig,
 	}
+	if cfg.Eth.Miner.GasPrice != nil {
+		cfg.Eth.Miner.GasPrice = new(big.Int).Set(cfg.Eth.Miner.GasPrice)
+	}
+	if cfg.Eth.GPO.MaxPrice != nil {
+		cfg.Eth.GPO.MaxPrice = new(big.Int).Set(cfg.Eth.GPO.MaxPrice)
+	}
+	if cfg.Eth.GPO.IgnorePrice != nil {
+		cfg.Eth.GPO.IgnorePrice = new(big.Int).Set(cfg.Eth.GPO.IgnorePrice)
+	}
+	return cfg
+}
+
+// loadBaseConfig loads the gethConfig based on the given command line
+// parameters and config file.
+func loadBaseConfig(ctx *cli.Context) gethConfig {
+	// Load defaults.
+	cfg := defaultGethConfig()
 
 	// Load config file.
 	if file := ctx.String(configFileFlag.Name); file != "" {
@@ -156,6 +182,9 @@ func loadBaseConfig(ctx *cli.Context) gethConfig {
 
 	// Apply flags.
 	utils.SetNodeConfig(ctx, &cfg.Node)
+	if err := applyLogConfig(ctx, cfg.Log); err != nil {
+		utils.Fatalf("%v", err)
+	}
 	return cfg
 }
 
@@ -253,6 +282,14 @@ func makeFullNode(ctx *cli.Context) *node.Node {
 			utils.Fatalf("failed to register catalyst service: %v", err)
 		}
 	}
+	// Allow reloading the config file at runtime.
+	if file := ctx.String(configFileFlag.Name); file != "" && eth != nil {
+		reloader, err := newConfigReloader(ctx, file, stack, eth)
+		if err != nil {
+			utils.Fatalf("Failed to set up config reloading: %v", err)
+		}
+		stack.SetConfigReloader(reloader.reload)
+	}
 	return stack
 }
 
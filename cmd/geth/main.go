@@ -72,6 +72,7 @@ var (
 		utils.TxPoolNoLocalsFlag,
 		utils.TxPoolJournalFlag,
 		utils.TxPoolRejournalFlag,
+		utils.TxPoolSnapshotFlag,
 		utils.TxPoolPriceLimitFlag,
 		utils.TxPoolPriceBumpFlag,
 		utils.TxPoolAccountSlotsFlag,
@@ -130,6 +131,8 @@ var (
 		utils.DiscoveryV5Flag,
 		utils.LegacyDiscoveryV5Flag, // deprecated
 		utils.NetrestrictFlag,
+		utils.PeerPolicyFlag,
+		utils.PeerServeLimitFlag,
 		utils.NodeKeyFileFlag,
 		utils.NodeKeyHexFlag,
 		utils.DNSDiscoveryFlag,
@@ -146,6 +149,7 @@ var (
 		utils.GpoPercentileFlag,
 		utils.GpoMaxGasPriceFlag,
 		utils.GpoIgnoreGasPriceFlag,
+		utils.GpoPredictiveFlag,
 		configFileFlag,
 		utils.LogDebugFlag,
 		utils.LogBacktraceAtFlag,
@@ -189,6 +193,22 @@ var (
 		utils.AllowUnprotectedTxs,
 		utils.BatchRequestLimit,
 		utils.BatchResponseMaxSize,
+		utils.BatchConcurrency,
+		utils.RPCResponseMaxSize,
+		utils.RPCRateLimitFlag,
+		utils.RPCRateLimitBurstFlag,
+		utils.RPCRateLimitQuotaFlag,
+		utils.RPCRateLimitHeaderFlag,
+		utils.RPCRateLimitKeysFlag,
+		utils.RPCRateLimitClaimFlag,
+		utils.RPCRecordFileFlag,
+		utils.RPCRecordMaxSizeFlag,
+		utils.RPCRecordMaxFilesFlag,
+		utils.RPCAuthSecretFlag,
+		utils.RPCAuthPublicKeyFlag,
+		utils.RPCAuthPublicFlag,
+		utils.RPCTracingEndpointFlag,
+		utils.RPCTracingSampleRatioFlag,
 	}
 
 	metricsFlags = []cli.Flag{
@@ -246,6 +266,8 @@ func init() {
 		snapshotCommand,
 		// See verkle.go
 		verkleCommand,
+		// See txpoolcmd.go
+		txpoolCommand,
 	}
 	if logTestCommand != nil {
 		app.Commands = append(app.Commands, logTestCommand)
@@ -354,6 +376,11 @@ func startNode(ctx *cli.Context, stack *node.Node, isConsole bool) {
 	// Start up the node itself
 	utils.StartNode(ctx, stack, isConsole)
 
+	// Reload the config file on SIGHUP, if there is one
+	if ctx.String(configFileFlag.Name) != "" {
+		go watchConfigReload(stack)
+	}
+
 	if ctx.IsSet(utils.UnlockedAccountFlag.Name) {
 		log.Warn(`The "unlock" flag has been deprecated and has no effect`)
 	}
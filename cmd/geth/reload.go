@@ -0,0 +1,282 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/internal/debug"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/urfave/cli/v2"
+)
+
+// reloadGroups maps the config fields which can be changed at runtime to the
+// group of settings applied together.
+var reloadGroups = map[string]string{
+	"Log.Verbosity":           "log",
+	"Log.Vmodule":             "log",
+	"Node.P2P.StaticNodes":    "static",
+	"Node.P2P.TrustedNodes":   "trusted",
+	"Node.HTTPCors":           "http",
+	"Node.HTTPVirtualHosts":   "http",
+	"Eth.Miner.GasPrice":      "gasprice",
+	"Eth.TxPool.PriceLimit":   "pricelimit",
+	"Eth.TxPool.PriceBump":    "txpool",
+	"Eth.TxPool.AccountSlots": "txpool",
+	"Eth.TxPool.GlobalSlots":  "txpool",
+	"Eth.TxPool.AccountQueue": "txpool",
+	"Eth.TxPool.GlobalQueue":  "txpool",
+	"Eth.TxPool.Lifetime":     "txpool",
+}
+
+// configReloader applies changes of the config file to a running node. Changes
+// are detected by comparing the file against its contents at the previous load,
+// so settings given as command line flags are not reported as changed. Only the
+// fields changed in the file are applied on top of the running configuration, so
+// settings given as flags are kept unless the file changes the same field.
+type configReloader struct {
+	ctx   *cli.Context
+	file  string
+	stack *node.Node
+	eth   *eth.Ethereum
+	last  gethConfig // config file applied on top of the defaults
+}
+
+func newConfigReloader(ctx *cli.Context, file string, stack *node.Node, eth *eth.Ethereum) (*configReloader, error) {
+	last := defaultGethConfig()
+	if err := loadConfig(file, &last); err != nil {
+		return nil, err
+	}
+	return &configReloader{ctx: ctx, file: file, stack: stack, eth: eth, last: last}, nil
+}
+
+// reload re-reads the config file and applies the changed fields which are safe
+// to change at runtime. Changes requiring a restart keep being reported until the
+// node is restarted or they are reverted.
+func (r *configReloader) reload() (*node.ConfigReloadReport, error) {
+	next := defaultGethConfig()
+	if err := loadConfig(r.file, &next); err != nil {
+		return nil, err
+	}
+	var (
+		report  = &node.ConfigReloadReport{Applied: []string{}, RestartRequired: []string{}}
+		groups  = make(map[string][]string)
+		changes = configChanges("", reflect.ValueOf(r.last), reflect.ValueOf(next))
+	)
+	for _, field := range changes {
+		if group, ok := reloadGroups[field]; ok {
+			groups[group] = append(groups[group], field)
+			report.Applied = append(report.Applied, field)
+		} else {
+			report.RestartRequired = append(report.RestartRequired, field)
+		}
+	}
+	for _, group := range []string{"log", "static", "trusted", "http", "gasprice", "pricelimit", "txpool"} {
+		if len(groups[group]) == 0 {
+			continue
+		}
+		if err := r.apply(group, groups[group], &next); err != nil {
+			return nil, fmt.Errorf("failed to apply %s settings: %v", group, err)
+		}
+	}
+	// Keep the running values of settings which weren't applied, so they are
+	// reported again by the next reload.
+	for _, field := range report.RestartRequired {
+		configField(reflect.ValueOf(&next).Elem(), field).Set(configField(reflect.ValueOf(&r.last).Elem(), field))
+	}
+	r.last = next
+
+	log.Info("Configuration reloaded", "file", r.file, "applied", len(report.Applied), "restart", len(report.RestartRequired))
+	if len(report.RestartRequired) > 0 {
+		log.Warn("Configuration changes require a restart", "fields", strings.Join(report.RestartRequired, ","))
+	}
+	return report, nil
+}
+
+// apply applies the changed fields of a settings group to the running node.
+func (r *configReloader) apply(group string, fields []string, next *gethConfig) error {
+	switch group {
+	case "log":
+		return applyLogConfig(r.ctx, next.Log)
+
+	case "static":
+		server := r.stack.Server()
+		added, removed := diffNodes(r.last.Node.P2P.StaticNodes, next.Node.P2P.StaticNodes)
+		for _, n := range removed {
+			server.RemovePeer(n)
+		}
+		for _, n := range added {
+			server.AddPeer(n)
+		}
+		config := r.stack.Config()
+		config.P2P.StaticNodes = updateNodes(config.P2P.StaticNodes, added, removed)
+
+	case "trusted":
+		server := r.stack.Server()
+		added, removed := diffNodes(r.last.Node.P2P.TrustedNodes, next.Node.P2P.TrustedNodes)
+		for _, n := range removed {
+			server.RemoveTrustedPeer(n)
+		}
+		for _, n := range added {
+			server.AddTrustedPeer(n)
+		}
+		config := r.stack.Config()
+		config.P2P.TrustedNodes = updateNodes(config.P2P.TrustedNodes, added, removed)
+
+	case "http":
+		config := r.stack.Config()
+		cors, vhosts := config.HTTPCors, config.HTTPVirtualHosts
+		for _, field := range fields {
+			switch field {
+			case "Node.HTTPCors":
+				cors = next.Node.HTTPCors
+			case "Node.HTTPVirtualHosts":
+				vhosts = next.Node.HTTPVirtualHosts
+			}
+		}
+		return r.stack.SetHTTPFilters(cors, vhosts)
+
+	case "gasprice":
+		if next.Eth.Miner.GasPrice == nil || next.Eth.Miner.GasPrice.Sign() <= 0 {
+			return fmt.Errorf("invalid gas price %v", next.Eth.Miner.GasPrice)
+		}
+		r.eth.SetGasPrice(new(big.Int).Set(next.Eth.Miner.GasPrice))
+
+	case "pricelimit":
+		r.eth.TxPool().SetGasTip(new(big.Int).SetUint64(next.Eth.TxPool.PriceLimit))
+
+	case "txpool":
+		limits := r.eth.TxPoolLimits()
+		for _, field := range fields {
+			dst := configField(reflect.ValueOf(&limits).Elem(), strings.TrimPrefix(field, "Eth.TxPool."))
+			dst.Set(configField(reflect.ValueOf(next).Elem(), field))
+		}
+		r.eth.SetTxPoolLimits(limits)
+	}
+	return nil
+}
+
+// applyLogConfig sets the log verbosity from the config file, unless it was set
+// by a flag.
+func applyLogConfig(ctx *cli.Context, cfg logConfig) error {
+	if !ctx.IsSet("verbosity") {
+		verbosity := 3
+		if cfg.Verbosity != nil {
+			verbosity = *cfg.Verbosity
+		}
+		debug.Handler.Verbosity(verbosity)
+	}
+	if !ctx.IsSet("log.vmodule") && !ctx.IsSet("vmodule") {
+		if err := debug.Handler.Vmodule(cfg.Vmodule); err != nil {
+			return fmt.Errorf("invalid log vmodule %q: %v", cfg.Vmodule, err)
+		}
+	}
+	return nil
+}
+
+// watchConfigReload reloads the configuration whenever the process receives
+// SIGHUP.
+func watchConfigReload(stack *node.Node) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGHUP)
+	for range sigc {
+		log.Info("Got SIGHUP, reloading configuration")
+		if _, err := stack.ReloadConfig(); err != nil {
+			log.Warn("Failed to reload configuration", "err", err)
+		}
+	}
+}
+
+// configChanges returns the paths of all fields which differ between the two
+// configs. Structs are compared field by field, everything else as a whole.
+func configChanges(prefix string, old, next reflect.Value) []string {
+	if old.Kind() != reflect.Struct {
+		if reflect.DeepEqual(old.Interface(), next.Interface()) {
+			return nil
+		}
+		return []string{prefix}
+	}
+	var changes []string
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		if !field.IsExported() || field.Tag.Get("toml") == "-" {
+			continue
+		}
+		path := field.Name
+		if prefix != "" {
+			path = prefix + "." + field.Name
+		}
+		changes = append(changes, configChanges(path, old.Field(i), next.Field(i))...)
+	}
+	return changes
+}
+
+// configField resolves the field at the given path of a config.
+func configField(v reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		v = v.FieldByName(name)
+	}
+	return v
+}
+
+// updateNodes applies the nodes added to and removed from the config file to the
+// running node list, keeping the nodes which didn't come from the file.
+func updateNodes(nodes, added, removed []*enode.Node) []*enode.Node {
+	result := make([]*enode.Node, 0, len(nodes)+len(added))
+	for _, n := range nodes {
+		if !slices.ContainsFunc(removed, func(r *enode.Node) bool { return r.ID() == n.ID() }) {
+			result = append(result, n)
+		}
+	}
+	for _, n := range added {
+		if !slices.ContainsFunc(result, func(r *enode.Node) bool { return r.ID() == n.ID() }) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// diffNodes returns the nodes added to and removed from a node list.
+func diffNodes(old, next []*enode.Node) (added, removed []*enode.Node) {
+	oldIDs := make(map[enode.ID]bool)
+	for _, n := range old {
+		oldIDs[n.ID()] = true
+	}
+	nextIDs := make(map[enode.ID]bool)
+	for _, n := range next {
+		nextIDs[n.ID()] = true
+		if !oldIDs[n.ID()] {
+			added = append(added, n)
+		}
+	}
+	for _, n := range old {
+		if !nextIDs[n.ID()] {
+			removed = append(removed, n)
+		}
+	}
+	return added, removed
+}
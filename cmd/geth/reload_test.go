@@ -0,0 +1,159 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestConfigChanges(t *testing.T) {
+	old := defaultGethConfig()
+	next := defaultGethConfig()
+	if changes := configChanges("", reflect.ValueOf(old), reflect.ValueOf(next)); len(changes) != 0 {
+		t.Fatalf("changes reported for identical configs: %v", changes)
+	}
+	next.Node.HTTPCors = []string{"example.com"}
+	next.Eth.TxPool.GlobalSlots++
+	next.Eth.NetworkId++
+
+	changes := configChanges("", reflect.ValueOf(old), reflect.ValueOf(next))
+	slices.Sort(changes)
+	want := []string{"Eth.NetworkId", "Eth.TxPool.GlobalSlots", "Node.HTTPCors"}
+	if !slices.Equal(changes, want) {
+		t.Fatalf("wrong changes %v, want %v", changes, want)
+	}
+}
+
+func TestDiffNodes(t *testing.T) {
+	nodes := make([]*enode.Node, 4)
+	for i := range nodes {
+		key, _ := crypto.GenerateKey()
+		nodes[i] = enode.NewV4(&key.PublicKey, nil, 30303, 30303)
+	}
+	added, removed := diffNodes(nodes[:3], nodes[1:])
+	if !slices.Equal(added, nodes[3:]) {
+		t.Errorf("wrong added nodes %v", added)
+	}
+	if !slices.Equal(removed, nodes[:1]) {
+		t.Errorf("wrong removed nodes %v", removed)
+	}
+	// Nodes not listed in the file are kept in the running list
+	running := updateNodes([]*enode.Node{nodes[0], nodes[2]}, added, removed)
+	if !slices.Equal(running, []*enode.Node{nodes[2], nodes[3]}) {
+		t.Errorf("wrong running nodes %v", running)
+	}
+}
+
+// writeConfig writes a config file with the given modifications applied to the
+// default config.
+func writeConfig(t *testing.T, file string, modify func(*gethConfig)) {
+	cfg := defaultGethConfig()
+	modify(&cfg)
+	out, err := tomlSettings.Marshal(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, out, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.toml")
+	writeConfig(t, file, func(*gethConfig) {})
+
+	// Start a node with settings given as flags, which are not in the file
+	nodeConf := defaultNodeConfig()
+	nodeConf.DataDir = t.TempDir()
+	nodeConf.P2P.MaxPeers = 0
+	nodeConf.P2P.NoDiscovery = true
+	nodeConf.P2P.ListenAddr = ""
+	nodeConf.HTTPHost = "127.0.0.1"
+	nodeConf.HTTPCors = []string{"flag.example.com"}
+	nodeConf.HTTPVirtualHosts = []string{"flag.vhost"}
+	stack, err := node.New(&nodeConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stack.Close()
+
+	ethConf := defaultGethConfig().Eth
+	ethConf.Genesis = core.DeveloperGenesisBlock(11_500_000, nil)
+	ethConf.TxPool.AccountSlots = 32
+	backend, err := eth.New(stack, &ethConf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatal(err)
+	}
+	reloader, err := newConfigReloader(nil, file, stack, backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Change runtime and restart settings in the file
+	writeConfig(t, file, func(cfg *gethConfig) {
+		cfg.Node.HTTPVirtualHosts = []string{"file.vhost"}
+		cfg.Eth.TxPool.GlobalSlots = 1234
+		cfg.Eth.NetworkId = 1337
+	})
+	report, err := reloader.reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(report.Applied)
+	if want := []string{"Eth.TxPool.GlobalSlots", "Node.HTTPVirtualHosts"}; !slices.Equal(report.Applied, want) {
+		t.Errorf("wrong applied changes %v, want %v", report.Applied, want)
+	}
+	if want := []string{"Eth.NetworkId"}; !slices.Equal(report.RestartRequired, want) {
+		t.Errorf("wrong restart changes %v, want %v", report.RestartRequired, want)
+	}
+	// Only the changed fields are applied, the flags are kept otherwise
+	if cors := stack.Config().HTTPCors; !slices.Equal(cors, []string{"flag.example.com"}) {
+		t.Errorf("CORS origins set by flag overwritten: %v", cors)
+	}
+	if vhosts := stack.Config().HTTPVirtualHosts; !slices.Equal(vhosts, []string{"file.vhost"}) {
+		t.Errorf("virtual hosts not applied: %v", vhosts)
+	}
+	limits := backend.TxPoolLimits()
+	if limits.GlobalSlots != 1234 {
+		t.Errorf("global slots not applied: %d", limits.GlobalSlots)
+	}
+	if limits.AccountSlots != 32 {
+		t.Errorf("account slots set by flag overwritten: %d", limits.AccountSlots)
+	}
+
+	// Changes requiring a restart are reported until reverted
+	report, err = reloader.reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Applied) != 0 || !slices.Equal(report.RestartRequired, []string{"Eth.NetworkId"}) {
+		t.Errorf("wrong report after unchanged reload: %+v", report)
+	}
+}
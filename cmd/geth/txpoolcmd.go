@@ -0,0 +1,140 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	txpoolCommand = &cli.Command{
+		Name:  "txpool",
+		Usage: "A set of commands to move transaction pool snapshots between nodes",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "export",
+				Usage:     "Export the transaction pool snapshot into a file",
+				ArgsUsage: "<filename>",
+				Action:    exportTxPool,
+				Flags:     slices.Concat([]cli.Flag{utils.TxPoolSnapshotFlag, configFileFlag}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth txpool export <filename>
+Copies the transaction pool snapshot persisted by the last shutdown of the node
+(see --txpool.snapshot) into the given file. The node must not be running.
+`,
+			},
+			{
+				Name:      "import",
+				Usage:     "Import transactions into the transaction pool snapshot",
+				ArgsUsage: "<filename> (<filename 2> ... <filename N>)",
+				Action:    importTxPool,
+				Flags:     slices.Concat([]cli.Flag{utils.TxPoolSnapshotFlag, configFileFlag}, utils.NetworkFlags, utils.DatabaseFlags),
+				Description: `
+geth txpool import <filename> (<filename 2> ... <filename N>)
+Merges the transactions of the given export files into the transaction pool
+snapshot (see --txpool.snapshot) of the node. The transactions are revalidated
+and loaded into the pool as remote ones on the next startup. The node must not
+be running.
+`,
+			},
+		},
+	}
+)
+
+// txpoolSnapshotPath resolves the location of the transaction pool snapshot of
+// the configured node.
+func txpoolSnapshotPath(ctx *cli.Context) (string, func()) {
+	stack, cfg := makeConfigNode(ctx)
+	if cfg.Eth.TxPool.Snapshot == "" {
+		stack.Close()
+		utils.Fatalf("Transaction pool snapshot disabled, set --%s", utils.TxPoolSnapshotFlag.Name)
+	}
+	return stack.ResolvePath(cfg.Eth.TxPool.Snapshot), func() { stack.Close() }
+}
+
+func exportTxPool(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		utils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	path, closer := txpoolSnapshotPath(ctx)
+	defer closer()
+
+	entries, err := legacypool.ReadSnapshot(path)
+	if err != nil {
+		return fmt.Errorf("failed to read transaction pool snapshot: %v", err)
+	}
+	if len(entries) == 0 {
+		return errors.New("transaction pool snapshot empty or missing")
+	}
+	if err := legacypool.WriteSnapshot(ctx.Args().First(), entries); err != nil {
+		return fmt.Errorf("failed to write export file: %v", err)
+	}
+	log.Info("Exported transaction pool", "transactions", len(entries), "file", ctx.Args().First())
+	return nil
+}
+
+func importTxPool(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		utils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	path, closer := txpoolSnapshotPath(ctx)
+	defer closer()
+
+	entries, err := legacypool.ReadSnapshot(path)
+	if err != nil {
+		return fmt.Errorf("failed to read transaction pool snapshot: %v", err)
+	}
+	known := make(map[common.Hash]struct{}, len(entries))
+	for _, entry := range entries {
+		known[entry.Tx.Hash()] = struct{}{}
+	}
+	for _, file := range ctx.Args().Slice() {
+		if _, err := os.Stat(file); err != nil {
+			return err
+		}
+		imports, err := legacypool.ReadSnapshot(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", file, err)
+		}
+		var added int
+		for _, entry := range imports {
+			if _, ok := known[entry.Tx.Hash()]; ok {
+				continue
+			}
+			known[entry.Tx.Hash()] = struct{}{}
+
+			// Local status is a property of the originating node, don't carry it over
+			entry.Local = false
+			entries = append(entries, entry)
+			added++
+		}
+		log.Info("Imported transaction pool file", "file", file, "transactions", len(imports), "added", added)
+	}
+	if err := legacypool.WriteSnapshot(path, entries); err != nil {
+		return fmt.Errorf("failed to write transaction pool snapshot: %v", err)
+	}
+	return nil
+}
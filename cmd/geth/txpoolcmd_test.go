@@ -0,0 +1,124 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+// runTxPoolCommand runs a txpool subcommand on the given data directory. The
+// node flags are registered globally like in geth, so their defaults apply.
+func runTxPoolCommand(t *testing.T, datadir string, args ...string) error {
+	t.Helper()
+
+	app := cli.NewApp()
+	app.Flags = nodeFlags
+	app.Commands = []*cli.Command{txpoolCommand}
+	return app.Run(append([]string{"geth", "txpool", args[0], "--datadir", datadir, "--txpool.snapshot", "txpool.rlp"}, args[1:]...))
+}
+
+// makeSnapshotEntries creates snapshot entries of signed transfers with distinct
+// arrival times.
+func makeSnapshotEntries(t *testing.T, count int, local bool) []*legacypool.SnapshotEntry {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := types.LatestSigner(params.MainnetChainConfig)
+
+	entries := make([]*legacypool.SnapshotEntry, count)
+	for i := range entries {
+		tx := types.MustSignNewTx(key, signer, &types.LegacyTx{Nonce: uint64(i), Gas: 21000, GasPrice: big.NewInt(1)})
+		entries[i] = &legacypool.SnapshotEntry{
+			Tx:    tx,
+			Time:  uint64(time.Unix(1700000000+int64(i), 0).UnixNano()),
+			Local: local,
+		}
+	}
+	return entries
+}
+
+func TestTxPoolImportExport(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		datadir  = filepath.Join(dir, "datadir")
+		imported = filepath.Join(dir, "import.rlp")
+		exported = filepath.Join(dir, "export.rlp")
+	)
+	// Exporting without a snapshot fails
+	if err := runTxPoolCommand(t, datadir, "export", exported); err == nil {
+		t.Fatal("export of missing snapshot succeeded")
+	}
+	entries := makeSnapshotEntries(t, 3, true)
+	if err := legacypool.WriteSnapshot(imported, entries); err != nil {
+		t.Fatal(err)
+	}
+	// Importing the same file twice doesn't duplicate the transactions
+	for i := 0; i < 2; i++ {
+		if err := runTxPoolCommand(t, datadir, "import", imported); err != nil {
+			t.Fatalf("import %d failed: %v", i, err)
+		}
+	}
+	if err := runTxPoolCommand(t, datadir, "export", exported); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	have, err := legacypool.ReadSnapshot(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(have) != len(entries) {
+		t.Fatalf("exported entry count mismatch: have %d, want %d", len(have), len(entries))
+	}
+	for i, entry := range have {
+		if entry.Tx.Hash() != entries[i].Tx.Hash() || entry.Time != entries[i].Time {
+			t.Errorf("entry %d mismatch: have %x/%d, want %x/%d", i, entry.Tx.Hash(), entry.Time, entries[i].Tx.Hash(), entries[i].Time)
+		}
+		// The local flag of the originating node isn't carried over
+		if entry.Local {
+			t.Errorf("entry %d imported as local", i)
+		}
+	}
+	// Corrupt and missing files are rejected without touching the snapshot
+	corrupt := filepath.Join(dir, "corrupt.rlp")
+	if err := os.WriteFile(corrupt, []byte("not a snapshot"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runTxPoolCommand(t, datadir, "import", corrupt); err == nil {
+		t.Fatal("import of corrupt file succeeded")
+	}
+	if err := runTxPoolCommand(t, datadir, "import", filepath.Join(dir, "missing.rlp")); err == nil {
+		t.Fatal("import of missing file succeeded")
+	}
+	if err := runTxPoolCommand(t, datadir, "export", exported); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if have, err := legacypool.ReadSnapshot(exported); err != nil || len(have) != len(entries) {
+		t.Fatalf("snapshot changed by failed imports: %d entries, err %v", len(have), err)
+	}
+}
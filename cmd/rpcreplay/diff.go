@@ -0,0 +1,149 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// difference is a single mismatch between a recorded and a replayed response.
+type difference struct {
+	path               string
+	recorded, replayed interface{}
+}
+
+func (d difference) String() string {
+	return fmt.Sprintf("%s: recorded %s, replayed %s", d.path, formatValue(d.recorded), formatValue(d.replayed))
+}
+
+// missing marks a value which is absent in one of the responses.
+type missing struct{}
+
+func formatValue(v interface{}) string {
+	if _, ok := v.(missing); ok {
+		return "<missing>"
+	}
+	enc, _ := json.Marshal(v)
+	return string(enc)
+}
+
+// ignoreSet holds the response fields ignored when diffing. A field is ignored
+// if its path (e.g. "result.transactions[2].hash"), its path without array
+// indices (e.g. "result.transactions.hash") or, for patterns without a dot, its
+// name (e.g. "hash") is in the set.
+type ignoreSet map[string]bool
+
+func newIgnoreSet(list string) ignoreSet {
+	set := make(ignoreSet)
+	for _, field := range strings.Split(list, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			set[field] = true
+		}
+	}
+	return set
+}
+
+var arrayIndex = regexp.MustCompile(`\[\d+\]`)
+
+func (s ignoreSet) ignores(path, name string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	return s[path] || s[arrayIndex.ReplaceAllString(path, "")] || s[name]
+}
+
+// decodeJSON decodes a JSON value, keeping numbers in their original form.
+func decodeJSON(data string) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// diffJSON returns the differences between two decoded JSON values, skipping
+// the ignored fields.
+func diffJSON(recorded, replayed interface{}, ignore ignoreSet) []difference {
+	var diffs []difference
+	diffValue("", "", recorded, replayed, ignore, &diffs)
+	return diffs
+}
+
+func diffValue(path, name string, a, b interface{}, ignore ignoreSet, diffs *[]difference) {
+	if path != "" && ignore.ignores(path, name) {
+		return
+	}
+	switch a := a.(type) {
+	case map[string]interface{}:
+		if b, ok := b.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(a)+len(b))
+			for key := range a {
+				keys = append(keys, key)
+			}
+			for key := range b {
+				if _, ok := a[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			slices.Sort(keys)
+			for _, key := range keys {
+				av, aok := a[key]
+				bv, bok := b[key]
+				if !aok {
+					av = missing{}
+				}
+				if !bok {
+					bv = missing{}
+				}
+				diffValue(joinPath(path, key), key, av, bv, ignore, diffs)
+			}
+			return
+		}
+	case []interface{}:
+		if b, ok := b.([]interface{}); ok {
+			for i := 0; i < max(len(a), len(b)); i++ {
+				var av, bv interface{} = missing{}, missing{}
+				if i < len(a) {
+					av = a[i]
+				}
+				if i < len(b) {
+					bv = b[i]
+				}
+				diffValue(fmt.Sprintf("%s[%d]", path, i), name, av, bv, ignore, diffs)
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*diffs = append(*diffs, difference{path: path, recorded: a, replayed: b})
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"testing"
+)
+
+func TestDiffJSON(t *testing.T) {
+	recorded, _ := decodeJSON(`{"result":{"number":"0x1","timestamp":"0x10","txs":[{"hash":"0xa","gas":1},{"hash":"0xb","gas":2}],"extra":true}}`)
+	replayed, _ := decodeJSON(`{"result":{"number":"0x1","timestamp":"0x20","txs":[{"hash":"0xc","gas":1},{"hash":"0xd","gas":3}]}}`)
+
+	tests := []struct {
+		ignore string
+		want   []string
+	}{
+		{
+			ignore: "",
+			want: []string{
+				`result.extra: recorded true, replayed <missing>`,
+				`result.timestamp: recorded "0x10", replayed "0x20"`,
+				`result.txs[0].hash: recorded "0xa", replayed "0xc"`,
+				`result.txs[1].gas: recorded 2, replayed 3`,
+				`result.txs[1].hash: recorded "0xb", replayed "0xd"`,
+			},
+		},
+		{
+			ignore: "hash, result.timestamp",
+			want: []string{
+				`result.extra: recorded true, replayed <missing>`,
+				`result.txs[1].gas: recorded 2, replayed 3`,
+			},
+		},
+		{
+			ignore: "result.txs.hash,result.txs[1].gas,extra,timestamp",
+			want:   nil,
+		},
+	}
+	for i, test := range tests {
+		diffs := diffJSON(recorded, replayed, newIgnoreSet(test.ignore))
+		if len(diffs) != len(test.want) {
+			t.Fatalf("test %d: wrong number of differences: have %v, want %v", i, diffs, test.want)
+		}
+		for j, d := range diffs {
+			if d.String() != test.want[j] {
+				t.Errorf("test %d: difference %d mismatch: have %q, want %q", i, j, d.String(), test.want[j])
+			}
+		}
+	}
+}
+
+func TestNormalizeResponse(t *testing.T) {
+	ok, err := normalizeResponse([]byte(`{"jsonrpc":"2.0","id":7,"result":["0x1"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := decodeJSON(`{"result":["0x1"]}`)
+	if diffs := diffJSON(want, ok, nil); len(diffs) > 0 {
+		t.Fatalf("wrong normalized result: %v", diffs)
+	}
+	fail, err := normalizeResponse([]byte(`{"jsonrpc":"2.0","id":8,"error":{"code":-32000,"message":"boom"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ = decodeJSON(`{"error":{"code":-32000,"message":"boom"}}`)
+	if diffs := diffJSON(want, fail, nil); len(diffs) > 0 {
+		t.Fatalf("wrong normalized error: %v", diffs)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// rpcreplay replays RPC calls recorded by a node (see --rpc.record) against
+// another node or a simulated backend, and reports the responses which differ
+// from the recorded ones.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	targetFlag = &cli.StringFlag{
+		Name:  "target",
+		Usage: "RPC endpoint of the node to replay the calls against",
+	}
+	simulatedFlag = &cli.BoolFlag{
+		Name:  "simulated",
+		Usage: "Replay the calls against an in-process simulated backend",
+	}
+	allocFlag = &cli.StringFlag{
+		Name:  "alloc",
+		Usage: "JSON file with the genesis allocation of the simulated backend",
+	}
+	ignoreFlag = &cli.StringFlag{
+		Name:  "ignore",
+		Usage: "Comma separated response fields to ignore when diffing (e.g. result.timestamp,blockHash)",
+	}
+	realtimeFlag = &cli.BoolFlag{
+		Name:  "realtime",
+		Usage: "Keep the original time between the recorded calls",
+	}
+	timeoutFlag = &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Timeout of a single replayed call",
+		Value: 30 * time.Second,
+	}
+)
+
+var app = flags.NewApp("RPC call replay tool")
+
+func init() {
+	app.ArgsUsage = "<recording>..."
+	app.Flags = []cli.Flag{
+		targetFlag,
+		simulatedFlag,
+		allocFlag,
+		ignoreFlag,
+		realtimeFlag,
+		timeoutFlag,
+	}
+	app.Action = replay
+}
+
+func main() {
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// replayTarget is the node the calls are replayed against.
+type replayTarget struct {
+	client  *rpc.Client
+	backend *simulated.Backend // set when replaying against a simulated backend
+}
+
+func openTarget(ctx *cli.Context) (*replayTarget, error) {
+	switch {
+	case ctx.IsSet(targetFlag.Name) && ctx.Bool(simulatedFlag.Name):
+		return nil, errors.New("--target and --simulated are mutually exclusive")
+
+	case ctx.IsSet(targetFlag.Name):
+		client, err := rpc.Dial(ctx.String(targetFlag.Name))
+		if err != nil {
+			return nil, err
+		}
+		return &replayTarget{client: client}, nil
+
+	case ctx.Bool(simulatedFlag.Name):
+		var alloc types.GenesisAlloc
+		if file := ctx.String(allocFlag.Name); file != "" {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(data, &alloc); err != nil {
+				return nil, fmt.Errorf("invalid genesis allocation: %v", err)
+			}
+		}
+		// The simulated node is reached over IPC, so the calls go through the same
+		// RPC stack as on a real node.
+		var endpoint string
+		backend := simulated.NewBackend(alloc, func(nodeConf *node.Config, ethConf *ethconfig.Config) {
+			nodeConf.IPCPath = fmt.Sprintf("rpcreplay-%d.ipc", os.Getpid())
+			endpoint = nodeConf.IPCEndpoint()
+		})
+		client, err := rpc.Dial(endpoint)
+		if err != nil {
+			backend.Close()
+			return nil, err
+		}
+		return &replayTarget{client: client, backend: backend}, nil
+
+	default:
+		return nil, errors.New("either --target or --simulated is required")
+	}
+}
+
+func (t *replayTarget) close() {
+	t.client.Close()
+	if t.backend != nil {
+		t.backend.Close()
+	}
+}
+
+func replay(ctx *cli.Context) error {
+	if ctx.NArg() == 0 {
+		return errors.New("no recording given")
+	}
+	target, err := openTarget(ctx)
+	if err != nil {
+		return err
+	}
+	defer target.close()
+
+	var (
+		ignore = newIgnoreSet(ctx.String(ignoreFlag.Name))
+		stats  replayStats
+	)
+	for _, file := range ctx.Args().Slice() {
+		if err := replayFile(ctx, target, file, ignore, &stats); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Replayed %d calls: %d matched, %d differed, %d failed, %d skipped\n",
+		stats.replayed, stats.replayed-stats.differed-stats.failed, stats.differed, stats.failed, stats.skipped)
+	if stats.differed > 0 || stats.failed > 0 {
+		return errors.New("replay found differences")
+	}
+	return nil
+}
+
+type replayStats struct {
+	replayed, differed, failed, skipped int
+}
+
+// recordedRequest is the part of a recorded request needed to replay it.
+type recordedRequest struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// recordedResponse is the part of a recorded response which is compared.
+type recordedResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data,omitempty"`
+	} `json:"error"`
+}
+
+func replayFile(ctx *cli.Context, target *replayTarget, file string, ignore ignoreSet, stats *replayStats) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	entries, err := rpc.ReadRecording(f)
+	if err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	var last time.Time
+	for i, entry := range entries {
+		var req recordedRequest
+		if err := json.Unmarshal(entry.Request, &req); err != nil {
+			return fmt.Errorf("%s: entry %d: invalid request: %v", file, i, err)
+		}
+		// Subscriptions can't be replayed without their notifications
+		if strings.HasSuffix(req.Method, "_subscribe") || strings.HasSuffix(req.Method, "_unsubscribe") {
+			stats.skipped++
+			continue
+		}
+		if ctx.Bool(realtimeFlag.Name) && !last.IsZero() {
+			time.Sleep(entry.Time.Sub(last))
+		}
+		last = entry.Time
+
+		replayed, err := target.call(ctx, req)
+		stats.replayed++
+		if err != nil {
+			stats.failed++
+			fmt.Printf("%s #%d %s: call failed: %v\n", file, i, req.Method, err)
+			continue
+		}
+		recorded, err := normalizeResponse(entry.Response)
+		if err != nil {
+			return fmt.Errorf("%s: entry %d: invalid response: %v", file, i, err)
+		}
+		if diffs := diffJSON(recorded, replayed, ignore); len(diffs) > 0 {
+			stats.differed++
+			fmt.Printf("%s #%d %s: responses differ\n", file, i, req.Method)
+			for _, d := range diffs {
+				fmt.Printf("    %v\n", d)
+			}
+		}
+	}
+	return nil
+}
+
+// call replays a request, returning the response in the normalized form of
+// normalizeResponse. Errors are only returned for transport failures.
+func (t *replayTarget) call(ctx *cli.Context, req recordedRequest) (interface{}, error) {
+	var params []json.RawMessage
+	if len(req.Params) > 0 && string(req.Params) != "null" {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("unsupported params: %v", err)
+		}
+	}
+	args := make([]interface{}, len(params))
+	for i, param := range params {
+		args[i] = param
+	}
+	callctx, cancel := context.WithTimeout(context.Background(), ctx.Duration(timeoutFlag.Name))
+	defer cancel()
+
+	var result json.RawMessage
+	err := t.client.CallContext(callctx, &result, req.Method, args...)
+
+	// Transactions sent to the simulated backend are mined right away, so later
+	// calls observe them like on the recorded node.
+	if t.backend != nil && err == nil && (req.Method == "eth_sendRawTransaction" || req.Method == "eth_sendTransaction") {
+		t.backend.Commit()
+	}
+	var rpcErr rpc.Error
+	switch {
+	case err == nil:
+		if result == nil {
+			result = json.RawMessage("null")
+		}
+		return decodeJSON(`{"result":` + string(result) + `}`)
+	case errors.As(err, &rpcErr):
+		resp := map[string]interface{}{"code": json.Number(fmt.Sprint(rpcErr.ErrorCode())), "message": rpcErr.Error()}
+		var dataErr rpc.DataError
+		if errors.As(err, &dataErr) && dataErr.ErrorData() != nil {
+			data, _ := json.Marshal(dataErr.ErrorData())
+			if resp["data"], err = decodeJSON(string(data)); err != nil {
+				return nil, err
+			}
+		}
+		return map[string]interface{}{"error": resp}, nil
+	default:
+		return nil, err
+	}
+}
+
+// normalizeResponse strips a recorded response down to its result or error,
+// leaving out the envelope fields which differ between runs.
+func normalizeResponse(data json.RawMessage) (interface{}, error) {
+	var resp recordedResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		out := map[string]interface{}{"code": json.Number(fmt.Sprint(resp.Error.Code)), "message": resp.Error.Message}
+		if len(resp.Error.Data) > 0 {
+			data, err := decodeJSON(string(resp.Error.Data))
+			if err != nil {
+				return nil, err
+			}
+			out["data"] = data
+		}
+		return map[string]interface{}{"error": out}, nil
+	}
+	result := resp.Result
+	if len(result) == 0 {
+		result = json.RawMessage("null")
+	}
+	return decodeJSON(`{"result":` + string(result) + `}`)
+}
@@ -347,6 +347,12 @@ var (
 		Value:    ethconfig.Defaults.TxPool.Rejournal,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolSnapshotFlag = &cli.StringFlag{
+		Name:     "txpool.snapshot",
+		Usage:    "Disk snapshot of the full transaction pool to survive node restarts (disabled if empty)",
+		Value:    ethconfig.Defaults.TxPool.Snapshot,
+		Category: flags.TxPoolCategory,
+	}
 	TxPoolPriceLimitFlag = &cli.Uint64Flag{
 		Name:     "txpool.pricelimit",
 		Usage:    "Minimum gas price tip to enforce for acceptance into the pool",
@@ -722,6 +728,91 @@ var (
 		Value:    node.DefaultConfig.BatchResponseMaxSize,
 		Category: flags.APICategory,
 	}
+	BatchConcurrency = &cli.IntFlag{
+		Name:     "rpc.batch-concurrency",
+		Usage:    "Maximum number of calls of a batch executed concurrently per connection (0 or 1 = sequential)",
+		Value:    node.DefaultConfig.BatchConcurrency,
+		Category: flags.APICategory,
+	}
+	RPCResponseMaxSize = &cli.IntFlag{
+		Name:     "rpc.response-max-size",
+		Usage:    "Maximum number of bytes returned from a single call (0 = unlimited)",
+		Value:    node.DefaultConfig.RPCResponseMaxSize,
+		Category: flags.APICategory,
+	}
+	RPCRateLimitFlag = &cli.Float64Flag{
+		Name:     "rpc.ratelimit",
+		Usage:    "Cost units per second a single HTTP/WS RPC client may spend (0 = unlimited)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitBurstFlag = &cli.IntFlag{
+		Name:     "rpc.ratelimit.burst",
+		Usage:    "Maximum cost units a single HTTP/WS RPC client may spend at once",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitQuotaFlag = &cli.Uint64Flag{
+		Name:     "rpc.ratelimit.quota",
+		Usage:    "Cost units a single HTTP/WS RPC client may spend per hour (0 = unlimited)",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitHeaderFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.header",
+		Usage:    "HTTP header carrying the API key identifying rate limited RPC clients (e.g. X-Api-Key), see --rpc.ratelimit.keys",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitKeysFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.keys",
+		Usage:    "File listing the API keys accepted in the rate limit header, one per line",
+		Category: flags.APICategory,
+	}
+	RPCRateLimitClaimFlag = &cli.StringFlag{
+		Name:     "rpc.ratelimit.claim",
+		Usage:    "JWT claim identifying rate limited RPC clients (e.g. sub)",
+		Category: flags.APICategory,
+	}
+	RPCRecordFileFlag = &cli.StringFlag{
+		Name:     "rpc.record",
+		Usage:    "File to record the calls served by the HTTP/WS RPC endpoints to, for replaying them later",
+		Category: flags.APICategory,
+	}
+	RPCRecordMaxSizeFlag = &cli.Int64Flag{
+		Name:     "rpc.record.maxsize",
+		Usage:    "Size in megabytes after which the RPC recording is rotated (0 = no rotation)",
+		Value:    128,
+		Category: flags.APICategory,
+	}
+	RPCRecordMaxFilesFlag = &cli.IntFlag{
+		Name:     "rpc.record.maxfiles",
+		Usage:    "Number of rotated RPC recording files to keep",
+		Value:    5,
+		Category: flags.APICategory,
+	}
+	RPCAuthSecretFlag = &cli.StringFlag{
+		Name:     "rpc.auth.secret",
+		Usage:    "Path to a hex-encoded secret verifying JWTs which grant access to HTTP/WS RPC APIs",
+		Category: flags.APICategory,
+	}
+	RPCAuthPublicKeyFlag = &cli.StringFlag{
+		Name:     "rpc.auth.pubkey",
+		Usage:    "Path to a PEM public key (RSA, ECDSA or Ed25519) verifying JWTs which grant access to HTTP/WS RPC APIs",
+		Category: flags.APICategory,
+	}
+	RPCAuthPublicFlag = &cli.StringFlag{
+		Name:     "rpc.auth.public",
+		Usage:    "Comma separated list of RPC namespaces and methods callable without a JWT when authorization is enabled (add \"rpc\" to allow method discovery)",
+		Category: flags.APICategory,
+	}
+	RPCTracingEndpointFlag = &cli.StringFlag{
+		Name:     "rpc.tracing.endpoint",
+		Usage:    "OTLP/HTTP endpoint to export RPC request traces to (e.g. http://localhost:4318/v1/traces)",
+		Category: flags.APICategory,
+	}
+	RPCTracingSampleRatioFlag = &cli.Float64Flag{
+		Name:     "rpc.tracing.sampleratio",
+		Usage:    "Fraction of RPC requests traced, requests not sampled upstream via a traceparent header are never traced",
+		Value:    node.DefaultConfig.TracingSampleRatio,
+		Category: flags.APICategory,
+	}
 
 	// Network Settings
 	MaxPeersFlag = &cli.IntFlag{
@@ -760,7 +851,7 @@ var (
 	}
 	NATFlag = &cli.StringFlag{
 		Name:     "nat",
-		Usage:    "NAT port mapping mechanism (any|none|upnp|pmp|pmp:<IP>|extip:<IP>)",
+		Usage:    "NAT port mapping mechanism (any|none|upnp|pmp|pmp:<IP>|pcp|pcp:<IP>|extip:<IP>)",
 		Value:    "any",
 		Category: flags.NetworkingCategory,
 	}
@@ -788,6 +879,16 @@ var (
 		Usage:    "Restricts network communication to the given IP networks (CIDR masks)",
 		Category: flags.NetworkingCategory,
 	}
+	PeerPolicyFlag = &cli.StringFlag{
+		Name:     "peerpolicy",
+		Usage:    "JSON file with allow/deny rules for peer connections (reloaded on change)",
+		Category: flags.NetworkingCategory,
+	}
+	PeerServeLimitFlag = &cli.IntFlag{
+		Name:     "peerservelimit",
+		Usage:    "Maximum bandwidth in KiB/s for serving snap and historical chain data to a single untrusted peer (0 = unlimited)",
+		Category: flags.NetworkingCategory,
+	}
 	DNSDiscoveryFlag = &cli.StringFlag{
 		Name:     "discovery.dns",
 		Usage:    "Sets DNS discovery entry points (use \"\" to disable DNS)",
@@ -839,6 +940,11 @@ var (
 		Value:    ethconfig.Defaults.GPO.IgnorePrice.Int64(),
 		Category: flags.GasPriceCategory,
 	}
+	GpoPredictiveFlag = &cli.BoolFlag{
+		Name:     "gpo.predictive",
+		Usage:    "Take the pending transaction pool into account when estimating fees (eth_feeEstimates)",
+		Category: flags.GasPriceCategory,
+	}
 
 	// Metrics flags
 	MetricsEnabledFlag = &cli.BoolFlag{
@@ -1153,6 +1259,65 @@ func setHTTP(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(BatchResponseMaxSize.Name) {
 		cfg.BatchResponseMaxSize = ctx.Int(BatchResponseMaxSize.Name)
 	}
+
+	if ctx.IsSet(BatchConcurrency.Name) {
+		cfg.BatchConcurrency = ctx.Int(BatchConcurrency.Name)
+	}
+
+	if ctx.IsSet(RPCResponseMaxSize.Name) {
+		cfg.RPCResponseMaxSize = ctx.Int(RPCResponseMaxSize.Name)
+	}
+
+	if ctx.IsSet(RPCRateLimitFlag.Name) {
+		cfg.RPCRateLimit.Rate = ctx.Float64(RPCRateLimitFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitBurstFlag.Name) {
+		cfg.RPCRateLimit.Burst = ctx.Int(RPCRateLimitBurstFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitQuotaFlag.Name) {
+		cfg.RPCRateLimit.Quota = ctx.Uint64(RPCRateLimitQuotaFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitHeaderFlag.Name) {
+		cfg.RPCRateLimit.ClientHeader = ctx.String(RPCRateLimitHeaderFlag.Name)
+	}
+	if ctx.IsSet(RPCRateLimitKeysFlag.Name) {
+		data, err := os.ReadFile(ctx.String(RPCRateLimitKeysFlag.Name))
+		if err != nil {
+			Fatalf("Failed to read rate limit API keys: %v", err)
+		}
+		cfg.RPCRateLimit.ClientKeys = nil
+		for _, line := range strings.Split(string(data), "\n") {
+			if key := strings.TrimSpace(line); key != "" && !strings.HasPrefix(key, "#") {
+				cfg.RPCRateLimit.ClientKeys = append(cfg.RPCRateLimit.ClientKeys, key)
+			}
+		}
+	}
+	if ctx.IsSet(RPCRateLimitClaimFlag.Name) {
+		cfg.RPCRateLimit.ClientClaim = ctx.String(RPCRateLimitClaimFlag.Name)
+	}
+
+	if ctx.IsSet(RPCRecordFileFlag.Name) {
+		cfg.RPCRecordFile = ctx.String(RPCRecordFileFlag.Name)
+		cfg.RPCRecordMaxSize = ctx.Int64(RPCRecordMaxSizeFlag.Name) * 1024 * 1024
+		cfg.RPCRecordMaxFiles = ctx.Int(RPCRecordMaxFilesFlag.Name)
+	}
+
+	if ctx.IsSet(RPCAuthSecretFlag.Name) {
+		cfg.RPCAuth.Secret = ctx.String(RPCAuthSecretFlag.Name)
+	}
+	if ctx.IsSet(RPCAuthPublicKeyFlag.Name) {
+		cfg.RPCAuth.PublicKey = ctx.String(RPCAuthPublicKeyFlag.Name)
+	}
+	if ctx.IsSet(RPCAuthPublicFlag.Name) {
+		cfg.RPCAuth.Public = SplitAndTrim(ctx.String(RPCAuthPublicFlag.Name))
+	}
+
+	if ctx.IsSet(RPCTracingEndpointFlag.Name) {
+		cfg.TracingEndpoint = ctx.String(RPCTracingEndpointFlag.Name)
+	}
+	if ctx.IsSet(RPCTracingSampleRatioFlag.Name) {
+		cfg.TracingSampleRatio = ctx.Float64(RPCTracingSampleRatioFlag.Name)
+	}
 }
 
 // setGraphQL creates the GraphQL listener interface string from the set
@@ -1308,6 +1473,12 @@ func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
 		}
 		cfg.NetRestrict = list
 	}
+	if ctx.IsSet(PeerPolicyFlag.Name) {
+		cfg.PeerPolicyFile = ctx.String(PeerPolicyFlag.Name)
+	}
+	if ctx.IsSet(PeerServeLimitFlag.Name) {
+		cfg.PeerServeLimit = ctx.Int(PeerServeLimitFlag.Name) * 1024
+	}
 
 	if ctx.Bool(DeveloperFlag.Name) {
 		// --dev mode can't use p2p networking.
@@ -1422,6 +1593,9 @@ func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
 	if ctx.IsSet(GpoIgnoreGasPriceFlag.Name) {
 		cfg.IgnorePrice = big.NewInt(ctx.Int64(GpoIgnoreGasPriceFlag.Name))
 	}
+	if ctx.IsSet(GpoPredictiveFlag.Name) {
+		cfg.Predictive = ctx.Bool(GpoPredictiveFlag.Name)
+	}
 }
 
 func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
@@ -1444,6 +1618,9 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	if ctx.IsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.Duration(TxPoolRejournalFlag.Name)
 	}
+	if ctx.IsSet(TxPoolSnapshotFlag.Name) {
+		cfg.Snapshot = ctx.String(TxPoolSnapshotFlag.Name)
+	}
 	if ctx.IsSet(TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.Uint64(TxPoolPriceLimitFlag.Name)
 	}
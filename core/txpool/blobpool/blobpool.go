@@ -1998,6 +1998,17 @@ func (p *BlobPool) ContentFrom(addr common.Address) ([]*types.Transaction, []*ty
 	return []*types.Transaction{}, []*types.Transaction{}
 }
 
+// Authorizations retrieves the pooled set code transactions carrying an
+// authorization signed by the given address.
+//
+// The blob pool does not accept set code transactions, so this is always empty.
+// Authorizations 检索携带由给定地址签署的授权的池内 set code 交易。
+//
+// blob 池不接受 set code 交易，因此结果总是为空。
+func (p *BlobPool) Authorizations(addr common.Address) []*types.Transaction {
+	return nil
+}
+
 // Locals retrieves the accounts currently considered local by the pool.
 //
 // There is no notion of local accounts in the blob pool.
@@ -2081,3 +2092,102 @@ func (p *BlobPool) Clear() {
 	)
 	p.evict = newPriceHeap(basefee, blobfee, p.index)
 }
+
+// Remove implements txpool.SubPool, evicting the blob transaction identified by
+// hash and all the higher-nonce transactions of the same sender.
+// Remove 实现 txpool.SubPool 接口，驱逐由哈希标识的 blob 交易以及同一发送者所有更高 nonce 的交易。
+func (p *BlobPool) Remove(hash common.Hash) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// The lookup only tracks storage ids, so find the owning account by scanning
+	// the index. This is an operator-only path, the cost is fine.
+	// 查找表只跟踪存储 ID，因此通过扫描索引来查找所属账户。这是仅供运维使用的路径，开销可以接受。
+	for addr, txs := range p.index {
+		for _, meta := range txs {
+			if meta.hash == hash {
+				return p.removeFrom(addr, meta.nonce)
+			}
+		}
+	}
+	return 0
+}
+
+// RemoveFrom implements txpool.SubPool, evicting all the blob transactions of
+// the given account from the pool.
+// RemoveFrom 实现 txpool.SubPool 接口，从池中驱逐给定账户的所有 blob 交易。
+func (p *BlobPool) RemoveFrom(addr common.Address) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.removeFrom(addr, 0)
+}
+
+// removeFrom drops all the transactions of an account with a nonce at or above
+// the given threshold. The pool lock must be held.
+// removeFrom 丢弃账户中 nonce 大于或等于给定阈值的所有交易。必须持有池锁。
+func (p *BlobPool) removeFrom(addr common.Address, nonce uint64) int {
+	txs, ok := p.index[addr]
+	if !ok {
+		return 0
+	}
+	// Transactions are sorted by nonce, find the first one to drop
+	// 交易按 nonce 排序，找到第一个需要丢弃的交易
+	keep := sort.Search(len(txs), func(i int) bool { return txs[i].nonce >= nonce })
+	if keep == len(txs) {
+		return 0
+	}
+	var (
+		ids    []uint64 // 待删除的交易 ID 列表
+		nonces []uint64 // 待删除的交易 nonce 列表
+	)
+	for _, drop := range txs[keep:] {
+		ids = append(ids, drop.id)
+		nonces = append(nonces, drop.nonce)
+
+		p.spent[addr] = new(uint256.Int).Sub(p.spent[addr], drop.costCap) // 更新支出
+		p.stored -= uint64(drop.size)                                     // 减少存储大小
+		p.lookup.untrack(drop)                                            // 取消跟踪
+	}
+	if keep == 0 {
+		delete(p.index, addr)
+		delete(p.spent, addr)
+		heap.Remove(p.evict, p.evict.index[addr]) // 从驱逐堆中移除
+		p.reserve(addr, false)                    // 释放地址保留
+	} else {
+		for i := keep; i < len(txs); i++ {
+			txs[i] = nil
+		}
+		p.index[addr] = txs[:keep]
+		heap.Fix(p.evict, p.evict.index[addr]) // 修复驱逐堆
+	}
+	log.Info("Removed pooled blob transactions", "from", addr, "drop", nonces, "ids", ids)
+
+	// 从存储中删除交易
+	for _, id := range ids {
+		if err := p.store.Delete(id); err != nil {
+			log.Error("Failed to delete blob transaction", "from", addr, "id", id, "err", err)
+		}
+	}
+	p.updateStorageMetrics()
+	return len(ids)
+}
+
+// SetLocal implements txpool.SubPool.
+//
+// There is no notion of local accounts in the blob pool.
+// SetLocal 实现 txpool.SubPool 接口。
+//
+// 在 blob 池中没有本地账户的概念。
+func (p *BlobPool) SetLocal(addr common.Address, local bool) {}
+
+// FlushJournal implements txpool.SubPool.
+//
+// The blob pool persists every transaction into its backing store on arrival,
+// so there is nothing to flush.
+// FlushJournal 实现 txpool.SubPool 接口。
+//
+// blob 池在交易到达时就将其持久化到后端存储中，因此没有需要刷新的内容。
+func (p *BlobPool) FlushJournal() error {
+	return nil
+}
@@ -1449,6 +1449,90 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+// Tests that removing a transaction from the pool also drops all subsequent ones
+// of the same account, as the blob pool does not allow nonce gaps, and that
+// removing an account's transactions releases its address reservation.
+func TestRemove(t *testing.T) {
+	var (
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		key, _     = crypto.GenerateKey()
+		other, _   = crypto.GenerateKey()
+		addr       = crypto.PubkeyToAddress(key.PublicKey)
+	)
+	statedb.AddBalance(addr, uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
+	statedb.AddBalance(crypto.PubkeyToAddress(other.PublicKey), uint256.NewInt(1_000_000_000), tracing.BalanceChangeUnspecified)
+	statedb.Commit(0, true)
+
+	chain := &testBlockChain{
+		config:  params.MainnetChainConfig,
+		basefee: uint256.NewInt(1050),
+		blobfee: uint256.NewInt(105),
+		statedb: statedb,
+	}
+	pool := New(Config{Datadir: t.TempDir()}, chain)
+	if err := pool.Init(1, chain.CurrentBlock(), makeAddressReserver()); err != nil {
+		t.Fatalf("failed to create blob pool: %v", err)
+	}
+	defer pool.Close()
+
+	txs := make([]*types.Transaction, 4)
+	for i := range txs {
+		txs[i] = makeTx(uint64(i), 1, 1100, 110, key)
+		if err := pool.add(txs[i]); err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	if err := pool.add(makeTx(0, 1, 1100, 110, other)); err != nil {
+		t.Fatalf("failed to add transaction of other account: %v", err)
+	}
+	verifyPoolInternals(t, pool)
+
+	// Unknown transactions are not removed
+	if dropped := pool.Remove(common.Hash{0x01}); dropped != 0 {
+		t.Fatalf("dropped unknown transaction: %d", dropped)
+	}
+	// Removing a transaction drops the ones behind the nonce gap too
+	if dropped := pool.Remove(txs[2].Hash()); dropped != 2 {
+		t.Fatalf("dropped transaction mismatch: have %d, want %d", dropped, 2)
+	}
+	for i, tx := range txs {
+		if have, want := pool.Has(tx.Hash()), i < 2; have != want {
+			t.Errorf("tx %d: pool presence mismatch: have %v, want %v", i, have, want)
+		}
+	}
+	verifyPoolInternals(t, pool)
+
+	// The gap must be filled before the later transactions are accepted again
+	if err := pool.add(txs[3]); !errors.Is(err, core.ErrNonceTooHigh) {
+		t.Fatalf("gapped transaction error mismatch: have %v, want %v", err, core.ErrNonceTooHigh)
+	}
+	for i := 2; i < len(txs); i++ {
+		if err := pool.add(txs[i]); err != nil {
+			t.Fatalf("tx %d: failed to re-add transaction: %v", i, err)
+		}
+	}
+	verifyPoolInternals(t, pool)
+
+	// Removing all transactions of an account leaves the others alone and
+	// releases the reservation, the test reserver panics otherwise
+	if dropped := pool.RemoveFrom(addr); dropped != len(txs) {
+		t.Fatalf("dropped transaction mismatch: have %d, want %d", dropped, len(txs))
+	}
+	if _, ok := pool.index[addr]; ok {
+		t.Fatalf("removed account still indexed")
+	}
+	if dropped := pool.RemoveFrom(addr); dropped != 0 {
+		t.Fatalf("dropped transactions of empty account: %d", dropped)
+	}
+	verifyPoolInternals(t, pool)
+
+	if err := pool.add(txs[0]); err != nil {
+		t.Fatalf("failed to re-add transaction after removal: %v", err)
+	}
+	verifyPoolInternals(t, pool)
+	verifyBlobRetrievals(t, pool)
+}
+
 // fakeBilly is a billy.Database implementation which just drops data on the floor.
 type fakeBilly struct {
 	billy.Database
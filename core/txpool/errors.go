@@ -73,4 +73,21 @@ var (
 	// ErrAlreadyReserved 如果发送者地址在不同的子池中有一个待处理的交易，则返回此错误。
 	// 例如，当来自该发送者的 Blob 交易仍然待处理时（反之亦然），针对任何非 Blob 类型的输入交易，都会返回此错误。
 	ErrAlreadyReserved = errors.New("address already reserved")
+
+	// ErrInflightTxLimitReached is returned when the maximum number of in-flight
+	// transactions is reached for accounts that are delegated (EIP-7702) or have
+	// a pending delegation in the pool.
+	// ErrInflightTxLimitReached 当已委托（EIP-7702）或在池中有待处理委托的账户达到最大在途交易数量时返回。
+	ErrInflightTxLimitReached = errors.New("in-flight transaction limit reached for delegated accounts")
+
+	// ErrAuthorityReserved is returned if a set code transaction carries an
+	// authorization from an account that already has transactions in flight.
+	// ErrAuthorityReserved 如果 set code 交易携带的授权来自已有在途交易的账户，则返回此错误。
+	ErrAuthorityReserved = errors.New("authority already reserved")
+
+	// ErrAuthorizationConflict is returned if a set code transaction carries an
+	// authorization whose nonce collides with a transaction or another
+	// authorization of the same account already tracked by the pool.
+	// ErrAuthorizationConflict 如果 set code 交易携带的授权的 nonce 与池中已跟踪的同一账户的交易或其他授权冲突，则返回此错误。
+	ErrAuthorizationConflict = errors.New("authorization nonce conflicts with pooled transaction")
 )
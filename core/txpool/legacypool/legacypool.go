@@ -19,8 +19,10 @@ package legacypool
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
+	"slices"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -56,6 +58,15 @@ const (
 	// to validate whether they fit into the pool or not.
 	// txMaxSize 是单个交易的最大大小。此字段有非 trivial 的后果：较大的交易传播起来明显更困难且成本更高；较大的交易还需要更多资源来验证是否适合放入交易池。
 	txMaxSize = 4 * txSlotSize // 128KB
+
+	// maxDelegatedInflight is the maximum number of transactions an account may
+	// have in the pool if it is delegated (EIP-7702) or if it has signed a set
+	// code authorization that is still pending. The code of such accounts may
+	// drain their balance or bump their nonce at any time, invalidating stacked
+	// transactions without them ever paying for the spam.
+	// maxDelegatedInflight 是已委托（EIP-7702）账户或签署了仍在待处理的 set code 授权的账户在池中可以拥有的最大交易数量。
+	// 这类账户的代码可能随时耗尽其余额或增加其 nonce，使堆叠的交易失效，而无需为这些垃圾交易支付任何费用。
+	maxDelegatedInflight = 1
 )
 
 var (
@@ -153,6 +164,8 @@ type Config struct {
 	// Journal 本地交易日志，用于节点重启后恢复
 	Rejournal time.Duration // Time interval to regenerate the local transaction journal
 	// Rejournal 重新生成本地交易日志的时间间隔
+	Snapshot string // Snapshot of the full pool (pending and queued) to survive node restarts, empty to disable
+	// Snapshot 整个交易池（待处理和排队交易）的快照，用于节点重启后恢复，为空则禁用
 
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	// PriceLimit 接受进入交易池的最低 gas 价格
@@ -342,16 +355,17 @@ func New(config Config, chain BlockChain) *LegacyPool {
 }
 
 // Filter returns whether the given transaction can be consumed by the legacy
-// pool, specifically, whether it is a Legacy, AccessList or Dynamic transaction.
-// Filter 返回给定交易是否可以被遗留池消费，具体来说，是否是 Legacy、AccessList 或 Dynamic 交易。
+// pool, specifically, whether it is a Legacy, AccessList, Dynamic or SetCode
+// transaction.
+// Filter 返回给定交易是否可以被遗留池消费，具体来说，是否是 Legacy、AccessList、Dynamic 或 SetCode 交易。
 func (pool *LegacyPool) Filter(tx *types.Transaction) bool {
 	switch tx.Type() {
-	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType:
+	case types.LegacyTxType, types.AccessListTxType, types.DynamicFeeTxType, types.SetCodeTxType:
 		return true // 如果交易类型是支持的类型，返回 true
 	default:
 		return false // 否则返回 false
 	}
-	// 逻辑注解：此函数检查交易类型，只接受 Legacy、AccessList 和 DynamicFee 三种类型。这是为了确保遗留池只处理兼容的交易类型。
+	// 逻辑注解：此函数检查交易类型，只接受 Legacy、AccessList、DynamicFee 和 SetCode 四种类型。这是为了确保遗留池只处理兼容的交易类型。
 }
 
 // Init sets the gas price needed to keep a transaction in the pool and the chain
@@ -401,6 +415,15 @@ func (pool *LegacyPool) Init(gasTip uint64, head *types.Header, reserve txpool.A
 			// 如果旋转交易日志失败，记录警告
 		}
 	}
+	// If full pool snapshotting is enabled, revalidate and reload the pool
+	// contents persisted on the last shutdown
+	// 如果启用了完整的池快照，则重新验证并重新加载上次关闭时持久化的池内容
+	if pool.config.Snapshot != "" {
+		if err := pool.loadSnapshot(); err != nil {
+			log.Warn("Failed to load transaction pool snapshot", "err", err)
+			// 如果加载交易池快照失败，记录警告
+		}
+	}
 	pool.wg.Add(1)
 	go pool.loop() // 启动主事件循环
 	return nil
@@ -500,6 +523,12 @@ func (pool *LegacyPool) Close() error {
 	if pool.journal != nil {
 		pool.journal.close() // 关闭交易日志
 	}
+	if pool.config.Snapshot != "" {
+		if err := pool.saveSnapshot(); err != nil {
+			log.Warn("Failed to save transaction pool snapshot", "err", err)
+			// 如果保存交易池快照失败，记录警告
+		}
+	}
 	log.Info("Transaction pool stopped")
 	return nil
 	// 逻辑注解：此函数关闭交易池，停止重组循环并等待所有任务完成。如果有日志，则关闭日志文件。关键逻辑是确保池安全关闭。
@@ -554,6 +583,46 @@ func (pool *LegacyPool) SetGasTip(tip *big.Int) {
 	// 逻辑注解：此函数更新最低 gas tip，并移除低于新阈值的交易。关键逻辑是确保池中的交易满足新的 gas 价格要求。
 }
 
+// SetLimits updates the price bump, slot, queue and lifetime limits of the pool
+// from the given config, ignoring all other fields. Transactions exceeding the
+// new limits are dropped right away.
+// SetLimits 使用给定配置更新池的价格提升、槽位、队列和存活时间限制，忽略所有其他字段。
+// 超出新限制的交易会被立即丢弃。
+func (pool *LegacyPool) SetLimits(config Config) {
+	config = (&config).sanitize()
+
+	pool.mu.Lock()
+	pool.config.PriceBump = config.PriceBump
+	pool.config.AccountSlots = config.AccountSlots
+	pool.config.GlobalSlots = config.GlobalSlots
+	pool.config.AccountQueue = config.AccountQueue
+	pool.config.GlobalQueue = config.GlobalQueue
+	pool.config.Lifetime = config.Lifetime
+
+	// Recheck all queued accounts, which also truncates the pending and queued
+	// transactions to the new limits
+	// 重新检查所有排队的账户，这也会将待处理和排队的交易截断到新的限制
+	accounts := newAccountSet(pool.signer)
+	for addr := range pool.queue {
+		accounts.add(addr)
+	}
+	pool.mu.Unlock()
+
+	<-pool.requestPromoteExecutables(accounts)
+	log.Info("Legacy pool limits updated", "accountslots", config.AccountSlots, "globalslots", config.GlobalSlots,
+		"accountqueue", config.AccountQueue, "globalqueue", config.GlobalQueue, "lifetime", config.Lifetime)
+}
+
+// Limits returns the configuration of the pool, including the limits currently
+// in effect.
+// Limits 返回池的配置，包括当前生效的限制。
+func (pool *LegacyPool) Limits() Config {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.config
+}
+
 // Nonce returns the next nonce of an account, with all transactions executable
 // by the pool already applied on top.
 // Nonce 返回账户的下一个 nonce，池中所有可执行交易已应用其上。
@@ -629,6 +698,13 @@ func (pool *LegacyPool) ContentFrom(addr common.Address) ([]*types.Transaction,
 	// 逻辑注解：此函数返回指定地址的交易内容。关键逻辑是提供特定账户的交易视图。
 }
 
+// Authorizations retrieves the pooled set code transactions carrying an
+// authorization signed by the given address, i.e. its pending delegations.
+// Authorizations 获取携带由给定地址签署的授权的池内 set code 交易，即其待处理的委托。
+func (pool *LegacyPool) Authorizations(addr common.Address) []*types.Transaction {
+	return pool.all.Authorizations(addr)
+}
+
 // Pending retrieves all currently processable transactions, grouped by origin
 // account and sorted by nonce.
 //
@@ -733,7 +809,8 @@ func (pool *LegacyPool) validateTxBasics(tx *types.Transaction, local bool) erro
 		Accept: 0 |
 			1<<types.LegacyTxType |
 			1<<types.AccessListTxType |
-			1<<types.DynamicFeeTxType, // 设置接受的交易类型
+			1<<types.DynamicFeeTxType |
+			1<<types.SetCodeTxType, // 设置接受的交易类型
 		MaxSize: txMaxSize,                  // 设置最大交易大小
 		MinTip:  pool.gasTip.Load().ToBig(), // 设置最低 tip
 	}
@@ -784,10 +861,122 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction) error {
 	if err := txpool.ValidateTransactionWithState(tx, pool.signer, opts); err != nil {
 		return err // 如果验证失败，返回错误
 	}
-	return nil
+	return pool.validateAuth(tx)
 	// 逻辑注解：此函数执行完整的交易验证，包括状态检查（如余额、nonce）。关键逻辑是确保交易符合共识规则和本地限制。
 }
 
+// validateAuth checks whether a transaction complies with the restrictions the
+// pool places on EIP-7702 delegations:
+//
+//   - delegated accounts, and accounts with a pending authorization, may only
+//     have a limited number of transactions in flight (replacements allowed)
+//   - authorities of a set code transaction may not exceed the same limit
+//   - authorization nonces may not collide with pooled transactions or pooled
+//     authorizations of the same authority
+//
+// validateAuth 检查交易是否符合池对 EIP-7702 委托施加的限制：
+//   - 已委托账户以及有待处理授权的账户只能有有限数量的在途交易（允许替换）
+//   - set code 交易的授权者不能超过相同的限制
+//   - 授权 nonce 不能与池中同一授权者的交易或授权冲突
+func (pool *LegacyPool) validateAuth(tx *types.Transaction) error {
+	from, _ := types.Sender(pool.signer, tx) // already validated
+
+	// Limit the number of in-flight transactions of delegated senders. A
+	// replacement of an already pooled transaction is always allowed.
+	// 限制已委托发送者的在途交易数量。替换已在池中的交易总是被允许的。
+	if pool.isDelegated(from) || pool.all.HasAuthorizations(from) {
+		if count := pool.inflight(from); count >= maxDelegatedInflight && pool.pooled(from, tx.Nonce()) == nil {
+			return fmt.Errorf("%w: sender %v, in-flight %d", txpool.ErrInflightTxLimitReached, from, count)
+		}
+	}
+	auths := tx.SetCodeAuthorizations()
+	if len(auths) == 0 {
+		return nil
+	}
+	// Recover the authorities once, the result is cached in the transaction and
+	// reused by the lookup when the transaction is added
+	// 仅恢复一次授权者，结果缓存在交易中，并在交易被添加时由查找表复用
+	authorities := tx.SetCodeAuthorityList()
+	// Transaction being replaced by this one, if any, its authorizations and
+	// nonce will be released on replacement
+	// 被此交易替换的交易（如果有），其授权和 nonce 将在替换时释放
+	replaced := pool.pooled(from, tx.Nonce())
+
+	for i, auth := range auths {
+		if authorities[i] == nil {
+			continue // invalid authorizations are skipped during execution
+		}
+		authority := *authorities[i]
+		// Ensure the authority does not exceed the in-flight limit once this
+		// transaction lands, counting itself if the authority is the sender
+		// 确保此交易落地后授权者不超过在途限制，如果授权者是发送者则计入自身
+		count := pool.inflight(authority)
+		if authority == from {
+			count++
+			if replaced != nil {
+				count--
+			}
+		}
+		if count > maxDelegatedInflight {
+			return fmt.Errorf("%w: authority %v, in-flight %d", txpool.ErrAuthorityReserved, authority, count)
+		}
+		// Ensure the authorization nonce is not consumed by a pooled transaction
+		// or by an authorization of some other pooled transaction
+		// 确保授权 nonce 未被池中交易或其他池中交易的授权使用
+		if pooled := pool.pooled(authority, auth.Nonce); pooled != nil && pooled != replaced {
+			return fmt.Errorf("%w: authority %v, nonce %d", txpool.ErrAuthorizationConflict, authority, auth.Nonce)
+		}
+		for _, pooled := range pool.all.Authorizations(authority) {
+			if pooled == replaced {
+				continue
+			}
+			signers := pooled.SetCodeAuthorityList() // cached when the transaction was pooled
+			for j, other := range pooled.SetCodeAuthorizations() {
+				if other.Nonce == auth.Nonce && signers[j] != nil && *signers[j] == authority {
+					return fmt.Errorf("%w: authority %v, nonce %d", txpool.ErrAuthorizationConflict, authority, auth.Nonce)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isDelegated reports whether the account has a delegation designator (EIP-7702)
+// deployed in the current state.
+// isDelegated 报告账户在当前状态中是否部署了委托标识符（EIP-7702）。
+func (pool *LegacyPool) isDelegated(addr common.Address) bool {
+	_, ok := types.ParseDelegation(pool.currentState.GetCode(addr))
+	return ok
+}
+
+// inflight returns the number of pending and queued transactions of an account.
+// inflight 返回账户的待处理和队列交易数量。
+func (pool *LegacyPool) inflight(addr common.Address) int {
+	var count int
+	if list := pool.pending[addr]; list != nil {
+		count += list.Len()
+	}
+	if list := pool.queue[addr]; list != nil {
+		count += list.Len()
+	}
+	return count
+}
+
+// pooled returns the pending or queued transaction of an account with the given
+// nonce, or nil if there is none.
+// pooled 返回账户具有给定 nonce 的待处理或队列交易，如果没有则返回 nil。
+func (pool *LegacyPool) pooled(addr common.Address, nonce uint64) *types.Transaction {
+	if list := pool.pending[addr]; list != nil {
+		if tx := list.txs.Get(nonce); tx != nil {
+			return tx
+		}
+	}
+	if list := pool.queue[addr]; list != nil {
+		return list.txs.Get(nonce)
+	}
+	return nil
+}
+
 // add validates a transaction and inserts it into the non-executable queue for later
 // pending promotion and execution. If the transaction is a replacement for an already
 // pending or queued one, it overwrites the previous transaction if its price is higher.
@@ -2096,6 +2285,13 @@ func (as *accountSet) addTx(tx *types.Transaction) {
 	// 逻辑注解：此函数添加交易发送者。关键逻辑是复用 add 方法。
 }
 
+// remove deletes an address from the set.
+// remove 从集合中删除一个地址。
+func (as *accountSet) remove(addr common.Address) {
+	delete(as.accounts, addr) // 删除地址
+	as.cache = nil            // 清除缓存
+}
+
 // flatten returns the list of addresses within this set, also caching it for later
 // reuse. The returned slice should not be changed!
 // flatten 返回集合中的地址列表，并缓存以供稍后重用。返回的切片不应更改！
@@ -2135,6 +2331,9 @@ type lookup struct {
 	lock    sync.RWMutex                       // 读写锁
 	locals  map[common.Hash]*types.Transaction // 本地交易映射
 	remotes map[common.Hash]*types.Transaction // 远程交易映射
+
+	auths map[common.Address][]common.Hash // Set code transactions carrying an authorization, grouped by authority
+	// auths 携带授权的 set code 交易，按授权者分组
 }
 
 // newLookup returns a new lookup structure.
@@ -2143,6 +2342,7 @@ func newLookup() *lookup {
 	return &lookup{
 		locals:  make(map[common.Hash]*types.Transaction), // 初始化本地交易映射
 		remotes: make(map[common.Hash]*types.Transaction), // 初始化远程交易映射
+		auths:   make(map[common.Address][]common.Hash),   // 初始化授权映射
 	}
 	// 逻辑注解：此函数初始化查找结构。关键逻辑是创建空映射以区分本地和远程交易。
 }
@@ -2249,9 +2449,18 @@ func (t *lookup) Slots() int {
 // Add adds a transaction to the lookup.
 // Add 将交易添加到查找中。
 func (t *lookup) Add(tx *types.Transaction, local bool) {
+	// Recover the authorities outside the lock. They are cached in the transaction,
+	// so Remove doesn't need to recover them again while holding it.
+	// 在锁外恢复授权者。它们缓存在交易中，因此 Remove 持有锁时无需再次恢复。
+	authorities := tx.SetCodeAuthorities()
+
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	for _, addr := range authorities {
+		t.auths[addr] = append(t.auths[addr], tx.Hash())
+	}
+
 	t.slots += numSlots(tx)           // 增加槽数
 	slotsGauge.Update(int64(t.slots)) // 更新槽计数器
 
@@ -2282,9 +2491,46 @@ func (t *lookup) Remove(hash common.Hash) {
 
 	delete(t.locals, hash)  // 从本地交易中删除
 	delete(t.remotes, hash) // 从远程交易中删除
+
+	for _, addr := range tx.SetCodeAuthorities() { // cached by Add, no recovery
+		hashes := slices.DeleteFunc(t.auths[addr], func(h common.Hash) bool { return h == hash })
+		if len(hashes) == 0 {
+			delete(t.auths, addr)
+		} else {
+			t.auths[addr] = hashes
+		}
+	}
 	// 逻辑注解：此函数移除交易并更新槽数。关键逻辑是确保从正确映射中删除并调整资源计数。
 }
 
+// HasAuthorizations reports whether the given account signed an authorization
+// of any tracked set code transaction.
+// HasAuthorizations 报告给定账户是否签署了任何被跟踪的 set code 交易的授权。
+func (t *lookup) HasAuthorizations(addr common.Address) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return len(t.auths[addr]) != 0
+}
+
+// Authorizations returns the tracked set code transactions carrying an
+// authorization signed by the given account.
+// Authorizations 返回携带由给定账户签署的授权的被跟踪 set code 交易。
+func (t *lookup) Authorizations(addr common.Address) []*types.Transaction {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	txs := make([]*types.Transaction, 0, len(t.auths[addr]))
+	for _, hash := range t.auths[addr] {
+		if tx := t.locals[hash]; tx != nil {
+			txs = append(txs, tx)
+		} else if tx := t.remotes[hash]; tx != nil {
+			txs = append(txs, tx)
+		}
+	}
+	return txs
+}
+
 // RemoteToLocals migrates the transactions belongs to the given locals to locals
 // set. The assumption is held the locals set is thread-safe to be used.
 // RemoteToLocals 将属于给定本地账户的交易迁移到本地集。假设本地集是线程安全的。
@@ -2304,6 +2550,25 @@ func (t *lookup) RemoteToLocals(locals *accountSet) int {
 	// 逻辑注解：此函数将远程交易迁移到本地集。关键逻辑是根据本地账户检查并移动交易。
 }
 
+// LocalsToRemotes migrates the given transactions from the locals set to the
+// remotes set, returning the ones that were actually moved.
+// LocalsToRemotes 将给定交易从本地集迁移到远程集，返回实际被迁移的交易。
+func (t *lookup) LocalsToRemotes(txs types.Transactions) types.Transactions {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var migrated types.Transactions
+	for _, tx := range txs {
+		hash := tx.Hash()
+		if _, ok := t.locals[hash]; ok {
+			t.remotes[hash] = tx   // 迁移到远程交易
+			delete(t.locals, hash) // 从本地交易中删除
+			migrated = append(migrated, tx)
+		}
+	}
+	return migrated
+}
+
 // RemotesBelowTip finds all remote transactions below the given tip threshold.
 // RemotesBelowTip 查找低于给定 tip 阈值的所有远程交易。
 func (t *lookup) RemotesBelowTip(threshold *big.Int) types.Transactions {
@@ -2378,3 +2643,135 @@ func (pool *LegacyPool) Clear() {
 	}
 	// 逻辑注解：此函数清除池中所有交易并重置状态，旋转日志以保留本地交易。关键逻辑是安全释放预留并重建池结构。
 }
+
+// Remove implements txpool.SubPool, evicting the transaction identified by hash
+// and all the higher-nonce transactions of the same sender.
+// Remove 实现 txpool.SubPool 接口，驱逐由哈希标识的交易以及同一发送者所有更高 nonce 的交易。
+func (pool *LegacyPool) Remove(hash common.Hash) int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	tx := pool.all.Get(hash)
+	if tx == nil {
+		return 0 // 如果交易不存在，返回 0
+	}
+	from, _ := types.Sender(pool.signer, tx) // already validated during insertion // 获取发送者，已验证
+	return pool.removeFrom(from, tx.Nonce())
+}
+
+// RemoveFrom implements txpool.SubPool, evicting all the transactions of the
+// given account from the pool.
+// RemoveFrom 实现 txpool.SubPool 接口，从池中驱逐给定账户的所有交易。
+func (pool *LegacyPool) RemoveFrom(addr common.Address) int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.removeFrom(addr, 0)
+}
+
+// removeFrom drops all the pending and queued transactions of an account with a
+// nonce at or above the given threshold.
+//
+// Note, this method assumes the pool lock is held!
+// removeFrom 丢弃账户中 nonce 大于或等于给定阈值的所有待处理和队列交易。
+// 注意，此方法假设已持有池锁！
+func (pool *LegacyPool) removeFrom(addr common.Address, nonce uint64) int {
+	var drop types.Transactions
+	if pending := pool.pending[addr]; pending != nil {
+		drop = append(drop, pending.Flatten()...)
+	}
+	if queued := pool.queue[addr]; queued != nil {
+		drop = append(drop, queued.Flatten()...)
+	}
+	// Remove the transactions starting from the highest nonce, otherwise every
+	// removal from the pending list would shuffle the rest back into the queue.
+	// 从最高 nonce 开始移除交易，否则每次从待处理列表中移除都会将其余交易挪回队列。
+	var dropped int
+	for i := len(drop) - 1; i >= 0; i-- {
+		if drop[i].Nonce() < nonce {
+			break
+		}
+		hash := drop[i].Hash()
+		pool.removeTx(hash, pool.all.GetRemote(hash) != nil, true)
+		dropped++
+	}
+	if dropped == 0 {
+		return 0
+	}
+	log.Info("Removed pooled transactions", "from", addr, "nonce", nonce, "dropped", dropped)
+
+	// Regenerate the journal for local accounts, otherwise the evicted transactions
+	// would be resurrected on the next restart.
+	// 为本地账户重新生成日志，否则被驱逐的交易将在下次重启时复活。
+	if pool.journal != nil && pool.locals.contains(addr) {
+		if err := pool.journal.rotate(pool.local()); err != nil {
+			log.Warn("Failed to rotate local tx journal", "err", err)
+		}
+	}
+	return dropped
+}
+
+// SetLocal implements txpool.SubPool, marking or unmarking an account as local
+// and migrating its already pooled transactions accordingly.
+// SetLocal 实现 txpool.SubPool 接口，将账户标记或取消标记为本地账户，并相应地迁移其已在池中的交易。
+func (pool *LegacyPool) SetLocal(addr common.Address, local bool) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if local {
+		if pool.config.NoLocals {
+			log.Warn("Local transaction handling disabled, ignoring local account", "address", addr)
+			return
+		}
+		if pool.locals.contains(addr) {
+			return
+		}
+		log.Info("Setting new local account", "address", addr)
+		pool.locals.add(addr)
+
+		migrated := pool.all.RemoteToLocals(pool.locals) // 将远程交易迁移为本地
+		pool.priced.Removed(migrated)
+		localGauge.Inc(int64(migrated))
+	} else {
+		if !pool.locals.contains(addr) {
+			return
+		}
+		log.Info("Removing local account", "address", addr)
+		pool.locals.remove(addr)
+
+		var txs types.Transactions
+		if pending := pool.pending[addr]; pending != nil {
+			txs = append(txs, pending.Flatten()...)
+		}
+		if queued := pool.queue[addr]; queued != nil {
+			txs = append(txs, queued.Flatten()...)
+		}
+		// Demoted transactions need to be tracked by the price heap again to be
+		// subject to the regular eviction rules.
+		// 降级的交易需要重新由价格堆跟踪，以便受常规驱逐规则约束。
+		for _, tx := range pool.all.LocalsToRemotes(txs) {
+			pool.priced.Put(tx, false)
+			localGauge.Dec(1)
+		}
+	}
+	// Regenerate the journal to reflect the updated set of local transactions
+	// 重新生成日志以反映更新后的本地交易集合
+	if pool.journal != nil {
+		if err := pool.journal.rotate(pool.local()); err != nil {
+			log.Warn("Failed to rotate local tx journal", "err", err)
+		}
+	}
+}
+
+// FlushJournal implements txpool.SubPool, regenerating the local transaction
+// journal from the current contents of the pool.
+// FlushJournal 实现 txpool.SubPool 接口，根据池的当前内容重新生成本地交易日志。
+func (pool *LegacyPool) FlushJournal() error {
+	if pool.journal == nil {
+		return nil // 如果日志未启用，直接返回
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.journal.rotate(pool.local())
+}
@@ -24,6 +24,8 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -1230,6 +1232,48 @@ func TestPendingGlobalLimiting(t *testing.T) {
 	}
 }
 
+// Tests that lowering the limits of a running pool drops the transactions above
+// the new allowance.
+func TestSetLimits(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.GlobalSlots = config.AccountSlots * 10
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	keys := make([]*ecdsa.PrivateKey, 5)
+	for i := 0; i < len(keys); i++ {
+		keys[i], _ = crypto.GenerateKey()
+		testAddBalance(pool, crypto.PubkeyToAddress(keys[i].PublicKey), big.NewInt(1000000))
+	}
+	txs := types.Transactions{}
+	for _, key := range keys {
+		for j := 0; j < int(config.GlobalSlots)/len(keys); j++ {
+			txs = append(txs, transaction(uint64(j), 100000, key))
+		}
+	}
+	pool.addRemotesSync(txs)
+	if pending, _ := pool.Stats(); pending != int(config.GlobalSlots) {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, config.GlobalSlots)
+	}
+	// Lower the global limit, the excess must be dropped
+	config.GlobalSlots = config.AccountSlots * uint64(len(keys))
+	pool.SetLimits(config)
+
+	if pending, _ := pool.Stats(); pending > int(config.GlobalSlots) {
+		t.Fatalf("total pending transactions overflow new allowance: %d > %d", pending, config.GlobalSlots)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
 // Test the limit on transaction size is enforced correctly.
 // This test verifies every transaction having allowed size
 // is added to the pool, and longer transactions are rejected.
@@ -2665,3 +2709,504 @@ func BenchmarkMultiAccountBatchInsert(b *testing.B) {
 		pool.addRemotesSync([]*types.Transaction{tx})
 	}
 }
+
+// Tests that removing a transaction by hash also evicts all the subsequent
+// transactions of the same sender, but leaves the earlier ones and other
+// accounts intact.
+func TestRemoveCascading(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	other, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(other.PublicKey), big.NewInt(1000000))
+
+	txs := []*types.Transaction{
+		transaction(0, 100000, key),
+		transaction(1, 100000, key),
+		transaction(2, 100000, key),
+		transaction(4, 100000, key),
+		transaction(0, 100000, other),
+	}
+	for i, err := range pool.addRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	if dropped := pool.Remove(txs[1].Hash()); dropped != 3 {
+		t.Fatalf("dropped transaction mismatch: have %d, want %d", dropped, 3)
+	}
+	pending, queued := pool.Stats()
+	if pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	if queued != 0 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 0)
+	}
+	if nonce := pool.Nonce(account); nonce != 1 {
+		t.Fatalf("pending nonce mismatch: have %d, want %d", nonce, 1)
+	}
+	if dropped := pool.Remove(txs[1].Hash()); dropped != 0 {
+		t.Fatalf("dropped unknown transaction: have %d, want %d", dropped, 0)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that all the transactions of an account can be removed at once and
+// that the account reservation is released afterwards.
+func TestRemoveFrom(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	txs := []*types.Transaction{
+		transaction(0, 100000, key),
+		transaction(1, 100000, key),
+		transaction(3, 100000, key),
+	}
+	for i, err := range pool.addRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	if dropped := pool.RemoveFrom(account); dropped != 3 {
+		t.Fatalf("dropped transaction mismatch: have %d, want %d", dropped, 3)
+	}
+	if pending, queued := pool.Stats(); pending+queued != 0 {
+		t.Fatalf("pool not empty: pending %d, queued %d", pending, queued)
+	}
+	// Re-adding a transaction should succeed, the test reserver panics if the
+	// address was not released.
+	if err := pool.addRemoteSync(txs[0]); err != nil {
+		t.Fatalf("failed to re-add transaction: %v", err)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that accounts can be marked and unmarked as local at runtime, with
+// their pooled transactions migrating between the local and remote sets.
+func TestSetLocal(t *testing.T) {
+	t.Parallel()
+
+	pool, key := setupPool()
+	defer pool.Close()
+
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	testAddBalance(pool, account, big.NewInt(1000000))
+
+	if err := pool.addRemoteSync(transaction(0, 100000, key)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	pool.SetLocal(account, true)
+	if local, remote := pool.all.LocalCount(), pool.all.RemoteCount(); local != 1 || remote != 0 {
+		t.Fatalf("transaction set mismatch: have %d/%d local/remote, want %d/%d", local, remote, 1, 0)
+	}
+	if locals := pool.Locals(); len(locals) != 1 || locals[0] != account {
+		t.Fatalf("local accounts mismatch: have %v, want %v", locals, []common.Address{account})
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	pool.SetLocal(account, false)
+	if local, remote := pool.all.LocalCount(), pool.all.RemoteCount(); local != 0 || remote != 1 {
+		t.Fatalf("transaction set mismatch: have %d/%d local/remote, want %d/%d", local, remote, 0, 1)
+	}
+	if locals := pool.Locals(); len(locals) != 0 {
+		t.Fatalf("local accounts mismatch: have %v, want none", locals)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the full pool content, remote transactions and queued ones included,
+// survives a restart if snapshotting is enabled, and that stale transactions are
+// dropped on reload.
+func TestSnapshotting(t *testing.T) {
+	t.Parallel()
+
+	snapshot := filepath.Join(t.TempDir(), "txpool.rlp")
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.Snapshot = snapshot
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+
+	first, _ := crypto.GenerateKey()
+	second, _ := crypto.GenerateKey()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(first.PublicKey), big.NewInt(1000000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(second.PublicKey), big.NewInt(1000000000))
+
+	txs := []*types.Transaction{
+		pricedTransaction(0, 100000, big.NewInt(1), first),
+		pricedTransaction(1, 100000, big.NewInt(1), first),
+		pricedTransaction(3, 100000, big.NewInt(1), first),
+		pricedTransaction(0, 100000, big.NewInt(1), second),
+	}
+	arrival := time.Unix(1700000000, 0)
+	txs[3].SetTime(arrival)
+
+	for i, err := range pool.addRemotesSync(txs) {
+		if err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	pool.Close()
+
+	// Include the first transaction of the first account and restart the pool
+	statedb.SetNonce(crypto.PubkeyToAddress(first.PublicKey), 1)
+	blockchain = newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	pool = New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	pending, queued := pool.Stats()
+	if pending != 2 {
+		t.Fatalf("pending transactions mismatched: have %d, want %d", pending, 2)
+	}
+	if queued != 1 {
+		t.Fatalf("queued transactions mismatched: have %d, want %d", queued, 1)
+	}
+	if tx := pool.Get(txs[3].Hash()); tx == nil || !tx.Time().Equal(arrival) {
+		t.Fatalf("arrival time not restored")
+	}
+	if _, err := os.Stat(snapshot); !os.IsNotExist(err) {
+		t.Fatalf("snapshot not removed after load: %v", err)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that pool snapshots survive a write and read round trip, keeping the
+// arrival times and local flags of the transactions, and that damaged snapshot
+// files are reported.
+func TestSnapshotReadWrite(t *testing.T) {
+	t.Parallel()
+
+	var (
+		path   = filepath.Join(t.TempDir(), "txpool.rlp")
+		key, _ = crypto.GenerateKey()
+	)
+	// Missing snapshots are empty
+	if entries, err := ReadSnapshot(path); err != nil || len(entries) != 0 {
+		t.Fatalf("missing snapshot: have %d entries, err %v", len(entries), err)
+	}
+	entries := []*SnapshotEntry{
+		newSnapshotEntry(pricedTransaction(0, 100000, big.NewInt(1), key), true),
+		newSnapshotEntry(pricedTransaction(1, 100000, big.NewInt(1), key), false),
+		newSnapshotEntry(dynamicFeeTx(2, 100000, big.NewInt(2), big.NewInt(1), key), false),
+	}
+	entries[1].Time = uint64(time.Unix(1700000000, 0).UnixNano())
+
+	if err := WriteSnapshot(path, entries); err != nil {
+		t.Fatalf("failed to write snapshot: %v", err)
+	}
+	if _, err := os.Stat(path + ".new"); !os.IsNotExist(err) {
+		t.Fatalf("temporary snapshot file left behind: %v", err)
+	}
+	loaded, err := ReadSnapshot(path)
+	if err != nil {
+		t.Fatalf("failed to read snapshot: %v", err)
+	}
+	if len(loaded) != len(entries) {
+		t.Fatalf("entry count mismatch: have %d, want %d", len(loaded), len(entries))
+	}
+	for i, entry := range loaded {
+		if entry.Tx.Hash() != entries[i].Tx.Hash() || entry.Time != entries[i].Time || entry.Local != entries[i].Local {
+			t.Errorf("entry %d mismatch: have %x/%d/%v, want %x/%d/%v", i, entry.Tx.Hash(), entry.Time, entry.Local, entries[i].Tx.Hash(), entries[i].Time, entries[i].Local)
+		}
+		if have, want := entry.Transaction().Time(), time.Unix(0, int64(entries[i].Time)); !have.Equal(want) {
+			t.Errorf("entry %d: arrival time mismatch: have %v, want %v", i, have, want)
+		}
+	}
+	// A truncated snapshot yields the entries before the damage and an error
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, blob[:len(blob)-10], 0644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err = ReadSnapshot(path)
+	if err == nil {
+		t.Fatalf("truncated snapshot read without error")
+	}
+	if len(loaded) != len(entries)-1 {
+		t.Fatalf("truncated snapshot entry count mismatch: have %d, want %d", len(loaded), len(entries)-1)
+	}
+	// Garbage is rejected outright
+	if err := os.WriteFile(path, []byte("not a snapshot"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if loaded, err := ReadSnapshot(path); err == nil || len(loaded) != 0 {
+		t.Fatalf("corrupt snapshot: have %d entries, err %v", len(loaded), err)
+	}
+}
+
+// Tests that the snapshot restores local transactions as local ones, drops the
+// entries which are no longer valid, and loads the intact prefix of a truncated
+// snapshot.
+func TestSnapshottingRevalidation(t *testing.T) {
+	t.Parallel()
+
+	snapshot := filepath.Join(t.TempDir(), "txpool.rlp")
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.Snapshot = snapshot
+
+	pool := New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+
+	local, _ := crypto.GenerateKey()
+	remote, _ := crypto.GenerateKey()
+	broke, _ := crypto.GenerateKey()
+
+	testAddBalance(pool, crypto.PubkeyToAddress(local.PublicKey), big.NewInt(1000000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(broke.PublicKey), big.NewInt(1000000000))
+
+	if err := pool.addLocal(pricedTransaction(0, 100000, big.NewInt(1), local)); err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	remotes := []*types.Transaction{
+		pricedTransaction(0, 100000, big.NewInt(1), remote),
+		pricedTransaction(0, 100000, big.NewInt(1), broke),
+	}
+	for i, err := range pool.addRemotesSync(remotes) {
+		if err != nil {
+			t.Fatalf("tx %d: failed to add transaction: %v", i, err)
+		}
+	}
+	pool.Close()
+
+	// Drain the funds of an account, its transaction must not be restored
+	statedb.SetBalance(crypto.PubkeyToAddress(broke.PublicKey), new(uint256.Int), tracing.BalanceChangeUnspecified)
+	blockchain = newTestBlockChain(params.TestChainConfig, 1000000, statedb, new(event.Feed))
+
+	pool = New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+
+	if pending, queued := pool.Stats(); pending != 2 || queued != 0 {
+		t.Fatalf("transaction count mismatch: have %d/%d, want %d/%d", pending, queued, 2, 0)
+	}
+	if pool.Has(remotes[1].Hash()) {
+		t.Fatalf("unfunded transaction restored")
+	}
+	if !pool.locals.contains(crypto.PubkeyToAddress(local.PublicKey)) {
+		t.Fatalf("local account not restored")
+	}
+	if pool.locals.contains(crypto.PubkeyToAddress(remote.PublicKey)) {
+		t.Fatalf("remote account restored as local")
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+	pool.Close()
+
+	// Damage the end of the snapshot, the intact entries are still loaded
+	blob, err := os.ReadFile(snapshot)
+	if err != nil {
+		t.Fatalf("snapshot not written on shutdown: %v", err)
+	}
+	if err := os.WriteFile(snapshot, blob[:len(blob)-10], 0644); err != nil {
+		t.Fatal(err)
+	}
+	pool = New(config, blockchain)
+	pool.Init(config.PriceLimit, blockchain.CurrentBlock(), makeAddressReserver())
+	defer pool.Close()
+
+	if pending, queued := pool.Stats(); pending != 1 || queued != 0 {
+		t.Fatalf("transaction count mismatch after truncation: have %d/%d, want %d/%d", pending, queued, 1, 0)
+	}
+	if _, err := os.Stat(snapshot); !os.IsNotExist(err) {
+		t.Fatalf("damaged snapshot not removed after load: %v", err)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+type unsignedAuth struct {
+	nonce uint64
+	key   *ecdsa.PrivateKey
+}
+
+func setCodeTx(nonce uint64, key *ecdsa.PrivateKey, unsigned []unsignedAuth) *types.Transaction {
+	var authList []types.SetCodeAuthorization
+	for _, u := range unsigned {
+		auth, _ := types.SignSetCode(u.key, types.SetCodeAuthorization{
+			ChainID: *uint256.MustFromBig(params.MergedTestChainConfig.ChainID),
+			Address: common.Address{0x42},
+			Nonce:   u.nonce,
+		})
+		authList = append(authList, auth)
+	}
+	return types.MustSignNewTx(key, types.LatestSignerForChainID(params.MergedTestChainConfig.ChainID), &types.SetCodeTx{
+		ChainID:   uint256.MustFromBig(params.MergedTestChainConfig.ChainID),
+		Nonce:     nonce,
+		GasTipCap: uint256.NewInt(1),
+		GasFeeCap: uint256.NewInt(1000),
+		Gas:       500000,
+		To:        common.Address{},
+		Value:     uint256.NewInt(100),
+		AuthList:  authList,
+	})
+}
+
+// Tests that the pool enforces the EIP-7702 delegation rules: limited in-flight
+// transactions for delegated accounts and those with pending authorizations,
+// and no authorization nonces colliding with pooled transactions.
+func TestSetCodeTransactions(t *testing.T) {
+	t.Parallel()
+
+	pool, keyA := setupPoolWithConfig(params.MergedTestChainConfig)
+	defer pool.Close()
+
+	keyB, _ := crypto.GenerateKey()
+	keyC, _ := crypto.GenerateKey()
+	keyD, _ := crypto.GenerateKey()
+
+	addrA := crypto.PubkeyToAddress(keyA.PublicKey)
+	addrB := crypto.PubkeyToAddress(keyB.PublicKey)
+	addrC := crypto.PubkeyToAddress(keyC.PublicKey)
+	addrD := crypto.PubkeyToAddress(keyD.PublicKey)
+
+	for _, addr := range []common.Address{addrA, addrB, addrC, addrD} {
+		testAddBalance(pool, addr, big.NewInt(params.Ether))
+	}
+	// Delegate D in the state, it may only have a single transaction in flight
+	pool.currentState.SetCode(addrD, types.AddressToDelegation(common.Address{0x42}))
+
+	if err := pool.addRemoteSync(dynamicFeeTx(0, 100000, big.NewInt(1000), big.NewInt(1), keyD)); err != nil {
+		t.Fatalf("failed to add first delegated transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(dynamicFeeTx(1, 100000, big.NewInt(1000), big.NewInt(1), keyD)); !errors.Is(err, txpool.ErrInflightTxLimitReached) {
+		t.Fatalf("second delegated transaction error mismatch: have %v, want %v", err, txpool.ErrInflightTxLimitReached)
+	}
+	if err := pool.addRemoteSync(dynamicFeeTx(0, 100000, big.NewInt(2000), big.NewInt(2), keyD)); err != nil {
+		t.Fatalf("failed to replace delegated transaction: %v", err)
+	}
+	// An authorization from an account with a pooled transaction at the same
+	// nonce is rejected, one at a different nonce is accepted
+	if err := pool.addRemoteSync(dynamicFeeTx(0, 100000, big.NewInt(1000), big.NewInt(1), keyB)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(setCodeTx(0, keyA, []unsignedAuth{{0, keyB}})); !errors.Is(err, txpool.ErrAuthorizationConflict) {
+		t.Fatalf("conflicting authorization error mismatch: have %v, want %v", err, txpool.ErrAuthorizationConflict)
+	}
+	if err := pool.addRemoteSync(setCodeTx(0, keyA, []unsignedAuth{{1, keyB}})); err != nil {
+		t.Fatalf("failed to add set code transaction: %v", err)
+	}
+	// B has a pending authorization now, its in-flight transactions are limited
+	if err := pool.addRemoteSync(dynamicFeeTx(1, 100000, big.NewInt(1000), big.NewInt(1), keyB)); !errors.Is(err, txpool.ErrInflightTxLimitReached) {
+		t.Fatalf("pending delegation transaction error mismatch: have %v, want %v", err, txpool.ErrInflightTxLimitReached)
+	}
+	if txs := pool.Authorizations(addrB); len(txs) != 1 {
+		t.Fatalf("pending authorizations mismatch: have %d, want %d", len(txs), 1)
+	}
+	// Authorizations from the same authority may not reuse a nonce
+	if err := pool.addRemoteSync(setCodeTx(0, keyC, []unsignedAuth{{1, keyB}})); !errors.Is(err, txpool.ErrAuthorizationConflict) {
+		t.Fatalf("duplicate authorization error mismatch: have %v, want %v", err, txpool.ErrAuthorizationConflict)
+	}
+	// Authorities with more than the allowed in-flight transactions are rejected
+	if err := pool.addRemoteSync(dynamicFeeTx(0, 100000, big.NewInt(1000), big.NewInt(1), keyC)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(dynamicFeeTx(1, 100000, big.NewInt(1000), big.NewInt(1), keyC)); err != nil {
+		t.Fatalf("failed to add transaction: %v", err)
+	}
+	if err := pool.addRemoteSync(setCodeTx(1, keyA, []unsignedAuth{{5, keyC}})); !errors.Is(err, txpool.ErrAuthorityReserved) {
+		t.Fatalf("reserved authority error mismatch: have %v, want %v", err, txpool.ErrAuthorityReserved)
+	}
+	// Replacing the set code transaction doesn't conflict with its own authorizations
+	replacement := types.MustSignNewTx(keyA, types.LatestSignerForChainID(params.MergedTestChainConfig.ChainID), &types.SetCodeTx{
+		ChainID:   uint256.MustFromBig(params.MergedTestChainConfig.ChainID),
+		Nonce:     0,
+		GasTipCap: uint256.NewInt(10),
+		GasFeeCap: uint256.NewInt(10000),
+		Gas:       500000,
+		AuthList:  pool.Authorizations(addrB)[0].SetCodeAuthorizations(),
+	})
+	if err := pool.addRemoteSync(replacement); err != nil {
+		t.Fatalf("failed to replace set code transaction: %v", err)
+	}
+	if txs := pool.Authorizations(addrB); len(txs) != 1 || txs[0].Hash() != replacement.Hash() {
+		t.Fatalf("pending authorizations mismatch after replacement: have %v, want [%v]", txs, replacement.Hash())
+	}
+	// Evicting the set code transaction releases the pending delegation
+	pool.Remove(pool.Authorizations(addrB)[0].Hash())
+	if txs := pool.Authorizations(addrB); len(txs) != 0 {
+		t.Fatalf("pending authorizations mismatch after removal: have %d, want %d", len(txs), 0)
+	}
+	if err := pool.addRemoteSync(dynamicFeeTx(1, 100000, big.NewInt(1000), big.NewInt(1), keyB)); err != nil {
+		t.Fatalf("failed to add transaction after delegation removal: %v", err)
+	}
+	if err := validatePoolInternals(pool); err != nil {
+		t.Fatalf("pool internal state corrupted: %v", err)
+	}
+}
+
+// Tests that the senders of locally submitted transactions are reported by the
+// transaction pool even if the legacy pool is configured to not track locals,
+// and that they are forgotten once their transactions leave the pool.
+func TestLocalSendersNoLocals(t *testing.T) {
+	t.Parallel()
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	blockchain := newTestBlockChain(params.TestChainConfig, 10000000, statedb, new(event.Feed))
+
+	config := testTxPoolConfig
+	config.NoLocals = true
+	pool := New(config, blockchain)
+	txs, err := txpool.New(config.PriceLimit, blockchain, []txpool.SubPool{pool})
+	if err != nil {
+		t.Fatalf("failed to create transaction pool: %v", err)
+	}
+	defer txs.Close()
+
+	local, _ := crypto.GenerateKey()
+	remote, _ := crypto.GenerateKey()
+	testAddBalance(pool, crypto.PubkeyToAddress(local.PublicKey), big.NewInt(1000000))
+	testAddBalance(pool, crypto.PubkeyToAddress(remote.PublicKey), big.NewInt(1000000))
+
+	if err := txs.Add([]*types.Transaction{transaction(0, 100000, local)}, true, true)[0]; err != nil {
+		t.Fatalf("failed to add local transaction: %v", err)
+	}
+	if err := txs.Add([]*types.Transaction{transaction(0, 100000, remote)}, false, true)[0]; err != nil {
+		t.Fatalf("failed to add remote transaction: %v", err)
+	}
+	if locals := txs.Locals(); len(locals) != 0 {
+		t.Fatalf("locals tracked despite being disabled: %v", locals)
+	}
+	want := []common.Address{crypto.PubkeyToAddress(local.PublicKey)}
+	if senders := txs.LocalSenders(); !slices.Equal(senders, want) {
+		t.Fatalf("local senders mismatch: have %v, want %v", senders, want)
+	}
+	// Once the transactions are gone, the sender is forgotten
+	txs.RemoveFrom(want[0])
+	if senders := txs.LocalSenders(); len(senders) != 0 {
+		t.Fatalf("local senders retained after removal: %v", senders)
+	}
+}
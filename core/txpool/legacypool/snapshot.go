@@ -0,0 +1,198 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package legacypool
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// 交易池快照 (Transaction Pool Snapshot)
+//
+// 与只记录本地交易的 journal 不同，快照在节点关闭时保存整个交易池（待处理和排队的交易）以及它们的到达时间，
+// 在启动时重新验证并加载，避免 RPC 节点每次重启都需要花费数分钟从对等节点重新填充内存池。
+// 同样的文件格式也被 `geth txpool export/import` 命令使用，以便在节点之间迁移内存池。
+
+// SnapshotEntry is a single transaction stored in a pool snapshot, along with
+// the metadata needed to faithfully restore it into a pool.
+// SnapshotEntry 是存储在交易池快照中的单个交易，以及将其忠实恢复到池中所需的元数据。
+type SnapshotEntry struct {
+	Tx *types.Transaction // Transaction to restore into the pool
+	// Tx 需要恢复到池中的交易
+	Time uint64 // Time when the transaction was first seen, in unix nanoseconds
+	// Time 首次看到交易的时间，以 unix 纳秒表示
+	Local bool // Whether the transaction was tracked as local
+	// Local 交易是否被作为本地交易跟踪
+}
+
+// newSnapshotEntry wraps a pooled transaction into a snapshot entry.
+// newSnapshotEntry 将池中的交易包装为快照条目。
+func newSnapshotEntry(tx *types.Transaction, local bool) *SnapshotEntry {
+	return &SnapshotEntry{
+		Tx:    tx,
+		Time:  uint64(tx.Time().UnixNano()),
+		Local: local,
+	}
+}
+
+// Transaction returns the wrapped transaction with its original arrival time
+// restored.
+// Transaction 返回包装的交易，并恢复其原始到达时间。
+func (entry *SnapshotEntry) Transaction() *types.Transaction {
+	entry.Tx.SetTime(time.Unix(0, int64(entry.Time)))
+	return entry.Tx
+}
+
+// ReadSnapshot parses a transaction pool snapshot from disk. A missing file is
+// not an error, rather an empty snapshot.
+// ReadSnapshot 从磁盘解析交易池快照。文件不存在不是错误，而是一个空快照。
+func ReadSnapshot(path string) ([]*SnapshotEntry, error) {
+	input, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+
+	var (
+		stream  = rlp.NewStream(input, 0)
+		entries []*SnapshotEntry
+	)
+	for {
+		entry := new(SnapshotEntry)
+		if err := stream.Decode(entry); err != nil {
+			if err == io.EOF {
+				return entries, nil
+			}
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// WriteSnapshot atomically replaces the transaction pool snapshot on disk with
+// the given entries.
+// WriteSnapshot 使用给定条目原子性地替换磁盘上的交易池快照。
+func WriteSnapshot(path string, entries []*SnapshotEntry) error {
+	output, err := os.OpenFile(path+".new", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = rlp.Encode(output, entry); err != nil {
+			output.Close()
+			return err
+		}
+	}
+	if err = output.Close(); err != nil {
+		return err
+	}
+	return os.Rename(path+".new", path)
+}
+
+// loadSnapshot reads the full pool snapshot persisted on the last shutdown and
+// injects its content into the pool, revalidating every transaction against the
+// current chain state. The snapshot is deleted afterwards to avoid resurrecting
+// transactions evicted during the lifetime of this pool on a crash.
+// loadSnapshot 读取上次关闭时持久化的完整池快照，并将其内容注入到池中，根据当前链状态重新验证每个交易。
+// 之后删除快照，以避免在崩溃时复活在本池生命周期内被驱逐的交易。
+func (pool *LegacyPool) loadSnapshot() error {
+	entries, err := ReadSnapshot(pool.config.Snapshot)
+	if err != nil && len(entries) == 0 {
+		return err
+	}
+	var (
+		locals  []*types.Transaction
+		remotes []*types.Transaction
+		dropped int
+	)
+	for _, entry := range entries {
+		if entry.Local {
+			locals = append(locals, entry.Transaction())
+		} else {
+			remotes = append(remotes, entry.Transaction())
+		}
+	}
+	// Import the transactions in small-ish batches, similar to the journal. Any
+	// failure (already included, underpriced, nonce too low) is simply dropped.
+	// 以较小的批次导入交易，与日志类似。任何失败（已被包含、价格过低、nonce 过低）都直接丢弃。
+	for _, batch := range []struct {
+		txs   []*types.Transaction
+		local bool
+	}{{locals, true}, {remotes, false}} {
+		for start := 0; start < len(batch.txs); start += 1024 {
+			end := min(start+1024, len(batch.txs))
+			for _, err := range pool.Add(batch.txs[start:end], batch.local, true) {
+				if err != nil && !errors.Is(err, txpool.ErrAlreadyKnown) {
+					log.Trace("Failed to add snapshotted transaction", "err", err)
+					dropped++
+				}
+			}
+		}
+	}
+	log.Info("Loaded transaction pool snapshot", "transactions", len(entries), "dropped", dropped)
+
+	if rmErr := os.Remove(pool.config.Snapshot); rmErr != nil && !errors.Is(rmErr, fs.ErrNotExist) {
+		log.Warn("Failed to remove transaction pool snapshot", "err", rmErr)
+	}
+	return err
+}
+
+// saveSnapshot persists the entire content of the pool, pending as well as
+// queued transactions, to disk.
+// saveSnapshot 将池的全部内容（待处理和排队交易）持久化到磁盘。
+func (pool *LegacyPool) saveSnapshot() error {
+	pool.mu.RLock()
+	entries := pool.snapshot()
+	pool.mu.RUnlock()
+
+	if err := WriteSnapshot(pool.config.Snapshot, entries); err != nil {
+		return err
+	}
+	log.Info("Saved transaction pool snapshot", "transactions", len(entries))
+	return nil
+}
+
+// snapshot gathers the content of the pool into a list of snapshot entries,
+// grouped by account and sorted by nonce.
+//
+// Note, this method assumes the pool lock is held!
+// snapshot 将池的内容收集为快照条目列表，按账户分组并按 nonce 排序。
+// 注意，此方法假设已持有池锁！
+func (pool *LegacyPool) snapshot() []*SnapshotEntry {
+	entries := make([]*SnapshotEntry, 0, pool.all.Count())
+	for _, set := range []map[common.Address]*list{pool.pending, pool.queue} {
+		for addr, list := range set {
+			local := pool.locals.contains(addr)
+			for _, tx := range list.Flatten() {
+				entries = append(entries, newSnapshotEntry(tx, local))
+			}
+		}
+	}
+	return entries
+}
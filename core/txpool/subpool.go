@@ -215,6 +215,11 @@ type SubPool interface {
 	// ContentFrom 检索交易池的数据内容，返回此地址的待处理和排队的交易，按 nonce 分组。
 	ContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction)
 
+	// Authorizations retrieves the pooled set code transactions (EIP-7702) that
+	// carry an authorization signed by the given address.
+	// Authorizations 检索携带由给定地址签署的授权的池内 set code 交易（EIP-7702）。
+	Authorizations(addr common.Address) []*types.Transaction
+
 	// Locals retrieves the accounts currently considered local by the pool.
 	// Locals 检索池中当前被认为是本地的账户。
 	Locals() []common.Address
@@ -227,4 +232,28 @@ type SubPool interface {
 	// Clear removes all tracked transactions from the pool
 	// Clear 从池中删除所有跟踪的交易。
 	Clear()
+
+	// Remove evicts the transaction identified by hash from the pool, together
+	// with all the higher-nonce transactions of the same sender that would be
+	// rendered non-executable by the gap. The number of dropped transactions
+	// is returned.
+	// Remove 从池中驱逐由哈希标识的交易，以及同一发送者因出现间隙而变得不可执行的所有更高 nonce 的交易。
+	// 返回被丢弃的交易数量。
+	Remove(hash common.Hash) int
+
+	// RemoveFrom evicts all the transactions originating from the given account,
+	// returning the number of dropped transactions.
+	// RemoveFrom 驱逐来自给定账户的所有交易，返回被丢弃的交易数量。
+	RemoveFrom(addr common.Address) int
+
+	// SetLocal marks or unmarks an account as local at runtime, exempting its
+	// transactions from (or subjecting them again to) the pricing and eviction
+	// rules of the pool.
+	// SetLocal 在运行时将账户标记或取消标记为本地账户，使其交易免于（或重新受制于）池的定价和驱逐规则。
+	SetLocal(addr common.Address, local bool)
+
+	// FlushJournal persists the currently tracked transactions that are meant
+	// to survive a restart to disk on demand.
+	// FlushJournal 按需将当前跟踪的、需要在重启后保留的交易持久化到磁盘。
+	FlushJournal() error
 }
@@ -87,6 +87,11 @@ type TxPool struct {
 	reserveLock sync.Mutex // Lock protecting the account reservations
 	// reserveLock 保护账户预留的互斥锁。
 
+	submitted map[common.Address]struct{} // Senders of locally submitted transactions
+	// submitted 本地提交交易的发送者集合。
+	submitLock sync.Mutex // Lock protecting the locally submitted senders
+	// submitLock 保护本地提交发送者集合的互斥锁。
+
 	subs event.SubscriptionScope // Subscription scope to unsubscribe all on shutdown
 	// subs 用于在关闭时取消所有订阅的订阅范围。
 	quit chan chan error // Quit channel to tear down the head updater
@@ -111,6 +116,7 @@ func New(gasTip uint64, chain BlockChain, subpools []SubPool) (*TxPool, error) {
 	pool := &TxPool{
 		subpools:     subpools,
 		reservations: make(map[common.Address]SubPool),
+		submitted:    make(map[common.Address]struct{}),
 		quit:         make(chan chan error),
 		term:         make(chan struct{}),
 		sync:         make(chan chan error),
@@ -441,6 +447,24 @@ func (p *TxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
 		errs[i] = errsets[split][0]
 		errsets[split] = errsets[split][1:]
 	}
+	// Remember the senders of the accepted local transactions, independent of
+	// whether the subpools treat them as local
+	// 记住被接受的本地交易的发送者，不论子池是否将其视为本地交易。
+	if local {
+		p.submitLock.Lock()
+		for i, tx := range txs {
+			if errs[i] != nil {
+				continue
+			}
+			// The subpool already validated the signature, any signer of the
+			// transaction's chain recovers the same sender
+			// 子池已经验证过签名，交易所在链的任何签名者都会恢复出相同的发送者。
+			if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+				p.submitted[from] = struct{}{}
+			}
+		}
+		p.submitLock.Unlock()
+	}
 	return errs
 }
 
@@ -539,6 +563,18 @@ func (p *TxPool) ContentFrom(addr common.Address) ([]*types.Transaction, []*type
 	return []*types.Transaction{}, []*types.Transaction{}
 }
 
+// Authorizations retrieves the pooled set code transactions (EIP-7702) that
+// carry an authorization signed by the given address, i.e. its pending
+// delegations.
+// Authorizations 检索携带由给定地址签署的授权的池内 set code 交易（EIP-7702），即其待处理的委托。
+func (p *TxPool) Authorizations(addr common.Address) []*types.Transaction {
+	var txs []*types.Transaction
+	for _, subpool := range p.subpools {
+		txs = append(txs, subpool.Authorizations(addr)...)
+	}
+	return txs
+}
+
 // Locals retrieves the accounts currently considered local by the pool.
 // Locals 检索池中当前被认为是本地的账户。
 func (p *TxPool) Locals() []common.Address {
@@ -559,6 +595,41 @@ func (p *TxPool) Locals() []common.Address {
 	return flat
 }
 
+// LocalSenders retrieves the accounts which have transactions in the pool that
+// were submitted locally, together with the accounts considered local by the
+// subpools. Unlike Locals, it doesn't depend on the subpools tracking local
+// accounts, so it also covers blob transactions and pools configured without
+// local accounts. Senders whose transactions all left the pool are forgotten.
+// LocalSenders 检索在池中拥有本地提交交易的账户，以及被子池视为本地的账户。
+// 与 Locals 不同，它不依赖子池跟踪本地账户，因此也涵盖 blob 交易和未配置本地账户的池。
+// 所有交易都已离开池的发送者会被遗忘。
+func (p *TxPool) LocalSenders() []common.Address {
+	senders := make(map[common.Address]struct{})
+	for _, local := range p.Locals() {
+		senders[local] = struct{}{}
+	}
+	// Subpools hold the reservation of an account as long as they track any of
+	// its transactions, use that to drop the senders which left the pool
+	// 子池只要还跟踪某账户的任何交易就会持有该账户的预留，借此丢弃已离开池的发送者。
+	p.submitLock.Lock()
+	p.reserveLock.Lock()
+	for addr := range p.submitted {
+		if _, ok := p.reservations[addr]; !ok {
+			delete(p.submitted, addr)
+			continue
+		}
+		senders[addr] = struct{}{}
+	}
+	p.reserveLock.Unlock()
+	p.submitLock.Unlock()
+
+	flat := make([]common.Address, 0, len(senders))
+	for addr := range senders {
+		flat = append(flat, addr)
+	}
+	return flat
+}
+
 // Status returns the known status (unknown/pending/queued) of a transaction
 // identified by its hash.
 // Status 返回由其哈希标识的交易的已知状态（未知/待处理/排队）。
@@ -599,3 +670,52 @@ func (p *TxPool) Clear() {
 		subpool.Clear()
 	}
 }
+
+// Remove evicts the transaction identified by hash from whichever subpool is
+// tracking it, along with all the subsequent transactions of the same sender.
+// It returns the number of transactions dropped.
+// Remove 从跟踪该交易的子池中驱逐由哈希标识的交易，以及同一发送者的所有后续交易。
+// 返回被丢弃的交易数量。
+func (p *TxPool) Remove(hash common.Hash) int {
+	for _, subpool := range p.subpools {
+		if subpool.Has(hash) {
+			return subpool.Remove(hash)
+		}
+	}
+	return 0
+}
+
+// RemoveFrom evicts all the transactions originating from the given account
+// from the subpools, returning the number of transactions dropped.
+// RemoveFrom 从各子池中驱逐来自给定账户的所有交易，返回被丢弃的交易数量。
+func (p *TxPool) RemoveFrom(addr common.Address) int {
+	var dropped int
+	for _, subpool := range p.subpools {
+		dropped += subpool.RemoveFrom(addr)
+	}
+	return dropped
+}
+
+// SetLocal marks or unmarks an account as local in all the subpools.
+// SetLocal 在所有子池中将账户标记或取消标记为本地账户。
+func (p *TxPool) SetLocal(addr common.Address, local bool) {
+	for _, subpool := range p.subpools {
+		subpool.SetLocal(addr, local)
+	}
+}
+
+// FlushJournal requests all the subpools to persist their journaled transactions
+// to disk immediately, instead of waiting for the next periodic rotation.
+// FlushJournal 请求所有子池立即将其日志中的交易持久化到磁盘，而不是等待下一次周期性轮换。
+func (p *TxPool) FlushJournal() error {
+	var errs []error
+	for _, subpool := range p.subpools {
+		if err := subpool.FlushJournal(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("subpool journal errors: %v", errs)
+	}
+	return nil
+}
@@ -61,9 +61,10 @@ type Transaction struct {
 	time  time.Time // Time first seen locally (spam avoidance) 本地首次看到的时间（避免垃圾交易）
 
 	// caches
-	hash atomic.Pointer[common.Hash] // 交易哈希的缓存
-	size atomic.Uint64               // 交易编码后的大小（字节）
-	from atomic.Pointer[sigCache]    // 签名者地址的缓存
+	hash atomic.Pointer[common.Hash]       // 交易哈希的缓存
+	size atomic.Uint64                     // 交易编码后的大小（字节）
+	from atomic.Pointer[sigCache]          // 签名者地址的缓存
+	auth atomic.Pointer[[]*common.Address] // 授权签名者的缓存
 }
 
 // NewTx creates a new transaction.
@@ -580,6 +581,56 @@ func (tx *Transaction) SetCodeAuthorizations() []SetCodeAuthorization {
 	return setcodetx.AuthList
 }
 
+// SetCodeAuthorityList returns the account that signed each authorization of the
+// transaction, in the order of the authorization list. The entry of an
+// authorization with an invalid signature is nil. The signatures are recovered
+// on the first call only, later calls return the cached result.
+// SetCodeAuthorityList 按授权列表顺序返回签署每个授权的账户。签名无效的授权对应的条目为 nil。
+// 签名仅在第一次调用时恢复，之后的调用返回缓存结果。
+func (tx *Transaction) SetCodeAuthorityList() []*common.Address {
+	setcodetx, ok := tx.inner.(*SetCodeTx)
+	if !ok {
+		return nil
+	}
+	if auths := tx.auth.Load(); auths != nil {
+		return *auths
+	}
+	auths := make([]*common.Address, len(setcodetx.AuthList))
+	for i, auth := range setcodetx.AuthList {
+		if addr, err := auth.Authority(); err == nil {
+			auths[i] = &addr
+		}
+	}
+	tx.auth.Store(&auths)
+	return auths
+}
+
+// SetCodeAuthorities returns the deduplicated list of accounts that signed an
+// authorization in the transaction. Authorizations with an invalid signature
+// are skipped.
+// SetCodeAuthorities 返回交易中签署授权的账户列表（已去重）。签名无效的授权将被跳过。
+func (tx *Transaction) SetCodeAuthorities() []common.Address {
+	list := tx.SetCodeAuthorityList()
+	if list == nil {
+		return nil
+	}
+	var (
+		auths = make([]common.Address, 0, len(list))
+		seen  = make(map[common.Address]struct{})
+	)
+	for _, addr := range list {
+		if addr == nil {
+			continue
+		}
+		if _, ok := seen[*addr]; ok {
+			continue
+		}
+		seen[*addr] = struct{}{}
+		auths = append(auths, *addr)
+	}
+	return auths
+}
+
 // SetTime sets the decoding time of a transaction. This is used by tests to set
 // arbitrary times and by persistent transaction pools when loading old txs from
 // disk.
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // TestParseDelegation tests a few possible delegation designator values and
@@ -68,3 +69,32 @@ func TestParseDelegation(t *testing.T) {
 		}
 	}
 }
+
+// TestSetCodeAuthorities tests that the authorities of a set code transaction are
+// recovered in order, invalid signatures are skipped and the result is cached.
+func TestSetCodeAuthorities(t *testing.T) {
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	addr1 := crypto.PubkeyToAddress(key1.PublicKey)
+	addr2 := crypto.PubkeyToAddress(key2.PublicKey)
+
+	auth1, _ := SignSetCode(key1, SetCodeAuthorization{Nonce: 1})
+	auth2, _ := SignSetCode(key2, SetCodeAuthorization{Nonce: 2})
+	auth3, _ := SignSetCode(key1, SetCodeAuthorization{Nonce: 3})
+	invalid := SetCodeAuthorization{Nonce: 4, V: 5}
+
+	tx := NewTx(&SetCodeTx{AuthList: []SetCodeAuthorization{auth1, invalid, auth2, auth3}})
+	list := tx.SetCodeAuthorityList()
+	if len(list) != 4 || list[1] != nil || *list[0] != addr1 || *list[2] != addr2 || *list[3] != addr1 {
+		t.Fatalf("wrong authority list: %v", list)
+	}
+	if again := tx.SetCodeAuthorityList(); &again[0] != &list[0] {
+		t.Error("authority list not cached")
+	}
+	if auths := tx.SetCodeAuthorities(); len(auths) != 2 || auths[0] != addr1 || auths[1] != addr2 {
+		t.Errorf("wrong authorities: %v", auths)
+	}
+	if auths := NewTx(&DynamicFeeTx{}).SetCodeAuthorities(); auths != nil {
+		t.Errorf("authorities of non set code transaction: %v", auths)
+	}
+}
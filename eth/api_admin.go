@@ -24,6 +24,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -141,3 +142,44 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	}
 	return true, nil
 }
+
+// RemoveTransaction evicts the transaction with the given hash from the pool,
+// together with all the subsequent transactions of the same sender that would
+// otherwise be stuck behind the resulting nonce gap. It returns the number of
+// dropped transactions.
+func (api *AdminAPI) RemoveTransaction(hash common.Hash) (int, error) {
+	removed := api.eth.TxPool().Remove(hash)
+	if removed == 0 {
+		return 0, fmt.Errorf("transaction %#x not found in pool", hash)
+	}
+	return removed, nil
+}
+
+// RemoveTransactionsFrom evicts all the transactions of the given account from
+// the pool, returning the number of dropped transactions.
+func (api *AdminAPI) RemoveTransactionsFrom(addr common.Address) int {
+	return api.eth.TxPool().RemoveFrom(addr)
+}
+
+// AddLocalAccount marks the given account as local, exempting its pooled and
+// future transactions from the pricing and eviction rules of the pool.
+func (api *AdminAPI) AddLocalAccount(addr common.Address) bool {
+	api.eth.TxPool().SetLocal(addr, true)
+	return true
+}
+
+// RemoveLocalAccount removes the local marker from the given account, subjecting
+// its transactions to the regular pool rules again.
+func (api *AdminAPI) RemoveLocalAccount(addr common.Address) bool {
+	api.eth.TxPool().SetLocal(addr, false)
+	return true
+}
+
+// FlushTxJournal regenerates the transaction pool journal from the current pool
+// contents, instead of waiting for the next periodic rotation.
+func (api *AdminAPI) FlushTxJournal() (bool, error) {
+	if err := api.eth.TxPool().FlushJournal(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/ethereum/go-ethereum/eth/tracers"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rpc"
 )
@@ -203,7 +204,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	if header == nil {
 		return nil, nil, errors.New("header not found")
 	}
-	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+	stateDb, err := b.stateAt(ctx, header)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -225,7 +226,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 		if blockNrOrHash.RequireCanonical && b.eth.blockchain.GetCanonicalHash(header.Number.Uint64()) != hash {
 			return nil, nil, errors.New("hash is not currently canonical")
 		}
-		stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+		stateDb, err := b.stateAt(ctx, header)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -234,6 +235,16 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 	return nil, nil, errors.New("invalid arguments; neither block nor hash specified")
 }
 
+// stateAt opens the state of the given header, tracing the access.
+func (b *EthAPIBackend) stateAt(ctx context.Context, header *types.Header) (*state.StateDB, error) {
+	_, span := tracing.Start(ctx, "state.StateAt", tracing.Int64("block", header.Number.Int64()))
+	defer span.End()
+
+	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
+	span.SetError(err)
+	return stateDb, err
+}
+
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	return b.eth.blockchain.GetReceiptsByHash(hash), nil
 }
@@ -336,6 +347,10 @@ func (b *EthAPIBackend) TxPoolContentFrom(addr common.Address) ([]*types.Transac
 	return b.eth.txPool.ContentFrom(addr)
 }
 
+func (b *EthAPIBackend) TxPoolAuthorizations(addr common.Address) []*types.Transaction {
+	return b.eth.txPool.Authorizations(addr)
+}
+
 func (b *EthAPIBackend) TxPool() *txpool.TxPool {
 	return b.eth.txPool
 }
@@ -361,6 +376,10 @@ func (b *EthAPIBackend) FeeHistory(ctx context.Context, blockCount uint64, lastB
 	return b.gpo.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
 }
 
+func (b *EthAPIBackend) FeeEstimates(ctx context.Context) (*gasprice.FeeEstimates, error) {
+	return b.gpo.FeeEstimates(ctx)
+}
+
 func (b *EthAPIBackend) BlobBaseFee(ctx context.Context) *big.Int {
 	if excess := b.CurrentHeader().ExcessBlobGas; excess != nil {
 		return eip4844.CalcBlobFee(*excess)
@@ -420,9 +439,19 @@ func (b *EthAPIBackend) CurrentHeader() *types.Header {
 }
 
 func (b *EthAPIBackend) StateAtBlock(ctx context.Context, block *types.Block, reexec uint64, base *state.StateDB, readOnly bool, preferDisk bool) (*state.StateDB, tracers.StateReleaseFunc, error) {
-	return b.eth.stateAtBlock(ctx, block, reexec, base, readOnly, preferDisk)
+	ctx, span := tracing.Start(ctx, "state.StateAtBlock", tracing.Int64("block", block.Number().Int64()))
+	defer span.End()
+
+	statedb, release, err := b.eth.stateAtBlock(ctx, block, reexec, base, readOnly, preferDisk)
+	span.SetError(err)
+	return statedb, release, err
 }
 
 func (b *EthAPIBackend) StateAtTransaction(ctx context.Context, block *types.Block, txIndex int, reexec uint64) (*types.Transaction, vm.BlockContext, *state.StateDB, tracers.StateReleaseFunc, error) {
-	return b.eth.stateAtTransaction(ctx, block, txIndex, reexec)
+	ctx, span := tracing.Start(ctx, "state.StateAtTransaction", tracing.Int64("block", block.Number().Int64()), tracing.Int("index", txIndex))
+	defer span.End()
+
+	tx, vmctx, statedb, release, err := b.eth.stateAtTransaction(ctx, block, txIndex, reexec)
+	span.SetError(err)
+	return tx, vmctx, statedb, release, err
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/rawdb"
@@ -30,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
@@ -133,7 +135,7 @@ func (api *DebugAPI) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs, error)
 const AccountRangeMaxResults = 256
 
 // AccountRange enumerates all accounts in the given block and start point in paging request
-func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hexutil.Bytes, maxResults int, nocode, nostorage, incompletes bool) (state.Dump, error) {
+func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hexutil.Bytes, maxResults int, nocode, nostorage, incompletes bool) (*rpc.Stream, error) {
 	var stateDb *state.StateDB
 	var err error
 
@@ -144,7 +146,7 @@ func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hex
 			// the miner and operate on those
 			_, _, stateDb = api.eth.miner.Pending()
 			if stateDb == nil {
-				return state.Dump{}, errors.New("pending state is not available")
+				return nil, errors.New("pending state is not available")
 			}
 		} else {
 			var header *types.Header
@@ -158,29 +160,29 @@ func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hex
 			default:
 				block := api.eth.blockchain.GetBlockByNumber(uint64(number))
 				if block == nil {
-					return state.Dump{}, fmt.Errorf("block #%d not found", number)
+					return nil, fmt.Errorf("block #%d not found", number)
 				}
 				header = block.Header()
 			}
 			if header == nil {
-				return state.Dump{}, fmt.Errorf("block #%d not found", number)
+				return nil, fmt.Errorf("block #%d not found", number)
 			}
 			stateDb, err = api.eth.BlockChain().StateAt(header.Root)
 			if err != nil {
-				return state.Dump{}, err
+				return nil, err
 			}
 		}
 	} else if hash, ok := blockNrOrHash.Hash(); ok {
 		block := api.eth.blockchain.GetBlockByHash(hash)
 		if block == nil {
-			return state.Dump{}, fmt.Errorf("block %s not found", hash.Hex())
+			return nil, fmt.Errorf("block %s not found", hash.Hex())
 		}
 		stateDb, err = api.eth.BlockChain().StateAt(block.Root())
 		if err != nil {
-			return state.Dump{}, err
+			return nil, err
 		}
 	} else {
-		return state.Dump{}, errors.New("either block number or block hash must be specified")
+		return nil, errors.New("either block number or block hash must be specified")
 	}
 
 	opts := &state.DumpConfig{
@@ -193,7 +195,52 @@ func (api *DebugAPI) AccountRange(blockNrOrHash rpc.BlockNumberOrHash, start hex
 	if maxResults > AccountRangeMaxResults || maxResults <= 0 {
 		opts.Max = AccountRangeMaxResults
 	}
-	return stateDb.RawDump(opts), nil
+	// The accounts are streamed out while the trie is being iterated, rather than
+	// being collected into a map first.
+	return rpc.ObjectStream(func(enc *rpc.StreamEncoder) error {
+		var (
+			collector = &streamDumpCollector{}
+			next      []byte
+		)
+		accounts := rpc.ObjectStream(func(enc *rpc.StreamEncoder) error {
+			collector.enc = enc
+			next = stateDb.DumpToCollector(collector, opts)
+			return collector.err
+		})
+		if err := enc.EncodeField("accounts", accounts); err != nil {
+			return err
+		}
+		if err := enc.EncodeField("root", fmt.Sprintf("%x", collector.root)); err != nil {
+			return err
+		}
+		if next != nil {
+			return enc.EncodeField("next", next)
+		}
+		return nil
+	}), nil
+}
+
+// streamDumpCollector is a state.DumpCollector which encodes the accounts into
+// a streamed RPC result, using the same keys as state.Dump.
+type streamDumpCollector struct {
+	enc  *rpc.StreamEncoder
+	root common.Hash
+	err  error
+}
+
+func (c *streamDumpCollector) OnRoot(root common.Hash) {
+	c.root = root
+}
+
+func (c *streamDumpCollector) OnAccount(addr *common.Address, account state.DumpAccount) {
+	if c.err != nil {
+		return
+	}
+	key := fmt.Sprintf("pre(%s)", account.AddressHash)
+	if addr != nil {
+		key = addr.String()
+	}
+	c.err = c.enc.EncodeField(key, account)
 }
 
 // StorageRangeResult is the result of a debug_storageRangeAt API call.
@@ -443,3 +490,14 @@ func (api *DebugAPI) GetTrieFlushInterval() (string, error) {
 	}
 	return api.eth.blockchain.GetTrieFlushInterval().String(), nil
 }
+
+// GetPayloadBuildReport retrieves the diagnostics collected while building the
+// payload with the given id: the pool transactions considered and skipped in
+// every build iteration, along with the reasons, the fees and the timings.
+func (api *DebugAPI) GetPayloadBuildReport(id engine.PayloadID) (*miner.BuildReport, error) {
+	report := api.eth.miner.PayloadBuildReport(id)
+	if report == nil {
+		return nil, fmt.Errorf("unknown payload %v", id)
+	}
+	return report, nil
+}
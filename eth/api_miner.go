@@ -42,12 +42,7 @@ func (api *MinerAPI) SetExtra(extra string) (bool, error) {
 
 // SetGasPrice sets the minimum accepted gas price for the miner.
 func (api *MinerAPI) SetGasPrice(gasPrice hexutil.Big) bool {
-	api.e.lock.Lock()
-	api.e.gasPrice = (*big.Int)(&gasPrice)
-	api.e.lock.Unlock()
-
-	api.e.txPool.SetGasTip((*big.Int)(&gasPrice))
-	api.e.Miner().SetGasTip((*big.Int)(&gasPrice))
+	api.e.SetGasPrice((*big.Int)(&gasPrice))
 	return true
 }
 
@@ -69,6 +69,7 @@ type Ethereum struct {
 	// core protocol objects
 	config     *ethconfig.Config
 	txPool     *txpool.TxPool
+	legacyPool *legacypool.LegacyPool
 	blockchain *core.BlockChain
 
 	handler *handler
@@ -107,6 +108,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if !config.SyncMode.IsValid() {
 		return nil, fmt.Errorf("invalid sync mode %d", config.SyncMode)
 	}
+	if err := config.TxBroadcast.Validate(); err != nil {
+		return nil, err
+	}
 	if config.Miner.GasPrice == nil || config.Miner.GasPrice.Sign() <= 0 {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
@@ -231,9 +235,12 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if config.TxPool.Journal != "" {
 		config.TxPool.Journal = stack.ResolvePath(config.TxPool.Journal)
 	}
-	legacyPool := legacypool.New(config.TxPool, eth.blockchain)
+	if config.TxPool.Snapshot != "" {
+		config.TxPool.Snapshot = stack.ResolvePath(config.TxPool.Snapshot)
+	}
+	eth.legacyPool = legacypool.New(config.TxPool, eth.blockchain)
 
-	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{legacyPool, blobPool})
+	eth.txPool, err = txpool.New(config.TxPool.PriceLimit, eth.blockchain, []txpool.SubPool{eth.legacyPool, blobPool})
 	if err != nil {
 		return nil, err
 	}
@@ -249,6 +256,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		BloomCache:     uint64(cacheLimit),
 		EventMux:       eth.eventMux,
 		RequiredBlocks: config.RequiredBlocks,
+		TxBroadcast:    config.TxBroadcast,
 	}); err != nil {
 		return nil, err
 	}
@@ -328,6 +336,29 @@ func (s *Ethereum) ResetWithGenesisBlock(gb *types.Block) {
 
 func (s *Ethereum) Miner() *miner.Miner { return s.miner }
 
+// SetGasPrice updates the minimum gas tip accepted by the transaction pool and
+// included by the miner.
+func (s *Ethereum) SetGasPrice(gasPrice *big.Int) {
+	s.lock.Lock()
+	s.gasPrice = gasPrice
+	s.lock.Unlock()
+
+	s.txPool.SetGasTip(gasPrice)
+	s.miner.SetGasTip(gasPrice)
+}
+
+// SetTxPoolLimits updates the slot, queue and lifetime limits of the transaction
+// pool. Fields of the config which can't be changed at runtime are ignored.
+func (s *Ethereum) SetTxPoolLimits(config legacypool.Config) {
+	s.legacyPool.SetLimits(config)
+}
+
+// TxPoolLimits returns the configuration of the transaction pool, including the
+// slot, queue and lifetime limits currently in effect.
+func (s *Ethereum) TxPoolLimits() legacypool.Config {
+	return s.legacyPool.Limits()
+}
+
 func (s *Ethereum) AccountManager() *accounts.Manager  { return s.accountManager }
 func (s *Ethereum) BlockChain() *core.BlockChain       { return s.blockchain }
 func (s *Ethereum) TxPool() *txpool.TxPool             { return s.txPool }
@@ -345,7 +376,8 @@ func (s *Ethereum) BloomIndexer() *core.ChainIndexer   { return s.bloomIndexer }
 // network protocols to start.
 func (s *Ethereum) Protocols() []p2p.Protocol {
 	protos := eth.MakeProtocols((*ethHandler)(s.handler), s.networkID, s.discmix)
-	if s.config.SnapshotCache > 0 {
+	// Path-scheme nodes can serve snap from their tries if snapshots are disabled.
+	if s.config.SnapshotCache > 0 || s.blockchain.TrieDB().Scheme() == rawdb.PathScheme {
 		protos = append(protos, snap.MakeProtocols((*snapHandler)(s.handler))...)
 	}
 	return protos
@@ -147,6 +147,13 @@ func (d *Downloader) SetBadBlockCallback(onBadBlock badBlockFn) {
 	d.badBlock = onBadBlock
 }
 
+// SetPeerReporter sets the callback to run when a peer delivers invalid data or
+// serves a request quickly. This method is not thread safe and should be set
+// only once on startup before system events are fired.
+func (d *Downloader) SetPeerReporter(report peerReportFn) {
+	d.reportPeer = report
+}
+
 // BeaconSync is the post-merge version of the chain synchronization, where the
 // chain is not downloaded from genesis onward, rather from trusted head announces
 // backwards.
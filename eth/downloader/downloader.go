@@ -35,6 +35,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/triedb"
 )
@@ -84,6 +85,9 @@ const (
 // peerDropFn is a callback type for dropping a peer detected as malicious.
 type peerDropFn func(id string)
 
+// peerReportFn is a callback type for reporting the behaviour of a peer.
+type peerReportFn func(id string, ev p2p.ReputationEvent)
+
 // badBlockFn is a callback for the async beacon sync to notify the caller that
 // the origin header requested to sync to, produced a chain with a bad block.
 type badBlockFn func(invalid *types.Header, origin *types.Header)
@@ -112,8 +116,9 @@ type Downloader struct {
 	blockchain BlockChain
 
 	// Callbacks
-	dropPeer peerDropFn // Drops a peer for misbehaving
-	badBlock badBlockFn // Reports a block as rejected by the chain
+	dropPeer   peerDropFn   // Drops a peer for misbehaving
+	badBlock   badBlockFn   // Reports a block as rejected by the chain
+	reportPeer peerReportFn // Reports the behaviour of a peer
 
 	// Status
 	synchronising atomic.Bool
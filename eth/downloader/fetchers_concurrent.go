@@ -25,6 +25,7 @@ import (
 	"github.com/ethereum/go-ethereum/common/prque"
 	"github.com/ethereum/go-ethereum/eth/protocols/eth"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
 )
 
 // timeoutGracePeriod is the amount of time to allow for a peer to deliver a
@@ -336,6 +337,15 @@ func (d *Downloader) concurrentFetch(queue typedQueue) error {
 				if errors.Is(err, errInvalidChain) {
 					return err
 				}
+				// Let the peer's reputation reflect the quality of the delivery
+				if d.reportPeer != nil {
+					switch {
+					case errors.Is(err, errInvalidBody) || errors.Is(err, errInvalidReceipt):
+						d.reportPeer(peer.id, p2p.RepInvalidBlock)
+					case err == nil && accepted > 0 && res.Time <= d.peers.rates.TargetRoundTrip():
+						d.reportPeer(peer.id, p2p.RepGoodThroughput)
+					}
+				}
 				// Unless a peer delivered something completely else than requested (usually
 				// caused by a timed out request which came through in the end), set it to
 				// idle. If the delivery's stale, the peer should have already been idled.
@@ -58,6 +58,7 @@ type peerConnection struct {
 // Peer encapsulates the methods required to synchronise with a remote full peer.
 type Peer interface {
 	Head() (common.Hash, *big.Int)
+	ServesBlock(uint64) bool
 	RequestHeadersByHash(common.Hash, int, int, bool, chan *eth.Response) (*eth.Request, error)
 	RequestHeadersByNumber(uint64, int, int, bool, chan *eth.Response) (*eth.Request, error)
 
@@ -158,6 +159,12 @@ func (p *peerConnection) Lacks(hash common.Hash) bool {
 	return ok
 }
 
+// ServesBlock retrieves whether the peer announced to serve the body and receipts
+// of a block, i.e. it didn't prune that part of the history.
+func (p *peerConnection) ServesBlock(number uint64) bool {
+	return p.peer.ServesBlock(number)
+}
+
 // peeringEvent is sent on the peer event feed when a remote peer connects or
 // disconnects.
 type peeringEvent struct {
@@ -560,7 +560,7 @@ func (q *queue) reserveHeaders(p *peerConnection, count int, taskPool map[common
 		// Remove it from the task queue
 		taskQueue.PopItem()
 		// Otherwise unless the peer is known not to have the data, add to the retrieve list
-		if p.Lacks(header.Hash()) {
+		if p.Lacks(header.Hash()) || !p.ServesBlock(header.Number.Uint64()) {
 			skip = append(skip, header)
 		} else {
 			send = append(send, header)
@@ -952,7 +952,7 @@ func (q *queue) deliver(id string, taskPool map[common.Hash]*types.Header,
 	}
 	// If none of the data was good, it's a stale delivery
 	if accepted > 0 {
-		return accepted, fmt.Errorf("partial failure: %v", failure)
+		return accepted, fmt.Errorf("partial failure: %w", failure)
 	}
 	return accepted, fmt.Errorf("%w: %v", failure, errStaleDelivery)
 }
@@ -128,6 +128,9 @@ type Config struct {
 	TxPool   legacypool.Config
 	BlobPool blobpool.Config
 
+	// Transaction propagation policy
+	TxBroadcast TxBroadcastConfig
+
 	// Gas Price Oracle options
 	GPO gasprice.Config
 
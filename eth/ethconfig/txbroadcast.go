@@ -0,0 +1,86 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethconfig
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TxBroadcastConfig is the policy for propagating transactions to peers.
+//
+// By default, transactions are sent directly to roughly the square root of the
+// connected peers and announced to the rest. Blob transactions and large
+// transactions are always announced only.
+type TxBroadcastConfig struct {
+	// AnnounceOnly disables sending transactions directly, all transactions are
+	// only announced.
+	AnnounceOnly bool
+
+	// Private restricts locally submitted transactions to trusted peers. Local
+	// transactions are never sent or announced to other peers. This applies
+	// to blob transactions too and doesn't depend on the pool tracking local
+	// accounts, i.e. it also holds with txpool.nolocals.
+	Private bool
+
+	// MaxDirectPeers limits the number of peers a transaction is sent to directly.
+	// Zero means no limit beyond the square root of the peer count.
+	MaxDirectPeers int
+
+	// MaxAnnouncePeers limits the number of peers a transaction is announced to.
+	// Zero means no limit.
+	MaxAnnouncePeers int
+
+	// Rules override the policy for specific transaction types.
+	Rules []TxBroadcastRule `toml:",omitempty"`
+}
+
+// TxBroadcastRule is the propagation policy of a transaction type.
+type TxBroadcastRule struct {
+	Type         uint8 // Transaction type the rule applies to
+	AnnounceOnly bool  // Whether transactions of the type are only announced
+	MaxPeers     int   // Maximum number of peers transactions of the type are propagated to, zero for no limit
+}
+
+// Validate checks the policy for invalid values.
+func (c *TxBroadcastConfig) Validate() error {
+	if c.MaxDirectPeers < 0 || c.MaxAnnouncePeers < 0 {
+		return errors.New("negative transaction broadcast peer limit")
+	}
+	seen := make(map[uint8]bool)
+	for _, rule := range c.Rules {
+		if seen[rule.Type] {
+			return fmt.Errorf("duplicate transaction broadcast rule for type %d", rule.Type)
+		}
+		if rule.MaxPeers < 0 {
+			return fmt.Errorf("negative peer limit in transaction broadcast rule for type %d", rule.Type)
+		}
+		seen[rule.Type] = true
+	}
+	return nil
+}
+
+// Rule returns the rule for the given transaction type. If there is no rule for
+// the type, the zero rule is returned, which doesn't restrict propagation.
+func (c *TxBroadcastConfig) Rule(txType uint8) TxBroadcastRule {
+	for _, rule := range c.Rules {
+		if rule.Type == txType {
+			return rule
+		}
+	}
+	return TxBroadcastRule{Type: txType}
+}
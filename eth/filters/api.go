@@ -332,7 +332,7 @@ func (api *FilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 }
 
 // GetLogs returns logs matching the given argument that are stored within the state.
-func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
+func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) (*rpc.Stream, error) {
 	if len(crit.Topics) > maxTopics {
 		return nil, errExceedMaxTopics
 	}
@@ -356,12 +356,13 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 		// Construct the range filter
 		filter = api.sys.NewRangeFilter(begin, end, crit.Addresses, crit.Topics)
 	}
-	// Run the filter and return all the logs
-	logs, err := filter.Logs(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return returnLogs(logs), err
+	// Run the filter while the response is being sent, streaming out the logs
+	// as they are found
+	return rpc.ArrayStream(func(enc *rpc.StreamEncoder) error {
+		return filter.iterateLogs(ctx, func(log *types.Log) error {
+			return enc.Encode(log)
+		})
+	}), nil
 }
 
 // UninstallFilter removes the filter with the given filter id.
@@ -96,21 +96,42 @@ func newFilter(sys *FilterSystem, addresses []common.Address, topics [][]common.
 // Logs searches the blockchain for matching log entries, returning all from the
 // first block that contains matches, updating the start of the filter accordingly.
 func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
+	var logs []*types.Log
+	err := f.iterateLogs(ctx, func(log *types.Log) error {
+		logs = append(logs, log)
+		return nil
+	})
+	return logs, err
+}
+
+// iterateLogs is like Logs, but hands the matching log entries to the given
+// callback one by one instead of collecting them. The search is aborted if the
+// callback fails.
+func (f *Filter) iterateLogs(ctx context.Context, fn func(*types.Log) error) error {
 	// If we're doing singleton block filtering, execute and return
 	if f.block != nil {
 		header, err := f.sys.backend.HeaderByHash(ctx, *f.block)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if header == nil {
-			return nil, errors.New("unknown block")
+			return errors.New("unknown block")
+		}
+		logs, err := f.blockLogs(ctx, header)
+		if err != nil {
+			return err
+		}
+		for _, log := range logs {
+			if err := fn(log); err != nil {
+				return err
+			}
 		}
-		return f.blockLogs(ctx, header)
+		return nil
 	}
 
 	// Disallow pending logs.
 	if f.begin == rpc.PendingBlockNumber.Int64() || f.end == rpc.PendingBlockNumber.Int64() {
-		return nil, errPendingLogsUnsupported
+		return errPendingLogsUnsupported
 	}
 
 	resolveSpecial := func(number int64) (int64, error) {
@@ -142,20 +163,27 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	var err error
 	// range query need to resolve the special begin/end block number
 	if f.begin, err = resolveSpecial(f.begin); err != nil {
-		return nil, err
+		return err
 	}
 	if f.end, err = resolveSpecial(f.end); err != nil {
-		return nil, err
+		return err
 	}
 
+	// Stop the search if the callback fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	logChan, errChan := f.rangeLogsAsync(ctx)
-	var logs []*types.Log
 	for {
 		select {
 		case log := <-logChan:
-			logs = append(logs, log)
+			if err := fn(log); err != nil {
+				cancel()
+				<-errChan
+				return err
+			}
 		case err := <-errChan:
-			return logs, err
+			return err
 		}
 	}
 }
@@ -238,7 +266,11 @@ func (f *Filter) indexedLogs(ctx context.Context, end uint64, logChan chan *type
 				return err
 			}
 			for _, log := range found {
-				logChan <- log
+				select {
+				case logChan <- log:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
 			}
 
 		case <-ctx.Done():
@@ -0,0 +1,268 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// estimateHistoryBlocks is the number of recent blocks whose fee history is
+	// sampled when producing fee estimates.
+	estimateHistoryBlocks = 20
+
+	// defaultBlockPeriod is the assumed block time if it cannot be derived from
+	// the recent chain segment (e.g. right after genesis).
+	defaultBlockPeriod = 12 * time.Second
+)
+
+// estimateLevels defines the urgency levels estimates are produced for: the fee
+// history percentile used as the baseline tip and the number of blocks within
+// which the transaction is aimed to be included.
+var estimateLevels = [...]struct {
+	percentile float64
+	blocks     uint64
+}{
+	{percentile: 10, blocks: 10}, // low
+	{percentile: 50, blocks: 3},  // medium
+	{percentile: 90, blocks: 1},  // high
+}
+
+// FeeEstimate is a fee suggestion for a single urgency level.
+type FeeEstimate struct {
+	MaxPriorityFeePerGas *big.Int      // Tip to pay to the block producer
+	MaxFeePerGas         *big.Int      // Fee cap covering the worst case base fee until inclusion
+	MaxFeePerBlobGas     *big.Int      // Blob fee cap covering the worst case blob base fee until inclusion, nil pre-Cancun
+	InclusionBlocks      uint64        // Expected number of blocks until inclusion
+	InclusionTime        time.Duration // Expected time until inclusion
+}
+
+// FeeEstimates is the set of low, medium and high urgency fee suggestions for
+// the next block.
+type FeeEstimates struct {
+	BaseFee     *big.Int // Base fee of the next block
+	BlobBaseFee *big.Int // Blob base fee of the next block, nil pre-Cancun
+	Predictive  bool     // Whether the pending pool was taken into account
+
+	Low, Medium, High *FeeEstimate
+}
+
+// FeeEstimates returns low, medium and high fee suggestions along with their
+// expected inclusion times. The tips are based on the fee history of recent
+// blocks. If the oracle runs in predictive mode, they are raised further if
+// the tips of the pending pool transactions, competing for the remaining gas of
+// the next few blocks, indicate that demand is spiking.
+func (oracle *Oracle) FeeEstimates(ctx context.Context) (*FeeEstimates, error) {
+	head, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	config := oracle.backend.ChainConfig()
+
+	// Calculate the fees of the next block
+	estimates := new(FeeEstimates)
+	if config.IsLondon(new(big.Int).Add(head.Number, big.NewInt(1))) {
+		estimates.BaseFee = eip1559.CalcBaseFee(config, head)
+	} else {
+		estimates.BaseFee = new(big.Int)
+	}
+	if head.ExcessBlobGas != nil && head.BlobGasUsed != nil {
+		estimates.BlobBaseFee = eip4844.CalcBlobFee(eip4844.CalcExcessBlobGas(*head.ExcessBlobGas, *head.BlobGasUsed))
+	}
+	// Retrieve the historical tip percentiles of the recent blocks
+	percentiles := make([]float64, len(estimateLevels))
+	for i, level := range estimateLevels {
+		percentiles[i] = level.percentile
+	}
+	blocks := min(uint64(estimateHistoryBlocks), oracle.maxBlockHistory, head.Number.Uint64()+1)
+	_, rewards, _, _, _, _, err := oracle.FeeHistory(ctx, blocks, rpc.LatestBlockNumber, percentiles)
+	if err != nil {
+		return nil, err
+	}
+	tips := make([]*big.Int, len(estimateLevels))
+	for i := range estimateLevels {
+		samples := make([]*big.Int, 0, len(rewards))
+		for _, reward := range rewards {
+			if len(reward) > i {
+				samples = append(samples, reward[i])
+			}
+		}
+		tips[i] = median(samples)
+	}
+	// In predictive mode, make sure the tips beat the pending transactions that
+	// would otherwise fill up the blocks up until the target inclusion
+	if oracle.predictive {
+		estimates.Predictive = true
+
+		backlog, used := oracle.pendingBacklog(head, estimates.BaseFee)
+		for i, level := range estimateLevels {
+			capacity := level.blocks * head.GasLimit
+			if used >= capacity {
+				capacity = 0
+			} else {
+				capacity -= used
+			}
+			if tip := backlogTip(backlog, capacity); tip != nil && tip.Cmp(tips[i]) > 0 {
+				tips[i] = tip
+			}
+		}
+	}
+	// Assemble the estimates, keeping the levels monotonic and capped
+	period := oracle.blockPeriod(ctx, head, blocks)
+
+	levels := make([]*FeeEstimate, len(estimateLevels))
+	for i, level := range estimateLevels {
+		tip := tips[i]
+		if i > 0 && tip.Cmp(levels[i-1].MaxPriorityFeePerGas) < 0 {
+			tip = levels[i-1].MaxPriorityFeePerGas
+		}
+		if tip.Cmp(oracle.maxPrice) > 0 {
+			tip = oracle.maxPrice
+		}
+		estimate := &FeeEstimate{
+			MaxPriorityFeePerGas: new(big.Int).Set(tip),
+			MaxFeePerGas:         new(big.Int).Add(worstBaseFee(config, estimates.BaseFee, level.blocks), tip),
+			InclusionBlocks:      level.blocks,
+			InclusionTime:        time.Duration(level.blocks) * period,
+		}
+		if head.ExcessBlobGas != nil && head.BlobGasUsed != nil {
+			estimate.MaxFeePerBlobGas = worstBlobFee(*head.ExcessBlobGas, *head.BlobGasUsed, level.blocks)
+		}
+		levels[i] = estimate
+	}
+	estimates.Low, estimates.Medium, estimates.High = levels[0], levels[1], levels[2]
+	return estimates, nil
+}
+
+// pendingTx is the effective tip and gas allowance of a pending transaction.
+type pendingTx struct {
+	tip *big.Int
+	gas uint64
+}
+
+// pendingBacklog returns the pending pool transactions competing for inclusion
+// after the given head, along with the gas already used by the pending block.
+// The backlog is cached per head, so the pool is only queried once per block.
+func (oracle *Oracle) pendingBacklog(head *types.Header, baseFee *big.Int) ([]pendingTx, uint64) {
+	oracle.backlogLock.Lock()
+	defer oracle.backlogLock.Unlock()
+
+	if hash := head.Hash(); hash != oracle.backlogHead {
+		oracle.backlog, oracle.backlogUsed = oracle.pendingTips(hash, baseFee)
+		oracle.backlogHead = hash
+	}
+	return oracle.backlog, oracle.backlogUsed
+}
+
+// pendingTips collects the effective tips of the executable pool transactions
+// at the given base fee, sorted in descending order. Transactions which cannot
+// pay the base fee are ignored as they are not competing for inclusion. The gas
+// used by the pending block on top of the head is returned too, the transactions
+// it includes are left out of the backlog as their gas is already accounted for.
+func (oracle *Oracle) pendingTips(head common.Hash, baseFee *big.Int) ([]pendingTx, uint64) {
+	var (
+		used     uint64
+		included = make(map[common.Hash]struct{})
+	)
+	if block, _, _ := oracle.backend.Pending(); block != nil && block.ParentHash() == head {
+		used = block.GasUsed()
+		for _, tx := range block.Transactions() {
+			included[tx.Hash()] = struct{}{}
+		}
+	}
+	pending, _ := oracle.backend.TxPoolContent()
+
+	var txs []pendingTx
+	for _, list := range pending {
+		for _, tx := range list {
+			if _, ok := included[tx.Hash()]; ok {
+				continue
+			}
+			tip, err := tx.EffectiveGasTip(baseFee)
+			if err != nil || tip.Cmp(oracle.ignorePrice) < 0 {
+				continue
+			}
+			txs = append(txs, pendingTx{tip: tip, gas: tx.Gas()})
+		}
+	}
+	slices.SortFunc(txs, func(a, b pendingTx) int { return b.tip.Cmp(a.tip) })
+	return txs, used
+}
+
+// backlogTip returns the tip needed to outbid the pending backlog filling up the
+// given amount of gas, or nil if the backlog does not fill it up.
+func backlogTip(backlog []pendingTx, gas uint64) *big.Int {
+	var used uint64
+	for _, tx := range backlog {
+		if used += tx.gas; used >= gas {
+			return new(big.Int).Add(tx.tip, big.NewInt(1))
+		}
+	}
+	return nil
+}
+
+// blockPeriod estimates the block time from the given number of recent blocks.
+func (oracle *Oracle) blockPeriod(ctx context.Context, head *types.Header, blocks uint64) time.Duration {
+	if blocks < 2 || head.Number.Uint64() < blocks-1 {
+		return defaultBlockPeriod
+	}
+	first, err := oracle.backend.HeaderByNumber(ctx, rpc.BlockNumber(head.Number.Uint64()-(blocks-1)))
+	if err != nil || first == nil || head.Time <= first.Time {
+		return defaultBlockPeriod
+	}
+	return time.Duration(head.Time-first.Time) * time.Second / time.Duration(blocks-1)
+}
+
+// worstBaseFee returns the base fee after the given number of full blocks,
+// starting with the base fee of the next block.
+func worstBaseFee(config *params.ChainConfig, baseFee *big.Int, blocks uint64) *big.Int {
+	fee := new(big.Int).Set(baseFee)
+	for i := uint64(1); i < blocks; i++ {
+		fee.Add(fee, new(big.Int).Div(fee, new(big.Int).SetUint64(config.BaseFeeChangeDenominator())))
+	}
+	return fee
+}
+
+// worstBlobFee returns the blob base fee after the given number of blocks full
+// of blobs, starting with the blob base fee of the next block.
+func worstBlobFee(excessBlobGas, blobGasUsed uint64, blocks uint64) *big.Int {
+	excess := eip4844.CalcExcessBlobGas(excessBlobGas, blobGasUsed)
+	for i := uint64(1); i < blocks; i++ {
+		excess = eip4844.CalcExcessBlobGas(excess, params.MaxBlobGasPerBlock)
+	}
+	return eip4844.CalcBlobFee(excess)
+}
+
+// median returns the median of the given values, or zero if there are none.
+func median(values []*big.Int) *big.Int {
+	if len(values) == 0 {
+		return new(big.Int)
+	}
+	sorted := slices.Clone(values)
+	slices.SortFunc(sorted, func(a, b *big.Int) int { return a.Cmp(b) })
+	return new(big.Int).Set(sorted[len(sorted)/2])
+}
@@ -0,0 +1,250 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// estimateBackend is an in-memory oracle backend serving a generated chain and
+// a fixed pool content.
+type estimateBackend struct {
+	blocks   []*types.Block
+	receipts map[common.Hash]types.Receipts
+	pending  *types.Block
+	pool     map[common.Address][]*types.Transaction
+	queries  int // Number of pool content queries
+}
+
+func (b *estimateBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	block, err := b.BlockByNumber(ctx, number)
+	if block == nil {
+		return nil, err
+	}
+	return block.Header(), err
+}
+
+func (b *estimateBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error) {
+	switch {
+	case number == rpc.LatestBlockNumber:
+		return b.blocks[len(b.blocks)-1], nil
+	case number >= 0 && int(number) < len(b.blocks):
+		return b.blocks[number], nil
+	}
+	return nil, nil
+}
+
+func (b *estimateBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.receipts[hash], nil
+}
+
+func (b *estimateBackend) Pending() (*types.Block, types.Receipts, *state.StateDB) {
+	return b.pending, nil, nil
+}
+
+func (b *estimateBackend) TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction) {
+	b.queries++
+	return b.pool, nil
+}
+
+func (b *estimateBackend) ChainConfig() *params.ChainConfig {
+	return params.TestChainConfig
+}
+
+func (b *estimateBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return nil
+}
+
+// newEstimateBackend creates a chain in which every block carries transactions
+// paying tips of 1, 2, ..., 10 gwei.
+func newEstimateBackend(t *testing.T) *estimateBackend {
+	var (
+		key, _ = crypto.GenerateKey()
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		gspec  = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+	)
+	_, blocks, receipts := core.GenerateChainWithGenesis(gspec, ethash.NewFaker(), 10, func(i int, b *core.BlockGen) {
+		for tip := int64(1); tip <= 10; tip++ {
+			tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+				ChainID:   params.TestChainConfig.ChainID,
+				Nonce:     b.TxNonce(addr),
+				To:        &common.Address{},
+				Gas:       params.TxGas,
+				GasFeeCap: new(big.Int).Add(b.BaseFee(), big.NewInt(tip*params.GWei)),
+				GasTipCap: big.NewInt(tip * params.GWei),
+			})
+			b.AddTx(tx)
+		}
+	})
+	backend := &estimateBackend{
+		blocks:   append([]*types.Block{gspec.ToBlock()}, blocks...),
+		receipts: make(map[common.Hash]types.Receipts),
+	}
+	for i, block := range blocks {
+		backend.receipts[block.Hash()] = receipts[i]
+	}
+	return backend
+}
+
+// poolTxs creates n pending transactions of 1M gas paying the given tip.
+func poolTxs(t *testing.T, n int, tip int64) []*types.Transaction {
+	key, _ := crypto.GenerateKey()
+	signer := types.LatestSigner(params.TestChainConfig)
+
+	txs := make([]*types.Transaction, n)
+	for i := range txs {
+		txs[i] = types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   params.TestChainConfig.ChainID,
+			Nonce:     uint64(i),
+			To:        &common.Address{},
+			Gas:       1_000_000,
+			GasFeeCap: big.NewInt(1000 * params.GWei),
+			GasTipCap: big.NewInt(tip * params.GWei),
+		})
+	}
+	return txs
+}
+
+func TestFeeEstimates(t *testing.T) {
+	backend := newEstimateBackend(t)
+	oracle := NewOracle(backend, Config{Blocks: 20, Percentile: 60, MaxBlockHistory: 1024}, nil)
+
+	estimates, err := oracle.FeeEstimates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := backend.blocks[len(backend.blocks)-1].Header()
+	if want := eip1559.CalcBaseFee(params.TestChainConfig, head); estimates.BaseFee.Cmp(want) != 0 {
+		t.Errorf("wrong base fee %v, want %v", estimates.BaseFee, want)
+	}
+	if estimates.Predictive || estimates.BlobBaseFee != nil {
+		t.Errorf("unexpected predictive mode or blob fee")
+	}
+	if backend.queries != 0 {
+		t.Errorf("pool queried in non-predictive mode")
+	}
+	// The baseline tips are the 10th, 50th and 90th percentiles of the history
+	for i, want := range []int64{1, 5, 9} {
+		level := []*FeeEstimate{estimates.Low, estimates.Medium, estimates.High}[i]
+		if level.MaxPriorityFeePerGas.Cmp(big.NewInt(want*params.GWei)) != 0 {
+			t.Errorf("level %d: wrong tip %v, want %d gwei", i, level.MaxPriorityFeePerGas, want)
+		}
+		wantFee := new(big.Int).Add(worstBaseFee(params.TestChainConfig, estimates.BaseFee, level.InclusionBlocks), level.MaxPriorityFeePerGas)
+		if level.MaxFeePerGas.Cmp(wantFee) != 0 {
+			t.Errorf("level %d: wrong fee cap %v, want %v", i, level.MaxFeePerGas, wantFee)
+		}
+		// Generated blocks are 10 seconds apart
+		if want := time.Duration(level.InclusionBlocks) * 10 * time.Second; level.InclusionTime != want {
+			t.Errorf("level %d: wrong inclusion time %v, want %v", i, level.InclusionTime, want)
+		}
+	}
+}
+
+func TestFeeEstimatesPredictive(t *testing.T) {
+	backend := newEstimateBackend(t)
+	oracle := NewOracle(backend, Config{Blocks: 20, Percentile: 60, MaxBlockHistory: 1024, Predictive: true}, nil)
+
+	// A backlog filling up a single block only affects the high urgency level
+	gasLimit := backend.blocks[len(backend.blocks)-1].GasLimit()
+	backlog := poolTxs(t, int(gasLimit/1_000_000)+1, 50)
+	backend.pool = map[common.Address][]*types.Transaction{{1}: backlog}
+
+	estimates, err := oracle.FeeEstimates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !estimates.Predictive {
+		t.Error("predictive mode not reported")
+	}
+	if want := big.NewInt(50*params.GWei + 1); estimates.High.MaxPriorityFeePerGas.Cmp(want) != 0 {
+		t.Errorf("wrong high tip %v, want %v", estimates.High.MaxPriorityFeePerGas, want)
+	}
+	if want := big.NewInt(5 * params.GWei); estimates.Medium.MaxPriorityFeePerGas.Cmp(want) != 0 {
+		t.Errorf("wrong medium tip %v, want %v", estimates.Medium.MaxPriorityFeePerGas, want)
+	}
+	// The backlog is cached for the head, the pool is not queried again
+	if _, err := oracle.FeeEstimates(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if backend.queries != 1 {
+		t.Errorf("pool queried %d times, want 1", backend.queries)
+	}
+}
+
+func TestFeeEstimatesPendingBlock(t *testing.T) {
+	backend := newEstimateBackend(t)
+	oracle := NewOracle(backend, Config{Blocks: 20, Percentile: 60, MaxBlockHistory: 1024, Predictive: true}, nil)
+
+	// Half a block of backlog doesn't fill up the next block on its own, but it
+	// does if the pending block already used half of the gas.
+	head := backend.blocks[len(backend.blocks)-1]
+	backend.pool = map[common.Address][]*types.Transaction{{1}: poolTxs(t, int(head.GasLimit()/2_000_000)+1, 50)}
+	backend.pending = types.NewBlockWithHeader(&types.Header{
+		ParentHash: head.Hash(),
+		Number:     new(big.Int).Add(head.Number(), common.Big1),
+		GasLimit:   head.GasLimit(),
+		GasUsed:    head.GasLimit() / 2,
+	})
+	estimates, err := oracle.FeeEstimates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := big.NewInt(50*params.GWei + 1); estimates.High.MaxPriorityFeePerGas.Cmp(want) != 0 {
+		t.Errorf("wrong high tip %v, want %v", estimates.High.MaxPriorityFeePerGas, want)
+	}
+}
+
+func TestBacklogTip(t *testing.T) {
+	backlog := []pendingTx{
+		{tip: big.NewInt(30), gas: 100},
+		{tip: big.NewInt(20), gas: 100},
+		{tip: big.NewInt(10), gas: 100},
+	}
+	for _, tt := range []struct {
+		gas  uint64
+		want *big.Int
+	}{
+		{gas: 0, want: big.NewInt(31)},
+		{gas: 100, want: big.NewInt(31)},
+		{gas: 150, want: big.NewInt(21)},
+		{gas: 300, want: big.NewInt(11)},
+		{gas: 301, want: nil},
+	} {
+		tip := backlogTip(backlog, tt.gas)
+		if (tip == nil) != (tt.want == nil) || (tip != nil && tip.Cmp(tt.want) != 0) {
+			t.Errorf("gas %d: wrong tip %v, want %v", tt.gas, tip, tt.want)
+		}
+	}
+}
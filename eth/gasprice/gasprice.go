@@ -47,6 +47,7 @@ type Config struct {
 	MaxBlockHistory  uint64
 	MaxPrice         *big.Int `toml:",omitempty"`
 	IgnorePrice      *big.Int `toml:",omitempty"`
+	Predictive       bool     `toml:",omitempty"` // Whether fee estimates take the pending pool into account
 }
 
 // OracleBackend includes all necessary background APIs for oracle.
@@ -55,6 +56,7 @@ type OracleBackend interface {
 	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Block, error)
 	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
 	Pending() (*types.Block, types.Receipts, *state.StateDB)
+	TxPoolContent() (map[common.Address][]*types.Transaction, map[common.Address][]*types.Transaction)
 	ChainConfig() *params.ChainConfig
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 }
@@ -67,6 +69,7 @@ type Oracle struct {
 	lastPrice   *big.Int
 	maxPrice    *big.Int
 	ignorePrice *big.Int
+	predictive  bool
 	cacheLock   sync.RWMutex
 	fetchLock   sync.Mutex
 
@@ -74,6 +77,11 @@ type Oracle struct {
 	maxHeaderHistory, maxBlockHistory uint64
 
 	historyCache *lru.Cache[cacheKey, processedFees]
+
+	backlogLock sync.Mutex  // Protects the pending backlog cache of fee estimates
+	backlogHead common.Hash // Head the pending backlog was collected for
+	backlog     []pendingTx // Pending pool transactions, sorted by tip
+	backlogUsed uint64      // Gas used by the pending block on top of the head
 }
 
 // NewOracle returns a new gasprice oracle which can recommend suitable
@@ -143,6 +151,7 @@ func NewOracle(backend OracleBackend, params Config, startPrice *big.Int) *Oracl
 		lastPrice:        startPrice,
 		maxPrice:         maxPrice,
 		ignorePrice:      ignorePrice,
+		predictive:       params.Predictive,
 		checkBlocks:      blocks,
 		percentile:       percent,
 		maxHeaderHistory: maxHeaderHistory,
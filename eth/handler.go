@@ -52,6 +52,10 @@ const (
 	// All transactions with a higher size will be announced and need to be fetched
 	// by the peer.
 	txMaxBroadcastSize = 4096
+
+	// blockRangeUpdateInterval is the number of blocks the head needs to advance
+	// before the served block range is announced to the peers again.
+	blockRangeUpdateInterval = 32
 )
 
 var syncChallengeTimeout = 15 * time.Second // Time allowance for a node to reply to the sync progress challenge
@@ -78,20 +82,25 @@ type txPool interface {
 	// can decide whether to receive notifications only for newly seen transactions
 	// or also for reorged out ones.
 	SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription
+
+	// LocalSenders retrieves the accounts with locally submitted transactions in
+	// the pool, whether or not the pool treats them as local.
+	LocalSenders() []common.Address
 }
 
 // handlerConfig is the collection of initialization parameters to create a full
 // node network handler.
 type handlerConfig struct {
-	NodeID         enode.ID               // P2P node ID used for tx propagation topology
-	Database       ethdb.Database         // Database for direct sync insertions
-	Chain          *core.BlockChain       // Blockchain to serve data from
-	TxPool         txPool                 // Transaction pool to propagate from
-	Network        uint64                 // Network identifier to advertise
-	Sync           ethconfig.SyncMode     // Whether to snap or full sync
-	BloomCache     uint64                 // Megabytes to alloc for snap sync bloom
-	EventMux       *event.TypeMux         // Legacy event mux, deprecate for `feed`
-	RequiredBlocks map[uint64]common.Hash // Hard coded map of required block hashes for sync challenges
+	NodeID         enode.ID                    // P2P node ID used for tx propagation topology
+	Database       ethdb.Database              // Database for direct sync insertions
+	Chain          *core.BlockChain            // Blockchain to serve data from
+	TxPool         txPool                      // Transaction pool to propagate from
+	Network        uint64                      // Network identifier to advertise
+	Sync           ethconfig.SyncMode          // Whether to snap or full sync
+	BloomCache     uint64                      // Megabytes to alloc for snap sync bloom
+	EventMux       *event.TypeMux              // Legacy event mux, deprecate for `feed`
+	RequiredBlocks map[uint64]common.Hash      // Hard coded map of required block hashes for sync challenges
+	TxBroadcast    ethconfig.TxBroadcastConfig // Transaction propagation policy
 }
 
 type handler struct {
@@ -116,6 +125,7 @@ type handler struct {
 	txsSub   event.Subscription
 
 	requiredBlocks map[uint64]common.Hash
+	txBroadcast    ethconfig.TxBroadcastConfig
 
 	// channels for fetcher, syncer, txsyncLoop
 	quitSync chan struct{}
@@ -142,6 +152,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		chain:          config.Chain,
 		peers:          newPeerSet(),
 		requiredBlocks: config.RequiredBlocks,
+		txBroadcast:    config.TxBroadcast,
 		quitSync:       make(chan struct{}),
 		handlerDoneCh:  make(chan struct{}),
 		handlerStartCh: make(chan struct{}),
@@ -180,6 +191,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 	}
 	// Construct the downloader (long sync)
 	h.downloader = downloader.New(config.Database, h.eventMux, h.chain, h.removePeer, h.enableSyncedFeatures)
+	h.downloader.SetPeerReporter(h.reportPeer)
 
 	fetchTx := func(peer string, hashes []common.Hash) error {
 		p := h.peers.peer(peer)
@@ -256,7 +268,7 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 		td      = h.chain.GetTd(hash, number)
 	)
 	forkID := forkid.NewID(h.chain.Config(), genesis, number, head.Time)
-	if err := peer.Handshake(h.networkID, td, hash, genesis.Hash(), forkID, h.forkFilter); err != nil {
+	if err := peer.Handshake(h.networkID, td, genesis.Hash(), forkID, h.forkFilter, h.blockRange(head)); err != nil {
 		peer.Log().Debug("Ethereum handshake failed", "err", err)
 		return err
 	}
@@ -383,10 +395,41 @@ func (h *handler) runSnapExtension(peer *snap.Peer, handler snap.Handler) error
 func (h *handler) removePeer(id string) {
 	peer := h.peers.peer(id)
 	if peer != nil {
+		peer.Peer.Report(p2p.RepUselessResponse)
 		peer.Peer.Disconnect(p2p.DiscUselessPeer)
 	}
 }
 
+// reportPeer adjusts the reputation of a peer.
+func (h *handler) reportPeer(id string, ev p2p.ReputationEvent) {
+	if peer := h.peers.peer(id); peer != nil {
+		peer.Peer.Report(ev)
+	}
+}
+
+// reputationLoop lowers the reputation of peers whose eth or snap requests
+// time out.
+func (h *handler) reputationLoop() {
+	defer h.wg.Done()
+
+	var (
+		lost    = make(chan string, 64)
+		ethSub  = eth.SubscribeLostRequests(lost)
+		snapSub = snap.SubscribeLostRequests(lost)
+	)
+	defer ethSub.Unsubscribe()
+	defer snapSub.Unsubscribe()
+
+	for {
+		select {
+		case id := <-lost:
+			h.reportPeer(id, p2p.RepTimeout)
+		case <-h.quitSync:
+			return
+		}
+	}
+}
+
 // unregisterPeer removes a peer from the downloader, fetchers and main peer set.
 func (h *handler) unregisterPeer(id string) {
 	// Create a custom logger to avoid printing the entire id
@@ -427,6 +470,15 @@ func (h *handler) Start(maxPeers int) {
 	h.txsSub = h.txpool.SubscribeTransactions(h.txsCh, false)
 	go h.txBroadcastLoop()
 
+	// announce the served block range to eth/69 peers
+	h.wg.Add(1)
+	heads := make(chan core.ChainHeadEvent, 10)
+	go h.blockRangeLoop(h.chain.SubscribeChainHeadEvent(heads), heads)
+
+	// lower the reputation of peers timing out on requests
+	h.wg.Add(1)
+	go h.reputationLoop()
+
 	// start sync handlers
 	h.txFetcher.Start()
 
@@ -458,16 +510,23 @@ func (h *handler) Stop() {
 // - To a square root of all peers for non-blob transactions
 // - And, separately, as announcements to all peers which are not known to
 // already have the given transaction.
+//
+// The transaction broadcast policy may restrict transactions to announcements,
+// limit the number of peers they are propagated to and keep local transactions
+// private to trusted peers.
 func (h *handler) BroadcastTransactions(txs types.Transactions) {
 	var (
-		blobTxs  int // Number of blob transactions to announce only
-		largeTxs int // Number of large transactions to announce only
-
+		blobTxs     int // Number of blob transactions to announce only
+		largeTxs    int // Number of large transactions to announce only
+		annOnlyTxs  int // Number of transactions announced only due to the broadcast policy
+		privateTxs  int // Number of local transactions restricted to trusted peers
 		directCount int // Number of transactions sent directly to peers (duplicates included)
 		annCount    int // Number of transactions announced across all peers (duplicates included)
 
 		txset = make(map[*ethPeer][]common.Hash) // Set peer->hash to transfer directly
 		annos = make(map[*ethPeer][]common.Hash) // Set peer->hash to announce
+
+		policy = &h.txBroadcast
 	)
 	// Broadcast transactions to a batch of peers not knowing about it
 	direct := big.NewInt(int64(math.Sqrt(float64(h.peers.len())))) // Approximate number of peers to broadcast to
@@ -480,17 +539,28 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		signer = types.LatestSignerForChainID(h.chain.Config().ChainID) // Don't care about chain status, we just need *a* sender
 		hasher = crypto.NewKeccakState()
 		hash   = make([]byte, 32)
+		locals = h.privateAccounts()
 	)
 	for _, tx := range txs {
-		var maybeDirect bool
+		var (
+			rule        = policy.Rule(tx.Type())
+			maybeDirect bool
+		)
 		switch {
 		case tx.Type() == types.BlobTxType:
 			blobTxs++
 		case tx.Size() > txMaxBroadcastSize:
 			largeTxs++
+		case policy.AnnounceOnly || rule.AnnounceOnly:
+			annOnlyTxs++
 		default:
 			maybeDirect = true
 		}
+		from, _ := types.Sender(signer, tx) // Ignore error, we only use the addr as a propagation target splitter
+		_, private := locals[from]
+		if private {
+			privateTxs++
+		}
 		// Send the transaction (if it's small enough) directly to a subset of
 		// the peers that have not received it yet, ensuring that the flow of
 		// transactions is grouped by account to (try and) avoid nonce gaps.
@@ -498,14 +568,21 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		// To do this, we hash the local enode IW with together with a peer's
 		// enode ID together with the transaction sender and broadcast if
 		// `sha(self, peer, sender) mod peers < sqrt(peers)`.
+		//
+		// Private transactions are sent directly to all trusted peers instead.
+		var sent, directSent, annSent int
 		for _, peer := range h.peers.peersWithoutTransaction(tx.Hash()) {
-			var broadcast bool
-			if maybeDirect {
+			if private && !peer.Peer.Trusted() {
+				continue
+			}
+			if rule.MaxPeers > 0 && sent >= rule.MaxPeers {
+				break
+			}
+			broadcast := maybeDirect && private
+			if maybeDirect && !private {
 				hasher.Reset()
 				hasher.Write(h.nodeID.Bytes())
 				hasher.Write(peer.Node().ID().Bytes())
-
-				from, _ := types.Sender(signer, tx) // Ignore error, we only use the addr as a propagation target splitter
 				hasher.Write(from.Bytes())
 
 				hasher.Read(hash)
@@ -513,11 +590,20 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 					broadcast = true
 				}
 			}
-			if broadcast {
+			if broadcast && policy.MaxDirectPeers > 0 && directSent >= policy.MaxDirectPeers {
+				broadcast = false
+			}
+			switch {
+			case broadcast:
 				txset[peer] = append(txset[peer], tx.Hash())
-			} else {
+				directSent++
+			case policy.MaxAnnouncePeers > 0 && annSent >= policy.MaxAnnouncePeers:
+				continue
+			default:
 				annos[peer] = append(annos[peer], tx.Hash())
+				annSent++
 			}
+			sent++
 		}
 	}
 	for peer, hashes := range txset {
@@ -528,8 +614,23 @@ func (h *handler) BroadcastTransactions(txs types.Transactions) {
 		annCount += len(hashes)
 		peer.AsyncSendPooledTransactionHashes(hashes)
 	}
-	log.Debug("Distributed transactions", "plaintxs", len(txs)-blobTxs-largeTxs, "blobtxs", blobTxs, "largetxs", largeTxs,
-		"bcastpeers", len(txset), "bcastcount", directCount, "annpeers", len(annos), "anncount", annCount)
+	log.Debug("Distributed transactions", "plaintxs", len(txs)-blobTxs-largeTxs-annOnlyTxs, "blobtxs", blobTxs, "largetxs", largeTxs,
+		"annonlytxs", annOnlyTxs, "privatetxs", privateTxs, "bcastpeers", len(txset), "bcastcount", directCount, "annpeers", len(annos), "anncount", annCount)
+}
+
+// privateAccounts returns the set of accounts whose transactions may only be
+// propagated to trusted peers. It is empty unless the private broadcast policy
+// is enabled.
+func (h *handler) privateAccounts() map[common.Address]struct{} {
+	if !h.txBroadcast.Private {
+		return nil
+	}
+	locals := h.txpool.LocalSenders()
+	set := make(map[common.Address]struct{}, len(locals))
+	for _, addr := range locals {
+		set[addr] = struct{}{}
+	}
+	return set
 }
 
 // txBroadcastLoop announces new transactions to connected peers.
@@ -545,6 +646,49 @@ func (h *handler) txBroadcastLoop() {
 	}
 }
 
+// blockRange returns the range of blocks served by the local node, i.e. the ones
+// whose history hasn't been pruned yet.
+func (h *handler) blockRange(head *types.Header) eth.BlockRangeUpdatePacket {
+	number := head.Number.Uint64()
+	earliest, err := h.database.Tail()
+	if err != nil {
+		earliest = 0 // no ancient store, nothing was pruned
+	}
+	return eth.BlockRangeUpdatePacket{
+		EarliestBlock:   min(earliest, number),
+		LatestBlock:     number,
+		LatestBlockHash: head.Hash(),
+	}
+}
+
+// blockRangeLoop announces changes of the served block range to the peers, each
+// time the head advances by blockRangeUpdateInterval blocks, when the head is
+// rewound, or when old history is pruned.
+func (h *handler) blockRangeLoop(sub event.Subscription, heads chan core.ChainHeadEvent) {
+	defer h.wg.Done()
+	defer sub.Unsubscribe()
+
+	last := h.blockRange(h.chain.CurrentHeader())
+	for {
+		select {
+		case ev := <-heads:
+			current := h.blockRange(ev.Header)
+			if current.EarliestBlock == last.EarliestBlock && current.LatestBlock >= last.LatestBlock &&
+				current.LatestBlock < last.LatestBlock+blockRangeUpdateInterval {
+				continue
+			}
+			last = current
+			for _, peer := range h.peers.all() {
+				peer.AsyncSendBlockRangeUpdate(current)
+			}
+		case <-sub.Err():
+			return
+		case <-h.quitSync:
+			return
+		}
+	}
+}
+
 // enableSyncedFeatures enables the post-sync functionalities when the initial
 // sync is finished.
 func (h *handler) enableSyncedFeatures() {
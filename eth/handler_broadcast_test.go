@@ -0,0 +1,245 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/simnet"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// broadcastResult is the set of peers a transaction was sent to directly and
+// the set of peers it was announced to.
+type broadcastResult struct {
+	direct    map[enode.ID]bool
+	announced map[enode.ID]bool
+}
+
+// runBroadcast connects the given number of peers to a node running the given
+// broadcast policy, with the first trusted peers marked as trusted. It then adds
+// the transaction to the node's pool and collects the peers which received it,
+// until want deliveries were seen and no more arrive.
+func runBroadcast(t *testing.T, policy ethconfig.TxBroadcastConfig, peers, trusted int, tx *types.Transaction, local bool, want int) ([]*simnet.Node, broadcastResult) {
+	t.Helper()
+
+	net, nodes, handlers := newSimnet(t, simnet.Config{}, peers+1, policy)
+	for _, n := range nodes[1 : trusted+1] {
+		nodes[0].Server.AddTrustedPeer(n.Node())
+	}
+	events := make(chan *simnet.Event, 1024)
+	sub := net.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	net.ConnectStar(nodes[0], nodes[1:])
+	waitEthPeers(t, handlers[:1], peers)
+	waitEthPeers(t, handlers[1:], 1)
+
+	handlers[0].txpool.Add([]*types.Transaction{tx}, local, false)
+
+	// Only count the messages of the broadcasting node, the peers relay the
+	// transaction back to it after retrieval.
+	res := broadcastResult{direct: make(map[enode.ID]bool), announced: make(map[enode.ID]bool)}
+	timeout := time.After(10 * time.Second)
+	for {
+		quiet := time.After(200 * time.Millisecond)
+		if len(res.direct)+len(res.announced) < want {
+			quiet = nil
+		}
+		select {
+		case ev := <-events:
+			if ev.Type != p2p.PeerEventTypeMsgRecv || ev.Protocol != eth.ProtocolName || ev.Peer != nodes[0].ID() {
+				continue
+			}
+			switch *ev.MsgCode {
+			case eth.TransactionsMsg:
+				res.direct[ev.Node] = true
+			case eth.NewPooledTransactionHashesMsg:
+				res.announced[ev.Node] = true
+			}
+		case <-quiet:
+			return nodes, res
+		case <-timeout:
+			t.Fatalf("timeout waiting for broadcast, have %d direct and %d announced, want %d", len(res.direct), len(res.announced), want)
+		}
+	}
+}
+
+// newTestDynamicFeeTransaction creates a signed dynamic fee transfer of the
+// tester account.
+func newTestDynamicFeeTransaction(t *testing.T, nonce uint64) *types.Transaction {
+	t.Helper()
+
+	tx, err := types.SignNewTx(testKey, testSigner, &types.DynamicFeeTx{
+		ChainID:   params.TestChainConfig.ChainID,
+		Nonce:     nonce,
+		Gas:       100000,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// newTestBlobTransaction creates a signed blob transaction of the tester
+// account, without a sidecar.
+func newTestBlobTransaction(t *testing.T, nonce uint64) *types.Transaction {
+	t.Helper()
+
+	tx, err := types.SignNewTx(testKey, testSigner, &types.BlobTx{
+		ChainID:    uint256.MustFromBig(params.TestChainConfig.ChainID),
+		Nonce:      nonce,
+		Gas:        100000,
+		GasTipCap:  uint256.NewInt(1),
+		GasFeeCap:  uint256.NewInt(1),
+		BlobFeeCap: uint256.NewInt(1),
+		BlobHashes: []common.Hash{{0x01}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// Tests that local transactions are only propagated to trusted peers if the
+// private broadcast policy is enabled, while remote ones reach everyone.
+func TestBroadcastPrivate(t *testing.T) {
+	t.Parallel()
+
+	private := ethconfig.TxBroadcastConfig{Private: true}
+	tests := []struct {
+		name     string
+		tx       *types.Transaction
+		local    bool
+		direct   int
+		announce int
+	}{
+		// Small local transactions are sent to every trusted peer
+		{name: "local", tx: newTestTransaction(t, 0, 0), local: true, direct: 2},
+		// Blob transactions are only announced, but also only to trusted peers
+		{name: "local blob", tx: newTestBlobTransaction(t, 0), local: true, announce: 2},
+		// Large transactions are only announced, but also only to trusted peers
+		{name: "local large", tx: newTestTransaction(t, 0, txMaxBroadcastSize), local: true, announce: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			nodes, res := runBroadcast(t, private, 4, 2, test.tx, test.local, test.direct+test.announce)
+			if len(res.direct) != test.direct || len(res.announced) != test.announce {
+				t.Fatalf("wrong propagation: %d direct, %d announced, want %d and %d", len(res.direct), len(res.announced), test.direct, test.announce)
+			}
+			for _, n := range nodes[3:] {
+				if res.direct[n.ID()] || res.announced[n.ID()] {
+					t.Errorf("local transaction propagated to untrusted peer %v", n.ID())
+				}
+			}
+		})
+	}
+	// Transactions of other accounts aren't restricted
+	t.Run("remote", func(t *testing.T) {
+		t.Parallel()
+
+		_, res := runBroadcast(t, private, 4, 2, newTestTransaction(t, 0, 0), false, 4)
+		if n := len(res.direct) + len(res.announced); n != 4 {
+			t.Fatalf("remote transaction propagated to %d peers, want 4", n)
+		}
+	})
+}
+
+// Tests that the broadcast policy, its per-type rules and its peer limits are
+// applied to the propagation of transactions.
+func TestBroadcastPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		policy   ethconfig.TxBroadcastConfig
+		trusted  int
+		tx       *types.Transaction
+		local    bool
+		direct   int
+		announce int
+	}{
+		{
+			name:     "announce only",
+			policy:   ethconfig.TxBroadcastConfig{AnnounceOnly: true},
+			tx:       newTestTransaction(t, 0, 0),
+			announce: 4,
+		},
+		{
+			name:     "type announce only",
+			policy:   ethconfig.TxBroadcastConfig{Rules: []ethconfig.TxBroadcastRule{{Type: types.DynamicFeeTxType, AnnounceOnly: true}}},
+			tx:       newTestDynamicFeeTransaction(t, 0),
+			announce: 4,
+		},
+		{
+			name:     "type max peers",
+			policy:   ethconfig.TxBroadcastConfig{AnnounceOnly: true, Rules: []ethconfig.TxBroadcastRule{{Type: types.LegacyTxType, MaxPeers: 3}}},
+			tx:       newTestTransaction(t, 0, 0),
+			announce: 3,
+		},
+		{
+			// Private local transactions go directly to all trusted peers, which
+			// makes the direct peer count deterministic
+			name:     "max direct peers",
+			policy:   ethconfig.TxBroadcastConfig{Private: true, MaxDirectPeers: 1},
+			trusted:  4,
+			tx:       newTestTransaction(t, 0, 0),
+			local:    true,
+			direct:   1,
+			announce: 3,
+		},
+		{
+			name:     "max announce peers",
+			policy:   ethconfig.TxBroadcastConfig{AnnounceOnly: true, MaxAnnouncePeers: 2},
+			tx:       newTestTransaction(t, 0, 0),
+			announce: 2,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, res := runBroadcast(t, test.policy, 4, test.trusted, test.tx, test.local, test.direct+test.announce)
+			if len(res.direct) != test.direct || len(res.announced) != test.announce {
+				t.Fatalf("wrong propagation: %d direct, %d announced, want %d and %d", len(res.direct), len(res.announced), test.direct, test.announce)
+			}
+		})
+	}
+	// Rules only apply to their own transaction type
+	t.Run("other type", func(t *testing.T) {
+		t.Parallel()
+
+		policy := ethconfig.TxBroadcastConfig{Rules: []ethconfig.TxBroadcastRule{{Type: types.DynamicFeeTxType, AnnounceOnly: true, MaxPeers: 1}}}
+		_, res := runBroadcast(t, policy, 4, 0, newTestTransaction(t, 0, 0), false, 4)
+		if n := len(res.direct) + len(res.announced); n != 4 {
+			t.Fatalf("transaction propagated to %d peers, want 4", n)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/simnet"
+)
+
+// newSimnet creates a simulated network of count started nodes, each running
+// the eth protocol on a test handler with the given broadcast policy.
+func newSimnet(t *testing.T, cfg simnet.Config, count int, broadcast ethconfig.TxBroadcastConfig) (*simnet.Network, []*simnet.Node, []*testHandler) {
+	t.Helper()
+
+	cfg.Logger = testlog.Logger(t, log.LvlInfo)
+	net := simnet.New(cfg)
+	t.Cleanup(net.Shutdown)
+
+	var (
+		nodes    = make([]*simnet.Node, count)
+		handlers = make([]*testHandler, count)
+	)
+	for i := range nodes {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		handlers[i] = newTestHandler(t, enode.PubkeyToIDV4(&key.PublicKey), broadcast)
+		n, err := net.NewNode(simnet.NodeConfig{
+			PrivateKey: key,
+			Protocols:  eth.MakeProtocols((*ethHandler)(handlers[i].handler), 1, nil),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := n.Start(); err != nil {
+			t.Fatal(err)
+		}
+		nodes[i] = n
+	}
+	return net, nodes, handlers
+}
+
+// waitEthPeers waits until every handler has registered the given number of
+// eth peers.
+func waitEthPeers(t *testing.T, handlers []*testHandler, peers int) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for _, h := range handlers {
+		for h.handler.peers.len() < peers {
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout waiting for eth peers, have %d, want %d", h.handler.peers.len(), peers)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+}
+
+// Tests that transactions propagate across a simulated network, both by direct
+// broadcast and by announcement and retrieval, relayed by every node.
+func TestSimnetTransactionPropagation(t *testing.T) {
+	net, nodes, handlers := newSimnet(t, simnet.Config{Seed: 1, Link: simnet.Link{Latency: 10 * time.Millisecond}}, 8, ethconfig.TxBroadcastConfig{})
+
+	// Nodes farther apart than one hop in the ring only receive the transactions
+	// relayed by others.
+	net.ConnectRing(nodes)
+	waitEthPeers(t, handlers, 2)
+
+	txs := []*types.Transaction{newTestTransaction(t, 0, 0), newTestTransaction(t, 1, 2048)}
+	handlers[0].txpool.Add(txs, false, false)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for i, h := range handlers {
+		for _, tx := range txs {
+			for !h.txpool.Has(tx.Hash()) {
+				if time.Now().After(deadline) {
+					t.Fatalf("transaction %x not propagated to node %d", tx.Hash(), i)
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+var (
+	// testKey is a private key to use for funding a tester account.
+	testKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2b96dbea7d2f00")
+
+	// testAddr is the Ethereum address of the tester account.
+	testAddr = crypto.PubkeyToAddress(testKey.PublicKey)
+
+	// testSigner signs the transactions of the tester account.
+	testSigner = types.LatestSignerForChainID(params.TestChainConfig.ChainID)
+)
+
+// testTxPool is a mock transaction pool that blindly accepts all transactions.
+// Its goal is to get around setting up a valid statedb for the balance and nonce
+// checks.
+type testTxPool struct {
+	pool   map[common.Hash]*types.Transaction // Hash map of collected transactions
+	locals map[common.Address]struct{}        // Senders of locally submitted transactions
+
+	txFeed event.Feed   // Notification feed to allow waiting for inclusion
+	lock   sync.RWMutex // Protects the transaction pool
+}
+
+// newTestTxPool creates a mock transaction pool.
+func newTestTxPool() *testTxPool {
+	return &testTxPool{
+		pool:   make(map[common.Hash]*types.Transaction),
+		locals: make(map[common.Address]struct{}),
+	}
+}
+
+// Has returns an indicator whether txpool has a transaction
+// cached with the given hash.
+func (p *testTxPool) Has(hash common.Hash) bool {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.pool[hash] != nil
+}
+
+// Get retrieves the transaction from local txpool with given
+// tx hash.
+func (p *testTxPool) Get(hash common.Hash) *types.Transaction {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.pool[hash]
+}
+
+// Add appends a batch of transactions to the pool, and notifies any
+// listeners if the addition channel is non nil
+func (p *testTxPool) Add(txs []*types.Transaction, local bool, sync bool) []error {
+	p.lock.Lock()
+	var added []*types.Transaction
+	for _, tx := range txs {
+		if p.pool[tx.Hash()] != nil {
+			continue
+		}
+		p.pool[tx.Hash()] = tx
+		added = append(added, tx)
+		if local {
+			from, _ := types.Sender(testSigner, tx)
+			p.locals[from] = struct{}{}
+		}
+	}
+	p.lock.Unlock()
+
+	if len(added) > 0 {
+		p.txFeed.Send(core.NewTxsEvent{Txs: added})
+	}
+	return make([]error, len(txs))
+}
+
+// Pending returns all the transactions known to the pool
+func (p *testTxPool) Pending(filter txpool.PendingFilter) map[common.Address][]*txpool.LazyTransaction {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	batches := make(map[common.Address][]*txpool.LazyTransaction)
+	for _, tx := range p.pool {
+		from, _ := types.Sender(testSigner, tx)
+		batches[from] = append(batches[from], &txpool.LazyTransaction{
+			Hash:      tx.Hash(),
+			Tx:        tx,
+			Time:      tx.Time(),
+			GasFeeCap: uint256.MustFromBig(tx.GasFeeCap()),
+			GasTipCap: uint256.MustFromBig(tx.GasTipCap()),
+			Gas:       tx.Gas(),
+			BlobGas:   tx.BlobGas(),
+		})
+	}
+	return batches
+}
+
+// SubscribeTransactions should return an event subscription of NewTxsEvent and
+// send events to the given channel.
+func (p *testTxPool) SubscribeTransactions(ch chan<- core.NewTxsEvent, reorgs bool) event.Subscription {
+	return p.txFeed.Subscribe(ch)
+}
+
+// LocalSenders returns the senders of the transactions submitted as local.
+func (p *testTxPool) LocalSenders() []common.Address {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	locals := make([]common.Address, 0, len(p.locals))
+	for addr := range p.locals {
+		locals = append(locals, addr)
+	}
+	return locals
+}
+
+// testHandler is a live implementation of the Ethereum protocol handler, just
+// preinitialized with some sane testing defaults and the transaction pool mocked
+// out.
+type testHandler struct {
+	chain   *core.BlockChain
+	txpool  *testTxPool
+	handler *handler
+}
+
+// newTestHandler creates a new handler for testing purposes, with the given
+// node ID and transaction broadcast policy. The handler is considered synced,
+// so it accepts transactions from the network.
+func newTestHandler(t *testing.T, id enode.ID, broadcast ethconfig.TxBroadcastConfig) *testHandler {
+	t.Helper()
+
+	var (
+		db    = rawdb.NewMemoryDatabase()
+		gspec = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{testAddr: {Balance: big.NewInt(1000000)}},
+		}
+	)
+	chain, err := core.NewBlockChain(db, nil, gspec, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := newTestTxPool()
+	h, err := newHandler(&handlerConfig{
+		NodeID:      id,
+		Database:    db,
+		Chain:       chain,
+		TxPool:      pool,
+		Network:     1,
+		Sync:        ethconfig.FullSync,
+		BloomCache:  1,
+		TxBroadcast: broadcast,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.synced.Store(true)
+	h.Start(1000)
+	t.Cleanup(func() {
+		h.Stop()
+		chain.Stop()
+	})
+	return &testHandler{chain: chain, txpool: pool, handler: h}
+}
+
+// newTestTransaction creates a signed transfer of the tester account.
+func newTestTransaction(t *testing.T, nonce uint64, size int) *types.Transaction {
+	t.Helper()
+
+	tx, err := types.SignNewTx(testKey, testSigner, &types.LegacyTx{
+		Nonce:    nonce,
+		Gas:      100000,
+		GasPrice: big.NewInt(1),
+		Data:     make([]byte, size),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
@@ -164,3 +164,50 @@ func (p *Peer) announceTransactions() {
 		}
 	}
 }
+
+// announceBlockRange is a write loop that announces changes of the locally served
+// block range to the remote peer. At most one announcement is in flight, newer
+// ranges queued meanwhile replace each other.
+func (p *Peer) announceBlockRange() {
+	var (
+		queued *BlockRangeUpdatePacket // Latest range waiting to be announced
+		done   chan struct{}           // Non-nil if background announcer is running
+		fail   = make(chan error, 1)   // Channel used to receive network error
+		failed bool                    // Flag whether a send failed, discard everything onward
+	)
+	for {
+		// If there's no in-flight announce running, check if a new one is needed
+		if done == nil && queued != nil {
+			blockRange := *queued
+			queued = nil
+
+			done = make(chan struct{})
+			go func() {
+				if err := p.SendBlockRangeUpdate(blockRange); err != nil {
+					fail <- err
+					return
+				}
+				close(done)
+				p.Log().Trace("Sent block range announcement", "latest", blockRange.LatestBlock)
+			}()
+		}
+		// Transfer goroutine may or may not have been started, listen for events
+		select {
+		case blockRange := <-p.blockRangeUpdate:
+			// If the connection failed, discard all announcements
+			if failed {
+				continue
+			}
+			queued = &blockRange
+
+		case <-done:
+			done = nil
+
+		case <-fail:
+			failed = true
+
+		case <-p.term:
+			return
+		}
+	}
+}
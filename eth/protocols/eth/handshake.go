@@ -35,25 +35,85 @@ const (
 )
 
 // Handshake executes the eth protocol handshake, negotiating version number,
-// network IDs, difficulties, head and genesis blocks.
-func (p *Peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis common.Hash, forkID forkid.ID, forkFilter forkid.Filter) error {
+// network IDs, head and genesis blocks. On eth/68 the total difficulty is also
+// exchanged, on eth/69 the range of blocks served by the nodes.
+func (p *Peer) Handshake(network uint64, td *big.Int, genesis common.Hash, forkID forkid.ID, forkFilter forkid.Filter, blockRange BlockRangeUpdatePacket) error {
+	if p.version >= ETH69 {
+		return p.handshake69(network, genesis, forkID, forkFilter, blockRange)
+	}
+	return p.handshake68(network, td, blockRange.LatestBlockHash, genesis, forkID, forkFilter)
+}
+
+func (p *Peer) handshake68(network uint64, td *big.Int, head common.Hash, genesis common.Hash, forkID forkid.ID, forkFilter forkid.Filter) error {
+	var status StatusPacket68 // safe to read after two values have been received from errc
+
+	err := p.exchangeStatus(&StatusPacket68{
+		ProtocolVersion: uint32(p.version),
+		NetworkID:       network,
+		TD:              td,
+		Head:            head,
+		Genesis:         genesis,
+		ForkID:          forkID,
+	}, func() error {
+		if err := p.readStatus(&status); err != nil {
+			return err
+		}
+		return p.checkStatus(network, status.NetworkID, status.ProtocolVersion, genesis, status.Genesis, forkFilter, status.ForkID)
+	})
+	if err != nil {
+		return err
+	}
+	p.td, p.head = status.TD, status.Head
+
+	// TD at mainnet block #7753254 is 76 bits. If it becomes 100 million times
+	// larger, it will still fit within 100 bits
+	if tdlen := p.td.BitLen(); tdlen > 100 {
+		return fmt.Errorf("too large total difficulty: bitlen %d", tdlen)
+	}
+	return nil
+}
+
+func (p *Peer) handshake69(network uint64, genesis common.Hash, forkID forkid.ID, forkFilter forkid.Filter, blockRange BlockRangeUpdatePacket) error {
+	var status StatusPacket69 // safe to read after two values have been received from errc
+
+	err := p.exchangeStatus(&StatusPacket69{
+		ProtocolVersion: uint32(p.version),
+		NetworkID:       network,
+		Genesis:         genesis,
+		ForkID:          forkID,
+		EarliestBlock:   blockRange.EarliestBlock,
+		LatestBlock:     blockRange.LatestBlock,
+		LatestBlockHash: blockRange.LatestBlockHash,
+	}, func() error {
+		if err := p.readStatus(&status); err != nil {
+			return err
+		}
+		if err := p.checkStatus(network, status.NetworkID, status.ProtocolVersion, genesis, status.Genesis, forkFilter, status.ForkID); err != nil {
+			return err
+		}
+		remote := BlockRangeUpdatePacket{status.EarliestBlock, status.LatestBlock, status.LatestBlockHash}
+		return remote.Validate()
+	})
+	if err != nil {
+		return err
+	}
+	// The total difficulty is meaningless after the merge, it is not exchanged
+	p.td, p.head = new(big.Int), status.LatestBlockHash
+	p.blockRange = &BlockRangeUpdatePacket{status.EarliestBlock, status.LatestBlock, status.LatestBlockHash}
+	return nil
+}
+
+// exchangeStatus sends our status message while concurrently reading and
+// validating the remote one.
+func (p *Peer) exchangeStatus(status Packet, receive func() error) error {
 	// Send out own handshake in a new thread
 	errc := make(chan error, 2)
 
-	var status StatusPacket // safe to read after two values have been received from errc
-
 	go func() {
-		errc <- p2p.Send(p.rw, StatusMsg, &StatusPacket{
-			ProtocolVersion: uint32(p.version),
-			NetworkID:       network,
-			TD:              td,
-			Head:            head,
-			Genesis:         genesis,
-			ForkID:          forkID,
-		})
+		errc <- p2p.Send(p.rw, StatusMsg, status)
 	}()
 	go func() {
-		errc <- p.readStatus(network, &status, genesis, forkFilter)
+		errc <- receive()
 	}()
 	timeout := time.NewTimer(handshakeTimeout)
 	defer timeout.Stop()
@@ -69,18 +129,11 @@ func (p *Peer) Handshake(network uint64, td *big.Int, head common.Hash, genesis
 			return p2p.DiscReadTimeout
 		}
 	}
-	p.td, p.head = status.TD, status.Head
-
-	// TD at mainnet block #7753254 is 76 bits. If it becomes 100 million times
-	// larger, it will still fit within 100 bits
-	if tdlen := p.td.BitLen(); tdlen > 100 {
-		return fmt.Errorf("too large total difficulty: bitlen %d", tdlen)
-	}
 	return nil
 }
 
-// readStatus reads the remote handshake message.
-func (p *Peer) readStatus(network uint64, status *StatusPacket, genesis common.Hash, forkFilter forkid.Filter) error {
+// readStatus reads and decodes the remote handshake message.
+func (p *Peer) readStatus(status Packet) error {
 	msg, err := p.rw.ReadMsg()
 	if err != nil {
 		return err
@@ -91,20 +144,24 @@ func (p *Peer) readStatus(network uint64, status *StatusPacket, genesis common.H
 	if msg.Size > maxMessageSize {
 		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
 	}
-	// Decode the handshake and make sure everything matches
-	if err := msg.Decode(&status); err != nil {
+	if err := msg.Decode(status); err != nil {
 		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
 	}
-	if status.NetworkID != network {
-		return fmt.Errorf("%w: %d (!= %d)", errNetworkIDMismatch, status.NetworkID, network)
+	return nil
+}
+
+// checkStatus makes sure the remote handshake matches ours.
+func (p *Peer) checkStatus(network, remoteNetwork uint64, remoteVersion uint32, genesis, remoteGenesis common.Hash, forkFilter forkid.Filter, remoteForkID forkid.ID) error {
+	if remoteNetwork != network {
+		return fmt.Errorf("%w: %d (!= %d)", errNetworkIDMismatch, remoteNetwork, network)
 	}
-	if uint(status.ProtocolVersion) != p.version {
-		return fmt.Errorf("%w: %d (!= %d)", errProtocolVersionMismatch, status.ProtocolVersion, p.version)
+	if uint(remoteVersion) != p.version {
+		return fmt.Errorf("%w: %d (!= %d)", errProtocolVersionMismatch, remoteVersion, p.version)
 	}
-	if status.Genesis != genesis {
-		return fmt.Errorf("%w: %x (!= %x)", errGenesisMismatch, status.Genesis, genesis)
+	if remoteGenesis != genesis {
+		return fmt.Errorf("%w: %x (!= %x)", errGenesisMismatch, remoteGenesis, genesis)
 	}
-	if err := forkFilter(status.ForkID); err != nil {
+	if err := forkFilter(remoteForkID); err != nil {
 		return fmt.Errorf("%w: %v", errForkIDRejected, err)
 	}
 	return nil
@@ -50,14 +50,17 @@ type Peer struct {
 	rw        p2p.MsgReadWriter // Input/output streams for snap
 	version   uint              // Protocol version negotiated
 
-	head common.Hash // Latest advertised head block hash
-	td   *big.Int    // Latest advertised head block total difficulty
+	head       common.Hash             // Latest advertised head block hash
+	td         *big.Int                // Latest advertised head block total difficulty
+	blockRange *BlockRangeUpdatePacket // Latest advertised block range (eth/69), nil if unknown
 
 	txpool      TxPool             // Transaction pool used by the broadcasters for liveness checks
 	knownTxs    *knownCache        // Set of transaction hashes known to be known by this peer
 	txBroadcast chan []common.Hash // Channel used to queue transaction propagation requests
 	txAnnounce  chan []common.Hash // Channel used to queue transaction announcement requests
 
+	blockRangeUpdate chan BlockRangeUpdatePacket // Channel used to queue block range announcements
+
 	reqDispatch chan *request  // Dispatch channel to send requests and track then until fulfillment
 	reqCancel   chan *cancel   // Dispatch channel to cancel pending requests and untrack them
 	resDispatch chan *response // Dispatch channel to fulfil pending requests and untrack them
@@ -82,10 +85,15 @@ func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter, txpool TxPool) *Pe
 		resDispatch: make(chan *response),
 		txpool:      txpool,
 		term:        make(chan struct{}),
+
+		blockRangeUpdate: make(chan BlockRangeUpdatePacket),
 	}
 	// Start up all the broadcasters
 	go peer.broadcastTransactions()
 	go peer.announceTransactions()
+	if version >= ETH69 {
+		go peer.announceBlockRange()
+	}
 	go peer.dispatcher()
 
 	return peer
@@ -126,6 +134,66 @@ func (p *Peer) SetHead(hash common.Hash, td *big.Int) {
 	p.td.Set(td)
 }
 
+// BlockRange retrieves the range of blocks the peer announced to serve. It is nil
+// for peers not announcing it (eth/68), which are assumed to serve all blocks up
+// to their head.
+func (p *Peer) BlockRange() *BlockRangeUpdatePacket {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if p.blockRange == nil {
+		return nil
+	}
+	blockRange := *p.blockRange
+	return &blockRange
+}
+
+// setBlockRange updates the block range served by the peer, along with its head.
+func (p *Peer) setBlockRange(blockRange BlockRangeUpdatePacket) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.blockRange = &blockRange
+	p.head = blockRange.LatestBlockHash
+}
+
+// ServesBlock returns whether the peer announced to serve the body and receipts
+// of the given block.
+func (p *Peer) ServesBlock(number uint64) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.blockRange == nil || number >= p.blockRange.EarliestBlock
+}
+
+// SendBlockRangeUpdate announces a change of the range of blocks served by the
+// local node. It is a noop for peers not supporting the announcement.
+//
+// This method is a helper used by the async block range announcer. Don't call it
+// directly as the queueing (memory) and transmission (bandwidth) costs should
+// not be managed directly.
+func (p *Peer) SendBlockRangeUpdate(blockRange BlockRangeUpdatePacket) error {
+	if p.version < ETH69 {
+		return nil
+	}
+	return p2p.Send(p.rw, BlockRangeUpdateMsg, &blockRange)
+}
+
+// AsyncSendBlockRangeUpdate queues a change of the range of blocks served by the
+// local node to eventually announce to a remote peer. Only the latest queued
+// range is announced if the previous one is still being sent. It is a noop for
+// peers not supporting the announcement.
+func (p *Peer) AsyncSendBlockRangeUpdate(blockRange BlockRangeUpdatePacket) {
+	if p.version < ETH69 {
+		return
+	}
+	select {
+	case p.blockRangeUpdate <- blockRange:
+	case <-p.term:
+		p.Log().Debug("Dropping block range announcement", "latest", blockRange.LatestBlock)
+	}
+}
+
 // KnownTransaction returns whether peer is known to already have a transaction.
 func (p *Peer) KnownTransaction(hash common.Hash) bool {
 	return p.knownTxs.Contains(hash)
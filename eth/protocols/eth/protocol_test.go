@@ -0,0 +1,114 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Tests that receipts survive a round trip through the eth/69 network encoding.
+func TestReceipt69RoundTrip(t *testing.T) {
+	logs := []*types.Log{
+		{
+			Address: common.HexToAddress("0x01"),
+			Topics:  []common.Hash{common.HexToHash("0x02"), common.HexToHash("0x03")},
+			Data:    []byte{0x04, 0x05},
+		},
+		{
+			Address: common.HexToAddress("0x06"),
+			Topics:  []common.Hash{},
+			Data:    []byte{},
+		},
+	}
+	receipts := ReceiptsResponse{
+		{
+			{Type: types.LegacyTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*types.Log{}},
+			{Type: types.DynamicFeeTxType, Status: types.ReceiptStatusFailed, CumulativeGasUsed: 50000, Logs: logs},
+		},
+		{},
+		{
+			{Type: types.LegacyTxType, PostState: common.HexToHash("0xff").Bytes(), CumulativeGasUsed: 30000, Logs: logs[:1]},
+			{Type: types.BlobTxType, Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: 80000, Logs: logs[1:]},
+		},
+	}
+	for _, list := range receipts {
+		for _, r := range list {
+			r.Bloom = types.CreateBloom(types.Receipts{r})
+		}
+	}
+	packet := &ReceiptsPacket69{RequestId: 42, ReceiptsResponse69: make(ReceiptsResponse69, len(receipts))}
+	for i, list := range receipts {
+		packet.ReceiptsResponse69[i] = make([]*Receipt69, len(list))
+		for j, r := range list {
+			packet.ReceiptsResponse69[i][j] = NewReceipt69(r)
+		}
+	}
+	enc, err := rlp.EncodeToBytes(packet)
+	if err != nil {
+		t.Fatalf("failed to encode packet: %v", err)
+	}
+	var decoded ReceiptsPacket69
+	if err := rlp.DecodeBytes(enc, &decoded); err != nil {
+		t.Fatalf("failed to decode packet: %v", err)
+	}
+	if decoded.RequestId != packet.RequestId {
+		t.Fatalf("request id mismatch: have %d, want %d", decoded.RequestId, packet.RequestId)
+	}
+	unpacked, err := decoded.Unpack()
+	if err != nil {
+		t.Fatalf("failed to unpack receipts: %v", err)
+	}
+	if len(unpacked) != len(receipts) {
+		t.Fatalf("block count mismatch: have %d, want %d", len(unpacked), len(receipts))
+	}
+	for i := range receipts {
+		if len(unpacked[i]) != len(receipts[i]) {
+			t.Fatalf("block %d: receipt count mismatch: have %d, want %d", i, len(unpacked[i]), len(receipts[i]))
+		}
+		for j := range receipts[i] {
+			// The consensus encoding covers all fields transmitted over eth/69
+			have, err := unpacked[i][j].MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := receipts[i][j].MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(have, want) {
+				t.Errorf("block %d, receipt %d: mismatch after round trip:\nhave %x\nwant %x", i, j, have, want)
+			}
+		}
+	}
+}
+
+// Tests that receipts with malformed statuses are rejected when unpacked.
+func TestReceipt69InvalidStatus(t *testing.T) {
+	response := ReceiptsResponse69{{{TxType: types.LegacyTxType, PostStateOrStatus: []byte{0x02}}}}
+	if _, err := response.Unpack(); err == nil {
+		t.Error("receipt with invalid status accepted")
+	}
+	response = ReceiptsResponse69{{nil}}
+	if _, err := response.Unpack(); err == nil {
+		t.Error("nil receipt accepted")
+	}
+}
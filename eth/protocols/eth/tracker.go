@@ -19,8 +19,15 @@ package eth
 import (
 	"time"
 
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p/tracker"
 )
 
 // requestTracker is a singleton tracker for eth/66 and newer request times.
 var requestTracker = tracker.New(ProtocolName, 5*time.Minute)
+
+// SubscribeLostRequests subscribes to the IDs of peers which failed to answer
+// a `eth` request in time.
+func SubscribeLostRequests(ch chan<- string) event.Subscription {
+	return requestTracker.SubscribeLost(ch)
+}
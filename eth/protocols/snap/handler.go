@@ -18,11 +18,13 @@ package snap
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
@@ -100,7 +102,8 @@ func MakeProtocols(backend Backend) []p2p.Protocol {
 			PeerInfo: func(id enode.ID) interface{} {
 				return backend.PeerInfo(id)
 			},
-			Attributes: []enr.Entry{&enrEntry{}},
+			Attributes:    []enr.Entry{&enrEntry{}},
+			ServeMessages: serveMessages,
 		}
 	}
 	return protocols
@@ -274,15 +277,27 @@ func ServiceGetAccountRangeQuery(chain *core.BlockChain, req *GetAccountRangePac
 	if req.Bytes > softResponseLimit {
 		req.Bytes = softResponseLimit
 	}
+	start := time.Now()
+	defer accountRangeServeTimer.UpdateSince(start)
+
 	// Retrieve the requested state and bail out if non existent
 	tr, err := trie.New(trie.StateTrieID(req.Root), chain.TrieDB())
 	if err != nil {
+		rangeUnavailableMeter.Mark(1)
 		return nil, nil
 	}
-	it, err := chain.Snapshots().AccountIterator(req.Root, req.Origin)
+	reader := newRangeReader(chain, req.Root)
+	defer reader.release()
+
+	it, err := reader.accountIterator(req.Origin)
 	if err != nil {
+		if !errors.Is(err, errRangeThrottled) {
+			rangeUnavailableMeter.Mark(1)
+		}
 		return nil, nil
 	}
+	reader.markServed(accountRangeSnapshotMeter, accountRangeTrieMeter)
+
 	// Iterate over the requested range and pile accounts up
 	var (
 		accounts []*AccountData
@@ -308,6 +323,15 @@ func ServiceGetAccountRangeQuery(chain *core.BlockChain, req *GetAccountRangePac
 		if size > req.Bytes {
 			break
 		}
+		if time.Since(start) > maxRangeTimeSpent {
+			rangeTimeoutMeter.Mark(1)
+			break
+		}
+	}
+	if err := it.Error(); err != nil {
+		log.Debug("Failed to iterate account range", "root", req.Root, "err", err)
+		it.Release()
+		return nil, nil
 	}
 	it.Release()
 
@@ -337,6 +361,12 @@ func ServiceGetStorageRangesQuery(chain *core.BlockChain, req *GetStorageRangesP
 	// Calculate the hard limit at which to abort, even if mid storage trie
 	hardLimit := uint64(float64(req.Bytes) * (1 + stateLookupSlack))
 
+	start := time.Now()
+	defer storageRangeServeTimer.UpdateSince(start)
+
+	reader := newRangeReader(chain, req.Root)
+	defer reader.release()
+
 	// Retrieve storage ranges until the packet limit is reached
 	var (
 		slots  [][]*StorageData
@@ -359,8 +389,11 @@ func ServiceGetStorageRangesQuery(chain *core.BlockChain, req *GetStorageRangesP
 			limit, req.Limit = common.BytesToHash(req.Limit), nil
 		}
 		// Retrieve the requested state and bail out if non existent
-		it, err := chain.Snapshots().StorageIterator(req.Root, account, origin)
+		it, err := reader.storageIterator(account, origin)
 		if err != nil {
+			if !errors.Is(err, errRangeThrottled) {
+				rangeUnavailableMeter.Mark(1)
+			}
 			return nil, nil
 		}
 		// Iterate over the requested range and pile slots up
@@ -389,6 +422,17 @@ func ServiceGetStorageRangesQuery(chain *core.BlockChain, req *GetStorageRangesP
 			if bytes.Compare(hash[:], limit[:]) >= 0 {
 				break
 			}
+			// If we've exceeded the time allowance, cut the range short
+			if time.Since(start) > maxRangeTimeSpent {
+				rangeTimeoutMeter.Mark(1)
+				abort = true
+				break
+			}
+		}
+		if err := it.Error(); err != nil {
+			log.Debug("Failed to iterate storage range", "root", req.Root, "account", account, "err", err)
+			it.Release()
+			return nil, nil
 		}
 		if len(storage) > 0 {
 			slots = append(slots, storage)
@@ -432,6 +476,9 @@ func ServiceGetStorageRangesQuery(chain *core.BlockChain, req *GetStorageRangesP
 			break
 		}
 	}
+	if len(req.Accounts) > 0 {
+		reader.markServed(storageRangeSnapshotMeter, storageRangeTrieMeter)
+	}
 	return slots, proofs
 }
 
@@ -479,8 +526,11 @@ func ServiceGetTrieNodesQuery(chain *core.BlockChain, req *GetTrieNodesPacket, s
 		// We don't have the requested state available, bail out
 		return nil, nil
 	}
-	// The 'snap' might be nil, in which case we cannot serve storage slots.
-	snap := chain.Snapshots().Snapshot(req.Root)
+	// The 'snap' might be nil, in which case we look up accounts via the trie.
+	var snap snapshot.Snapshot
+	if snaps := chain.Snapshots(); snaps != nil {
+		snap = snaps.Snapshot(req.Root)
+	}
 	// Retrieve trie nodes until the packet size limit is reached
 	var (
 		nodes [][]byte
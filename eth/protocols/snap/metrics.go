@@ -66,4 +66,23 @@ var (
 	// discarded during the snap sync.
 	largeStorageDiscardGauge = metrics.NewRegisteredGauge("eth/protocols/snap/sync/storage/chunk/discard", nil)
 	largeStorageResumedGauge = metrics.NewRegisteredGauge("eth/protocols/snap/sync/storage/chunk/resume", nil)
+
+	// Range serving metrics, split by whether the snapshot or the tries were
+	// iterated to serve the request.
+	accountRangeSnapshotMeter = metrics.NewRegisteredMeter("eth/protocols/snap/serve/account/snapshot", nil)
+	accountRangeTrieMeter     = metrics.NewRegisteredMeter("eth/protocols/snap/serve/account/trie", nil)
+	storageRangeSnapshotMeter = metrics.NewRegisteredMeter("eth/protocols/snap/serve/storage/snapshot", nil)
+	storageRangeTrieMeter     = metrics.NewRegisteredMeter("eth/protocols/snap/serve/storage/trie", nil)
+	accountRangeServeTimer    = metrics.NewRegisteredTimer("eth/protocols/snap/serve/account/time", nil)
+	storageRangeServeTimer    = metrics.NewRegisteredTimer("eth/protocols/snap/serve/storage/time", nil)
+
+	// rangeThrottleMeter counts range requests rejected because too many requests
+	// were already being served from the tries.
+	rangeThrottleMeter = metrics.NewRegisteredMeter("eth/protocols/snap/serve/throttle", nil)
+
+	// rangeUnavailableMeter counts range requests for states which are not available.
+	rangeUnavailableMeter = metrics.NewRegisteredMeter("eth/protocols/snap/serve/unavailable", nil)
+
+	// rangeTimeoutMeter counts range responses cut short by the time limit.
+	rangeTimeoutMeter = metrics.NewRegisteredMeter("eth/protocols/snap/serve/timeout", nil)
 )
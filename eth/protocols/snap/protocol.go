@@ -42,6 +42,10 @@ var ProtocolVersions = []uint{SNAP1}
 // different protocol versions.
 var protocolLengths = map[uint]uint64{SNAP1: 8}
 
+// serveMessages are the responses carrying state data, which count against the
+// per-peer serving bandwidth limit.
+var serveMessages = []uint64{AccountRangeMsg, StorageRangesMsg, ByteCodesMsg, TrieNodesMsg}
+
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 10 * 1024 * 1024
 
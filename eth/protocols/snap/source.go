@@ -0,0 +1,218 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+const (
+	// maxTrieRangeServes is the maximum number of account and storage range
+	// requests served concurrently by iterating the tries. Trie iteration is a
+	// lot more expensive than iterating the snapshot, requests exceeding the
+	// limit are answered with an empty response.
+	maxTrieRangeServes = 4
+)
+
+var (
+	// maxRangeTimeSpent is the maximum time we should spend on iterating a state
+	// range. The response is cut short and proven at the last returned item if
+	// the limit is exceeded. It is a variable to allow tests to lower it.
+	maxRangeTimeSpent = 2 * time.Second
+
+	errRangeThrottled  = errors.New("too many concurrent trie range requests")
+	trieRangeServeSlot = make(chan struct{}, maxTrieRangeServes)
+)
+
+// accountIterator iterates over the accounts of a state in hash order, returning
+// accounts in the slim RLP format of the snapshot.
+type accountIterator interface {
+	Next() bool
+	Error() error
+	Hash() common.Hash
+	Account() []byte
+	Release()
+}
+
+// storageIterator iterates over the storage slots of an account in hash order.
+type storageIterator interface {
+	Next() bool
+	Error() error
+	Hash() common.Hash
+	Slot() []byte
+	Release()
+}
+
+// rangeReader opens the iterators for serving a single state range request.
+// The snapshot is preferred as it is a lot cheaper to iterate. If it is disabled,
+// still being generated or doesn't contain the requested state, the tries are
+// iterated instead. Only a few requests may iterate tries concurrently.
+type rangeReader struct {
+	chain   *core.BlockChain
+	root    common.Hash
+	useTrie bool // whether the trie serving slot is held
+}
+
+func newRangeReader(chain *core.BlockChain, root common.Hash) *rangeReader {
+	return &rangeReader{chain: chain, root: root}
+}
+
+// release frees the trie serving slot if it was taken.
+func (r *rangeReader) release() {
+	if r.useTrie {
+		<-trieRangeServeSlot
+		r.useTrie = false
+	}
+}
+
+// acquireTrie takes a trie serving slot, failing if all slots are in use.
+func (r *rangeReader) acquireTrie() error {
+	if r.useTrie {
+		return nil
+	}
+	select {
+	case trieRangeServeSlot <- struct{}{}:
+		r.useTrie = true
+		return nil
+	default:
+		rangeThrottleMeter.Mark(1)
+		return errRangeThrottled
+	}
+}
+
+// accountIterator opens an account iterator starting at origin.
+func (r *rangeReader) accountIterator(origin common.Hash) (accountIterator, error) {
+	if snaps := r.chain.Snapshots(); snaps != nil && !r.useTrie {
+		if it, err := snaps.AccountIterator(r.root, origin); err == nil {
+			return it, nil
+		}
+	}
+	if err := r.acquireTrie(); err != nil {
+		return nil, err
+	}
+	tr, err := trie.New(trie.StateTrieID(r.root), r.chain.TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	nodeIt, err := tr.NodeIterator(origin[:])
+	if err != nil {
+		return nil, err
+	}
+	return &trieAccountIterator{it: trie.NewIterator(nodeIt)}, nil
+}
+
+// storageIterator opens an iterator over the storage of account starting at origin.
+func (r *rangeReader) storageIterator(account common.Hash, origin common.Hash) (storageIterator, error) {
+	if snaps := r.chain.Snapshots(); snaps != nil && !r.useTrie {
+		if it, err := snaps.StorageIterator(r.root, account, origin); err == nil {
+			return it, nil
+		}
+	}
+	if err := r.acquireTrie(); err != nil {
+		return nil, err
+	}
+	accTrie, err := trie.NewStateTrie(trie.StateTrieID(r.root), r.chain.TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	acc, err := accTrie.GetAccountByHash(account)
+	if err != nil {
+		return nil, err
+	}
+	if acc == nil || acc.Root == types.EmptyRootHash {
+		return new(trieStorageIterator), nil
+	}
+	stTrie, err := trie.New(trie.StorageTrieID(r.root, account, acc.Root), r.chain.TrieDB())
+	if err != nil {
+		return nil, err
+	}
+	nodeIt, err := stTrie.NodeIterator(origin[:])
+	if err != nil {
+		return nil, err
+	}
+	return &trieStorageIterator{it: trie.NewIterator(nodeIt)}, nil
+}
+
+// markServed updates the serving metrics of the request.
+func (r *rangeReader) markServed(snapshotMeter, trieMeter *metrics.Meter) {
+	if r.useTrie {
+		trieMeter.Mark(1)
+	} else {
+		snapshotMeter.Mark(1)
+	}
+}
+
+// trieAccountIterator iterates the accounts of a state trie.
+type trieAccountIterator struct {
+	it      *trie.Iterator
+	account []byte
+	err     error
+}
+
+func (it *trieAccountIterator) Next() bool {
+	if it.err != nil || !it.it.Next() {
+		return false
+	}
+	// Tries hold the full account encoding, the snap protocol uses the slim one.
+	var acc types.StateAccount
+	if err := rlp.DecodeBytes(it.it.Value, &acc); err != nil {
+		it.err = err
+		return false
+	}
+	it.account = types.SlimAccountRLP(acc)
+	return true
+}
+
+func (it *trieAccountIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.it.Err
+}
+
+func (it *trieAccountIterator) Hash() common.Hash { return common.BytesToHash(it.it.Key) }
+func (it *trieAccountIterator) Account() []byte   { return it.account }
+func (it *trieAccountIterator) Release()          {}
+
+// trieStorageIterator iterates the slots of a storage trie. The zero value is an
+// exhausted iterator, used for accounts without storage.
+type trieStorageIterator struct {
+	it *trie.Iterator
+}
+
+func (it *trieStorageIterator) Next() bool {
+	return it.it != nil && it.it.Next()
+}
+
+func (it *trieStorageIterator) Error() error {
+	if it.it == nil {
+		return nil
+	}
+	return it.it.Err
+}
+
+func (it *trieStorageIterator) Hash() common.Hash { return common.BytesToHash(it.it.Key) }
+func (it *trieStorageIterator) Slot() []byte      { return it.it.Value }
+func (it *trieStorageIterator) Release()          {}
@@ -0,0 +1,200 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package snap
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	testRangeAccounts = 32 // number of accounts in the test state
+	testRangeSlots    = 16 // number of storage slots of the test contract
+)
+
+// testRangeContract is the account holding storage in the test state.
+var testRangeContract = common.HexToAddress("0xc0de")
+
+// newRangeTestChain creates a chain whose genesis state holds funded accounts and
+// a contract with storage. Snapshots are only enabled if requested, otherwise
+// ranges are served from the tries.
+func newRangeTestChain(t *testing.T, snapshots bool) *core.BlockChain {
+	t.Helper()
+
+	alloc := make(types.GenesisAlloc)
+	for i := 0; i < testRangeAccounts; i++ {
+		alloc[common.BigToAddress(big.NewInt(int64(i+1)))] = types.Account{Balance: big.NewInt(int64(i + 1))}
+	}
+	storage := make(map[common.Hash]common.Hash)
+	for i := 0; i < testRangeSlots; i++ {
+		storage[common.BigToHash(big.NewInt(int64(i)))] = common.BigToHash(big.NewInt(int64(i + 1)))
+	}
+	alloc[testRangeContract] = types.Account{Balance: big.NewInt(1), Code: []byte{0x00}, Storage: storage}
+
+	cacheConfig := core.DefaultCacheConfigWithScheme(rawdb.HashScheme)
+	if !snapshots {
+		cacheConfig.SnapshotLimit = 0
+	}
+	gspec := &core.Genesis{Config: params.TestChainConfig, Alloc: alloc}
+	chain, err := core.NewBlockChain(rawdb.NewMemoryDatabase(), cacheConfig, gspec, nil, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(chain.Stop)
+	return chain
+}
+
+// testAccountHashes returns the sorted hashes of the accounts in the test state.
+func testAccountHashes() []common.Hash {
+	hashes := []common.Hash{crypto.Keccak256Hash(testRangeContract[:])}
+	for i := 0; i < testRangeAccounts; i++ {
+		hashes = append(hashes, crypto.Keccak256Hash(common.BigToAddress(big.NewInt(int64(i+1))).Bytes()))
+	}
+	slices.SortFunc(hashes, func(a, b common.Hash) int { return bytes.Compare(a[:], b[:]) })
+	return hashes
+}
+
+func TestRangeReader(t *testing.T) {
+	for _, snapshots := range []bool{false, true} {
+		var (
+			chain  = newRangeTestChain(t, snapshots)
+			root   = chain.CurrentBlock().Root
+			hashes = testAccountHashes()
+			reader = newRangeReader(chain, root)
+		)
+		// Accounts are iterated in hash order from the origin, in slim encoding
+		it, err := reader.accountIterator(hashes[1])
+		if err != nil {
+			t.Fatalf("snapshots %v: failed to open account iterator: %v", snapshots, err)
+		}
+		var have []common.Hash
+		for it.Next() {
+			have = append(have, it.Hash())
+			if _, err := types.FullAccount(it.Account()); err != nil {
+				t.Fatalf("snapshots %v: invalid slim account: %v", snapshots, err)
+			}
+		}
+		if err := it.Error(); err != nil {
+			t.Fatalf("snapshots %v: iteration failed: %v", snapshots, err)
+		}
+		it.Release()
+		if !slices.Equal(have, hashes[1:]) {
+			t.Fatalf("snapshots %v: wrong accounts, have %d, want %d", snapshots, len(have), len(hashes)-1)
+		}
+		// Storage is iterated for accounts with and without storage
+		contract := crypto.Keccak256Hash(testRangeContract[:])
+		st, err := reader.storageIterator(contract, common.Hash{})
+		if err != nil {
+			t.Fatalf("snapshots %v: failed to open storage iterator: %v", snapshots, err)
+		}
+		var slots int
+		for st.Next() {
+			slots++
+		}
+		st.Release()
+		if slots != testRangeSlots {
+			t.Fatalf("snapshots %v: wrong slot count %d", snapshots, slots)
+		}
+		if reader.useTrie == snapshots {
+			t.Fatalf("snapshots %v: wrong source, trie used: %v", snapshots, reader.useTrie)
+		}
+		st, err = reader.storageIterator(hashes[0], common.Hash{})
+		if err != nil {
+			t.Fatalf("snapshots %v: failed to open empty storage iterator: %v", snapshots, err)
+		}
+		if st.Next() {
+			t.Fatalf("snapshots %v: storage returned for account without storage", snapshots)
+		}
+		st.Release()
+		reader.release()
+		if len(trieRangeServeSlot) != 0 {
+			t.Fatalf("snapshots %v: trie serving slot not released", snapshots)
+		}
+	}
+}
+
+func TestRangeTrieThrottle(t *testing.T) {
+	var (
+		chain = newRangeTestChain(t, false)
+		root  = chain.CurrentBlock().Root
+		req   = &GetAccountRangePacket{Root: root, Limit: common.MaxHash, Bytes: softResponseLimit}
+	)
+	// Take all the trie serving slots
+	readers := make([]*rangeReader, maxTrieRangeServes)
+	for i := range readers {
+		readers[i] = newRangeReader(chain, root)
+		if err := readers[i].acquireTrie(); err != nil {
+			t.Fatalf("failed to take slot %d: %v", i, err)
+		}
+	}
+	defer func() {
+		for _, r := range readers {
+			r.release()
+		}
+	}()
+	if _, err := newRangeReader(chain, root).accountIterator(common.Hash{}); !errors.Is(err, errRangeThrottled) {
+		t.Fatalf("wrong error with all slots taken: %v", err)
+	}
+	if accounts, proofs := ServiceGetAccountRangeQuery(chain, req); accounts != nil || proofs != nil {
+		t.Fatalf("throttled request served %d accounts", len(accounts))
+	}
+	storageReq := &GetStorageRangesPacket{Root: root, Accounts: []common.Hash{crypto.Keccak256Hash(testRangeContract[:])}, Bytes: softResponseLimit}
+	if slots, proofs := ServiceGetStorageRangesQuery(chain, storageReq); slots != nil || proofs != nil {
+		t.Fatal("throttled storage request served")
+	}
+	// Requests are served again once a slot is released
+	readers[0].release()
+	if accounts, _ := ServiceGetAccountRangeQuery(chain, req); len(accounts) != testRangeAccounts+1 {
+		t.Fatalf("wrong account count after release: %d", len(accounts))
+	}
+	if len(trieRangeServeSlot) != maxTrieRangeServes-1 {
+		t.Fatalf("slot of served request not released")
+	}
+}
+
+func TestRangeTimeLimit(t *testing.T) {
+	defer func(limit time.Duration) { maxRangeTimeSpent = limit }(maxRangeTimeSpent)
+	maxRangeTimeSpent = 0
+
+	var (
+		chain    = newRangeTestChain(t, false)
+		root     = chain.CurrentBlock().Root
+		contract = crypto.Keccak256Hash(testRangeContract[:])
+	)
+	// Responses exceeding the time limit are cut short and proven
+	accounts, proofs := ServiceGetAccountRangeQuery(chain, &GetAccountRangePacket{Root: root, Limit: common.MaxHash, Bytes: softResponseLimit})
+	if len(accounts) != 1 || len(proofs) == 0 {
+		t.Fatalf("wrong account response: %d accounts, %d proof nodes", len(accounts), len(proofs))
+	}
+	slots, proofs := ServiceGetStorageRangesQuery(chain, &GetStorageRangesPacket{Root: root, Accounts: []common.Hash{contract}, Bytes: softResponseLimit})
+	if len(slots) != 1 || len(slots[0]) != 1 || len(proofs) == 0 {
+		t.Fatalf("wrong storage response: %d ranges, %d proof nodes", len(slots), len(proofs))
+	}
+}
@@ -19,8 +19,15 @@ package snap
 import (
 	"time"
 
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/p2p/tracker"
 )
 
 // requestTracker is a singleton tracker for request times.
 var requestTracker = tracker.New(ProtocolName, time.Minute)
+
+// SubscribeLostRequests subscribes to the IDs of peers which failed to answer
+// a `snap` request in time.
+func SubscribeLostRequests(ch chan<- string) event.Subscription {
+	return requestTracker.SubscribeLost(ch)
+}
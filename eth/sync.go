@@ -23,9 +23,19 @@ import (
 )
 
 // syncTransactions starts sending all currently pending transactions to the given peer.
+// Private local transactions are only sent to trusted peers.
 func (h *handler) syncTransactions(p *eth.Peer) {
-	var hashes []common.Hash
-	for _, batch := range h.txpool.Pending(txpool.PendingFilter{OnlyPlainTxs: true}) {
+	var (
+		hashes  []common.Hash
+		private map[common.Address]struct{}
+	)
+	if !p.Peer.Trusted() {
+		private = h.privateAccounts()
+	}
+	for addr, batch := range h.txpool.Pending(txpool.PendingFilter{OnlyPlainTxs: true}) {
+		if _, ok := private[addr]; ok {
+			continue
+		}
 		for _, tx := range batch {
 			hashes = append(hashes, tx.Hash)
 		}
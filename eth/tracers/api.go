@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
 	"github.com/ethereum/go-ethereum/internal/ethapi/override"
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -205,6 +206,7 @@ type blockTraceResult struct {
 type txTraceTask struct {
 	statedb *state.StateDB // Intermediate state prepped for tracing
 	index   int            // Transaction offset in the block
+	result  *txTraceResult // Trace result of the transaction, set once done
 }
 
 // TraceChain returns the structured logs created during the execution of EVM
@@ -441,13 +443,21 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 }
 
 // TraceBlockByNumber returns the structured logs created during the execution of
-// EVM and returns them as a JSON object.
-func (api *API) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
+// EVM and returns them as a JSON object. The traces are streamed out one by one
+// while the block is being traced.
+func (api *API) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) (*rpc.Stream, error) {
 	block, err := api.blockByNumber(ctx, number)
 	if err != nil {
 		return nil, err
 	}
-	return api.traceBlock(ctx, block, config)
+	if block.NumberU64() == 0 {
+		return nil, errors.New("genesis is not traceable")
+	}
+	return rpc.ArrayStream(func(enc *rpc.StreamEncoder) error {
+		return api.traceBlockFunc(ctx, block, config, func(result *txTraceResult) error {
+			return enc.Encode(result)
+		})
+	}), nil
 }
 
 // TraceBlockByHash returns the structured logs created during the execution of
@@ -581,13 +591,31 @@ func (api *API) StandardTraceBadBlockToFile(ctx context.Context, hash common.Has
 // executes all the transactions contained within. The return value will be one item
 // per transaction, dependent on the requested tracer.
 func (api *API) traceBlock(ctx context.Context, block *types.Block, config *TraceConfig) ([]*txTraceResult, error) {
+	results := make([]*txTraceResult, 0, len(block.Transactions()))
+	err := api.traceBlockFunc(ctx, block, config, func(result *txTraceResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// traceBlockFunc is like traceBlock, but hands the trace of each transaction to
+// the given callback, in transaction order, as soon as it's available. Tracing is
+// aborted if the callback fails.
+func (api *API) traceBlockFunc(ctx context.Context, block *types.Block, config *TraceConfig, emit func(*txTraceResult) error) error {
+	ctx, span := tracing.Start(ctx, "tracers.traceBlock", tracing.Int64("block", block.Number().Int64()), tracing.Int("txs", len(block.Transactions())))
+	defer span.End()
+
 	if block.NumberU64() == 0 {
-		return nil, errors.New("genesis is not traceable")
+		return errors.New("genesis is not traceable")
 	}
 	// Prepare base state
 	parent, err := api.blockByNumberAndHash(ctx, rpc.BlockNumber(block.NumberU64()-1), block.ParentHash())
 	if err != nil {
-		return nil, err
+		return err
 	}
 	reexec := defaultTraceReexec
 	if config != nil && config.Reexec != nil {
@@ -595,7 +623,7 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	}
 	statedb, release, err := api.backend.StateAtBlock(ctx, parent, reexec, nil, true, false)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer release()
 
@@ -613,7 +641,7 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 	// in separate worker threads.
 	if config != nil && config.Tracer != nil && *config.Tracer != "" {
 		if isJS := DefaultDirectory.IsJS(*config.Tracer); isJS {
-			return api.traceBlockParallel(ctx, block, statedb, config)
+			return api.traceBlockParallel(ctx, block, statedb, config, emit)
 		}
 	}
 	// Native tracers have low overhead
@@ -621,7 +649,6 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		txs       = block.Transactions()
 		blockHash = block.Hash()
 		signer    = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
-		results   = make([]*txTraceResult, len(txs))
 	)
 	for i, tx := range txs {
 		// Generate the next state snapshot fast without tracing
@@ -634,23 +661,28 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		}
 		res, err := api.traceTx(ctx, tx, msg, txctx, blockCtx, statedb, config)
 		if err != nil {
-			return nil, err
+			return err
+		}
+		if err := emit(&txTraceResult{TxHash: tx.Hash(), Result: res}); err != nil {
+			return err
 		}
-		results[i] = &txTraceResult{TxHash: tx.Hash(), Result: res}
 	}
-	return results, nil
+	return nil
 }
 
 // traceBlockParallel is for tracers that have a high overhead (read JS tracers). One thread
 // runs along and executes txes without tracing enabled to generate their prestate.
-// Worker threads take the tasks and the prestate and trace them.
-func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, statedb *state.StateDB, config *TraceConfig) ([]*txTraceResult, error) {
+// Worker threads take the tasks and the prestate and trace them. The traces are
+// handed to the callback in transaction order.
+func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, statedb *state.StateDB, config *TraceConfig, emit func(*txTraceResult) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+
 	// Execute all the transaction contained within the block concurrently
 	var (
 		txs       = block.Transactions()
 		blockHash = block.Hash()
 		signer    = types.MakeSigner(api.backend.ChainConfig(), block.Number(), block.Time())
-		results   = make([]*txTraceResult, len(txs))
+		done      = make(chan *txTraceTask, len(txs))
 		pend      sync.WaitGroup
 	)
 	threads := runtime.NumCPU()
@@ -678,50 +710,73 @@ func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, stat
 				blockCtx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
 				res, err := api.traceTx(ctx, txs[task.index], msg, txctx, blockCtx, task.statedb, config)
 				if err != nil {
-					results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Error: err.Error()}
-					continue
+					task.result = &txTraceResult{TxHash: txs[task.index].Hash(), Error: err.Error()}
+				} else {
+					task.result = &txTraceResult{TxHash: txs[task.index].Hash(), Result: res}
 				}
-				results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Result: res}
+				task.statedb = nil
+				done <- task
 			}
 		}()
 	}
 
-	// Feed the transactions into the tracers and return
-	var failed error
-	blockCtx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
-	evm := vm.NewEVM(blockCtx, statedb, api.backend.ChainConfig(), vm.Config{})
+	// Feed the transactions into the tracers in the background
+	failed := make(chan error, 1)
+	go func() {
+		defer close(jobs)
 
-txloop:
-	for i, tx := range txs {
-		// Send the trace task over for execution
-		task := &txTraceTask{statedb: statedb.Copy(), index: i}
-		select {
-		case <-ctx.Done():
-			failed = ctx.Err()
-			break txloop
-		case jobs <- task:
-		}
+		blockCtx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil)
+		evm := vm.NewEVM(blockCtx, statedb, api.backend.ChainConfig(), vm.Config{})
+		for i, tx := range txs {
+			// Send the trace task over for execution
+			task := &txTraceTask{statedb: statedb.Copy(), index: i}
+			select {
+			case <-ctx.Done():
+				failed <- ctx.Err()
+				return
+			case jobs <- task:
+			}
 
-		// Generate the next state snapshot fast without tracing
-		msg, _ := core.TransactionToMessage(tx, signer, block.BaseFee())
-		statedb.SetTxContext(tx.Hash(), i)
-		if _, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
-			failed = err
-			break txloop
+			// Generate the next state snapshot fast without tracing
+			msg, _ := core.TransactionToMessage(tx, signer, block.BaseFee())
+			statedb.SetTxContext(tx.Hash(), i)
+			if _, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(msg.GasLimit)); err != nil {
+				failed <- err
+				return
+			}
+			// Finalize the state so any modifications are written to the trie
+			// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
+			statedb.Finalise(evm.ChainConfig().IsEIP158(block.Number()))
 		}
-		// Finalize the state so any modifications are written to the trie
-		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
-		statedb.Finalise(evm.ChainConfig().IsEIP158(block.Number()))
-	}
-
-	close(jobs)
-	pend.Wait()
+	}()
+	// Stop the feeder and the tracers on return, they use the state
+	defer func() {
+		cancel()
+		pend.Wait()
+	}()
 
-	// If execution failed in between, abort
-	if failed != nil {
-		return nil, failed
+	// Hand out the traces in order, holding back the ones finished early
+	var (
+		results = make([]*txTraceResult, len(txs))
+		next    int
+	)
+	for next < len(txs) {
+		select {
+		case task := <-done:
+			results[task.index] = task.result
+			for next < len(txs) && results[next] != nil {
+				if err := emit(results[next]); err != nil {
+					return err
+				}
+				results[next] = nil
+				next++
+			}
+		case err := <-failed:
+			// If execution failed in between, abort
+			return err
+		}
 	}
-	return results, nil
+	return nil
 }
 
 // standardTraceBlockToFile configures a new tracer which uses standard JSON output,
@@ -984,6 +1039,9 @@ func (api *API) TraceCall(ctx context.Context, args ethapi.TransactionArgs, bloc
 // executes the given message in the provided environment. The return value will
 // be tracer dependent.
 func (api *API) traceTx(ctx context.Context, tx *types.Transaction, message *core.Message, txctx *Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "tracers.traceTx", tracing.String("tx", txctx.TxHash.Hex()))
+	defer span.End()
+
 	var (
 		tracer  *Tracer
 		err     error
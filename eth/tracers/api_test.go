@@ -638,6 +638,84 @@ func TestTraceBlock(t *testing.T) {
 	}
 }
 
+// Tests that the traces of a block are streamed in transaction order, also if
+// the tracer runs in parallel, and that tracing stops when the stream fails.
+func TestTraceBlockStream(t *testing.T) {
+	t.Parallel()
+
+	// Register a "JS" tracer, which is run in parallel, finishing out of order
+	DefaultDirectory.Register("testParallelTracer", func(ctx *Context, cfg json.RawMessage, chainConfig *params.ChainConfig) (*Tracer, error) {
+		return &Tracer{
+			Hooks: &tracing.Hooks{},
+			GetResult: func() (json.RawMessage, error) {
+				time.Sleep(time.Duration(10-ctx.TxIndex) * time.Millisecond)
+				return json.Marshal(ctx.TxIndex)
+			},
+			Stop: func(error) {},
+		}, nil
+	}, true)
+
+	accounts := newAccounts(2)
+	genesis := &core.Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  types.GenesisAlloc{accounts[0].addr: {Balance: big.NewInt(params.Ether)}},
+	}
+	signer := types.HomesteadSigner{}
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		for j := 0; j < 8; j++ {
+			tx, _ := types.SignTx(types.NewTx(&types.LegacyTx{
+				Nonce:    uint64(j),
+				To:       &accounts[1].addr,
+				Value:    big.NewInt(1000),
+				Gas:      params.TxGas,
+				GasPrice: b.BaseFee(),
+			}), signer, accounts[0].key)
+			b.AddTx(tx)
+		}
+	})
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	tracer := "testParallelTracer"
+	for _, config := range []*TraceConfig{nil, {Tracer: &tracer}} {
+		block, _ := api.blockByNumber(context.Background(), 1)
+		want, err := api.traceBlock(context.Background(), block, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream, err := api.TraceBlockByNumber(context.Background(), 1, config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		have, _ := json.Marshal(stream)
+		wantJSON, _ := json.Marshal(want)
+		if string(have) != string(wantJSON) {
+			t.Errorf("streamed traces mismatch\nhave: %s\nwant: %s", have, wantJSON)
+		}
+		for i, res := range want {
+			if res.TxHash != block.Transactions()[i].Hash() {
+				t.Errorf("trace %d out of order", i)
+			}
+		}
+		// Tracing is aborted if the stream fails
+		var (
+			emitted int
+			failure = errors.New("stream failed")
+		)
+		err = api.traceBlockFunc(context.Background(), block, config, func(*txTraceResult) error {
+			emitted++
+			return failure
+		})
+		if err != failure || emitted != 1 {
+			t.Errorf("tracing not aborted: err %v, %d traces emitted", err, emitted)
+		}
+	}
+	// Untraceable blocks are rejected before streaming
+	if _, err := api.TraceBlockByNumber(context.Background(), 0, nil); err == nil {
+		t.Error("genesis trace accepted")
+	}
+}
+
 func TestTracingWithOverrides(t *testing.T) {
 	t.Parallel()
 	// Initialize test accounts
@@ -39,8 +39,10 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth/gasestimator"
+	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/eth/tracers/logger"
 	"github.com/ethereum/go-ethereum/internal/ethapi/override"
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/params"
@@ -146,6 +148,52 @@ func (api *EthereumAPI) BlobBaseFee(ctx context.Context) *hexutil.Big {
 	return (*hexutil.Big)(api.b.BlobBaseFee(ctx))
 }
 
+// feeEstimate is a single urgency level of the eth_feeEstimates result.
+type feeEstimate struct {
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxFeePerBlobGas     *hexutil.Big   `json:"maxFeePerBlobGas,omitempty"`
+	InclusionBlocks      hexutil.Uint64 `json:"inclusionBlocks"`
+	InclusionTime        hexutil.Uint64 `json:"inclusionTime"` // seconds
+}
+
+type feeEstimatesResult struct {
+	BaseFee     *hexutil.Big `json:"baseFeePerGas"`
+	BlobBaseFee *hexutil.Big `json:"baseFeePerBlobGas,omitempty"`
+	Predictive  bool         `json:"predictive"`
+	Low         *feeEstimate `json:"low"`
+	Medium      *feeEstimate `json:"medium"`
+	High        *feeEstimate `json:"high"`
+}
+
+// FeeEstimates returns low, medium and high fee suggestions for dynamic fee and
+// blob transactions, along with the number of blocks and the time expected until
+// their inclusion.
+// FeeEstimates 返回动态费用交易和 blob 交易的低、中、高三档费用建议，以及预期被打包前的区块数和时间。
+func (api *EthereumAPI) FeeEstimates(ctx context.Context) (*feeEstimatesResult, error) {
+	estimates, err := api.b.FeeEstimates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	convert := func(estimate *gasprice.FeeEstimate) *feeEstimate {
+		return &feeEstimate{
+			MaxPriorityFeePerGas: (*hexutil.Big)(estimate.MaxPriorityFeePerGas),
+			MaxFeePerGas:         (*hexutil.Big)(estimate.MaxFeePerGas),
+			MaxFeePerBlobGas:     (*hexutil.Big)(estimate.MaxFeePerBlobGas),
+			InclusionBlocks:      hexutil.Uint64(estimate.InclusionBlocks),
+			InclusionTime:        hexutil.Uint64(estimate.InclusionTime / time.Second),
+		}
+	}
+	return &feeEstimatesResult{
+		BaseFee:     (*hexutil.Big)(estimates.BaseFee),
+		BlobBaseFee: (*hexutil.Big)(estimates.BlobBaseFee),
+		Predictive:  estimates.Predictive,
+		Low:         convert(estimates.Low),
+		Medium:      convert(estimates.Medium),
+		High:        convert(estimates.High),
+	}, nil
+}
+
 // Syncing returns false in case the node is currently not syncing with the network. It can be up-to-date or has not
 // yet received the latest block headers from its peers. In case it is synchronizing:
 // - startingBlock: block number this node started to synchronize from
@@ -229,9 +277,13 @@ func (api *TxPoolAPI) Content() map[string]map[string]map[string]*RPCTransaction
 }
 
 // ContentFrom returns the transactions contained within the transaction pool.
-// ContentFrom 返回交易池中包含的交易。
+// Besides the pending and queued transactions sent by the account, the pooled
+// set code transactions delegating the account (EIP-7702) are returned too,
+// keyed by the nonce of the authorization.
+// ContentFrom 返回交易池中包含的交易。除了该账户发送的待处理和排队交易外，
+// 还会返回委托该账户的池内 set code 交易（EIP-7702），以授权的 nonce 为键。
 func (api *TxPoolAPI) ContentFrom(addr common.Address) map[string]map[string]*RPCTransaction {
-	content := make(map[string]map[string]*RPCTransaction, 2)
+	content := make(map[string]map[string]*RPCTransaction, 3)
 	pending, queue := api.b.TxPoolContentFrom(addr)
 	curHeader := api.b.CurrentHeader()
 
@@ -249,6 +301,18 @@ func (api *TxPoolAPI) ContentFrom(addr common.Address) map[string]map[string]*RP
 	}
 	content["queued"] = dump
 
+	// Build the pending delegations
+	auths := api.b.TxPoolAuthorizations(addr)
+	dump = make(map[string]*RPCTransaction, len(auths))
+	for _, tx := range auths {
+		for _, auth := range tx.SetCodeAuthorizations() {
+			if authority, err := auth.Authority(); err == nil && authority == addr {
+				dump[fmt.Sprintf("%d", auth.Nonce)] = NewRPCPendingTransaction(tx, curHeader, api.b.ChainConfig())
+			}
+		}
+	}
+	content["authorizations"] = dump
+
 	return content
 }
 
@@ -805,11 +869,17 @@ func applyMessageWithEVM(ctx context.Context, evm *vm.EVM, msg *core.Message, ti
 func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *override.StateOverride, blockOverrides *override.BlockOverrides, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
 	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
 
+	ctx, span := tracing.Start(ctx, "ethapi.DoCall", tracing.String("block", blockNrOrHash.String()))
+	defer span.End()
+
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
 	if state == nil || err != nil {
+		span.SetError(err)
 		return nil, err
 	}
-	return doCall(ctx, b, args, state, header, overrides, blockOverrides, timeout, globalGasCap)
+	result, err := doCall(ctx, b, args, state, header, overrides, blockOverrides, timeout, globalGasCap)
+	span.SetError(err)
+	return result, err
 }
 
 // Call executes the given transaction on the state for the given block number.
@@ -885,6 +955,9 @@ func (api *BlockChainAPI) SimulateV1(ctx context.Context, opts simOpts, blockNrO
 // DoEstimateGas 返回允许交易在块 `blockNrOrHash` 成功运行的最低 gas 限制。
 // 如果交易会 revert 或发生意外失败，则返回错误。gas 限制由 `args.Gas`（如果非 nil 且非零）和 `gasCap`（如果非零）限制。
 func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *override.StateOverride, blockOverrides *override.BlockOverrides, gasCap uint64) (hexutil.Uint64, error) {
+	ctx, span := tracing.Start(ctx, "ethapi.DoEstimateGas", tracing.String("block", blockNrOrHash.String()))
+	defer span.End()
+
 	// Retrieve the base state and mutate it with any overrides
 	// 获取基础状态并使用任何覆盖进行变异
 	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
@@ -919,6 +992,7 @@ func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNr
 	// 运行 gas 估算并将任何 revert 包装到自定义返回中
 	estimate, revert, err := gasestimator.Estimate(ctx, call, opts, gasCap)
 	if err != nil {
+		span.SetError(err)
 		if len(revert) > 0 {
 			return 0, newRevertError(revert)
 		}
@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/params"
@@ -54,6 +55,9 @@ type Backend interface {
 	BlobBaseFee(ctx context.Context) *big.Int
 	// 返回当前区块的基础费用（Blob Base Fee），用于 EIP-4844 数据 blob 交易。
 
+	FeeEstimates(ctx context.Context) (*gasprice.FeeEstimates, error)
+	// 返回低、中、高三档费用建议及其预期的打包时间，可选地结合交易池中待处理交易的分布进行预测。
+
 	ChainDb() ethdb.Database
 	// 返回底层链数据库，用于访问区块链数据存储。
 
@@ -149,6 +153,9 @@ type Backend interface {
 	TxPoolContentFrom(addr common.Address) ([]*types.Transaction, []*types.Transaction)
 	// 返回指定地址的交易池内容。
 
+	TxPoolAuthorizations(addr common.Address) []*types.Transaction
+	// 返回携带指定地址签署的 EIP-7702 授权的交易池交易。
+
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
 	// 订阅新交易事件。
 
@@ -0,0 +1,333 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// exportBatchSize is the number of spans triggering an export.
+	// exportBatchSize 是触发导出的 span 数量。
+	exportBatchSize = 512
+
+	// exportQueueSize is the number of finished spans buffered for export,
+	// beyond which spans are dropped.
+	// exportQueueSize 是为导出缓冲的已完成 span 数量，超过后 span 将被丢弃。
+	exportQueueSize = 4096
+
+	// exportInterval is the maximum time finished spans wait for export.
+	// exportInterval 是已完成 span 等待导出的最长时间。
+	exportInterval = 5 * time.Second
+
+	// exportTimeout is the time allowed for a single export request.
+	// exportTimeout 是单个导出请求允许的时间。
+	exportTimeout = 10 * time.Second
+)
+
+// Config contains the settings of the tracer.
+// Config 包含追踪器的设置。
+type Config struct {
+	Endpoint    string  // OTLP/HTTP traces endpoint, e.g. http://localhost:4318/v1/traces // OTLP/HTTP 追踪端点
+	SampleRatio float64 // Fraction of traces sampled, including those sampled by remote callers // 被采样的追踪比例，包括远程调用方已采样的追踪
+	ServiceName string  // Name of the service reported to the collector // 向收集器报告的服务名称
+}
+
+// Tracer records sampled spans and exports them in batches to an OTLP/HTTP
+// collector using the JSON encoding.
+// Tracer 记录被采样的 span，并使用 JSON 编码将它们批量导出到 OTLP/HTTP 收集器。
+type Tracer struct {
+	config Config
+	client *http.Client
+
+	queue chan *Span
+	flush chan chan struct{}
+	quit  chan struct{}
+	wg    sync.WaitGroup
+
+	randLock sync.Mutex
+	rand     *rand.Rand
+}
+
+// New creates a tracer exporting to the configured endpoint. Spans are recorded
+// with it in contexts created by ContextWithTracer. Close must be called to flush
+// the pending spans.
+// New 创建一个导出到配置端点的追踪器。在由 ContextWithTracer 创建的上下文中使用它记录 span。
+// 必须调用 Close 来刷新待处理的 span。
+func New(config Config) *Tracer {
+	if config.ServiceName == "" {
+		config.ServiceName = "geth"
+	}
+	t := &Tracer{
+		config: config,
+		client: &http.Client{Timeout: exportTimeout},
+		queue:  make(chan *Span, exportQueueSize),
+		flush:  make(chan chan struct{}),
+		quit:   make(chan struct{}),
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	t.wg.Add(1)
+	go t.loop()
+	return t
+}
+
+// Flush exports all the finished spans, blocking until done.
+// Flush 导出所有已完成的 span，阻塞直到完成。
+func (t *Tracer) Flush() {
+	done := make(chan struct{})
+	select {
+	case t.flush <- done:
+		<-done
+	case <-t.quit:
+	}
+}
+
+// Close stops the tracer and exports all the finished spans.
+// Close 停止追踪器并导出所有已完成的 span。
+func (t *Tracer) Close() {
+	close(t.quit)
+	t.wg.Wait()
+}
+
+// sample decides whether a new trace is recorded.
+// sample 决定是否记录一个新的追踪。
+func (t *Tracer) sample() bool {
+	switch {
+	case t.config.SampleRatio >= 1:
+		return true
+	case t.config.SampleRatio <= 0:
+		return false
+	}
+	t.randLock.Lock()
+	defer t.randLock.Unlock()
+	return t.rand.Float64() < t.config.SampleRatio
+}
+
+// export queues a finished span for export, dropping it if the exporter can't
+// keep up.
+// export 将已完成的 span 排队等待导出，如果导出器跟不上则丢弃它。
+func (t *Tracer) export(span *Span) {
+	select {
+	case t.queue <- span:
+	default:
+		log.Debug("Dropped trace span", "name", span.name)
+	}
+}
+
+// loop batches the finished spans and sends them to the collector.
+// loop 批量处理已完成的 span 并将它们发送到收集器。
+func (t *Tracer) loop() {
+	defer t.wg.Done()
+
+	var (
+		batch = make([]*Span, 0, exportBatchSize)
+		timer = time.NewTicker(exportInterval)
+	)
+	defer timer.Stop()
+
+	// drain moves all the queued spans into the batch, sending full batches
+	// drain 将所有排队的 span 移入批次，并发送已满的批次
+	drain := func() {
+		for {
+			select {
+			case span := <-t.queue:
+				if batch = append(batch, span); len(batch) >= exportBatchSize {
+					t.send(batch)
+					batch = batch[:0]
+				}
+			default:
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case span := <-t.queue:
+			if batch = append(batch, span); len(batch) >= exportBatchSize {
+				t.send(batch)
+				batch = batch[:0]
+			}
+		case <-timer.C:
+			t.send(batch)
+			batch = batch[:0]
+
+		case done := <-t.flush:
+			drain()
+			t.send(batch)
+			batch = batch[:0]
+			close(done)
+
+		case <-t.quit:
+			drain()
+			t.send(batch)
+			return
+		}
+	}
+}
+
+// send posts a batch of spans to the collector.
+// send 将一批 span 发送到收集器。
+func (t *Tracer) send(batch []*Span) {
+	if len(batch) == 0 {
+		return
+	}
+	body, err := json.Marshal(t.encode(batch))
+	if err != nil {
+		log.Warn("Failed to encode trace spans", "err", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		log.Warn("Failed to create trace export request", "err", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		log.Warn("Failed to export trace spans", "spans", len(batch), "err", err)
+		return
+	}
+	res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		log.Warn("Trace collector rejected spans", "spans", len(batch), "status", res.Status)
+	}
+}
+
+// OTLP JSON encoding of the trace export request, see the opentelemetry-proto
+// repository. Identifiers are hex encoded and 64 bit integers are strings.
+// 追踪导出请求的 OTLP JSON 编码，参见 opentelemetry-proto 仓库。标识符使用十六进制编码，64 位整数为字符串。
+type (
+	otlpRequest struct {
+		ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+	}
+	otlpResourceSpans struct {
+		Resource   otlpResource     `json:"resource"`
+		ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	}
+	otlpScopeSpans struct {
+		Scope otlpScope  `json:"scope"`
+		Spans []otlpSpan `json:"spans"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpSpan struct {
+		TraceID      string         `json:"traceId"`
+		SpanID       string         `json:"spanId"`
+		ParentSpanID string         `json:"parentSpanId,omitempty"`
+		Name         string         `json:"name"`
+		Kind         int            `json:"kind"`
+		Start        string         `json:"startTimeUnixNano"`
+		End          string         `json:"endTimeUnixNano"`
+		Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+		Status       otlpStatus     `json:"status"`
+	}
+	otlpStatus struct {
+		Code    int    `json:"code,omitempty"` // 0 = unset, 2 = error
+		Message string `json:"message,omitempty"`
+	}
+	otlpKeyValue struct {
+		Key   string    `json:"key"`
+		Value otlpValue `json:"value"`
+	}
+	otlpValue struct {
+		String *string  `json:"stringValue,omitempty"`
+		Int    *string  `json:"intValue,omitempty"`
+		Bool   *bool    `json:"boolValue,omitempty"`
+		Double *float64 `json:"doubleValue,omitempty"`
+	}
+)
+
+// encode converts a batch of spans into an OTLP export request.
+// encode 将一批 span 转换为 OTLP 导出请求。
+func (t *Tracer) encode(batch []*Span) *otlpRequest {
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, span := range batch {
+		span.lock.Lock()
+		s := otlpSpan{
+			TraceID:    hex.EncodeToString(span.ctx.TraceID[:]),
+			SpanID:     hex.EncodeToString(span.ctx.SpanID[:]),
+			Name:       span.name,
+			Kind:       span.kind,
+			Start:      strconv.FormatInt(span.start.UnixNano(), 10),
+			End:        strconv.FormatInt(span.end.UnixNano(), 10),
+			Attributes: encodeAttributes(span.attrs),
+		}
+		if span.parent != (SpanID{}) {
+			s.ParentSpanID = hex.EncodeToString(span.parent[:])
+		}
+		if span.err != "" {
+			s.Status = otlpStatus{Code: 2, Message: span.err}
+		}
+		span.lock.Unlock()
+		spans = append(spans, s)
+	}
+	return &otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: encodeAttributes([]Attribute{String("service.name", t.config.ServiceName)}),
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/ethereum/go-ethereum"},
+				Spans: spans,
+			}},
+		}},
+	}
+}
+
+// encodeAttributes converts span attributes into OTLP key-values.
+// encodeAttributes 将 span 属性转换为 OTLP 键值对。
+func encodeAttributes(attrs []Attribute) []otlpKeyValue {
+	kvs := make([]otlpKeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		kv := otlpKeyValue{Key: attr.Key}
+		switch v := attr.Value.(type) {
+		case string:
+			kv.Value.String = &v
+		case int64:
+			s := strconv.FormatInt(v, 10)
+			kv.Value.Int = &s
+		case bool:
+			kv.Value.Bool = &v
+		case float64:
+			kv.Value.Double = &v
+		default:
+			s := fmt.Sprint(v)
+			kv.Value.String = &s
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs
+}
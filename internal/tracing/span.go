@@ -0,0 +1,279 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package tracing implements lightweight distributed tracing of RPC requests,
+// compatible with W3C trace context propagation and OTLP/HTTP export.
+// tracing 包实现了 RPC 请求的轻量级分布式追踪，兼容 W3C 追踪上下文传播和 OTLP/HTTP 导出。
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// W3C Trace Context
+//
+// traceparent 头的格式为 "00-<32 位十六进制 trace-id>-<16 位十六进制 parent-id>-<2 位十六进制 flags>"，
+// 其中 flags 的最低位表示上游是否对该追踪进行了采样。节点接受该头，使 RPC 调用成为调用方追踪的一部分，
+// 并通过 context.Context 将 span 传播到 API 实现、追踪器和状态访问中。
+
+// TraceID is the identifier of a trace spanning multiple services.
+// TraceID 是跨越多个服务的追踪的标识符。
+type TraceID [16]byte
+
+// SpanID is the identifier of a single operation within a trace.
+// SpanID 是追踪中单个操作的标识符。
+type SpanID [8]byte
+
+// SpanContext is the propagated identity of a span.
+// SpanContext 是 span 被传播的身份信息。
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+}
+
+// IsValid returns whether the span context has non-zero identifiers.
+// IsValid 返回 span 上下文是否具有非零标识符。
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// Traceparent encodes the span context as a W3C traceparent header.
+// Traceparent 将 span 上下文编码为 W3C traceparent 头。
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%x-%x-%s", sc.TraceID[:], sc.SpanID[:], flags)
+}
+
+// ParseTraceparent decodes a W3C traceparent header.
+// ParseTraceparent 解码 W3C traceparent 头。
+func ParseTraceparent(header string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return SpanContext{}, errors.New("malformed traceparent")
+	}
+	// Version 00 has exactly four fields, future versions may append more
+	// 版本 00 恰好有四个字段，未来的版本可能会追加更多字段
+	if parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return SpanContext{}, errors.New("invalid traceparent version")
+	}
+	var (
+		sc    SpanContext
+		flags [1]byte
+	)
+	if _, err := hex.Decode(sc.TraceID[:], []byte(parts[1])); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace id: %v", err)
+	}
+	if _, err := hex.Decode(sc.SpanID[:], []byte(parts[2])); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid parent id: %v", err)
+	}
+	if _, err := hex.Decode(flags[:], []byte(parts[3])); err != nil {
+		return SpanContext{}, fmt.Errorf("invalid trace flags: %v", err)
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, errors.New("zero trace or parent id")
+	}
+	sc.Sampled = flags[0]&0x01 != 0
+	return sc, nil
+}
+
+// Attribute is a key-value pair annotating a span.
+// Attribute 是注解 span 的键值对。
+type Attribute struct {
+	Key   string
+	Value interface{} // string, int64, bool or float64
+}
+
+// String creates a string attribute.
+func String(key, value string) Attribute { return Attribute{key, value} }
+
+// Int64 creates an integer attribute.
+func Int64(key string, value int64) Attribute { return Attribute{key, value} }
+
+// Int creates an integer attribute.
+func Int(key string, value int) Attribute { return Attribute{key, int64(value)} }
+
+// Bool creates a boolean attribute.
+func Bool(key string, value bool) Attribute { return Attribute{key, value} }
+
+// Span is a single timed operation of a trace. A nil span is valid and ignores
+// all operations, which is what Start returns if the span is not sampled.
+// Span 是追踪中一个计时的操作。nil span 是有效的并忽略所有操作，当 span 未被采样时 Start 返回 nil。
+type Span struct {
+	tracer *Tracer
+	name   string
+	ctx    SpanContext
+	parent SpanID
+	kind   int
+	start  time.Time
+
+	lock  sync.Mutex
+	end   time.Time
+	attrs []Attribute
+	err   string
+	ended bool
+}
+
+// SetAttributes annotates the span with the given attributes.
+// SetAttributes 使用给定的属性注解 span。
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.attrs = append(s.attrs, attrs...)
+}
+
+// SetError marks the span as failed with the given error, if non-nil.
+// SetError 如果错误非 nil，则将 span 标记为以该错误失败。
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.err = err.Error()
+}
+
+// Context returns the propagated identity of the span.
+// Context 返回 span 被传播的身份信息。
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.ctx
+}
+
+// End finishes the span and hands it over for export. Calls after the first
+// are ignored.
+// End 结束 span 并将其交给导出器。第一次之后的调用将被忽略。
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	if s.ended {
+		s.lock.Unlock()
+		return
+	}
+	s.ended, s.end = true, time.Now()
+	s.lock.Unlock()
+
+	s.tracer.export(s)
+}
+
+type (
+	spanKey   struct{}
+	remoteKey struct{}
+	tracerKey struct{}
+)
+
+// ContextWithTracer returns a context recording the spans started within it
+// with the given tracer. Without a tracer in the context, no spans are recorded.
+// ContextWithTracer 返回一个使用给定追踪器记录其中启动的 span 的上下文。如果上下文中没有追踪器，则不记录任何 span。
+func ContextWithTracer(ctx context.Context, t *Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// ContextWithRemoteSpan returns a context carrying a span context received from
+// a remote caller, which becomes the parent of the next started span.
+// ContextWithRemoteSpan 返回一个携带从远程调用方接收的 span 上下文的上下文，它将成为下一个启动的 span 的父级。
+func ContextWithRemoteSpan(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, sc)
+}
+
+// SpanFromContext returns the current span of the context, or nil.
+// SpanFromContext 返回上下文的当前 span，或者 nil。
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// Span kinds as defined by OTLP.
+// OTLP 定义的 span 类型。
+const (
+	kindInternal = 1
+	kindServer   = 2
+)
+
+// Start creates a child span of the span in the context (or of the remote span
+// propagated into it). The returned context carries the new span. If the context
+// carries no tracer or the trace is not sampled, a nil span is returned, along with a
+// context suppressing the creation of further child spans.
+// Start 创建上下文中 span（或传播到其中的远程 span）的子 span。返回的上下文携带新的 span。
+// 如果上下文中没有追踪器或追踪未被采样，则返回 nil span，以及一个抑制后续子 span 创建的上下文。
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return start(ctx, name, kindInternal, attrs)
+}
+
+// StartServer is like Start, but marks the span as the server side handling of
+// a remote request.
+// StartServer 与 Start 类似，但将 span 标记为处理远程请求的服务端。
+func StartServer(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return start(ctx, name, kindServer, attrs)
+}
+
+func start(ctx context.Context, name string, kind int, attrs []Attribute) (context.Context, *Span) {
+	tracer, _ := ctx.Value(tracerKey{}).(*Tracer)
+	if tracer == nil {
+		return ctx, nil
+	}
+	span := &Span{tracer: tracer, name: name, kind: kind, start: time.Now(), attrs: attrs}
+	if value := ctx.Value(spanKey{}); value != nil {
+		// Within a trace, either continue it or stay unsampled along with it
+		// 在追踪内部，要么延续它，要么随之保持未采样
+		parent := value.(*Span)
+		if parent == nil || parent.tracer != tracer {
+			return ctx, nil
+		}
+		span.ctx.TraceID, span.parent = parent.ctx.TraceID, parent.ctx.SpanID
+	} else if remote, ok := ctx.Value(remoteKey{}).(SpanContext); ok && remote.IsValid() {
+		// Traces not sampled by the caller are never recorded. Sampled ones are
+		// still subject to the local policy, callers can't force the export.
+		// 调用方未采样的追踪永远不会被记录。已采样的追踪仍需遵循本地策略，调用方无法强制导出。
+		if !remote.Sampled || !tracer.sample() {
+			return unsampled(ctx), nil
+		}
+		span.ctx.TraceID, span.parent = remote.TraceID, remote.SpanID
+	} else {
+		// New root span, apply the local sampling policy
+		// 新的根 span，应用本地采样策略
+		if !tracer.sample() {
+			return unsampled(ctx), nil
+		}
+		rand.Read(span.ctx.TraceID[:])
+	}
+	rand.Read(span.ctx.SpanID[:])
+	span.ctx.Sampled = true
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// unsampled marks a context as part of an unsampled trace.
+// unsampled 将上下文标记为未采样追踪的一部分。
+func unsampled(ctx context.Context) context.Context {
+	return context.WithValue(ctx, spanKey{}, (*Span)(nil))
+}
@@ -0,0 +1,345 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/internal/tracing"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// collector is a stand-in for an OTLP/HTTP collector, gathering the received
+// spans.
+type collector struct {
+	*httptest.Server
+
+	lock  sync.Mutex
+	spans []map[string]interface{}
+}
+
+func newCollector(t *testing.T) *collector {
+	c := new(collector)
+	c.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("wrong content type: %q", r.Header.Get("Content-Type"))
+		}
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []map[string]interface{}
+				}
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid export request: %v", err)
+		}
+		c.lock.Lock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				c.spans = append(c.spans, ss.Spans...)
+			}
+		}
+		c.lock.Unlock()
+	}))
+	t.Cleanup(c.Close)
+	return c
+}
+
+func (c *collector) find(name string) map[string]interface{} {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, span := range c.spans {
+		if span["name"] == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func TestTraceparent(t *testing.T) {
+	header := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := tracing.ParseTraceparent(header)
+	if err != nil {
+		t.Fatalf("failed to parse traceparent: %v", err)
+	}
+	if !sc.Sampled {
+		t.Fatal("sampled flag not decoded")
+	}
+	if have := sc.Traceparent(); have != header {
+		t.Fatalf("traceparent mismatch: have %s, want %s", have, header)
+	}
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-xbf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	} {
+		if _, err := tracing.ParseTraceparent(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+// newTracedServer creates an RPC server recording spans with a new tracer,
+// serving HTTP and WebSocket.
+func newTracedServer(t *testing.T, config tracing.Config) (*tracing.Tracer, *httptest.Server) {
+	tracer := tracing.New(config)
+	t.Cleanup(tracer.Close)
+
+	server := rpc.NewServer()
+	t.Cleanup(server.Stop)
+	server.RegisterName("test", new(testService))
+	server.SetTracer(tracer)
+	httpsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "websocket" {
+			server.WebsocketHandler([]string{"*"}).ServeHTTP(w, r)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(httpsrv.Close)
+	return tracer, httpsrv
+}
+
+const sampledParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// Tests that the span of an RPC request continues the trace propagated by the
+// caller, nested spans link to it and everything gets exported.
+func TestRPCTracing(t *testing.T) {
+	c := newCollector(t)
+	tracer, httpsrv := newTracedServer(t, tracing.Config{Endpoint: c.URL, SampleRatio: 1})
+
+	// Issue a call continuing a sampled trace
+	client, err := rpc.DialOptions(context.Background(), httpsrv.URL, rpc.WithHeader("traceparent", sampledParent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := client.Call(nil, "test_work"); err != nil {
+		t.Fatal(err)
+	}
+	// Issue a call continuing an unsampled trace, which must not be recorded
+	unsampled, _ := rpc.DialOptions(context.Background(), httpsrv.URL, rpc.WithHeader("traceparent", strings.TrimSuffix(sampledParent, "01")+"00"))
+	defer unsampled.Close()
+	if err := unsampled.Call(nil, "test_other"); err != nil {
+		t.Fatal(err)
+	}
+	tracer.Flush()
+
+	call := c.find("test_work")
+	if call == nil {
+		t.Fatal("call span not exported")
+	}
+	if call["traceId"] != "4bf92f3577b34da6a3ce929d0e0e4736" || call["parentSpanId"] != "00f067aa0ba902b7" {
+		t.Fatalf("call span not linked to caller: %v", call)
+	}
+	inner := c.find("inner")
+	if inner == nil {
+		t.Fatal("inner span not exported")
+	}
+	if inner["traceId"] != call["traceId"] || inner["parentSpanId"] != call["spanId"] {
+		t.Fatalf("inner span not linked to call: %v", inner)
+	}
+	if c.find("test_other") != nil || c.find("other") != nil {
+		t.Fatal("unsampled trace exported")
+	}
+}
+
+// Tests that the trace propagated by the upgrade request of a WebSocket
+// connection is continued by the calls on the connection.
+func TestRPCTracingWebsocket(t *testing.T) {
+	c := newCollector(t)
+	tracer, httpsrv := newTracedServer(t, tracing.Config{Endpoint: c.URL, SampleRatio: 1})
+
+	url := "ws" + strings.TrimPrefix(httpsrv.URL, "http")
+	client, err := rpc.DialOptions(context.Background(), url, rpc.WithHeader("traceparent", sampledParent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := client.Call(nil, "test_work"); err != nil {
+		t.Fatal(err)
+	}
+	tracer.Flush()
+
+	call := c.find("test_work")
+	if call == nil {
+		t.Fatal("call span not exported")
+	}
+	if call["traceId"] != "4bf92f3577b34da6a3ce929d0e0e4736" || call["parentSpanId"] != "00f067aa0ba902b7" {
+		t.Fatalf("call span not linked to caller: %v", call)
+	}
+}
+
+// Tests that callers can't force the export of their traces past the local
+// sampling policy.
+func TestRPCTracingSampleRatio(t *testing.T) {
+	c := newCollector(t)
+	tracer, httpsrv := newTracedServer(t, tracing.Config{Endpoint: c.URL, SampleRatio: 0})
+
+	client, err := rpc.DialOptions(context.Background(), httpsrv.URL, rpc.WithHeader("traceparent", sampledParent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := client.Call(nil, "test_work"); err != nil {
+		t.Fatal(err)
+	}
+	tracer.Flush()
+
+	if c.find("test_work") != nil || c.find("inner") != nil {
+		t.Fatal("remotely sampled trace exported despite zero sample ratio")
+	}
+}
+
+// Tests that servers in the same process record spans with their own tracers.
+func TestRPCTracingMultipleServers(t *testing.T) {
+	var (
+		c1          = newCollector(t)
+		c2          = newCollector(t)
+		tracer1, s1 = newTracedServer(t, tracing.Config{Endpoint: c1.URL, SampleRatio: 1})
+		tracer2, s2 = newTracedServer(t, tracing.Config{Endpoint: c2.URL, SampleRatio: 1})
+	)
+	for i, url := range []string{s1.URL, s2.URL} {
+		client, err := rpc.Dial(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+		method := []string{"test_work", "test_other"}[i]
+		if err := client.Call(nil, method); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tracer1.Flush()
+	tracer2.Flush()
+
+	if c1.find("test_work") == nil || c1.find("test_other") != nil {
+		t.Error("wrong spans exported by first server")
+	}
+	if c2.find("test_other") == nil || c2.find("test_work") != nil {
+		t.Error("wrong spans exported by second server")
+	}
+	// Spans started without a tracer in the context are not recorded
+	if _, span := tracing.Start(context.Background(), "orphan"); span != nil {
+		t.Error("span recorded without tracer")
+	}
+}
+
+// Tests that the span of a call returning a streamed result stays open until
+// the result has been produced and written, and records stream failures.
+func TestRPCTracingStream(t *testing.T) {
+	c := newCollector(t)
+	tracer, httpsrv := newTracedServer(t, tracing.Config{Endpoint: c.URL, SampleRatio: 1})
+
+	client, err := rpc.DialOptions(context.Background(), httpsrv.URL, rpc.WithHeader("traceparent", sampledParent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	var result []int
+	if err := client.Call(&result, "test_stream"); err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("wrong stream result: %v", result)
+	}
+	if err := client.Call(&result, "test_failingStream"); err == nil {
+		t.Fatal("expected error from failing stream")
+	}
+	tracer.Flush()
+
+	call := c.find("test_stream")
+	if call == nil {
+		t.Fatal("stream span not exported")
+	}
+	if d := spanDuration(t, call); d < streamDelay {
+		t.Fatalf("stream span ended before the result was produced: duration %v, want >= %v", d, streamDelay)
+	}
+	if status, _ := call["status"].(map[string]interface{}); status["code"] != nil {
+		t.Fatalf("successful stream span has status %v", status)
+	}
+	failed := c.find("test_failingStream")
+	if failed == nil {
+		t.Fatal("failing stream span not exported")
+	}
+	if status, _ := failed["status"].(map[string]interface{}); status["code"] != float64(2) {
+		t.Fatalf("failing stream span has status %v, want error", status)
+	}
+}
+
+// spanDuration returns the duration of an exported span.
+func spanDuration(t *testing.T, span map[string]interface{}) time.Duration {
+	t.Helper()
+
+	start, err := strconv.ParseInt(span["startTimeUnixNano"].(string), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	end, err := strconv.ParseInt(span["endTimeUnixNano"].(string), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Duration(end - start)
+}
+
+// streamDelay is how long the producer of the streamed test method takes.
+const streamDelay = 50 * time.Millisecond
+
+type testService struct{}
+
+func (s *testService) Work(ctx context.Context) {
+	_, span := tracing.Start(ctx, "inner")
+	span.End()
+}
+
+func (s *testService) Other(ctx context.Context) {
+	_, span := tracing.Start(ctx, "other")
+	span.End()
+}
+
+func (s *testService) Stream() *rpc.Stream {
+	return rpc.ArrayStream(func(enc *rpc.StreamEncoder) error {
+		if err := enc.Encode(1); err != nil {
+			return err
+		}
+		time.Sleep(streamDelay)
+		return enc.Encode(2)
+	})
+}
+
+func (s *testService) FailingStream() *rpc.Stream {
+	return rpc.ArrayStream(func(enc *rpc.StreamEncoder) error {
+		if err := enc.Encode(1); err != nil {
+			return err
+		}
+		return errors.New("stream failed")
+	})
+}
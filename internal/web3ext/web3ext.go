@@ -110,6 +110,15 @@ web3._extend({
 			call: 'admin_removeTrustedPeer',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'addPeerRule',
+			call: 'admin_addPeerRule',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'listPeerRules',
+			call: 'admin_listPeerRules'
+		}),
 		new web3._extend.Method({
 			name: 'exportChain',
 			call: 'admin_exportChain',
@@ -158,6 +167,34 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'reloadConfig',
+			call: 'admin_reloadConfig'
+		}),
+		new web3._extend.Method({
+			name: 'removeTransaction',
+			call: 'admin_removeTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'removeTransactionsFrom',
+			call: 'admin_removeTransactionsFrom',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'addLocalAccount',
+			call: 'admin_addLocalAccount',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'removeLocalAccount',
+			call: 'admin_removeLocalAccount',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'flushTxJournal',
+			call: 'admin_flushTxJournal',
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -468,6 +505,11 @@ web3._extend({
 			call: 'debug_getTrieFlushInterval',
 			params: 0
 		}),
+		new web3._extend.Method({
+			name: 'getPayloadBuildReport',
+			call: 'debug_getPayloadBuildReport',
+			params: 1
+		}),
 	],
 	properties: []
 });
@@ -614,6 +656,10 @@ web3._extend({
 			getter: 'eth_maxPriorityFeePerGas',
 			outputFormatter: web3._extend.utils.toBigNumber
 		}),
+		new web3._extend.Property({
+			name: 'feeEstimates',
+			getter: 'eth_feeEstimates'
+		}),
 	]
 });
 `
@@ -660,7 +706,12 @@ web3._extend({
 const RpcJs = `
 web3._extend({
 	property: 'rpc',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'discover',
+			call: 'rpc_discover',
+		}),
+	],
 	properties: [
 		new web3._extend.Property({
 			name: 'modules',
@@ -23,8 +23,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -97,6 +99,8 @@ type Miner struct {
 	chain       *core.BlockChain    // 区块链实例
 	pending     *pending            // 待处理区块
 	pendingMu   sync.Mutex          // 保护待处理区块的锁
+
+	reports *lru.Cache[engine.PayloadID, *BuildReport] // Diagnostics of recently built payloads // 最近构建的有效载荷的诊断信息
 }
 
 // New creates a new miner with provided config.
@@ -109,6 +113,7 @@ func New(eth Backend, config Config, engine consensus.Engine) *Miner {
 		txpool:      eth.TxPool(),
 		chain:       eth.BlockChain(),
 		pending:     &pending{},
+		reports:     newBuildReports(),
 	}
 }
 
@@ -162,6 +167,17 @@ func (miner *Miner) BuildPayload(args *BuildPayloadArgs, witness bool) (*Payload
 	return miner.buildPayload(args, witness)
 }
 
+// PayloadBuildReport returns the diagnostics collected while building the payload
+// with the given id, or nil if the payload is unknown or too old.
+// PayloadBuildReport 返回构建给定 id 的有效载荷时收集的诊断信息，如果有效载荷未知或过旧则返回 nil。
+func (miner *Miner) PayloadBuildReport(id engine.PayloadID) *BuildReport {
+	report, ok := miner.reports.Get(id)
+	if !ok {
+		return nil
+	}
+	return report.copy()
+}
+
 // getPending retrieves the pending block based on the current head block.
 // The result might be nil if pending generation is failed.
 // getPending 根据当前头部区块检索待处理区块。
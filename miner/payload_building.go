@@ -20,13 +20,16 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"math/big"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/beacon/engine"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
@@ -105,6 +108,7 @@ type Payload struct {
 	emptyRequests [][]byte
 	requests      [][]byte
 	fullFees      *big.Int
+	report        *BuildReport
 	stop          chan struct{}
 	lock          sync.Mutex
 	cond          *sync.Cond
@@ -112,12 +116,13 @@ type Payload struct {
 
 // newPayload initializes the payload object.
 // newPayload 初始化有效载荷对象。
-func newPayload(empty *types.Block, emptyRequests [][]byte, witness *stateless.Witness, id engine.PayloadID) *Payload {
+func newPayload(empty *types.Block, emptyRequests [][]byte, witness *stateless.Witness, id engine.PayloadID, report *BuildReport) *Payload {
 	payload := &Payload{
 		id:            id,
 		empty:         empty,
 		emptyRequests: emptyRequests,
 		emptyWitness:  witness,
+		report:        report,
 		stop:          make(chan struct{}),
 	}
 	log.Info("Starting work on payload", "id", payload.id)
@@ -125,18 +130,20 @@ func newPayload(empty *types.Block, emptyRequests [][]byte, witness *stateless.W
 	return payload
 }
 
-// update updates the full-block with latest built version.
-// update 方法使用最新构建的版本更新完整区块。
-func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
+// update updates the full-block with latest built version, returning whether
+// it was accepted as the best one so far.
+// update 方法使用最新构建的版本更新完整区块，返回其是否被接受为目前最好的版本。
+func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) bool {
 	payload.lock.Lock()
 	defer payload.lock.Unlock()
 
 	select {
 	case <-payload.stop:
-		return // reject stale update
+		return false // reject stale update
 		// 拒绝过期的更新
 	default:
 	}
+	var accepted bool
 	// Ensure the newly provided full block has a higher transaction fee.
 	// In post-merge stage, there is no uncle reward anymore and transaction
 	// fee(apart from the mev revenue) is the only indicator for comparison.
@@ -148,6 +155,7 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 		payload.sidecars = r.sidecars
 		payload.requests = r.requests
 		payload.fullWitness = r.witness
+		accepted = true
 
 		feesInEther := new(big.Float).Quo(new(big.Float).SetInt(r.fees), big.NewFloat(params.Ether))
 		log.Info("Updated payload",
@@ -164,6 +172,7 @@ func (payload *Payload) update(r *newPayloadResult, elapsed time.Duration) {
 	}
 	payload.cond.Broadcast() // fire signal for notifying full block
 	// 广播信号以通知完整区块已更新
+	return accepted
 }
 
 // Resolve returns the latest built payload and also terminates the background
@@ -271,9 +280,13 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs, witness bool) (*Payload
 	if empty.err != nil {
 		return nil, empty.err
 	}
-	// Construct a payload object for return.
-	// 构造一个有效载荷对象以返回。
-	payload := newPayload(empty.block, empty.requests, empty.witness, args.Id())
+	// Construct a payload object for return, tracking the diagnostics of the
+	// build so they can be retrieved even after the payload is delivered.
+	// 构造一个有效载荷对象以返回，并跟踪构建的诊断信息，以便在有效载荷交付后仍可检索。
+	report := newBuildReport(args)
+	miner.reports.Add(report.ID, report)
+
+	payload := newPayload(empty.block, empty.requests, empty.witness, args.Id(), report)
 
 	// Spin up a routine for updating the payload in background. This strategy
 	// can maximum the revenue for including transactions with highest fee.
@@ -301,6 +314,7 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs, witness bool) (*Payload
 			withdrawals: args.Withdrawals,
 			beaconRoot:  args.BeaconRoot,
 			noTxs:       false,
+			diagnose:    true,
 		}
 
 		for {
@@ -308,11 +322,20 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs, witness bool) (*Payload
 			case <-timer.C:
 				start := time.Now()
 				r := miner.generateWork(fullParams, witness)
+
+				iteration := r.report
+				if iteration == nil {
+					iteration = newBuildIteration()
+				}
+				elapsed := time.Since(start)
+				iteration.Start, iteration.Elapsed = start, hexutil.Uint64(elapsed)
 				if r.err == nil {
-					payload.update(r, time.Since(start))
+					iteration.Best = payload.update(r, elapsed)
 				} else {
+					iteration.Error = r.err.Error()
 					log.Info("Error while generating work", "id", payload.id, "err", r.err)
 				}
+				report.add(iteration)
 				timer.Reset(miner.config.Recommit)
 			case <-payload.stop:
 				log.Info("Stopping work on payload", "id", payload.id, "reason", "delivery")
@@ -325,3 +348,187 @@ func (miner *Miner) buildPayload(args *BuildPayloadArgs, witness bool) (*Payload
 	}()
 	return payload, nil
 }
+
+// Reasons for which a pending pool transaction was left out of a built payload.
+// 待处理的池交易被排除在构建的有效载荷之外的原因。
+const (
+	SkipUnderpriced     = "underpriced"      // Fee cap below the base fee of the block
+	SkipNonceTooLow     = "nonce too low"    // Nonce already used in the parent state
+	SkipNonceGap        = "nonce gap"        // A previous transaction of the sender was skipped
+	SkipGasLimit        = "gas limit"        // Not enough gas left in the block
+	SkipBlobLimit       = "blob limit"       // Not enough blob space left in the block
+	SkipExecution       = "execution failed" // Transaction failed to apply on top of the block
+	SkipEvicted         = "evicted"          // Transaction dropped from the pool during the build
+	SkipReplayProtected = "replay protected" // Replay protected transaction before EIP-155
+	SkipNotReached      = "not reached"      // Build stopped before considering the transaction
+)
+
+const (
+	// maxReportedSkips is the maximum number of individually skipped transactions
+	// reported per build iteration. The per reason counters are always complete.
+	// maxReportedSkips 是每次构建迭代中单独报告的被跳过交易的最大数量。按原因统计的计数器始终是完整的。
+	maxReportedSkips = 256
+
+	// buildReportsLimit is the number of recent payload build reports retained.
+	// buildReportsLimit 是保留的最近有效载荷构建报告的数量。
+	buildReportsLimit = 64
+)
+
+// SkippedTx is a pending pool transaction that was not included into a payload.
+// SkippedTx 是未被包含在有效载荷中的待处理池交易。
+type SkippedTx struct {
+	Hash   common.Hash    `json:"hash"`
+	Sender common.Address `json:"sender"`
+	Reason string         `json:"reason"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// BuildIteration contains the diagnostics of a single attempt at filling a payload
+// with transactions from the pool.
+// BuildIteration 包含一次使用池中交易填充有效载荷的尝试的诊断信息。
+type BuildIteration struct {
+	Start        time.Time      `json:"start"`
+	Elapsed      hexutil.Uint64 `json:"elapsed"`      // Total time spent building the block, in nanoseconds
+	FillTime     hexutil.Uint64 `json:"fillTime"`     // Time spent selecting and executing transactions, in nanoseconds
+	FinalizeTime hexutil.Uint64 `json:"finalizeTime"` // Time spent finalizing and assembling the block, in nanoseconds
+	Error        string         `json:"error,omitempty"`
+	Interrupted  string         `json:"interrupted,omitempty"`
+
+	Number       hexutil.Uint64 `json:"number"`
+	Hash         common.Hash    `json:"hash"`
+	Transactions hexutil.Uint   `json:"transactions"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	BlobGasUsed  hexutil.Uint64 `json:"blobGasUsed"`
+	Fees         *hexutil.Big   `json:"fees"`
+	Best         bool           `json:"best"` // Whether the block became the best payload when built
+
+	Considered  hexutil.Uint            `json:"considered"`  // Pending transactions passing the pool fee filters
+	Underpriced hexutil.Uint            `json:"underpriced"` // Pending transactions rejected by the pool fee filters
+	Skips       map[string]hexutil.Uint `json:"skips"`       // Number of skipped transactions by reason
+	Skipped     []*SkippedTx            `json:"skipped"`     // Skipped transactions, capped at maxReportedSkips
+
+	failures map[common.Hash]*SkippedTx // Explicitly skipped transactions during the fill
+}
+
+// newBuildIteration creates an empty build iteration report.
+// newBuildIteration 创建一个空的构建迭代报告。
+func newBuildIteration() *BuildIteration {
+	return &BuildIteration{
+		Fees:     new(hexutil.Big),
+		Skips:    make(map[string]hexutil.Uint),
+		failures: make(map[common.Hash]*SkippedTx),
+	}
+}
+
+// skip records that a transaction was skipped during the fill for the given
+// reason. It is a noop if diagnostics are not collected.
+// skip 记录交易在填充过程中因给定原因被跳过。如果未收集诊断信息，则不执行任何操作。
+func (it *BuildIteration) skip(hash common.Hash, reason string, err error) {
+	if it == nil {
+		return
+	}
+	skipped := &SkippedTx{Hash: hash, Reason: reason}
+	if err != nil {
+		skipped.Error = err.Error()
+	}
+	it.failures[hash] = skipped
+}
+
+// classify goes over all the pending transactions considered for the block and
+// attributes a reason to every one of them that was not included.
+// classify 遍历区块考虑过的所有待处理交易，并为每一个未被包含的交易给出原因。
+func (it *BuildIteration) classify(env *environment, pending []map[common.Address][]*txpool.LazyTransaction) {
+	included := make(map[common.Hash]struct{}, len(env.txs))
+	for _, tx := range env.txs {
+		included[tx.Hash()] = struct{}{}
+	}
+	var (
+		gasFull  = env.gasPool == nil || env.gasPool.Gas() < params.TxGas
+		blobFull = env.blobs*params.BlobTxBlobGasPerBlob >= params.MaxBlobGasPerBlock
+	)
+	for _, set := range pending {
+		for from, txs := range set {
+			var gapped bool
+			for _, ltx := range txs {
+				if _, ok := included[ltx.Hash]; ok {
+					continue
+				}
+				skipped, ok := it.failures[ltx.Hash]
+				switch {
+				case ok:
+					gapped = gapped || skipped.Reason != SkipNonceTooLow
+				case env.header.BaseFee != nil && ltx.GasFeeCap.CmpBig(env.header.BaseFee) < 0:
+					skipped = &SkippedTx{Hash: ltx.Hash, Reason: SkipUnderpriced}
+					gapped = true
+				case gapped:
+					skipped = &SkippedTx{Hash: ltx.Hash, Reason: SkipNonceGap}
+				case ltx.BlobGas > 0 && blobFull:
+					skipped = &SkippedTx{Hash: ltx.Hash, Reason: SkipBlobLimit}
+				case gasFull:
+					skipped = &SkippedTx{Hash: ltx.Hash, Reason: SkipGasLimit}
+				default:
+					skipped = &SkippedTx{Hash: ltx.Hash, Reason: SkipNotReached}
+				}
+				skipped.Sender = from
+
+				it.Skips[skipped.Reason]++
+				if len(it.Skipped) < maxReportedSkips {
+					it.Skipped = append(it.Skipped, skipped)
+				}
+			}
+		}
+	}
+	it.failures = nil
+}
+
+// BuildReport contains the diagnostics of all the attempts at building a payload.
+// BuildReport 包含构建有效载荷的所有尝试的诊断信息。
+type BuildReport struct {
+	ID         engine.PayloadID  `json:"id"`
+	Parent     common.Hash       `json:"parent"`
+	Timestamp  hexutil.Uint64    `json:"timestamp"`
+	Iterations []*BuildIteration `json:"iterations"`
+
+	lock sync.Mutex
+}
+
+// newBuildReport creates an empty build report for a payload.
+// newBuildReport 为有效载荷创建一个空的构建报告。
+func newBuildReport(args *BuildPayloadArgs) *BuildReport {
+	return &BuildReport{
+		ID:        args.Id(),
+		Parent:    args.Parent,
+		Timestamp: hexutil.Uint64(args.Timestamp),
+	}
+}
+
+// newBuildReports creates the cache retaining the reports of recently built
+// payloads.
+// newBuildReports 创建保留最近构建的有效载荷报告的缓存。
+func newBuildReports() *lru.Cache[engine.PayloadID, *BuildReport] {
+	return lru.NewCache[engine.PayloadID, *BuildReport](buildReportsLimit)
+}
+
+// add appends a finished build iteration to the report.
+// add 将一个完成的构建迭代追加到报告中。
+func (report *BuildReport) add(it *BuildIteration) {
+	report.lock.Lock()
+	defer report.lock.Unlock()
+
+	report.Iterations = append(report.Iterations, it)
+}
+
+// copy returns a snapshot of the report which is safe to use concurrently with
+// the payload still being built.
+// copy 返回报告的快照，可在有效载荷仍在构建时安全地并发使用。
+func (report *BuildReport) copy() *BuildReport {
+	report.lock.Lock()
+	defer report.lock.Unlock()
+
+	return &BuildReport{
+		ID:         report.ID,
+		Parent:     report.Parent,
+		Timestamp:  report.Timestamp,
+		Iterations: slices.Clone(report.Iterations),
+	}
+}
@@ -17,8 +17,10 @@
 package miner
 
 import (
+	"encoding/json"
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -194,6 +196,69 @@ func TestBuildPayload(t *testing.T) {
 	}
 }
 
+func TestBuildPayloadReport(t *testing.T) {
+	var (
+		db        = rawdb.NewMemoryDatabase()
+		recipient = common.HexToAddress("0xdeadbeef")
+	)
+	w, b := newTestWorker(t, params.TestChainConfig, ethash.NewFaker(), db, 0)
+
+	args := &BuildPayloadArgs{
+		Parent:       b.chain.CurrentBlock().Hash(),
+		Timestamp:    uint64(time.Now().Unix()),
+		Random:       common.Hash{},
+		FeeRecipient: recipient,
+	}
+	payload, err := w.buildPayload(args, false)
+	if err != nil {
+		t.Fatalf("Failed to build payload %v", err)
+	}
+	full := payload.ResolveFull()
+
+	// The iteration is recorded right after the payload is updated, wait for it
+	var report *BuildReport
+	for i := 0; i < 100; i++ {
+		if report = w.PayloadBuildReport(args.Id()); report != nil && len(report.Iterations) > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if report == nil || len(report.Iterations) == 0 {
+		t.Fatal("Missing payload build report")
+	}
+	if report.ID != args.Id() || report.Parent != args.Parent {
+		t.Fatalf("Report metadata mismatch: have %v/%x, want %v/%x", report.ID, report.Parent, args.Id(), args.Parent)
+	}
+	it := report.Iterations[0]
+	if !it.Best {
+		t.Error("First iteration not marked as best payload")
+	}
+	if it.Hash != full.ExecutionPayload.BlockHash {
+		t.Errorf("Iteration block mismatch: have %x, want %x", it.Hash, full.ExecutionPayload.BlockHash)
+	}
+	if int(it.Considered) != len(pendingTxs) || int(it.Transactions) != len(pendingTxs) {
+		t.Errorf("Transaction counts mismatch: considered %d, included %d, want %d", it.Considered, it.Transactions, len(pendingTxs))
+	}
+	if len(it.Skips) != 0 || len(it.Skipped) != 0 {
+		t.Errorf("Unexpected skipped transactions: %v", it.Skips)
+	}
+	// Quantities are hex encoded like the rest of the RPC output
+	enc, err := json.Marshal(it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	json.Unmarshal(enc, &fields)
+	for _, field := range []string{"elapsed", "number", "gasUsed", "fees", "transactions"} {
+		if v, ok := fields[field].(string); !ok || !strings.HasPrefix(v, "0x") {
+			t.Errorf("Field %s not hex encoded: %v", field, fields[field])
+		}
+	}
+	if w.PayloadBuildReport(engine.PayloadID{0xff}) != nil {
+		t.Error("Report returned for unknown payload")
+	}
+}
+
 func TestPayloadId(t *testing.T) {
 	t.Parallel()
 	ids := make(map[string]int)
@@ -19,11 +19,13 @@ package miner
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"math/big"
 	"sync/atomic"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
@@ -64,6 +66,7 @@ type environment struct {
 	blobs    int
 
 	witness *stateless.Witness
+	report  *BuildIteration // Diagnostics of the block building, nil if not collected // 区块构建的诊断信息，未收集时为nil
 }
 
 const (
@@ -84,6 +87,7 @@ type newPayloadResult struct {
 	receipts []*types.Receipt       // Receipts collected during construction // 构建过程中收集的收据
 	requests [][]byte               // Consensus layer requests collected during block construction // 区块构建过程中收集的共识层请求
 	witness  *stateless.Witness     // Witness is an optional stateless proof // Witness是可选的无状态证明
+	report   *BuildIteration        // Diagnostics of the block building, if requested // 区块构建的诊断信息（如果请求）
 }
 
 // generateParams wraps various settings for generating sealing task.
@@ -97,6 +101,7 @@ type generateParams struct {
 	withdrawals types.Withdrawals // List of withdrawals to include in block (shanghai field) // 要包含在区块中的提款列表(上海升级字段)
 	beaconRoot  *common.Hash      // The beacon root (cancun field). // 信标根(坎昆升级字段)
 	noTxs       bool              // Flag whether an empty block without any transaction is expected // 标志是否期望生成不包含任何交易的空区块
+	diagnose    bool              // Flag whether to collect diagnostics about the transaction selection // 标志是否收集有关交易选择的诊断信息
 }
 
 // generateWork generates a sealing block based on the given parameters.
@@ -113,11 +118,22 @@ func (miner *Miner) generateWork(params *generateParams, witness bool) *newPaylo
 		})
 		defer timer.Stop()
 
+		if params.diagnose {
+			work.report = newBuildIteration()
+		}
+		start := time.Now()
 		err := miner.fillTransactions(interrupt, work)
 		if errors.Is(err, errBlockInterruptedByTimeout) {
 			log.Warn("Block building is interrupted", "allowance", common.PrettyDuration(miner.config.Recommit))
 		}
+		if work.report != nil {
+			work.report.FillTime = hexutil.Uint64(time.Since(start))
+			if err != nil {
+				work.report.Interrupted = err.Error()
+			}
+		}
 	}
+	finalizeStart := time.Now()
 
 	body := types.Body{Transactions: work.txs, Withdrawals: params.withdrawals}
 	allLogs := make([]*types.Log, 0)
@@ -148,14 +164,27 @@ func (miner *Miner) generateWork(params *generateParams, witness bool) *newPaylo
 	if err != nil {
 		return &newPayloadResult{err: err}
 	}
+	fees := totalFees(block, work.receipts)
+	if work.report != nil {
+		work.report.FinalizeTime = hexutil.Uint64(time.Since(finalizeStart))
+		work.report.Number = hexutil.Uint64(block.NumberU64())
+		work.report.Hash = block.Hash()
+		work.report.Transactions = hexutil.Uint(len(block.Transactions()))
+		work.report.GasUsed = hexutil.Uint64(block.GasUsed())
+		if block.BlobGasUsed() != nil {
+			work.report.BlobGasUsed = hexutil.Uint64(*block.BlobGasUsed())
+		}
+		work.report.Fees = (*hexutil.Big)(fees)
+	}
 	return &newPayloadResult{
 		block:    block,
-		fees:     totalFees(block, work.receipts),
+		fees:     fees,
 		sidecars: work.sidecars,
 		stateDB:  work.state,
 		receipts: work.receipts,
 		requests: requests,
 		witness:  work.witness,
+		report:   work.report,
 	}
 }
 
@@ -401,11 +430,13 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// 如果我们没有足够的空间用于下一个交易，跳过该账户
 		if env.gasPool.Gas() < ltx.Gas {
 			log.Trace("Not enough gas left for transaction", "hash", ltx.Hash, "left", env.gasPool.Gas(), "needed", ltx.Gas)
+			env.report.skip(ltx.Hash, SkipGasLimit, nil)
 			txs.Pop()
 			continue
 		}
 		if left := uint64(params.MaxBlobGasPerBlock - env.blobs*params.BlobTxBlobGasPerBlob); left < ltx.BlobGas {
 			log.Trace("Not enough blob gas left for transaction", "hash", ltx.Hash, "left", left, "needed", ltx.BlobGas)
+			env.report.skip(ltx.Hash, SkipBlobLimit, nil)
 			txs.Pop()
 			continue
 		}
@@ -414,6 +445,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		tx := ltx.Resolve()
 		if tx == nil {
 			log.Trace("Ignoring evicted transaction", "hash", ltx.Hash)
+			env.report.skip(ltx.Hash, SkipEvicted, nil)
 			txs.Pop()
 			continue
 		}
@@ -428,6 +460,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 		// 开始忽略发送者，直到我们进入该阶段
 		if tx.Protected() && !miner.chainConfig.IsEIP155(env.header.Number) {
 			log.Trace("Ignoring replay protected transaction", "hash", ltx.Hash, "eip155", miner.chainConfig.EIP155Block)
+			env.report.skip(ltx.Hash, SkipReplayProtected, nil)
 			txs.Pop()
 			continue
 		}
@@ -441,6 +474,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 			// New head notification data race between the transaction pool and miner, shift
 			// 交易池和矿工之间的新头通知数据竞争，移位
 			log.Trace("Skipping transaction with low nonce", "hash", ltx.Hash, "sender", from, "nonce", tx.Nonce())
+			env.report.skip(ltx.Hash, SkipNonceTooLow, err)
 			txs.Shift()
 
 		case errors.Is(err, nil):
@@ -454,6 +488,7 @@ func (miner *Miner) commitTransactions(env *environment, plainTxs, blobTxs *tran
 			// 交易被视为无效，由于`nonce-too-high`条款，
 			// 丢弃来自同一发送者的所有连续交易
 			log.Debug("Transaction failed, account skipped", "hash", ltx.Hash, "err", err)
+			env.report.skip(ltx.Hash, SkipExecution, err)
 			txs.Pop()
 		}
 	}
@@ -487,6 +522,22 @@ func (miner *Miner) fillTransactions(interrupt *atomic.Int32, env *environment)
 	filter.OnlyPlainTxs, filter.OnlyBlobTxs = false, true
 	pendingBlobTxs := miner.txpool.Pending(filter)
 
+	// If diagnostics are requested, retain the considered transactions (the
+	// maps are consumed during the fill) to explain the ones left out.
+	// 如果请求了诊断信息，保留考虑过的交易（映射在填充过程中会被消耗），以解释被排除的交易。
+	if env.report != nil {
+		considered := []map[common.Address][]*txpool.LazyTransaction{maps.Clone(pendingPlainTxs), maps.Clone(pendingBlobTxs)}
+		for _, set := range considered {
+			for _, txs := range set {
+				env.report.Considered += hexutil.Uint(len(txs))
+			}
+		}
+		if pending, _ := miner.txpool.Stats(); pending > int(env.report.Considered) {
+			env.report.Underpriced = hexutil.Uint(pending) - env.report.Considered
+		}
+		defer env.report.classify(env, considered)
+	}
+
 	// Split the pending transactions into locals and remotes.
 	// 将待处理的交易分为本地和远程
 	localPlainTxs, remotePlainTxs := make(map[common.Address][]*txpool.LazyTransaction), pendingPlainTxs
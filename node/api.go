@@ -213,6 +213,11 @@ func (api *adminAPI) StartHTTP(host *string, port *int, cors *string, apis *stri
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			batchConcurrency:       api.node.config.BatchConcurrency,
+			responseSizeLimit:      api.node.config.RPCResponseMaxSize,
+			rateLimiter:            api.node.rateLimiter,
+			authorizer:             api.node.authorizer,
+			recorder:               api.node.recorder,
 		},
 	}
 	if cors != nil {
@@ -271,6 +276,13 @@ func (api *adminAPI) StopRPC() (bool, error) {
 	return api.StopHTTP()
 }
 
+// ReloadConfig re-reads the configuration file and applies the changes which can
+// be made at runtime, reporting the ones requiring a restart.
+// ReloadConfig 重新读取配置文件并应用可以在运行时进行的更改，报告需要重启的更改。
+func (api *adminAPI) ReloadConfig() (*ConfigReloadReport, error) {
+	return api.node.ReloadConfig()
+}
+
 // StartWS starts the websocket RPC API server.
 // StartWS 启动 WebSocket RPC API 服务器。
 func (api *adminAPI) StartWS(host *string, port *int, allowedOrigins *string, apis *string) (bool, error) {
@@ -299,6 +311,11 @@ func (api *adminAPI) StartWS(host *string, port *int, allowedOrigins *string, ap
 		rpcEndpointConfig: rpcEndpointConfig{
 			batchItemLimit:         api.node.config.BatchRequestLimit,
 			batchResponseSizeLimit: api.node.config.BatchResponseMaxSize,
+			batchConcurrency:       api.node.config.BatchConcurrency,
+			responseSizeLimit:      api.node.config.RPCResponseMaxSize,
+			rateLimiter:            api.node.rateLimiter,
+			authorizer:             api.node.authorizer,
+			recorder:               api.node.recorder,
 		},
 	}
 	if apis != nil {
@@ -339,6 +356,32 @@ func (api *adminAPI) StopWS() (bool, error) {
 	return true, nil
 }
 
+// AddPeerRule adds an allow/deny rule to the peer policy. Rules added this way
+// take precedence over the rules of the policy file.
+// AddPeerRule 向对等方策略添加一条允许/拒绝规则。以这种方式添加的规则优先于策略文件中的规则。
+func (api *adminAPI) AddPeerRule(rule p2p.PeerRule) (bool, error) {
+	// Make sure the server is running, fail otherwise
+	// 确保服务器正在运行，否则失败
+	server := api.node.Server()
+	if server == nil {
+		return false, ErrNodeStopped
+	}
+	if err := server.AddPeerRule(rule); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListPeerRules returns the rules of the peer policy with their match counters.
+// ListPeerRules 返回对等方策略的规则及其匹配计数器。
+func (api *adminAPI) ListPeerRules() (*p2p.PeerPolicyInfo, error) {
+	server := api.node.Server()
+	if server == nil {
+		return nil, ErrNodeStopped
+	}
+	return server.PeerPolicy(), nil
+}
+
 // Peers retrieves all the information we know about each individual peer at the
 // protocol granularity.
 // Peers 检索我们所知道的每个单独节点在协议粒度上的所有信息。
@@ -274,10 +274,52 @@ type Config struct {
 	// BatchResponseMaxSize 是从批处理 RPC 调用返回的最大字节数。
 	BatchResponseMaxSize int `toml:",omitempty"`
 
+	// BatchConcurrency is the maximum number of calls of a batch executed concurrently
+	// on a single connection. Zero or one executes batches sequentially, which is the
+	// default.
+	// BatchConcurrency 是单个连接上批处理中并发执行的调用的最大数量。0 或 1 表示按顺序执行批处理，这是默认行为。
+	BatchConcurrency int `toml:",omitempty"`
+
+	// RPCResponseMaxSize is the maximum number of bytes returned from a single rpc
+	// call. Large results are checked while being streamed out. Zero means no limit.
+	// RPCResponseMaxSize 是单个 RPC 调用返回的最大字节数。大型结果在流式输出时被检查。零表示没有限制。
+	RPCResponseMaxSize int `toml:",omitempty"`
+
+	// RPCRecordFile is the file the calls served by the HTTP and WebSocket endpoints
+	// are recorded to, along with their responses. Empty disables recording.
+	// RPCRecordFile 是 HTTP 和 WebSocket 端点处理的调用及其响应被记录到的文件。为空时禁用记录。
+	RPCRecordFile string `toml:",omitempty"`
+
+	// RPCRecordMaxSize is the size in bytes after which the recording is rotated,
+	// keeping RPCRecordMaxFiles old files. Zero disables rotation.
+	// RPCRecordMaxSize 是记录轮换的字节大小，保留 RPCRecordMaxFiles 个旧文件。零表示禁用轮换。
+	RPCRecordMaxSize  int64 `toml:",omitempty"`
+	RPCRecordMaxFiles int   `toml:",omitempty"`
+
 	// JWTSecret is the path to the hex-encoded jwt secret.
 	// JWTSecret 是十六进制编码的 JWT 秘密的路径。
 	JWTSecret string `toml:",omitempty"`
 
+	// RPCRateLimit configures the per-client rate limits and quotas of the HTTP
+	// and WebSocket RPC endpoints.
+	// RPCRateLimit 配置 HTTP 和 WebSocket RPC 端点的按客户端速率限制和配额。
+	RPCRateLimit RateLimitConfig `toml:",omitempty"`
+
+	// RPCAuth configures the JWT based authorization of API namespaces and methods
+	// on the HTTP and WebSocket RPC endpoints.
+	// RPCAuth 配置 HTTP 和 WebSocket RPC 端点上基于 JWT 的 API 命名空间和方法授权。
+	RPCAuth RPCAuthConfig `toml:",omitempty"`
+
+	// TracingEndpoint is the OTLP/HTTP endpoint the traces of RPC requests are
+	// exported to, e.g. http://localhost:4318/v1/traces. Empty disables tracing.
+	// TracingEndpoint 是 RPC 请求追踪导出到的 OTLP/HTTP 端点，例如 http://localhost:4318/v1/traces。为空时禁用追踪。
+	TracingEndpoint string `toml:",omitempty"`
+
+	// TracingSampleRatio is the fraction of RPC requests traced. Requests whose
+	// traceparent header marks the trace as unsampled are never traced.
+	// TracingSampleRatio 是被追踪的 RPC 请求比例。traceparent 头将追踪标记为未采样的请求永远不会被追踪。
+	TracingSampleRatio float64 `toml:",omitempty"`
+
 	// EnablePersonal enables the deprecated personal namespace.
 	// EnablePersonal 启用已弃用的 personal 命名空间。
 	EnablePersonal bool `toml:"-"`
@@ -17,6 +17,7 @@
 package node
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -33,6 +34,18 @@ import (
 
 const jwtExpiryTimeout = 60 * time.Second // JWT 过期时间为 60 秒
 
+// jwtClaimsKey is the context key of the claims of a request's bearer token,
+// only set once the token has been verified.
+// jwtClaimsKey 是请求的 bearer 令牌声明的上下文键，仅在令牌验证通过后设置。
+type jwtClaimsKey struct{}
+
+// withVerifiedClaims returns the request tagged with the claims of its verified
+// bearer token.
+// withVerifiedClaims 返回标记了已验证 bearer 令牌声明的请求。
+func withVerifiedClaims(r *http.Request, claims jwt.MapClaims) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), jwtClaimsKey{}, claims))
+}
+
 type jwtHandler struct {
 	keyFunc func(token *jwt.Token) (interface{}, error) // 提供密钥的函数
 	next    http.Handler                                // 下一个处理程序
@@ -94,6 +107,12 @@ func (handler *jwtHandler) ServeHTTP(out http.ResponseWriter, r *http.Request) {
 	case time.Until(claims.IssuedAt.Time) > jwtExpiryTimeout:
 		http.Error(out, "future token", http.StatusUnauthorized) // 如果令牌来自未来（超过 60 秒），返回 401 错误
 	default:
+		// The signature was checked above, expose all the claims of the token
+		// 签名已在上面检查过，公开令牌的所有声明
+		verified := make(jwt.MapClaims)
+		if _, _, err := jwt.NewParser().ParseUnverified(strToken, verified); err == nil {
+			r = withVerifiedClaims(r, verified)
+		}
 		handler.next.ServeHTTP(out, r) // 如果令牌有效，调用下一个处理程序
 	}
 }
@@ -36,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -78,6 +79,14 @@ type Node struct {
 	ipc           *ipcServer  // Stores information about the ipc http server // 存储有关 IPC HTTP 服务器的信息
 	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests // 处理 API 请求的进程内 RPC 请求处理程序
 
+	rateLimiter *rateLimiter    // Per-client rate limiter shared by the HTTP and WS endpoints // HTTP 和 WS 端点共享的按客户端速率限制器
+	authorizer  *rpcAuthorizer  // JWT based API authorization of the HTTP and WS endpoints // HTTP 和 WS 端点基于 JWT 的 API 授权
+	recorder    *rpc.Recorder   // Recording of the calls served by the HTTP and WS endpoints, nil if disabled // HTTP 和 WS 端点处理的调用记录，禁用时为 nil
+	tracer      *tracing.Tracer // Exporter of RPC request traces, nil if disabled // RPC 请求追踪的导出器，禁用时为 nil
+
+	reloadLock sync.Mutex     // Serializes configuration reloads // 序列化配置重载
+	reloader   ConfigReloader // Re-reads and applies the configuration, nil if unsupported // 重新读取并应用配置，不支持时为 nil
+
 	databases map[*closeTrackingDB]struct{} // All open databases // 所有打开的数据库
 }
 
@@ -120,6 +129,12 @@ func New(conf *Config) (*Node, error) {
 	}
 	server := rpc.NewServer()
 	server.SetBatchLimits(conf.BatchRequestLimit, conf.BatchResponseMaxSize)
+	server.SetResponseSizeLimit(conf.RPCResponseMaxSize)
+	server.SetBatchConcurrency(conf.BatchConcurrency)
+	authorizer, err := newRPCAuthorizer(conf.RPCAuth)
+	if err != nil {
+		return nil, err
+	}
 	node := &Node{
 		config:        conf,
 		inprocHandler: server,
@@ -128,6 +143,8 @@ func New(conf *Config) (*Node, error) {
 		stop:          make(chan struct{}),
 		server:        &p2p.Server{Config: conf.P2P},
 		databases:     make(map[*closeTrackingDB]struct{}),
+		rateLimiter:   newRateLimiter(conf.RPCRateLimit),
+		authorizer:    authorizer,
 	}
 
 	// Register built-in APIs.
@@ -177,6 +194,25 @@ func New(conf *Config) (*Node, error) {
 	node.wsAuth = newHTTPServer(node.log, rpc.DefaultHTTPTimeouts)
 	node.ipc = newIPCServer(node.log, conf.IPCEndpoint())
 
+	// Open the recording of served RPC calls.
+	// 打开已处理 RPC 调用的记录。
+	if conf.RPCRecordFile != "" {
+		node.recorder, err = rpc.NewRecorder(node.ResolvePath(conf.RPCRecordFile), conf.RPCRecordMaxSize, conf.RPCRecordMaxFiles)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Configure RPC request tracing.
+	// 配置 RPC 请求追踪。
+	if conf.TracingEndpoint != "" {
+		node.tracer = tracing.New(tracing.Config{
+			Endpoint:    conf.TracingEndpoint,
+			SampleRatio: conf.TracingSampleRatio,
+			ServiceName: conf.Name,
+		})
+		server.SetTracer(node.tracer)
+	}
 	return node, nil
 }
 
@@ -282,6 +318,17 @@ func (n *Node) doClose(errs []error) error {
 	// 释放实例目录锁。
 	n.closeDataDir()
 
+	// Export the pending traces.
+	// 导出待处理的追踪。
+	if n.tracer != nil {
+		n.tracer.Close()
+	}
+	if n.recorder != nil {
+		if err := n.recorder.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// Unblock n.Wait.
 	// 解除 n.Wait 的阻塞。
 	close(n.stop)
@@ -443,6 +490,12 @@ func (n *Node) startRPC() error {
 	rpcConfig := rpcEndpointConfig{
 		batchItemLimit:         n.config.BatchRequestLimit,
 		batchResponseSizeLimit: n.config.BatchResponseMaxSize,
+		batchConcurrency:       n.config.BatchConcurrency,
+		responseSizeLimit:      n.config.RPCResponseMaxSize,
+		rateLimiter:            n.rateLimiter,
+		authorizer:             n.authorizer,
+		recorder:               n.recorder,
+		tracer:                 n.tracer,
 	}
 
 	initHttp := func(server *httpServer, port int) error {
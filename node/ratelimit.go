@@ -0,0 +1,440 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/time/rate"
+)
+
+// RPC 速率限制 (RPC Rate Limiting)
+//
+// 公共 RPC 端点需要防止单个客户端耗尽节点资源。速率限制器按客户端（IP 地址、API 密钥头或 JWT 声明）
+// 维护令牌桶：一个客户端全局桶，以及可选的按方法和按命名空间的桶。每次调用根据方法的成本权重消耗令牌
+// （例如 eth_getLogs 和 debug_trace* 比 eth_blockNumber 昂贵得多），并可额外设置按时间周期计算的成本配额。
+// 限制同时作用于 HTTP 和 WebSocket 上的每个调用（包括批处理中的每一项），超出限制的调用返回
+// EIP-1474 定义的 -32005 "limit exceeded" JSON-RPC 错误。
+
+const (
+	// errcodeLimitExceeded is the JSON-RPC error code returned for calls rejected
+	// by the rate limiter, as defined by EIP-1474.
+	// errcodeLimitExceeded 是速率限制器拒绝调用时返回的 JSON-RPC 错误代码，由 EIP-1474 定义。
+	errcodeLimitExceeded = -32005
+
+	// defaultRateLimitClients is the default number of clients tracked by the
+	// rate limiter. The least recently active clients are dropped beyond this.
+	// defaultRateLimitClients 是速率限制器默认跟踪的客户端数量。超出后丢弃最近最少活跃的客户端。
+	defaultRateLimitClients = 10000
+
+	// defaultQuotaPeriod is the window of the cost quota if none is configured.
+	// defaultQuotaPeriod 是未配置时成本配额的时间窗口。
+	defaultQuotaPeriod = time.Hour
+)
+
+// Aggregate metrics of the rate limiter, covering all clients. Clients which
+// are identified by a verified JWT claim or a configured API key additionally
+// have their own metrics under rateClientMetricsPrefix. Anonymous clients, i.e.
+// the ones identified by their IP address, are only counted in the aggregates,
+// as their number is unbounded.
+// 速率限制器的汇总指标，涵盖所有客户端。由已验证的 JWT 声明或已配置的 API 密钥标识的客户端
+// 还在 rateClientMetricsPrefix 下拥有自己的指标。匿名客户端（即按 IP 地址识别的客户端）
+// 只计入汇总指标，因为其数量没有上限。
+var (
+	rateAllowedMeter = metrics.NewRegisteredMeter("rpc/ratelimit/allowed", nil)
+	rateLimitedMeter = metrics.NewRegisteredMeter("rpc/ratelimit/limited", nil)
+	rateCostCounter  = metrics.NewRegisteredCounter("rpc/ratelimit/cost", nil)
+)
+
+// rateClientMetricsPrefix is the prefix of the metrics of identified clients.
+// rateClientMetricsPrefix 是已识别客户端指标的前缀。
+const rateClientMetricsPrefix = "rpc/ratelimit/client/"
+
+// defaultMethodCosts are the cost weights of RPC methods which are notably more
+// expensive to serve than a simple state lookup. All other methods cost 1.
+// defaultMethodCosts 是服务成本明显高于简单状态查询的 RPC 方法的成本权重。其他所有方法的成本为 1。
+var defaultMethodCosts = map[string]int{
+	"eth_call":                 2,
+	"eth_estimateGas":          2,
+	"eth_createAccessList":     5,
+	"eth_simulateV1":           10,
+	"eth_getProof":             5,
+	"eth_getLogs":              10,
+	"eth_getFilterLogs":        10,
+	"eth_getBlockReceipts":     5,
+	"debug_traceCall":          20,
+	"debug_traceTransaction":   20,
+	"debug_traceBlockByNumber": 50,
+	"debug_traceBlockByHash":   50,
+	"debug_traceChain":         100,
+}
+
+// RateLimit is the configuration of a single token bucket.
+// RateLimit 是单个令牌桶的配置。
+type RateLimit struct {
+	Rate  float64 // Number of cost units refilled per second // 每秒补充的成本单位数
+	Burst int     // Maximum number of cost units spendable at once // 一次可消耗的最大成本单位数
+}
+
+// RateLimitConfig is the configuration of the per-client rate limiting applied
+// to the HTTP and WebSocket RPC endpoints. Rate limiting is disabled unless a
+// client rate, a method or namespace limit, or a quota is configured.
+// RateLimitConfig 是应用于 HTTP 和 WebSocket RPC 端点的按客户端速率限制配置。
+// 除非配置了客户端速率、方法或命名空间限制或配额，否则速率限制被禁用。
+type RateLimitConfig struct {
+	// Rate and Burst limit the total cost units a single client may spend.
+	// Rate 和 Burst 限制单个客户端可以消耗的总成本单位。
+	Rate  float64 `toml:",omitempty"`
+	Burst int     `toml:",omitempty"`
+
+	// MethodLimits and NamespaceLimits are additional per-client buckets for
+	// individual methods (e.g. "eth_getLogs") or namespaces (e.g. "debug").
+	// MethodLimits 和 NamespaceLimits 是针对单个方法（例如 "eth_getLogs"）或命名空间（例如 "debug"）的额外按客户端令牌桶。
+	MethodLimits    map[string]RateLimit `toml:",omitempty"`
+	NamespaceLimits map[string]RateLimit `toml:",omitempty"`
+
+	// MethodCosts overrides the cost weights of methods. Unlisted methods use
+	// the built-in weights, or 1 if there is none.
+	// MethodCosts 覆盖方法的成本权重。未列出的方法使用内置权重，如果没有则为 1。
+	MethodCosts map[string]int `toml:",omitempty"`
+
+	// Quota is the total cost units a client may spend within QuotaPeriod
+	// (default one hour). Zero means unlimited.
+	// Quota 是客户端在 QuotaPeriod（默认一小时）内可以消耗的总成本单位。零表示不限制。
+	Quota       uint64        `toml:",omitempty"`
+	QuotaPeriod time.Duration `toml:",omitempty"`
+
+	// ClientHeader is the HTTP header carrying the API key identifying a client.
+	// Only the keys listed in ClientKeys are accepted, requests carrying any
+	// other key are identified by their remote IP address.
+	// ClientHeader 是携带标识客户端的 API 密钥的 HTTP 头。只接受 ClientKeys 中列出的密钥，
+	// 携带其他密钥的请求按其远程 IP 地址识别。
+	ClientHeader string   `toml:",omitempty"`
+	ClientKeys   []string `toml:",omitempty"`
+
+	// ClientClaim is the JWT claim identifying a client. The claim is only used
+	// if the endpoint verified the token (i.e. a JWT secret is set, or RPC
+	// authorization is enabled), otherwise the remote IP address is used.
+	// ClientClaim 是标识客户端的 JWT 声明。只有当端点验证了令牌（即设置了 JWT 密钥或启用了 RPC 授权）时才使用该声明，
+	// 否则使用远程 IP 地址。
+	ClientClaim string `toml:",omitempty"`
+
+	// MaxClients is the number of clients tracked at once (default 10000).
+	// MaxClients 是同时跟踪的客户端数量（默认 10000）。
+	MaxClients int `toml:",omitempty"`
+}
+
+// enabled returns whether the configuration imposes any limits.
+// enabled 返回配置是否施加了任何限制。
+func (c *RateLimitConfig) enabled() bool {
+	return c.Rate > 0 || len(c.MethodLimits) > 0 || len(c.NamespaceLimits) > 0 || c.Quota > 0
+}
+
+// rateLimitError is returned to clients whose calls were rejected.
+// rateLimitError 返回给调用被拒绝的客户端。
+type rateLimitError struct {
+	reason     string
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string  { return e.reason }
+func (e *rateLimitError) ErrorCode() int { return errcodeLimitExceeded }
+
+// ErrorData returns the number of seconds after which the call may succeed.
+// ErrorData 返回调用可能成功之前需要等待的秒数。
+func (e *rateLimitError) ErrorData() interface{} {
+	return map[string]interface{}{"retryAfter": math.Ceil(e.retryAfter.Seconds())}
+}
+
+// rateLimitClientKey is the context key of the client identifier.
+// rateLimitClientKey 是客户端标识符的上下文键。
+type rateLimitClientKey struct{}
+
+// rateLimiter enforces the per-client limits of a RateLimitConfig. A single
+// limiter is shared by the HTTP and WebSocket endpoints of a node, so that a
+// client's budget spans both transports.
+// rateLimiter 实施 RateLimitConfig 的按客户端限制。节点的 HTTP 和 WebSocket 端点共享同一个限制器，
+// 因此客户端的预算涵盖两种传输方式。
+type rateLimiter struct {
+	config RateLimitConfig
+	costs  map[string]int
+	keys   map[string]string // 接受的 API 密钥到其客户端标识符的映射
+
+	lock    sync.Mutex
+	clients lru.BasicLRU[string, *clientLimits] // 跟踪的客户端，按最近活跃排序
+}
+
+// newRateLimiter creates a rate limiter, or returns nil if the configuration
+// does not impose any limits.
+// newRateLimiter 创建速率限制器，如果配置没有施加任何限制，则返回 nil。
+func newRateLimiter(config RateLimitConfig) *rateLimiter {
+	if !config.enabled() {
+		return nil
+	}
+	if config.MaxClients <= 0 {
+		config.MaxClients = defaultRateLimitClients
+	}
+	if config.QuotaPeriod <= 0 {
+		config.QuotaPeriod = defaultQuotaPeriod
+	}
+	costs := make(map[string]int, len(defaultMethodCosts)+len(config.MethodCosts))
+	for method, cost := range defaultMethodCosts {
+		costs[method] = cost
+	}
+	for method, cost := range config.MethodCosts {
+		costs[method] = cost
+	}
+	// Don't leak the API keys into logs, clients are identified by the key hash
+	// 不要将 API 密钥泄露到日志中，客户端由密钥哈希标识
+	keys := make(map[string]string, len(config.ClientKeys))
+	for _, key := range config.ClientKeys {
+		hash := sha256.Sum256([]byte(key))
+		keys[key] = "key:" + hex.EncodeToString(hash[:8])
+	}
+	return &rateLimiter{
+		config:  config,
+		costs:   costs,
+		keys:    keys,
+		clients: lru.NewBasicLRU[string, *clientLimits](config.MaxClients),
+	}
+}
+
+// handler wraps an HTTP handler, tagging every request with the identifier of
+// the client which is later used by the call filter. For WebSocket connections
+// the identifier of the upgrade request applies to all calls.
+// handler 包装一个 HTTP 处理程序，为每个请求标记客户端标识符，稍后由调用过滤器使用。
+// 对于 WebSocket 连接，升级请求的标识符适用于所有调用。
+func (l *rateLimiter) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), rateLimitClientKey{}, l.identify(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// identify returns the identifier of the client issuing the request. The claim
+// of a verified JWT takes precedence over a configured API key, which in turn
+// takes precedence over the remote IP address. Unverified tokens and unknown
+// API keys are ignored, as otherwise any caller could mint new identities.
+// identify 返回发出请求的客户端标识符。已验证 JWT 的声明优先于已配置的 API 密钥，API 密钥又优先于远程 IP 地址。
+// 未验证的令牌和未知的 API 密钥会被忽略，否则任何调用方都可以伪造新的身份。
+func (l *rateLimiter) identify(r *http.Request) string {
+	if l.config.ClientClaim != "" {
+		if claims, ok := r.Context().Value(jwtClaimsKey{}).(jwt.MapClaims); ok {
+			if id, _ := claims[l.config.ClientClaim].(string); id != "" {
+				return "jwt:" + id
+			}
+		}
+	}
+	if l.config.ClientHeader != "" {
+		if id, ok := l.keys[r.Header.Get(l.config.ClientHeader)]; ok {
+			return id
+		}
+	}
+	return "ip:" + remoteIP(r.RemoteAddr)
+}
+
+// remoteIP strips the port from a remote address.
+// remoteIP 从远程地址中去除端口。
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// filter is the rpc.CallFilter enforcing the limits of the calling client.
+// filter 是实施调用客户端限制的 rpc.CallFilter。
+func (l *rateLimiter) filter(ctx context.Context, method string) error {
+	id, ok := ctx.Value(rateLimitClientKey{}).(string)
+	if !ok {
+		id = "ip:" + remoteIP(rpc.PeerInfoFromContext(ctx).RemoteAddr)
+	}
+	cost := 1
+	if c, ok := l.costs[method]; ok {
+		cost = c
+	}
+	return l.client(id).allow(method, cost, time.Now())
+}
+
+// client retrieves the limits of a client, creating them if it is not yet
+// tracked.
+// client 检索客户端的限制，如果尚未跟踪则创建。
+func (l *rateLimiter) client(id string) *clientLimits {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if client, ok := l.clients.Get(id); ok {
+		return client
+	}
+	client := newClientLimits(id, &l.config)
+	l.clients.Add(id, client)
+	return client
+}
+
+// clientLimits tracks the token buckets and quota usage of a single client.
+// clientLimits 跟踪单个客户端的令牌桶和配额使用情况。
+type clientLimits struct {
+	config *RateLimitConfig
+
+	lock       sync.Mutex
+	global     *rate.Limiter            // 客户端全局令牌桶，未配置时为 nil
+	methods    map[string]*rate.Limiter // 按方法的令牌桶，按需创建
+	namespaces map[string]*rate.Limiter // 按命名空间的令牌桶，按需创建
+	spent      uint64                   // 当前配额周期内消耗的成本单位
+	period     time.Time                // 当前配额周期的开始时间
+
+	allowed *metrics.Meter   // 允许的调用，匿名客户端为 nil
+	limited *metrics.Meter   // 被拒绝的调用，匿名客户端为 nil
+	cost    *metrics.Counter // 消耗的成本单位，匿名客户端为 nil
+}
+
+func newClientLimits(id string, config *RateLimitConfig) *clientLimits {
+	client := &clientLimits{
+		config:     config,
+		methods:    make(map[string]*rate.Limiter),
+		namespaces: make(map[string]*rate.Limiter),
+	}
+	if config.Rate > 0 {
+		client.global = newBucket(RateLimit{Rate: config.Rate, Burst: config.Burst})
+	}
+	// Identified clients come from a bounded set (configured keys and claims of
+	// tokens signed by the operator), so their metrics are kept registered even
+	// if the client is dropped from the tracked set.
+	// 已识别的客户端来自一个有限集合（已配置的密钥和运营者签名令牌的声明），因此即使客户端
+	// 从跟踪集合中删除，其指标也保持注册。
+	if !strings.HasPrefix(id, "ip:") {
+		prefix := rateClientMetricsPrefix + strings.NewReplacer("/", "_", ":", "_").Replace(id)
+		client.allowed = metrics.GetOrRegisterMeter(prefix+"/allowed", nil)
+		client.limited = metrics.GetOrRegisterMeter(prefix+"/limited", nil)
+		client.cost = metrics.GetOrRegisterCounter(prefix+"/cost", nil)
+	}
+	return client
+}
+
+// newBucket creates a token bucket, making sure it can hold at least a single
+// token.
+// newBucket 创建一个令牌桶，确保它至少可以容纳一个令牌。
+func newBucket(limit RateLimit) *rate.Limiter {
+	burst := limit.Burst
+	if burst <= 0 {
+		burst = max(int(math.Ceil(limit.Rate)), 1)
+	}
+	return rate.NewLimiter(rate.Limit(limit.Rate), burst)
+}
+
+// allow charges the cost of a method call to all the applicable buckets and
+// the quota, returning an error if any of them is exhausted. A rejected call
+// is not charged at all.
+// allow 将方法调用的成本计入所有适用的令牌桶和配额，如果其中任何一个耗尽则返回错误。被拒绝的调用完全不计费。
+func (c *clientLimits) allow(method string, cost int, now time.Time) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// Check the quota first, it's the cheapest and doesn't need rollback
+	// 首先检查配额，它最简单且不需要回滚
+	if c.config.Quota > 0 {
+		if now.Sub(c.period) >= c.config.QuotaPeriod {
+			c.period, c.spent = now, 0
+		}
+		if c.spent+uint64(cost) > c.config.Quota {
+			c.markLimited()
+			return &rateLimitError{
+				reason:     "quota exceeded",
+				retryAfter: c.period.Add(c.config.QuotaPeriod).Sub(now),
+			}
+		}
+	}
+	// Reserve the tokens from every bucket, rolling back if any is exhausted
+	// 从每个令牌桶中预留令牌，如果任何一个耗尽则回滚
+	var reservations []*rate.Reservation
+	for _, bucket := range c.buckets(method) {
+		// Costs above the bucket size could never be served, charge a full bucket
+		// 超过桶容量的成本永远无法被满足，按满桶计费
+		r := bucket.ReserveN(now, min(cost, bucket.Burst()))
+		if !r.OK() || r.DelayFrom(now) > 0 {
+			var delay time.Duration
+			if r.OK() {
+				delay = r.DelayFrom(now)
+			}
+			r.CancelAt(now)
+			for _, prev := range reservations {
+				prev.CancelAt(now)
+			}
+			c.markLimited()
+			return &rateLimitError{reason: "rate limit exceeded", retryAfter: delay}
+		}
+		reservations = append(reservations, r)
+	}
+	c.spent += uint64(cost)
+	rateAllowedMeter.Mark(1)
+	rateCostCounter.Inc(int64(cost))
+	if c.allowed != nil {
+		c.allowed.Mark(1)
+		c.cost.Inc(int64(cost))
+	}
+	return nil
+}
+
+// markLimited counts a rejected call in the aggregate and client metrics.
+// markLimited 在汇总指标和客户端指标中记录一次被拒绝的调用。
+func (c *clientLimits) markLimited() {
+	rateLimitedMeter.Mark(1)
+	if c.limited != nil {
+		c.limited.Mark(1)
+	}
+}
+
+// buckets returns all the token buckets applicable to a method call, lazily
+// creating the method and namespace ones.
+// buckets 返回适用于方法调用的所有令牌桶，按需创建方法和命名空间的令牌桶。
+func (c *clientLimits) buckets(method string) []*rate.Limiter {
+	var buckets []*rate.Limiter
+	if c.global != nil {
+		buckets = append(buckets, c.global)
+	}
+	if limit, ok := c.config.MethodLimits[method]; ok {
+		bucket := c.methods[method]
+		if bucket == nil {
+			bucket = newBucket(limit)
+			c.methods[method] = bucket
+		}
+		buckets = append(buckets, bucket)
+	}
+	namespace, _, _ := strings.Cut(method, "_")
+	if limit, ok := c.config.NamespaceLimits[namespace]; ok {
+		bucket := c.namespaces[namespace]
+		if bucket == nil {
+			bucket = newBucket(limit)
+			c.namespaces[namespace] = bucket
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestRateLimitDisabled(t *testing.T) {
+	if l := newRateLimiter(RateLimitConfig{ClientHeader: "X-Api-Key", ClientClaim: "sub"}); l != nil {
+		t.Fatal("rate limiter created without limits")
+	}
+}
+
+func TestRateLimitBuckets(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{
+		Rate:            10,
+		Burst:           10,
+		NamespaceLimits: map[string]RateLimit{"debug": {Rate: 1, Burst: 50}},
+		MethodCosts:     map[string]int{"eth_call": 4},
+	})
+	var (
+		client = l.client("ip:127.0.0.1")
+		now    = time.Now()
+	)
+	// Costs are charged from the client bucket
+	for i := 0; i < 2; i++ {
+		if err := client.allow("eth_call", l.costs["eth_call"], now); err != nil {
+			t.Fatalf("call %d rejected: %v", i, err)
+		}
+	}
+	err := client.allow("eth_call", l.costs["eth_call"], now)
+	var limitErr *rateLimitError
+	if !errors.As(err, &limitErr) || limitErr.ErrorCode() != errcodeLimitExceeded {
+		t.Fatalf("wrong error for exhausted bucket: %v", err)
+	}
+	if limitErr.retryAfter <= 0 {
+		t.Errorf("missing retry delay")
+	}
+	// The remaining tokens are still spendable, the rejected call wasn't charged
+	if err := client.allow("eth_blockNumber", 1, now); err != nil {
+		t.Fatalf("cheap call rejected: %v", err)
+	}
+	// Tokens are refilled over time
+	now = now.Add(time.Second)
+	if err := client.allow("eth_call", 4, now); err != nil {
+		t.Fatalf("call rejected after refill: %v", err)
+	}
+	// Calls costing more than a bucket holds are charged a full bucket, from
+	// both the namespace and the client bucket
+	now = now.Add(time.Second)
+	if err := client.allow("debug_traceCall", 60, now); err != nil {
+		t.Fatalf("oversized call rejected: %v", err)
+	}
+	if err := client.allow("debug_traceCall", 1, now); err == nil {
+		t.Fatal("call accepted from exhausted namespace bucket")
+	}
+	if err := client.allow("eth_blockNumber", 10, now); err == nil {
+		t.Fatal("client bucket not charged by oversized call")
+	}
+	// Other clients have their own buckets
+	if err := l.client("ip:127.0.0.2").allow("eth_call", 4, now); err != nil {
+		t.Fatalf("call of other client rejected: %v", err)
+	}
+}
+
+func TestRateLimitQuota(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{Quota: 100, QuotaPeriod: time.Minute})
+	var (
+		client = l.client("ip:127.0.0.1")
+		start  = time.Now()
+	)
+	if err := client.allow("debug_traceBlockByNumber", 50, start); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.allow("debug_traceBlockByNumber", 50, start.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	err := client.allow("eth_blockNumber", 1, start.Add(2*time.Second))
+	var limitErr *rateLimitError
+	if !errors.As(err, &limitErr) || limitErr.reason != "quota exceeded" {
+		t.Fatalf("wrong error for exhausted quota: %v", err)
+	}
+	if limitErr.retryAfter != time.Minute-2*time.Second {
+		t.Errorf("wrong retry delay %v", limitErr.retryAfter)
+	}
+	// The quota is reset once the period is over
+	if err := client.allow("eth_blockNumber", 1, start.Add(time.Minute)); err != nil {
+		t.Fatalf("call rejected in new quota period: %v", err)
+	}
+}
+
+func TestRateLimitMaxClients(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{Quota: 1, MaxClients: 2})
+	now := time.Now()
+	l.client("ip:1").allow("eth_blockNumber", 1, now)
+	l.client("ip:2").allow("eth_blockNumber", 1, now)
+	l.client("ip:1") // mark as recently active
+	l.client("ip:3")
+
+	if l.clients.Len() != 2 {
+		t.Fatalf("wrong number of tracked clients %d", l.clients.Len())
+	}
+	if l.client("ip:1").allow("eth_blockNumber", 1, now) == nil {
+		t.Error("quota of active client was reset")
+	}
+}
+
+func TestRateLimitClientMetrics(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{Quota: 2})
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		l.client("jwt:metrics/alice").allow("eth_blockNumber", 1, now)
+		l.client("ip:192.0.2.99").allow("eth_blockNumber", 1, now)
+	}
+	prefix := rateClientMetricsPrefix + "jwt_metrics_alice"
+	if m, ok := metrics.DefaultRegistry.Get(prefix + "/allowed").(*metrics.Meter); !ok || m.Snapshot().Count() != 2 {
+		t.Errorf("wrong allowed calls metric of identified client: %v", m)
+	}
+	if m, ok := metrics.DefaultRegistry.Get(prefix + "/limited").(*metrics.Meter); !ok || m.Snapshot().Count() != 1 {
+		t.Errorf("wrong limited calls metric of identified client: %v", m)
+	}
+	if c, ok := metrics.DefaultRegistry.Get(prefix + "/cost").(*metrics.Counter); !ok || c.Snapshot().Count() != 2 {
+		t.Errorf("wrong cost metric of identified client: %v", c)
+	}
+	// Anonymous clients are only counted in the aggregates
+	metrics.DefaultRegistry.Each(func(name string, _ interface{}) {
+		if strings.HasPrefix(name, rateClientMetricsPrefix+"ip") {
+			t.Errorf("metric registered for anonymous client: %s", name)
+		}
+	})
+}
+
+func TestRateLimitIdentify(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{
+		Rate:         1,
+		ClientHeader: "X-Api-Key",
+		ClientKeys:   []string{"secret-key"},
+		ClientClaim:  "sub",
+	})
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice"}).SignedString([]byte("secret"))
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		claims jwt.MapClaims // claims of a verified token
+		want   string
+	}{
+		{
+			name: "remote IP",
+			want: "ip:192.0.2.1",
+		},
+		{
+			name:   "configured API key",
+			header: map[string]string{"X-Api-Key": "secret-key"},
+			want:   l.keys["secret-key"],
+		},
+		{
+			name:   "unknown API key",
+			header: map[string]string{"X-Api-Key": "made-up-key"},
+			want:   "ip:192.0.2.1",
+		},
+		{
+			name:   "unverified token",
+			header: map[string]string{"Authorization": "Bearer " + token},
+			want:   "ip:192.0.2.1",
+		},
+		{
+			name:   "verified token",
+			header: map[string]string{"Authorization": "Bearer " + token, "X-Api-Key": "secret-key"},
+			claims: jwt.MapClaims{"sub": "alice"},
+			want:   "jwt:alice",
+		},
+		{
+			name:   "verified token without claim",
+			header: map[string]string{"X-Api-Key": "secret-key"},
+			claims: jwt.MapClaims{"iat": 1},
+			want:   l.keys["secret-key"],
+		},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		for k, v := range tt.header {
+			r.Header.Set(k, v)
+		}
+		if tt.claims != nil {
+			r = withVerifiedClaims(r, tt.claims)
+		}
+		if id := l.identify(r); id != tt.want {
+			t.Errorf("%s: wrong client %q, want %q", tt.name, id, tt.want)
+		}
+	}
+	if id := l.keys["secret-key"]; !strings.HasPrefix(id, "key:") || strings.Contains(id, "secret") {
+		t.Errorf("API key leaked into client identifier %q", id)
+	}
+}
+
+// TestRateLimitJWTHandler checks that the claims of tokens verified by the JWT
+// handler are available to the rate limiter.
+func TestRateLimitJWTHandler(t *testing.T) {
+	var (
+		secret = []byte("0123456789abcdef0123456789abcdef")
+		l      = newRateLimiter(RateLimitConfig{Rate: 1, ClientClaim: "id"})
+		id     string
+	)
+	handler := newJWTHandler(secret, l.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ = r.Context().Value(rateLimitClientKey{}).(string)
+	})))
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iat": time.Now().Unix(),
+		"id":  "node-1",
+	}).SignedString(secret)
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if id != "jwt:node-1" {
+		t.Fatalf("wrong client %q", id)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import "errors"
+
+// 配置热重载 (Configuration Hot Reload)
+//
+// 节点本身不知道配置文件的格式，重新读取配置的工作由创建节点的程序（例如 geth）通过 ConfigReloader 完成。
+// 节点负责序列化重载请求，并通过 admin_reloadConfig 将其公开；重载器比较新旧配置，
+// 应用可以在运行时安全更改的字段，并报告哪些更改需要重启才能生效。
+
+// errReloadUnsupported is returned if no config reloader was installed.
+// errReloadUnsupported 在未安装配置重载器时返回。
+var errReloadUnsupported = errors.New("config reload not supported")
+
+// ConfigReloadReport describes the outcome of a configuration reload. Changes are
+// identified by the path of the changed field, e.g. "Node.HTTPCors".
+// ConfigReloadReport 描述配置重载的结果。更改由已更改字段的路径标识，例如 "Node.HTTPCors"。
+type ConfigReloadReport struct {
+	Applied         []string `json:"applied"`         // Changes applied to the running node // 已应用到运行节点的更改
+	RestartRequired []string `json:"restartRequired"` // Changes only taking effect after a restart // 仅在重启后生效的更改
+}
+
+// ConfigReloader re-reads the configuration and applies it to the running node.
+// ConfigReloader 重新读取配置并将其应用到运行中的节点。
+type ConfigReloader func() (*ConfigReloadReport, error)
+
+// SetConfigReloader installs the function reloading the node configuration, which
+// is invoked by ReloadConfig.
+// SetConfigReloader 安装重新加载节点配置的函数，该函数由 ReloadConfig 调用。
+func (n *Node) SetConfigReloader(reloader ConfigReloader) {
+	n.reloadLock.Lock()
+	defer n.reloadLock.Unlock()
+
+	n.reloader = reloader
+}
+
+// ReloadConfig re-reads the configuration and applies the changes which are safe
+// to make at runtime. Concurrent reloads are serialized.
+// ReloadConfig 重新读取配置并应用可以在运行时安全进行的更改。并发的重载会被序列化。
+func (n *Node) ReloadConfig() (*ConfigReloadReport, error) {
+	n.reloadLock.Lock()
+	defer n.reloadLock.Unlock()
+
+	if n.reloader == nil {
+		return nil, errReloadUnsupported
+	}
+	return n.reloader()
+}
+
+// SetHTTPFilters updates the CORS origins and virtual hosts accepted by the HTTP
+// RPC endpoint of the running node.
+// SetHTTPFilters 更新运行中节点的 HTTP RPC 端点接受的 CORS 来源和虚拟主机。
+func (n *Node) SetHTTPFilters(cors, vhosts []string) error {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.state != runningState {
+		return ErrNodeStopped
+	}
+	n.config.HTTPCors, n.config.HTTPVirtualHosts = cors, vhosts
+	n.http.setHTTPFilters(cors, vhosts)
+	return nil
+}
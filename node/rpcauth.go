@@ -0,0 +1,314 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// RPC 命名空间授权 (RPC Namespace Authorization)
+//
+// 同一个 HTTP/WS 监听器可以同时服务公共 API（例如 eth）和受保护的 API（例如 admin、debug）。
+// 不携带令牌的请求只能调用公开的命名空间和方法；携带有效 JWT 的请求还可以调用令牌的 "apis" 声明中列出的
+// 命名空间（例如 "debug"）或方法（例如 "admin_peers"），"*" 表示允许所有 API。
+// 令牌使用共享密钥（HS256/384/512）或公钥（RSA、ECDSA 或 Ed25519）验证。令牌必须带有过期时间（exp），
+// 且距今不超过 rpcTokenMaxLifetime。WebSocket 连接在令牌过期后只能调用公开的 API。
+// 携带无效令牌的请求会被拒绝（HTTP 401），而未授权的调用返回 JSON-RPC 错误。
+
+const (
+	// errcodeUnauthorized is the JSON-RPC error code returned for calls which are
+	// not granted to the caller.
+	// errcodeUnauthorized 是调用方未被授权的调用返回的 JSON-RPC 错误代码。
+	errcodeUnauthorized = -32006
+
+	// rpcAuthClaim is the JWT claim listing the namespaces and methods granted
+	// by a token.
+	// rpcAuthClaim 是列出令牌授予的命名空间和方法的 JWT 声明。
+	rpcAuthClaim = "apis"
+
+	// rpcTokenMaxLifetime is the maximum remaining lifetime of accepted tokens,
+	// i.e. how far in the future their expiry claim may be.
+	// rpcTokenMaxLifetime 是被接受令牌的最大剩余有效期，即其过期声明最多可以在未来多远。
+	rpcTokenMaxLifetime = time.Hour
+)
+
+// RPCAuthConfig is the configuration of the JWT based authorization of API
+// namespaces and methods on the HTTP and WebSocket RPC endpoints. Authorization
+// is disabled unless a secret or a public key is configured.
+// RPCAuthConfig 是 HTTP 和 WebSocket RPC 端点上基于 JWT 的 API 命名空间和方法授权的配置。
+// 除非配置了密钥或公钥，否则授权被禁用。
+type RPCAuthConfig struct {
+	// Secret is the path of a file holding the hex-encoded shared secret of
+	// HMAC signed tokens.
+	// Secret 是保存 HMAC 签名令牌的十六进制编码共享密钥的文件路径。
+	Secret string `toml:",omitempty"`
+
+	// PublicKey is the path of a PEM file holding the RSA, ECDSA or Ed25519 public
+	// key verifying tokens.
+	// PublicKey 是保存用于验证令牌的 RSA、ECDSA 或 Ed25519 公钥的 PEM 文件路径。
+	PublicKey string `toml:",omitempty"`
+
+	// Public lists the namespaces (e.g. "eth") and methods (e.g. "debug_traceTransaction")
+	// callable without a token. All other APIs of the endpoints require a token,
+	// including the "rpc" namespace describing the available methods.
+	// Public 列出无需令牌即可调用的命名空间（例如 "eth"）和方法（例如 "debug_traceTransaction"）。
+	// 端点的所有其他 API 都需要令牌，包括描述可用方法的 "rpc" 命名空间。
+	Public []string `toml:",omitempty"`
+}
+
+// enabled returns whether tokens can be verified, i.e. authorization is on.
+// enabled 返回是否可以验证令牌，即授权是否开启。
+func (c *RPCAuthConfig) enabled() bool {
+	return c.Secret != "" || c.PublicKey != ""
+}
+
+// unauthorizedError is returned for calls which aren't granted to the caller.
+// unauthorizedError 在调用未授予调用方时返回。
+type unauthorizedError struct {
+	method  string
+	expired bool // 调用方的令牌已过期
+}
+
+func (e *unauthorizedError) Error() string {
+	if e.expired {
+		return fmt.Sprintf("unauthorized: method %s requires a token granting access, the token has expired", e.method)
+	}
+	return fmt.Sprintf("unauthorized: method %s requires a token granting access", e.method)
+}
+func (e *unauthorizedError) ErrorCode() int { return errcodeUnauthorized }
+
+// rpcGrantKey is the context key of the rpcAuthorization of a request.
+// rpcGrantKey 是请求的 rpcAuthorization 的上下文键。
+type rpcGrantKey struct{}
+
+// rpcAuthorization is the set of APIs granted to a request, along with the expiry
+// of the token granting them. The expiry is zero for requests without a token.
+// rpcAuthorization 是授予请求的 API 集合，以及授予它们的令牌的过期时间。
+// 没有令牌的请求的过期时间为零值。
+type rpcAuthorization struct {
+	grant   rpcGrant
+	expires time.Time
+}
+
+// rpcGrant is a set of namespaces and methods.
+// rpcGrant 是命名空间和方法的集合。
+type rpcGrant map[string]bool
+
+// allows reports whether the method is in the set, either directly, via its
+// namespace or via the wildcard.
+// allows 报告方法是否在集合中，无论是直接包含、通过其命名空间还是通过通配符。
+func (g rpcGrant) allows(method string) bool {
+	if g["*"] || g[method] {
+		return true
+	}
+	namespace, _, ok := strings.Cut(method, "_")
+	return ok && g[namespace]
+}
+
+// rpcAuthorizer enforces an RPCAuthConfig. It is shared by the HTTP and WebSocket
+// endpoints of a node.
+// rpcAuthorizer 实施 RPCAuthConfig。它由节点的 HTTP 和 WebSocket 端点共享。
+type rpcAuthorizer struct {
+	public  rpcGrant
+	key     interface{} // 验证令牌的 HMAC 密钥或公钥
+	methods []string    // 允许的签名算法
+}
+
+// newRPCAuthorizer creates an authorizer, or returns nil if the configuration
+// doesn't enable authorization.
+// newRPCAuthorizer 创建授权器，如果配置未启用授权则返回 nil。
+func newRPCAuthorizer(config RPCAuthConfig) (*rpcAuthorizer, error) {
+	if !config.enabled() {
+		return nil, nil
+	}
+	if config.Secret != "" && config.PublicKey != "" {
+		return nil, errors.New("RPC authorization can't use both a secret and a public key")
+	}
+	a := &rpcAuthorizer{public: make(rpcGrant)}
+	for _, api := range config.Public {
+		a.public[api] = true
+	}
+
+	if config.Secret != "" {
+		data, err := os.ReadFile(config.Secret)
+		if err != nil {
+			return nil, err
+		}
+		secret := common.FromHex(strings.TrimSpace(string(data)))
+		if len(secret) == 0 {
+			return nil, fmt.Errorf("invalid RPC authorization secret in %s", config.Secret)
+		}
+		a.key, a.methods = secret, []string{"HS256", "HS384", "HS512"}
+		return a, nil
+	}
+	data, err := os.ReadFile(config.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if a.key, err = parsePublicKeyPEM(data); err != nil {
+		return nil, fmt.Errorf("invalid RPC authorization public key in %s: %v", config.PublicKey, err)
+	}
+	switch a.key.(type) {
+	case *rsa.PublicKey:
+		a.methods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+	case *ecdsa.PublicKey:
+		a.methods = []string{"ES256", "ES384", "ES512"}
+	case ed25519.PublicKey:
+		a.methods = []string{"EdDSA"}
+	}
+	return a, nil
+}
+
+// parsePublicKeyPEM parses a PEM encoded RSA, ECDSA or Ed25519 public key.
+// parsePublicKeyPEM 解析 PEM 编码的 RSA、ECDSA 或 Ed25519 公钥。
+func parsePublicKeyPEM(data []byte) (interface{}, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(data); err == nil {
+		return key, nil
+	}
+	key, err := jwt.ParseEdPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, errors.New("not an RSA, ECDSA or Ed25519 public key")
+	}
+	return key, nil
+}
+
+// handler wraps an HTTP handler, verifying the bearer token of every request and
+// tagging it with the granted APIs, which are later checked by the call filter.
+// Requests without a token are granted the public APIs only. For WebSocket
+// connections the token of the upgrade request applies to all calls until it
+// expires, later calls are granted the public APIs only.
+// handler 包装一个 HTTP 处理程序，验证每个请求的 bearer 令牌并为其标记授予的 API，稍后由调用过滤器检查。
+// 没有令牌的请求只被授予公共 API。对于 WebSocket 连接，升级请求的令牌在过期前适用于所有调用，
+// 之后的调用只被授予公共 API。
+func (a *rpcAuthorizer) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := &rpcAuthorization{grant: a.public}
+		if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+			var (
+				claims jwt.MapClaims
+				err    error
+			)
+			if auth, claims, err = a.verify(strings.TrimPrefix(header, "Bearer ")); err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			r = withVerifiedClaims(r, claims)
+		}
+		ctx := context.WithValue(r.Context(), rpcGrantKey{}, auth)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// verify checks the token and returns the APIs it grants, including the public ones,
+// along with the claims of the token. Tokens must expire within rpcTokenMaxLifetime.
+// The not-before claim is enforced if present.
+// verify 检查令牌并返回它授予的 API（包括公共 API）以及令牌的声明。令牌必须在
+// rpcTokenMaxLifetime 内过期。如果存在生效时间声明，则会强制执行。
+func (a *rpcAuthorizer) verify(token string) (*rpcAuthorization, jwt.MapClaims, error) {
+	claims := make(jwt.MapClaims)
+	parsed, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return a.key, nil
+	}, jwt.WithValidMethods(a.methods))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !parsed.Valid {
+		return nil, nil, errors.New("invalid token")
+	}
+	// The parser already rejected expired tokens
+	// 解析器已经拒绝了过期的令牌
+	exp, ok := claimTime(claims["exp"])
+	if !ok {
+		return nil, nil, errors.New("token has no expiry claim")
+	}
+	if time.Until(exp) > rpcTokenMaxLifetime {
+		return nil, nil, fmt.Errorf("token expires more than %v in the future", rpcTokenMaxLifetime)
+	}
+	grant := make(rpcGrant, len(a.public))
+	for api := range a.public {
+		grant[api] = true
+	}
+	switch apis := claims[rpcAuthClaim].(type) {
+	case nil:
+	case string:
+		for _, api := range strings.Split(apis, ",") {
+			grant[strings.TrimSpace(api)] = true
+		}
+	case []interface{}:
+		for _, api := range apis {
+			name, ok := api.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("invalid %q claim", rpcAuthClaim)
+			}
+			grant[name] = true
+		}
+	default:
+		return nil, nil, fmt.Errorf("invalid %q claim", rpcAuthClaim)
+	}
+	return &rpcAuthorization{grant: grant, expires: exp}, claims, nil
+}
+
+// claimTime decodes a numeric date claim.
+// claimTime 解码数值日期声明。
+func claimTime(claim interface{}) (time.Time, bool) {
+	switch v := claim.(type) {
+	case float64:
+		return time.Unix(int64(v), 0), true
+	case json.Number:
+		n, err := v.Int64()
+		return time.Unix(n, 0), err == nil
+	}
+	return time.Time{}, false
+}
+
+// filter is the rpc.CallFilter rejecting calls which aren't granted to the caller.
+// Once the token of a WebSocket connection expired, only the public APIs remain
+// callable.
+// filter 是拒绝未授予调用方的调用的 rpc.CallFilter。一旦 WebSocket 连接的令牌过期，
+// 只有公共 API 仍可调用。
+func (a *rpcAuthorizer) filter(ctx context.Context, method string) error {
+	auth, ok := ctx.Value(rpcGrantKey{}).(*rpcAuthorization)
+	if !ok {
+		auth = &rpcAuthorization{grant: a.public}
+	}
+	if auth.grant.allows(method) {
+		if auth.expires.IsZero() || time.Now().Before(auth.expires) {
+			return nil
+		}
+		if a.public.allows(method) {
+			return nil
+		}
+		return &unauthorizedError{method: method, expired: true}
+	}
+	return &unauthorizedError{method: method}
+}
@@ -0,0 +1,294 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var testRPCAuthSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestAuthorizer(t *testing.T, public ...string) *rpcAuthorizer {
+	file := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(file, []byte(hex.EncodeToString(testRPCAuthSecret)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := newRPCAuthorizer(RPCAuthConfig{Secret: file, Public: public})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func signTestToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestRPCAuthDisabled(t *testing.T) {
+	if a, err := newRPCAuthorizer(RPCAuthConfig{Public: []string{"eth"}}); a != nil || err != nil {
+		t.Fatalf("authorizer created without key: %v", err)
+	}
+	if _, err := newRPCAuthorizer(RPCAuthConfig{Secret: "a", PublicKey: "b"}); err == nil {
+		t.Fatal("authorizer created with both a secret and a public key")
+	}
+}
+
+func TestRPCAuthVerify(t *testing.T) {
+	var (
+		a   = newTestAuthorizer(t, "eth")
+		now = time.Now()
+	)
+	tests := []struct {
+		name   string
+		token  string
+		grants []string
+		denies []string
+		fail   bool
+	}{
+		{
+			name:   "expiring",
+			token:  signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"exp": now.Add(time.Minute).Unix(), "apis": "debug, admin_peers"}),
+			grants: []string{"eth_call", "debug_traceTransaction", "admin_peers"},
+			denies: []string{"admin_addPeer", "rpc_modules"},
+		},
+		{
+			name:   "wildcard",
+			token:  signTestToken(t, jwt.SigningMethodHS384, testRPCAuthSecret, jwt.MapClaims{"exp": now.Add(rpcTokenMaxLifetime - time.Minute).Unix(), "apis": []string{"*"}}),
+			grants: []string{"eth_call", "admin_addPeer", "rpc_modules"},
+		},
+		{
+			name:   "no apis claim",
+			token:  signTestToken(t, jwt.SigningMethodHS512, testRPCAuthSecret, jwt.MapClaims{"exp": now.Add(time.Minute).Unix()}),
+			grants: []string{"eth_call"},
+			denies: []string{"debug_traceTransaction"},
+		},
+		{
+			name:  "expired",
+			token: signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"exp": now.Add(-time.Minute).Unix()}),
+			fail:  true,
+		},
+		{
+			name:  "not yet valid",
+			token: signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"exp": now.Add(time.Hour).Unix(), "nbf": now.Add(time.Minute).Unix()}),
+			fail:  true,
+		},
+		{
+			name:  "no expiry",
+			token: signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"apis": "debug"}),
+			fail:  true,
+		},
+		{
+			name:  "recently issued without expiry",
+			token: signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"iat": now.Add(-time.Minute).Unix(), "apis": "debug"}),
+			fail:  true,
+		},
+		{
+			name:  "expiring too far in the future",
+			token: signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"exp": now.Add(rpcTokenMaxLifetime + time.Minute).Unix(), "apis": "debug"}),
+			fail:  true,
+		},
+		{
+			name:  "wrong secret",
+			token: signTestToken(t, jwt.SigningMethodHS256, []byte("wrong"), jwt.MapClaims{"exp": now.Add(time.Minute).Unix()}),
+			fail:  true,
+		},
+		{
+			name:  "unsigned",
+			token: signTestToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, jwt.MapClaims{"exp": now.Add(time.Minute).Unix()}),
+			fail:  true,
+		},
+		{
+			name:  "invalid apis claim",
+			token: signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"exp": now.Add(time.Minute).Unix(), "apis": 1}),
+			fail:  true,
+		},
+		{
+			name:  "invalid apis element",
+			token: signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"exp": now.Add(time.Minute).Unix(), "apis": []interface{}{"eth", 1}}),
+			fail:  true,
+		},
+	}
+	for _, test := range tests {
+		auth, _, err := a.verify(test.token)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%s: token accepted", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: token rejected: %v", test.name, err)
+			continue
+		}
+		grant := auth.grant
+		for _, method := range test.grants {
+			if !grant.allows(method) {
+				t.Errorf("%s: %s not granted", test.name, method)
+			}
+		}
+		for _, method := range test.denies {
+			if grant.allows(method) {
+				t.Errorf("%s: %s granted", test.name, method)
+			}
+		}
+	}
+}
+
+func TestRPCAuthPublicKey(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "key.pem")
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := newRPCAuthorizer(RPCAuthConfig{PublicKey: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := signTestToken(t, jwt.SigningMethodEdDSA, priv, jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix(), "apis": "debug"})
+	auth, _, err := a.verify(token)
+	if err != nil {
+		t.Fatalf("token rejected: %v", err)
+	}
+	if !auth.grant.allows("debug_traceTransaction") {
+		t.Error("granted namespace not allowed")
+	}
+	// HMAC tokens aren't accepted by a public key authorizer
+	token = signTestToken(t, jwt.SigningMethodHS256, []byte(pub), jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix()})
+	if _, _, err := a.verify(token); err == nil {
+		t.Error("HMAC token accepted by public key authorizer")
+	}
+}
+
+func TestRPCGrantAllows(t *testing.T) {
+	grant := rpcGrant{"eth": true, "debug_traceTransaction": true}
+	for method, want := range map[string]bool{
+		"eth_call":               true,
+		"debug_traceTransaction": true,
+		"debug_traceCall":        false,
+		"ethx_call":              false,
+		"rpc_modules":            false,
+	} {
+		if got := grant.allows(method); got != want {
+			t.Errorf("allows(%q) = %v, want %v", method, got, want)
+		}
+	}
+	if !(rpcGrant{"*": true}).allows("admin_addPeer") {
+		t.Error("wildcard grant doesn't allow method")
+	}
+}
+
+func TestRPCAuthFilter(t *testing.T) {
+	a := newTestAuthorizer(t, "eth", "debug_traceTransaction")
+
+	// Calls without a grant in the context get the public APIs, the rpc
+	// namespace isn't public unless configured
+	ctx := context.Background()
+	if err := a.filter(ctx, "eth_call"); err != nil {
+		t.Errorf("public namespace rejected: %v", err)
+	}
+	if err := a.filter(ctx, "debug_traceTransaction"); err != nil {
+		t.Errorf("public method rejected: %v", err)
+	}
+	var authErr *unauthorizedError
+	if err := a.filter(ctx, "rpc_discover"); !errors.As(err, &authErr) || authErr.ErrorCode() != errcodeUnauthorized {
+		t.Errorf("wrong error for rpc namespace: %v", err)
+	}
+	ctx = context.WithValue(ctx, rpcGrantKey{}, &rpcAuthorization{grant: rpcGrant{"admin": true, "eth": true}, expires: time.Now().Add(time.Minute)})
+	if err := a.filter(ctx, "admin_peers"); err != nil {
+		t.Errorf("granted namespace rejected: %v", err)
+	}
+	if err := a.filter(ctx, "net_version"); err == nil {
+		t.Error("call outside the grant accepted")
+	}
+	// Once the token of a long-lived connection expired, only the public APIs
+	// remain callable
+	ctx = context.WithValue(ctx, rpcGrantKey{}, &rpcAuthorization{grant: rpcGrant{"admin": true, "eth": true}, expires: time.Now().Add(-time.Second)})
+	if err := a.filter(ctx, "admin_peers"); !errors.As(err, &authErr) || !authErr.expired {
+		t.Errorf("wrong error for call with expired token: %v", err)
+	}
+	if err := a.filter(ctx, "eth_call"); err != nil {
+		t.Errorf("public namespace rejected after token expiry: %v", err)
+	}
+	a = newTestAuthorizer(t, "rpc")
+	if err := a.filter(context.Background(), "rpc_discover"); err != nil {
+		t.Errorf("configured public rpc namespace rejected: %v", err)
+	}
+}
+
+func TestRPCAuthHandler(t *testing.T) {
+	var (
+		a     = newTestAuthorizer(t, "eth")
+		grant rpcGrant
+	)
+	h := a.handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth, ok := r.Context().Value(rpcGrantKey{}).(*rpcAuthorization); ok {
+			grant = auth.grant
+		}
+	}))
+	serve := func(token string) int {
+		grant = nil
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	// Requests without a token are granted the public APIs
+	if code := serve(""); code != http.StatusOK || !grant.allows("eth_call") || grant.allows("debug_traceCall") {
+		t.Fatalf("wrong result without token: code %d, grant %v", code, grant)
+	}
+	// Valid tokens grant their APIs
+	token := signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"exp": time.Now().Add(time.Minute).Unix(), "apis": "debug"})
+	if code := serve(token); code != http.StatusOK || !grant.allows("debug_traceCall") {
+		t.Fatalf("wrong result with valid token: code %d, grant %v", code, grant)
+	}
+	// Invalid tokens are rejected before reaching the server
+	token = signTestToken(t, jwt.SigningMethodHS256, testRPCAuthSecret, jwt.MapClaims{"apis": "debug"})
+	if code := serve(token); code != http.StatusUnauthorized || grant != nil {
+		t.Fatalf("wrong result with token without expiry: code %d", code)
+	}
+	if code := serve("garbage"); code != http.StatusUnauthorized || grant != nil {
+		t.Fatalf("wrong result with malformed token: code %d", code)
+	}
+}
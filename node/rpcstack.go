@@ -31,6 +31,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/rs/cors"
@@ -77,7 +78,55 @@ type rpcEndpointConfig struct {
 	jwtSecret              []byte // optional JWT secret // 可选的 JWT 密钥
 	batchItemLimit         int    // 批处理请求项限制
 	batchResponseSizeLimit int    // 批处理响应大小限制
+	batchConcurrency       int    // 批处理并发调用数量
 	httpBodyLimit          int    // HTTP 请求体大小限制
+	responseSizeLimit      int    // 单个响应大小限制
+
+	rateLimiter *rateLimiter    // optional per-client rate limiter // 可选的按客户端速率限制器
+	authorizer  *rpcAuthorizer  // optional JWT based API authorization // 可选的基于 JWT 的 API 授权
+	recorder    *rpc.Recorder   // optional recording of served calls // 可选的已处理调用记录
+	tracer      *tracing.Tracer // optional tracing of served calls // 可选的已处理调用追踪
+}
+
+// callFilter returns the combined call filter of the endpoint, nil if there is none.
+// Authorization is checked first, so that rejected calls don't count towards the
+// rate limits.
+// callFilter 返回端点的组合调用过滤器，如果没有则返回 nil。首先检查授权，使被拒绝的调用不计入速率限制。
+func (c *rpcEndpointConfig) callFilter() rpc.CallFilter {
+	var filters []rpc.CallFilter
+	if c.authorizer != nil {
+		filters = append(filters, c.authorizer.filter)
+	}
+	if c.rateLimiter != nil {
+		filters = append(filters, c.rateLimiter.filter)
+	}
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0]
+	}
+	return func(ctx context.Context, method string) error {
+		for _, filter := range filters {
+			if err := filter(ctx, method); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// wrapHandler wraps an RPC handler into the request tagging of the endpoint's
+// call filters.
+// wrapHandler 将 RPC 处理程序包装到端点调用过滤器的请求标记中。
+func (c *rpcEndpointConfig) wrapHandler(handler http.Handler) http.Handler {
+	if c.rateLimiter != nil {
+		handler = c.rateLimiter.handler(handler) // 识别发出请求的客户端
+	}
+	if c.authorizer != nil {
+		handler = c.authorizer.handler(handler) // 验证令牌并记录授予的 API
+	}
+	return handler
 }
 
 type rpcHandler struct {
@@ -346,20 +395,57 @@ func (h *httpServer) enableRPC(apis []rpc.API, config httpConfig) error {
 	// Create RPC server and handler. // 创建 RPC 服务器和处理程序
 	srv := rpc.NewServer()                                                   // 创建新的 RPC 服务器
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit) // 设置批处理限制
+	srv.SetResponseSizeLimit(config.responseSizeLimit)                       // 设置单个响应大小限制
+	srv.SetBatchConcurrency(config.batchConcurrency)                         // 设置批处理并发调用数量
+	if config.recorder != nil {
+		srv.SetRecorder(config.recorder) // 记录处理的调用
+	}
+	if config.tracer != nil {
+		srv.SetTracer(config.tracer) // 追踪处理的调用
+	}
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit) // 设置 HTTP 请求体限制
 	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil { // 注册 API
 		return err
 	}
+	if filter := config.callFilter(); filter != nil {
+		srv.SetCallFilter(filter) // 对每个调用实施授权和速率限制
+	}
 	h.httpConfig = config // 保存配置
 	h.httpHandler.Store(&rpcHandler{
-		Handler: NewHTTPHandlerStack(srv, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret), // 创建处理栈
+		Handler: rpcHTTPHandler(srv, config), // 创建处理栈
 		server:  srv,
 	})
 	return nil // 返回成功
 }
 
+// rpcHTTPHandler wraps the RPC server into the HTTP handler stack of the config.
+// rpcHTTPHandler 将 RPC 服务器包装到配置的 HTTP 处理栈中。
+func rpcHTTPHandler(srv *rpc.Server, config httpConfig) http.Handler {
+	handler := config.wrapHandler(srv)
+	return NewHTTPHandlerStack(handler, config.CorsAllowedOrigins, config.Vhosts, config.jwtSecret)
+}
+
+// setHTTPFilters replaces the CORS origins and virtual hosts accepted by the running
+// HTTP RPC handler. It reports whether the handler is enabled.
+// setHTTPFilters 替换正在运行的 HTTP RPC 处理程序接受的 CORS 来源和虚拟主机。返回处理程序是否已启用。
+func (h *httpServer) setHTTPFilters(cors, vhosts []string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	handler := h.httpHandler.Load().(*rpcHandler)
+	if handler == nil {
+		return false
+	}
+	h.httpConfig.CorsAllowedOrigins, h.httpConfig.Vhosts = cors, vhosts
+	h.httpHandler.Store(&rpcHandler{
+		Handler: rpcHTTPHandler(handler.server, h.httpConfig),
+		server:  handler.server,
+	})
+	return true
+}
+
 // disableRPC stops the HTTP RPC handler. This is internal, the caller must hold h.mu.
 // disableRPC 停止 HTTP RPC 处理程序。这是内部函数，调用者必须持有 h.mu。
 func (h *httpServer) disableRPC() bool {
@@ -383,15 +469,27 @@ func (h *httpServer) enableWS(apis []rpc.API, config wsConfig) error {
 	// Create RPC server and handler. // 创建 RPC 服务器和处理程序
 	srv := rpc.NewServer()                                                   // 创建新的 RPC 服务器
 	srv.SetBatchLimits(config.batchItemLimit, config.batchResponseSizeLimit) // 设置批处理限制
+	srv.SetResponseSizeLimit(config.responseSizeLimit)                       // 设置单个响应大小限制
+	srv.SetBatchConcurrency(config.batchConcurrency)                         // 设置批处理并发调用数量
+	if config.recorder != nil {
+		srv.SetRecorder(config.recorder) // 记录处理的调用
+	}
+	if config.tracer != nil {
+		srv.SetTracer(config.tracer) // 追踪处理的调用
+	}
 	if config.httpBodyLimit > 0 {
 		srv.SetHTTPBodyLimit(config.httpBodyLimit) // 设置 HTTP 请求体限制
 	}
 	if err := RegisterApis(apis, config.Modules, srv); err != nil { // 注册 API
 		return err
 	}
+	if filter := config.callFilter(); filter != nil {
+		srv.SetCallFilter(filter) // 对每个调用实施授权和速率限制
+	}
+	handler := config.wrapHandler(srv.WebsocketHandler(config.Origins))
 	h.wsConfig = config // 保存配置
 	h.wsHandler.Store(&rpcHandler{
-		Handler: NewWSHandlerStack(srv.WebsocketHandler(config.Origins), config.jwtSecret), // 创建 WebSocket 处理栈
+		Handler: NewWSHandlerStack(handler, config.jwtSecret), // 创建 WebSocket 处理栈
 		server:  srv,
 	})
 	return nil // 返回成功
@@ -25,6 +25,7 @@ import (
 	mrand "math/rand"
 	"net"
 	"net/netip"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -64,6 +65,11 @@ const (
 	// 端点解析受到限制，使用有界退避。
 	initialResolveDelay = 60 * time.Second
 	maxResolveDelay     = time.Hour
+
+	// Discovered nodes are buffered while no dial slot is free, so the ones with
+	// the best reputation can be dialed first.
+	// 在没有空闲拨号槽位时缓冲已发现的节点，以便优先拨号信誉最好的节点。
+	dialCandidateBuffer = 16
 )
 
 // NodeDialer is used to connect to nodes in the network, typically by using
@@ -98,6 +104,8 @@ var (
 	errNetRestrict      = errors.New("not contained in netrestrict list")
 	errNoPort           = errors.New("node does not provide TCP port")
 	errNoResolvedIP     = errors.New("node does not provide a resolved IP")
+	errLowReputation    = errors.New("reputation too low")
+	errPolicyDenied     = errors.New("denied by peer policy")
 )
 
 // dialer creates outbound connections and submits them into Server.
@@ -123,6 +131,7 @@ type dialScheduler struct {
 	doneCh        chan *dialTask
 	addStaticCh   chan *enode.Node
 	remStaticCh   chan *enode.Node
+	policyCh      chan struct{}
 	addPeerCh     chan *conn
 	remPeerCh     chan *conn
 
@@ -142,6 +151,11 @@ type dialScheduler struct {
 	static     map[enode.ID]*dialTask
 	staticPool []*dialTask
 
+	// Discovered nodes waiting for a free dial slot, only buffered if reputations
+	// are known. The best of them is dialed first.
+	// 等待空闲拨号槽位的已发现节点，仅在已知信誉时缓冲。其中最好的节点首先被拨号。
+	candidates []dialCandidate
+
 	// The dial history keeps recently dialed nodes. Members of history are not dialed.
 	// dial history 保留最近拨号的节点。history 中的成员不会被拨号。
 	history      expHeap
@@ -156,10 +170,12 @@ type dialScheduler struct {
 type dialSetupFunc func(net.Conn, connFlag, *enode.Node) error
 
 type dialConfig struct {
-	self           enode.ID         // our own ID // 我们自己的 ID
-	maxDialPeers   int              // maximum number of dialed peers // 拨号 peer 的最大数量
-	maxActiveDials int              // maximum number of active dials // 活动拨号的最大数量
-	netRestrict    *netutil.Netlist // IP netrestrict list, disabled if nil // IP netrestrict 列表，如果为 nil 则禁用
+	self           enode.ID                // our own ID // 我们自己的 ID
+	maxDialPeers   int                     // maximum number of dialed peers // 拨号 peer 的最大数量
+	maxActiveDials int                     // maximum number of active dials // 活动拨号的最大数量
+	netRestrict    *netutil.Netlist        // IP netrestrict list, disabled if nil // IP netrestrict 列表，如果为 nil 则禁用
+	reputation     func(enode.ID) float64  // peer reputation scores, disabled if nil // 对等方信誉分数，如果为 nil 则禁用
+	policy         func(*enode.Node) error // peer policy check, disabled if nil // 对等方策略检查，如果为 nil 则禁用
 	resolver       nodeResolver
 	dialer         NodeDialer
 	log            log.Logger
@@ -200,6 +216,7 @@ func newDialScheduler(config dialConfig, it enode.Iterator, setupFunc dialSetupF
 		nodesIn:       make(chan *enode.Node),
 		addStaticCh:   make(chan *enode.Node),
 		remStaticCh:   make(chan *enode.Node),
+		policyCh:      make(chan struct{}),
 		addPeerCh:     make(chan *conn),
 		remPeerCh:     make(chan *conn),
 	}
@@ -236,6 +253,17 @@ func (d *dialScheduler) removeStatic(n *enode.Node) {
 	}
 }
 
+// policyChanged makes the dialer re-check the static nodes against the peer
+// policy.
+//
+// policyChanged 使 dialer 根据对等方策略重新检查静态节点。
+func (d *dialScheduler) policyChanged() {
+	select {
+	case d.policyCh <- struct{}{}:
+	case <-d.ctx.Done():
+	}
+}
+
 // peerAdded updates the peer set.
 // peerAdded 更新 peer 集合。
 func (d *dialScheduler) peerAdded(c *conn) {
@@ -267,7 +295,8 @@ loop:
 		// 如果有可用的槽位，启动新的拨号。
 		slots := d.freeDialSlots()
 		slots -= d.startStaticDials(slots)
-		if slots > 0 {
+		slots -= d.startCandidateDials(slots)
+		if slots > 0 || (d.reputation != nil && len(d.candidates) < dialCandidateBuffer) {
 			nodesCh = d.nodesIn
 		} else {
 			nodesCh = nil
@@ -277,9 +306,16 @@ loop:
 
 		select {
 		case node := <-nodesCh:
-			if err := d.checkDial(node); err != nil {
+			err := d.checkDial(node)
+			if err == nil {
+				err = d.checkReputation(node)
+			}
+			switch {
+			case err != nil:
 				d.log.Trace("Discarding dial candidate", "id", node.ID(), "ip", node.IPAddr(), "reason", err)
-			} else {
+			case d.reputation != nil:
+				d.addCandidate(node)
+			default:
 				d.startDial(newDialTask(node, dynDialedConn))
 			}
 
@@ -335,6 +371,15 @@ loop:
 				}
 			}
 
+		case <-d.policyCh:
+			for id, task := range d.static {
+				if task.staticPoolIndex >= 0 && d.checkDial(task.dest()) != nil {
+					d.removeFromStaticPool(task.staticPoolIndex)
+				} else {
+					d.updateStaticPool(id)
+				}
+			}
+
 		case <-d.historyTimer.C():
 			d.expireHistory()
 
@@ -444,14 +489,76 @@ func (d *dialScheduler) checkDial(n *enode.Node) error {
 	if d.history.contains(string(n.ID().Bytes())) {
 		return errRecentlyDialed
 	}
+	if d.policy != nil && d.policy(n) != nil {
+		return errPolicyDenied
+	}
+	return nil
+}
+
+// checkReputation returns an error if the discovered node n has a reputation
+// too low to be dialed. Static nodes are dialed regardless of their reputation.
+//
+// checkReputation 如果已发现节点 n 的信誉过低而不应被拨号，则返回错误。静态节点无论信誉如何都会被拨号。
+func (d *dialScheduler) checkReputation(n *enode.Node) error {
+	if d.reputation != nil && d.reputation(n.ID()) < dialMinReputation {
+		return errLowReputation
+	}
 	return nil
 }
 
+// dialCandidate is a discovered node waiting to be dialed.
+// dialCandidate 是等待拨号的已发现节点。
+type dialCandidate struct {
+	node  *enode.Node
+	score float64
+}
+
+// addCandidate buffers a discovered node until a dial slot is free. Nodes which
+// are already buffered are updated.
+//
+// addCandidate 缓冲已发现的节点，直到有空闲的拨号槽位。已缓冲的节点会被更新。
+func (d *dialScheduler) addCandidate(n *enode.Node) {
+	c := dialCandidate{node: n, score: d.reputation(n.ID())}
+	for i := range d.candidates {
+		if d.candidates[i].node.ID() == n.ID() {
+			d.candidates[i] = c
+			return
+		}
+	}
+	d.candidates = append(d.candidates, c)
+}
+
+// startCandidateDials starts up to n dial tasks for buffered discovered nodes,
+// best reputation first. Candidates which can no longer be dialed are dropped.
+//
+// startCandidateDials 为缓冲的已发现节点启动最多 n 个拨号任务，信誉最好的优先。
+// 不能再拨号的候选节点会被丢弃。
+func (d *dialScheduler) startCandidateDials(n int) (started int) {
+	for started < n && len(d.candidates) > 0 {
+		best := 0
+		for i := range d.candidates {
+			if d.candidates[i].score > d.candidates[best].score {
+				best = i
+			}
+		}
+		node := d.candidates[best].node
+		d.candidates = slices.Delete(d.candidates, best, best+1)
+
+		if err := d.checkDial(node); err != nil {
+			d.log.Trace("Discarding dial candidate", "id", node.ID(), "ip", node.IPAddr(), "reason", err)
+			continue
+		}
+		d.startDial(newDialTask(node, dynDialedConn))
+		started++
+	}
+	return started
+}
+
 // startStaticDials starts n static dial tasks.
 // startStaticDials 启动 n 个静态拨号任务。
 func (d *dialScheduler) startStaticDials(n int) (started int) {
 	for started = 0; started < n && len(d.staticPool) > 0; started++ {
-		idx := d.rand.Intn(len(d.staticPool))
+		idx := d.pickStatic()
 		task := d.staticPool[idx]
 		d.startDial(task)
 		d.removeFromStaticPool(idx)
@@ -459,6 +566,24 @@ func (d *dialScheduler) startStaticDials(n int) (started int) {
 	return started
 }
 
+// pickStatic returns the index of the next static task to dial. Tasks are
+// picked at random, but if reputations are known, the better of two random
+// tasks is chosen so well-behaving nodes are preferred.
+//
+// pickStatic 返回下一个要拨号的静态任务的索引。任务是随机选择的，但如果已知信誉，
+// 则选择两个随机任务中较好的一个，以便优先选择表现良好的节点。
+func (d *dialScheduler) pickStatic() int {
+	idx := d.rand.Intn(len(d.staticPool))
+	if d.reputation == nil || len(d.staticPool) == 1 {
+		return idx
+	}
+	other := d.rand.Intn(len(d.staticPool))
+	if d.reputation(d.staticPool[other].dest().ID()) > d.reputation(d.staticPool[idx].dest().ID()) {
+		return other
+	}
+	return idx
+}
+
 // updateStaticPool attempts to move the given static dial back into staticPool.
 //
 // updateStaticPool 尝试将给定的静态拨号移回 staticPool。
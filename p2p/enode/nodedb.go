@@ -21,6 +21,7 @@ import (
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"net/netip"
 	"os"
 	"sync"
@@ -53,6 +54,8 @@ const (
 	dbNodePing      = "lastping" // 上次 ping 时间
 	dbNodePong      = "lastpong" // 上次 pong 时间
 	dbNodeSeq       = "seq"      // 记录序列号
+	dbNodeRep       = "rep"      // 对等方信誉分数
+	dbNodeRepTime   = "reptime"  // 信誉分数的更新时间
 
 	// Local information is keyed by ID only, the full key is "local:<ID>:seq".
 	// Use localItemKey to create those keys.
@@ -483,6 +486,28 @@ func (db *DB) UpdateFindFailsV5(id ID, ip netip.Addr, fails int) error {
 	return db.storeInt64(v5Key(id, ip, dbNodeFindFails), int64(fails)) // 存储失败次数
 }
 
+// Reputation retrieves the stored reputation score of a node and the time it
+// was last updated. Nodes without a stored score have a zero score.
+//
+// Reputation 检索节点存储的信誉分数及其最后更新时间。没有存储分数的节点分数为零。
+func (db *DB) Reputation(id ID) (score float64, updated time.Time) {
+	updatedAt := db.fetchInt64(nodeItemKey(id, zeroIP, dbNodeRepTime)) // 获取更新时间
+	if updatedAt == 0 {
+		return 0, time.Time{}
+	}
+	score = math.Float64frombits(db.fetchUint64(nodeItemKey(id, zeroIP, dbNodeRep))) // 获取分数
+	return score, time.UnixMilli(updatedAt)
+}
+
+// UpdateReputation stores the reputation score of a node.
+// UpdateReputation 存储节点的信誉分数。
+func (db *DB) UpdateReputation(id ID, score float64, updated time.Time) error {
+	if err := db.storeUint64(nodeItemKey(id, zeroIP, dbNodeRep), math.Float64bits(score)); err != nil { // 存储分数
+		return err
+	}
+	return db.storeInt64(nodeItemKey(id, zeroIP, dbNodeRepTime), updated.UnixMilli()) // 存储更新时间
+}
+
 // localSeq retrieves the local record sequence counter, defaulting to the current
 // timestamp if no previous exists. This ensures that wiping all data associated
 // with a node (apart from its key) will not generate already used sequence nums.
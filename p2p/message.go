@@ -54,6 +54,8 @@ type Msg struct {
 	meterCap  Cap    // Protocol name and version for egress metering / 用于出口计量的协议名称和版本
 	meterCode uint64 //  Message within protocol for egress metering / 用于出口计量的协议内的消息代码
 	meterSize uint32 // Compressed message size for ingress metering / 用于入口计量的压缩消息大小
+
+	meterEgress *trafficCounter // Per-peer counter for egress accounting / 用于出口统计的每个对等方计数器
 }
 
 // Decode parses the RLP content of a message into
@@ -44,6 +44,11 @@ const (
 	// egressMeterName is the prefix of the per-packet outbound metrics.
 	// egressMeterName 是每个数据包出站度量的前缀。
 	egressMeterName = "p2p/egress"
+
+	// uncompressedMeterSuffix is appended to the per-packet metrics to meter the
+	// uncompressed payload size instead of the size on the wire.
+	// uncompressedMeterSuffix 附加到每个数据包度量之后，用于度量未压缩的有效载荷大小，而不是线路上的大小。
+	uncompressedMeterSuffix = "/uncompressed"
 )
 
 var (
@@ -71,6 +76,11 @@ var (
 	dialUnexpectedIdentity  = metrics.NewRegisteredMeter("p2p/dials/error/id/unexpected", nil)
 	dialEncHandshakeError   = metrics.NewRegisteredMeter("p2p/dials/error/rlpx/enc", nil)
 	dialProtoHandshakeError = metrics.NewRegisteredMeter("p2p/dials/error/rlpx/proto", nil)
+
+	// serveThrottleTimer measures how long served messages are delayed by the
+	// per-peer serving bandwidth limit.
+	// serveThrottleTimer 测量提供的消息因每个对等方的服务带宽限制而延迟的时间。
+	serveThrottleTimer = metrics.NewRegisteredResettingTimer("p2p/serve/throttle", nil)
 )
 
 // markDialError matches errors that occur while setting up a dial connection
@@ -38,7 +38,7 @@ import (
 // EIP-778（ENR）：节点记录中包含 IP 和端口信息，NAT 映射确保这些信息对外可见。
 
 // 端口映射需求：以太坊节点需要映射 UDP 端口（如 30303）用于节点发现（基于 Kademlia 协议），映射 TCP 端口用于数据传输。
-// 自动发现：Any 函数并发尝试 UPnP 和 NAT-PMP，适应不同网络环境。
+// 自动发现：Any 函数并发尝试 PCP、UPnP 和 NAT-PMP，适应不同网络环境。
 // 外部 IP：ExternalIP 方法提供节点在 ENR 中的外部地址。
 
 // Interface An implementation of nat.Interface can map local ports to ports
@@ -80,6 +80,8 @@ type Interface interface {
 //	"upnp"               uses the Universal Plug and Play protocol
 //	"pmp"                uses NAT-PMP with an auto-detected gateway address
 //	"pmp:192.168.0.1"    uses NAT-PMP with the given gateway address
+//	"pcp"                uses PCP with an auto-detected gateway address
+//	"pcp:192.168.0.1"    uses PCP with the given gateway address (IPv4 or IPv6)
 //
 // Parse 解析 NAT 接口描述。
 // 当前接受以下格式。
@@ -91,6 +93,8 @@ type Interface interface {
 //	"upnp"               使用通用即插即用协议
 //	"pmp"                使用 NAT-PMP 并自动检测网关地址
 //	"pmp:192.168.0.1"    使用 NAT-PMP 并使用给定的网关地址
+//	"pcp"                使用 PCP 并自动检测网关地址
+//	"pcp:192.168.0.1"    使用 PCP 并使用给定的网关地址（IPv4 或 IPv6）
 func Parse(spec string) (Interface, error) {
 	var (
 		before, after, found = strings.Cut(spec, ":")  // 分割字符串为前缀和后缀
@@ -117,6 +121,8 @@ func Parse(spec string) (Interface, error) {
 		return UPnP(), nil // 返回 UPnP 接口
 	case "pmp", "natpmp", "nat-pmp":
 		return PMP(ip), nil // 返回 NAT-PMP 接口
+	case "pcp":
+		return PCP(ip), nil // 返回 PCP 接口
 	default:
 		return nil, fmt.Errorf("unknown mechanism %q", before) // 返回未知机制错误
 	}
@@ -187,26 +193,38 @@ func (ExtIP) AddMapping(protocol string, extport, intport int, name string, life
 func (ExtIP) DeleteMapping(string, int, int) error { return nil } // 不执行删除
 
 // Any returns a port mapper that tries to discover any supported
-// mechanism on the local network.
+// mechanism on the local network. If several are found, PCP is preferred
+// over UPnP, and UPnP over NAT-PMP.
 //
 // Any 返回一个端口映射器，尝试发现本地网络上支持的任何机制。
+// 如果发现多个机制，PCP 优先于 UPnP，UPnP 优先于 NAT-PMP。
 func Any() Interface {
 	// TODO: attempt to discover whether the local machine has an
 	// TODO：尝试发现本地机器是否具有互联网级地址，在此情况下返回 ExtIP。
 	// Internet-class address. Return ExtIP in this case.
-	return startautodisc("UPnP or NAT-PMP", func() Interface { // 启动自动发现
-		found := make(chan Interface, 2)        // 创建发现通道
-		go func() { found <- discoverUPnP() }() // 并发发现 UPnP
-		go func() { found <- discoverPMP() }()  // 并发发现 NAT-PMP
-		for i := 0; i < cap(found); i++ {       // 检查所有结果
-			if c := <-found; c != nil {
-				return c // 返回第一个成功的机制
-			}
-		}
-		return nil // 如果都失败则返回 nil
+	return startautodisc("PCP, UPnP or NAT-PMP", func() Interface { // 启动自动发现
+		return discoverFirst(discoverPCP, discoverUPnP, discoverPMP)
 	})
 }
 
+// discoverFirst runs the discovery functions concurrently and returns the result
+// of the first one in the list which found a mechanism.
+//
+// discoverFirst 并发运行发现函数，并返回列表中第一个发现机制的函数的结果。
+func discoverFirst(discover ...func() Interface) Interface {
+	found := make([]chan Interface, len(discover))
+	for i, fn := range discover {
+		found[i] = make(chan Interface, 1)
+		go func() { found[i] <- fn() }()
+	}
+	for _, ch := range found {
+		if c := <-ch; c != nil {
+			return c // 返回优先级最高的成功机制
+		}
+	}
+	return nil // 如果都失败则返回 nil
+}
+
 // UPnP returns a port mapper that uses UPnP. It will attempt to
 // discover the address of your router using UDP broadcasts.
 //
@@ -228,6 +246,19 @@ func PMP(gateway net.IP) Interface {
 	return startautodisc("NAT-PMP", discoverPMP) // 启动 NAT-PMP 自动发现
 }
 
+// PCP returns a port mapper that uses the Port Control Protocol. The provided
+// gateway address should be the IP of your router. If the given gateway address
+// is nil, PCP will attempt to auto-discover the router.
+//
+// PCP 返回一个使用端口控制协议的端口映射器。提供的网关地址应为路由器的 IP。
+// 如果给定的网关地址为 nil，PCP 将尝试自动发现路由器。
+func PCP(gateway net.IP) Interface {
+	if gateway != nil {
+		return newPCP(gateway, pcpPort) // 使用指定网关创建 PCP
+	}
+	return startautodisc("PCP", discoverPCP) // 启动 PCP 自动发现
+}
+
 // autodisc represents a port mapping mechanism that is still being
 // auto-discovered. Calls to the Interface methods on this type will
 // wait until the discovery is done and then call the method on the
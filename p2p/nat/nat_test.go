@@ -61,3 +61,36 @@ func TestAutoDiscRace(t *testing.T) {
 		}
 	}
 }
+
+func TestDiscoverFirst(t *testing.T) {
+	var (
+		slow = func() Interface {
+			time.Sleep(100 * time.Millisecond)
+			return ExtIP{1, 1, 1, 1}
+		}
+		fast = func() Interface { return ExtIP{2, 2, 2, 2} }
+		none = func() Interface { return nil }
+	)
+	// The first mechanism in the list wins, even if it is found later.
+	if c := discoverFirst(slow, fast); !net.IP(c.(ExtIP)).Equal(net.IP{1, 1, 1, 1}) {
+		t.Errorf("wrong mechanism %v", c)
+	}
+	if c := discoverFirst(none, fast); !net.IP(c.(ExtIP)).Equal(net.IP{2, 2, 2, 2}) {
+		t.Errorf("wrong mechanism %v", c)
+	}
+	if c := discoverFirst(none, none); c != nil {
+		t.Errorf("found mechanism %v", c)
+	}
+}
+
+func TestParsePCP(t *testing.T) {
+	for _, spec := range []string{"pcp:192.168.0.1", "PCP:fe80::1"} {
+		n, err := Parse(spec)
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+		if _, ok := n.(*pcp); !ok {
+			t.Errorf("%s: wrong interface %v", spec, n)
+		}
+	}
+}
@@ -0,0 +1,519 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package nat
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 代码背景：以太坊 P2P 网络中的 PCP
+// PCP（Port Control Protocol，RFC 6887）是 NAT-PMP 的后继协议，被较新的家庭路由器和运营商级 NAT 使用。
+// 与 NAT-PMP 不同，PCP 支持 IPv6，并且服务器可以授予比请求更短的映射生命周期。
+//
+// 实现要点：
+// 映射续期：如果服务器授予的生命周期短于请求的生命周期，映射会在授予生命周期的一半时自动续期，直到请求的生命周期结束。
+// 纪元检查：每个响应都包含服务器的纪元时间。如果纪元表明服务器丢失了状态（例如重启），所有映射会立即重新创建。
+// 外部地址：PCP 没有单独的外部地址请求，外部地址从 MAP 响应中获得。
+
+const (
+	pcpPort    = 5351 // PCP 服务器端口
+	pcpVersion = 2
+
+	pcpOpAnnounce = 0
+	pcpOpMap      = 1
+	pcpResponse   = 0x80 // R bit of the opcode field // 操作码字段的 R 位
+
+	pcpHeaderSize  = 24
+	pcpMapSize     = 36
+	pcpMaxDatagram = 1100
+
+	pcpResultSuccess = 0
+
+	// pcpInitialTimeout is the response timeout of the first request attempt. It
+	// doubles with every retransmission.
+	// pcpInitialTimeout 是第一次请求尝试的响应超时时间，每次重传时加倍。
+	pcpInitialTimeout = 250 * time.Millisecond
+	pcpAttempts       = 4
+
+	// pcpRetryInterval is the delay before retrying a failed renewal.
+	// pcpRetryInterval 是重试失败续期之前的延迟。
+	pcpRetryInterval = 30 * time.Second
+
+	// pcpProbePort and pcpProbeLifetime are used for the temporary mapping which
+	// discovers the external address when there are no other mappings.
+	// pcpProbePort 和 pcpProbeLifetime 用于在没有其他映射时发现外部地址的临时映射。
+	pcpProbePort     = 9 // discard
+	pcpProbeLifetime = 30 * time.Second
+)
+
+var pcpResultNames = map[byte]string{
+	1:  "UNSUPP_VERSION",
+	2:  "NOT_AUTHORIZED",
+	3:  "MALFORMED_REQUEST",
+	4:  "UNSUPP_OPCODE",
+	5:  "UNSUPP_OPTION",
+	6:  "MALFORMED_OPTION",
+	7:  "NETWORK_FAILURE",
+	8:  "NO_RESOURCES",
+	9:  "UNSUPP_PROTOCOL",
+	10: "USER_EX_QUOTA",
+	11: "CANNOT_PROVIDE_EXTERNAL",
+	12: "ADDRESS_MISMATCH",
+	13: "EXCESSIVE_REMOTE_PEERS",
+}
+
+// pcpError is a non-success result code returned by the PCP server.
+// pcpError 是 PCP 服务器返回的非成功结果代码。
+type pcpError byte
+
+func (e pcpError) Error() string {
+	if name, ok := pcpResultNames[byte(e)]; ok {
+		return "PCP error " + name
+	}
+	return fmt.Sprintf("PCP error %d", byte(e))
+}
+
+// pcp implements the Port Control Protocol.
+// pcp 实现端口控制协议。
+type pcp struct {
+	gw   net.IP // 网关 IP 地址
+	port int    // 服务器端口，测试以外为 pcpPort
+
+	mu       sync.Mutex
+	mappings map[pcpKey]*pcpMapping
+	extIP    net.IP // external address of the latest mapping // 最近映射的外部地址
+
+	// Server epoch tracking, see RFC 6887 section 8.5.
+	// 服务器纪元跟踪，参见 RFC 6887 第 8.5 节。
+	epochValid  bool
+	epochServer uint32
+	epochClient time.Time
+}
+
+type pcpKey struct {
+	protocol byte
+	intport  uint16
+}
+
+// pcpMapping is a mapping kept alive by renewals.
+// pcpMapping 是通过续期保持活动的映射。
+type pcpMapping struct {
+	key      pcpKey
+	nonce    [12]byte
+	extport  uint16
+	deadline time.Time   // end of the lifetime requested by the caller // 调用者请求的生命周期的结束时间
+	timer    *time.Timer // renewal timer // 续期定时器
+}
+
+func newPCP(gw net.IP, port int) *pcp {
+	return &pcp{gw: gw, port: port, mappings: make(map[pcpKey]*pcpMapping)}
+}
+
+func (n *pcp) String() string {
+	return fmt.Sprintf("PCP(%v)", n.gw)
+}
+
+func pcpProtocol(protocol string) (byte, error) {
+	switch strings.ToUpper(protocol) {
+	case "TCP":
+		return 6, nil
+	case "UDP":
+		return 17, nil
+	default:
+		return 0, fmt.Errorf("unsupported protocol %q", protocol)
+	}
+}
+
+// AddMapping creates a mapping and keeps it alive for the given lifetime, even if
+// the server grants a shorter one.
+//
+// AddMapping 创建映射并使其在给定生命周期内保持活动，即使服务器授予的生命周期更短。
+func (n *pcp) AddMapping(protocol string, extport, intport int, name string, lifetime time.Duration) (uint16, error) {
+	if lifetime <= 0 {
+		return 0, errors.New("lifetime must not be <= 0")
+	}
+	proto, err := pcpProtocol(protocol)
+	if err != nil {
+		return 0, err
+	}
+	key := pcpKey{proto, uint16(intport)}
+
+	// Reuse the nonce of an existing mapping, the server only allows changing it
+	// with the same nonce.
+	// 重用现有映射的随机数，服务器只允许使用相同的随机数更改映射。
+	n.mu.Lock()
+	m := n.mappings[key]
+	if m == nil {
+		m = &pcpMapping{key: key}
+		if _, err := rand.Read(m.nonce[:]); err != nil {
+			n.mu.Unlock()
+			return 0, err
+		}
+	}
+	nonce := m.nonce
+	n.mu.Unlock()
+
+	assigned, granted, err := n.requestMap(key, nonce, uint16(extport), lifetime)
+	if err != nil {
+		return 0, err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if old := n.mappings[key]; old != nil && old.timer != nil {
+		old.timer.Stop()
+	}
+	m = &pcpMapping{key: key, nonce: nonce, extport: assigned, deadline: time.Now().Add(lifetime)}
+	n.mappings[key] = m
+	n.scheduleRenewal(m, granted)
+	return assigned, nil
+}
+
+// scheduleRenewal arms the renewal timer of m if the granted lifetime ends before
+// the requested one. The lock must be held.
+//
+// scheduleRenewal 如果授予的生命周期在请求的生命周期之前结束，则设置 m 的续期定时器。必须持有锁。
+func (n *pcp) scheduleRenewal(m *pcpMapping, granted time.Duration) {
+	if time.Until(m.deadline) <= granted {
+		n.scheduleRenewalIn(m, -1)
+	} else {
+		n.scheduleRenewalIn(m, granted/2)
+	}
+}
+
+// scheduleRenewalIn replaces the renewal timer of m. A negative delay disarms it.
+// The lock must be held.
+//
+// scheduleRenewalIn 替换 m 的续期定时器。负延迟会解除定时器。必须持有锁。
+func (n *pcp) scheduleRenewalIn(m *pcpMapping, delay time.Duration) {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	if delay >= 0 {
+		m.timer = time.AfterFunc(delay, func() { n.renew(m) })
+	}
+}
+
+// renew extends the lifetime of m up to its deadline.
+// renew 将 m 的生命周期延长到其截止时间。
+func (n *pcp) renew(m *pcpMapping) error {
+	n.mu.Lock()
+	if n.mappings[m.key] != m {
+		n.mu.Unlock()
+		return nil // deleted or replaced
+	}
+	remaining := time.Until(m.deadline)
+	if remaining < time.Second {
+		delete(n.mappings, m.key)
+		n.mu.Unlock()
+		return nil
+	}
+	extport := m.extport
+	n.mu.Unlock()
+
+	assigned, granted, err := n.requestMap(m.key, m.nonce, extport, remaining)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.mappings[m.key] != m {
+		return err
+	}
+	if err != nil {
+		n.scheduleRenewalIn(m, min(pcpRetryInterval, remaining/2))
+		return err
+	}
+	m.extport = assigned
+	n.scheduleRenewal(m, granted)
+	return nil
+}
+
+// renewAll renews all mappings, used when the server lost its state.
+// renewAll 续期所有映射，在服务器丢失状态时使用。
+func (n *pcp) renewAll() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, m := range n.mappings {
+		n.scheduleRenewalIn(m, 0)
+	}
+}
+
+// DeleteMapping removes a mapping by requesting a zero lifetime.
+// DeleteMapping 通过请求零生命周期来移除映射。
+func (n *pcp) DeleteMapping(protocol string, extport, intport int) error {
+	proto, err := pcpProtocol(protocol)
+	if err != nil {
+		return err
+	}
+	key := pcpKey{proto, uint16(intport)}
+	n.mu.Lock()
+	m := n.mappings[key]
+	if m == nil {
+		n.mu.Unlock()
+		return nil
+	}
+	if m.timer != nil {
+		m.timer.Stop()
+	}
+	delete(n.mappings, key)
+	n.mu.Unlock()
+
+	// Deleting requires zero as the suggested external port.
+	// 删除时建议的外部端口必须为零。
+	_, _, err = n.requestMap(key, m.nonce, 0, 0)
+	return err
+}
+
+// ExternalIP returns the external address assigned to the mappings. Since PCP
+// has no separate request for it, an existing mapping is renewed, or a short-lived
+// mapping is created and deleted when there is none.
+//
+// ExternalIP 返回分配给映射的外部地址。由于 PCP 没有单独的请求，因此会续期现有映射；
+// 如果没有映射，则创建并删除一个短期映射。
+func (n *pcp) ExternalIP() (net.IP, error) {
+	n.mu.Lock()
+	var existing *pcpMapping
+	for _, m := range n.mappings {
+		existing = m
+		break
+	}
+	n.mu.Unlock()
+
+	if existing != nil {
+		if err := n.renew(existing); err != nil {
+			return nil, err
+		}
+	} else {
+		key := pcpKey{17, pcpProbePort}
+		var nonce [12]byte
+		if _, err := rand.Read(nonce[:]); err != nil {
+			return nil, err
+		}
+		if _, _, err := n.requestMap(key, nonce, 0, pcpProbeLifetime); err != nil {
+			return nil, err
+		}
+		n.requestMap(key, nonce, 0, 0)
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.extIP == nil {
+		return nil, errors.New("no external address")
+	}
+	return n.extIP, nil
+}
+
+// requestMap sends a MAP request, returning the assigned external port and the
+// granted lifetime.
+//
+// requestMap 发送 MAP 请求，返回分配的外部端口和授予的生命周期。
+func (n *pcp) requestMap(key pcpKey, nonce [12]byte, extport uint16, lifetime time.Duration) (uint16, time.Duration, error) {
+	var data [pcpMapSize]byte
+	copy(data[0:12], nonce[:])
+	data[12] = key.protocol
+	binary.BigEndian.PutUint16(data[16:18], key.intport)
+	binary.BigEndian.PutUint16(data[18:20], extport)
+	// Suggest the all-zeros address of the gateway's family.
+	// 建议与网关地址族相同的全零地址。
+	if n.gw.To4() != nil {
+		copy(data[20:36], net.IPv4zero.To16())
+	}
+	seconds := uint32((lifetime + time.Second - 1) / time.Second)
+
+	resp, granted, err := n.roundTrip(pcpOpMap, seconds, data[:], pcpAttempts, func(resp []byte) bool {
+		return len(resp) >= pcpHeaderSize+pcpMapSize &&
+			[12]byte(resp[pcpHeaderSize:pcpHeaderSize+12]) == nonce &&
+			resp[pcpHeaderSize+12] == key.protocol &&
+			binary.BigEndian.Uint16(resp[pcpHeaderSize+16:]) == key.intport
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	body := resp[pcpHeaderSize:]
+	if lifetime > 0 {
+		ip := net.IP(append([]byte{}, body[20:36]...))
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		n.mu.Lock()
+		n.extIP = ip
+		n.mu.Unlock()
+	}
+	return binary.BigEndian.Uint16(body[18:20]), time.Duration(granted) * time.Second, nil
+}
+
+// announce sends an ANNOUNCE request, which is used to check whether the gateway
+// supports PCP.
+//
+// announce 发送 ANNOUNCE 请求，用于检查网关是否支持 PCP。
+func (n *pcp) announce(attempts int) error {
+	_, _, err := n.roundTrip(pcpOpAnnounce, 0, nil, attempts, nil)
+	return err
+}
+
+// roundTrip sends a request and waits for the matching response, retransmitting
+// it with exponential backoff.
+//
+// roundTrip 发送请求并等待匹配的响应，以指数退避方式重传。
+func (n *pcp) roundTrip(op byte, lifetime uint32, data []byte, attempts int, match func([]byte) bool) ([]byte, uint32, error) {
+	conn, err := net.DialUDP("udp", nil, &net.UDPAddr{IP: n.gw, Port: n.port})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+
+	// The request carries the address the server sees the client on.
+	// 请求携带服务器看到的客户端地址。
+	req := make([]byte, pcpHeaderSize+len(data))
+	req[0] = pcpVersion
+	req[1] = op
+	binary.BigEndian.PutUint32(req[4:8], lifetime)
+	copy(req[8:24], conn.LocalAddr().(*net.UDPAddr).IP.To16())
+	copy(req[pcpHeaderSize:], data)
+
+	var (
+		buf     = make([]byte, pcpMaxDatagram)
+		timeout = pcpInitialTimeout
+	)
+	for i := 0; i < attempts; i++ {
+		if _, err := conn.Write(req); err != nil {
+			return nil, 0, err
+		}
+		deadline := time.Now().Add(timeout)
+		conn.SetReadDeadline(deadline)
+		for {
+			nr, err := conn.Read(buf)
+			if err != nil {
+				var nerr net.Error
+				if errors.As(err, &nerr) && nerr.Timeout() {
+					break
+				}
+				return nil, 0, err
+			}
+			resp := buf[:nr]
+			if nr < 4 || resp[1] != op|pcpResponse {
+				continue
+			}
+			// NAT-PMP servers answer with their own, shorter error response.
+			// NAT-PMP 服务器会用其自己的、更短的错误响应进行回复。
+			if resp[0] != pcpVersion {
+				return nil, 0, pcpError(1)
+			}
+			if nr < pcpHeaderSize {
+				continue
+			}
+			if match != nil && resp[3] == pcpResultSuccess && !match(resp) {
+				continue
+			}
+			n.checkEpoch(binary.BigEndian.Uint32(resp[8:12]))
+			if resp[3] != pcpResultSuccess {
+				return nil, 0, pcpError(resp[3])
+			}
+			return resp, binary.BigEndian.Uint32(resp[4:8]), nil
+		}
+		timeout *= 2
+	}
+	return nil, 0, fmt.Errorf("no response from PCP server %v", n.gw)
+}
+
+// checkEpoch validates the server epoch of a response. If the server lost its
+// state, the mappings are renewed.
+//
+// checkEpoch 验证响应的服务器纪元。如果服务器丢失了状态，则续期映射。
+func (n *pcp) checkEpoch(server uint32) {
+	now := time.Now()
+	n.mu.Lock()
+	lost := false
+	if n.epochValid {
+		if server+1 < n.epochServer {
+			lost = true
+		} else {
+			clientDelta := now.Sub(n.epochClient).Seconds()
+			serverDelta := float64(server - n.epochServer)
+			lost = clientDelta+2 < serverDelta-serverDelta/16 || serverDelta+2 < clientDelta-clientDelta/16
+		}
+	}
+	n.epochValid, n.epochServer, n.epochClient = true, server, now
+	n.mu.Unlock()
+
+	if lost {
+		n.renewAll()
+	}
+}
+
+func discoverPCP() Interface {
+	gws := append(potentialGateways(), potentialGateways6()...)
+	found := make(chan *pcp, len(gws))
+	for i := range gws {
+		gw := gws[i]
+		go func() {
+			c := newPCP(gw, pcpPort)
+			if err := c.announce(2); err != nil {
+				found <- nil
+			} else {
+				found <- c
+			}
+		}()
+	}
+	// Like NAT-PMP discovery, give up after a short timeout.
+	// 与 NAT-PMP 发现一样，在短暂超时后放弃。
+	timeout := time.NewTimer(1 * time.Second)
+	defer timeout.Stop()
+	for range gws {
+		select {
+		case c := <-found:
+			if c != nil {
+				return c
+			}
+		case <-timeout.C:
+			return nil
+		}
+	}
+	return nil
+}
+
+// potentialGateways6 assumes that the router is the first address of the /64
+// prefix of global IPv6 addresses.
+//
+// potentialGateways6 假设路由器是全局 IPv6 地址 /64 前缀中的第一个地址。
+func potentialGateways6() (gws []net.IP) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+	for _, iface := range ifaces {
+		ifaddrs, err := iface.Addrs()
+		if err != nil {
+			return gws
+		}
+		for _, addr := range ifaddrs {
+			x, ok := addr.(*net.IPNet)
+			if !ok || x.IP.To4() != nil || !x.IP.IsGlobalUnicast() {
+				continue
+			}
+			ip := x.IP.Mask(net.CIDRMask(64, 128))
+			ip[15] = 1
+			gws = append(gws, ip)
+		}
+	}
+	return gws
+}
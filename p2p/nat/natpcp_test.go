@@ -0,0 +1,317 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package nat
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakePCP is a minimal in-process PCP server.
+type fakePCP struct {
+	t     *testing.T
+	conn  *net.UDPConn
+	extIP net.IP
+
+	mu          sync.Mutex
+	maxLifetime uint32
+	epochBase   uint32
+	epochStart  time.Time
+	mappings    map[pcpKey]*fakePCPMapping
+	requests    map[pcpKey]int
+}
+
+type fakePCPMapping struct {
+	nonce   [12]byte
+	extport uint16
+}
+
+func startFakePCP(t *testing.T, network, addr string, extIP net.IP) *fakePCP {
+	laddr, err := net.ResolveUDPAddr(network, addr)
+	if err != nil {
+		t.Skipf("can't resolve %s: %v", addr, err)
+	}
+	conn, err := net.ListenUDP(network, laddr)
+	if err != nil {
+		t.Skipf("can't listen on %s: %v", addr, err)
+	}
+	s := &fakePCP{
+		t:           t,
+		conn:        conn,
+		extIP:       extIP,
+		maxLifetime: 3600,
+		epochBase:   1000,
+		epochStart:  time.Now(),
+		mappings:    make(map[pcpKey]*fakePCPMapping),
+		requests:    make(map[pcpKey]int),
+	}
+	go s.serve()
+	t.Cleanup(func() { conn.Close() })
+	return s
+}
+
+// client returns a PCP client for the server.
+func (s *fakePCP) client() *pcp {
+	addr := s.conn.LocalAddr().(*net.UDPAddr)
+	return newPCP(addr.IP, addr.Port)
+}
+
+// restart simulates a server restart, which loses all mappings.
+func (s *fakePCP) restart() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mappings = make(map[pcpKey]*fakePCPMapping)
+	s.epochBase = 0
+	s.epochStart = time.Now()
+}
+
+func (s *fakePCP) mapping(key pcpKey) (*fakePCPMapping, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mappings[key], s.requests[key]
+}
+
+func (s *fakePCP) serve() {
+	buf := make([]byte, pcpMaxDatagram)
+	for {
+		n, from, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		if resp := s.handle(buf[:n], from); resp != nil {
+			s.conn.WriteToUDP(resp, from)
+		}
+	}
+}
+
+func (s *fakePCP) handle(req []byte, from *net.UDPAddr) []byte {
+	if len(req) < pcpHeaderSize || req[0] != pcpVersion {
+		s.t.Errorf("invalid request from %v", from)
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op := req[1]
+	resp := make([]byte, pcpHeaderSize, pcpHeaderSize+pcpMapSize)
+	resp[0] = pcpVersion
+	resp[1] = op | pcpResponse
+	binary.BigEndian.PutUint32(resp[8:12], s.epochBase+uint32(time.Since(s.epochStart)/time.Second))
+	if !net.IP(req[8:24]).Equal(from.IP) {
+		resp[3] = 12 // ADDRESS_MISMATCH
+		return resp
+	}
+	switch op {
+	case pcpOpAnnounce:
+		return resp
+	case pcpOpMap:
+	default:
+		resp[3] = 4 // UNSUPP_OPCODE
+		return resp
+	}
+	if len(req) < pcpHeaderSize+pcpMapSize {
+		resp[3] = 3 // MALFORMED_REQUEST
+		return resp
+	}
+	var (
+		body     = req[pcpHeaderSize:]
+		nonce    = [12]byte(body[0:12])
+		key      = pcpKey{body[12], binary.BigEndian.Uint16(body[16:18])}
+		extport  = binary.BigEndian.Uint16(body[18:20])
+		lifetime = binary.BigEndian.Uint32(req[4:8])
+	)
+	s.requests[key]++
+	if m := s.mappings[key]; m != nil && m.nonce != nonce {
+		resp[3] = 2 // NOT_AUTHORIZED
+		return resp
+	}
+	if lifetime == 0 {
+		delete(s.mappings, key)
+	} else {
+		if extport == 0 {
+			extport = key.intport
+		}
+		lifetime = min(lifetime, s.maxLifetime)
+		s.mappings[key] = &fakePCPMapping{nonce: nonce, extport: extport}
+	}
+	binary.BigEndian.PutUint32(resp[4:8], lifetime)
+	resp = resp[:pcpHeaderSize+pcpMapSize]
+	copy(resp[pcpHeaderSize:], body[:20])
+	binary.BigEndian.PutUint16(resp[pcpHeaderSize+18:], extport)
+	copy(resp[pcpHeaderSize+20:], s.extIP.To16())
+	return resp
+}
+
+func TestPCPMapping(t *testing.T) {
+	var (
+		server = startFakePCP(t, "udp4", "127.0.0.1:0", net.IP{33, 44, 55, 66})
+		client = server.client()
+		key    = pcpKey{6, 30303}
+	)
+	if err := client.announce(1); err != nil {
+		t.Fatalf("announce failed: %v", err)
+	}
+	port, err := client.AddMapping("TCP", 30304, 30303, "test", 10*time.Minute)
+	if err != nil {
+		t.Fatalf("AddMapping failed: %v", err)
+	}
+	if port != 30304 {
+		t.Errorf("wrong external port %d", port)
+	}
+	if m, _ := server.mapping(key); m == nil || m.extport != 30304 {
+		t.Fatalf("wrong server mapping %+v", m)
+	}
+	ip, err := client.ExternalIP()
+	if err != nil {
+		t.Fatalf("ExternalIP failed: %v", err)
+	}
+	if !ip.Equal(server.extIP) || len(ip) != net.IPv4len {
+		t.Errorf("wrong external IP %v", ip)
+	}
+	if err := client.DeleteMapping("TCP", 30304, 30303); err != nil {
+		t.Fatalf("DeleteMapping failed: %v", err)
+	}
+	if m, _ := server.mapping(key); m != nil {
+		t.Fatal("mapping not deleted")
+	}
+}
+
+func TestPCPExternalIPProbe(t *testing.T) {
+	server := startFakePCP(t, "udp4", "127.0.0.1:0", net.IP{33, 44, 55, 66})
+	ip, err := server.client().ExternalIP()
+	if err != nil {
+		t.Fatalf("ExternalIP failed: %v", err)
+	}
+	if !ip.Equal(server.extIP) {
+		t.Errorf("wrong external IP %v", ip)
+	}
+	// The probe mapping is removed again.
+	if m, n := server.mapping(pcpKey{17, pcpProbePort}); m != nil || n != 2 {
+		t.Errorf("probe mapping not cleaned up: %+v, %d requests", m, n)
+	}
+}
+
+func TestPCPRenewal(t *testing.T) {
+	var (
+		server = startFakePCP(t, "udp4", "127.0.0.1:0", net.IP{33, 44, 55, 66})
+		client = server.client()
+		key    = pcpKey{17, 30303}
+	)
+	server.mu.Lock()
+	server.maxLifetime = 2
+	server.mu.Unlock()
+
+	if _, err := client.AddMapping("UDP", 30303, 30303, "test", 10*time.Minute); err != nil {
+		t.Fatalf("AddMapping failed: %v", err)
+	}
+	// The server grants two seconds, so the mapping is renewed every second.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, n := server.mapping(key); n >= 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("mapping not renewed")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	client.DeleteMapping("UDP", 30303, 30303)
+	_, n := server.mapping(key)
+	time.Sleep(1500 * time.Millisecond)
+	if _, n2 := server.mapping(key); n2 != n {
+		t.Fatalf("deleted mapping renewed: %d requests after deletion", n2-n)
+	}
+}
+
+func TestPCPServerRestart(t *testing.T) {
+	var (
+		server = startFakePCP(t, "udp4", "127.0.0.1:0", net.IP{33, 44, 55, 66})
+		client = server.client()
+	)
+	if _, err := client.AddMapping("TCP", 30303, 30303, "test", 10*time.Minute); err != nil {
+		t.Fatalf("AddMapping failed: %v", err)
+	}
+	if _, err := client.AddMapping("UDP", 30303, 30303, "test", 10*time.Minute); err != nil {
+		t.Fatalf("AddMapping failed: %v", err)
+	}
+	// The restart is noticed on the next response, and all mappings are recreated.
+	server.restart()
+	if _, err := client.ExternalIP(); err != nil {
+		t.Fatalf("ExternalIP failed: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		tcp, _ := server.mapping(pcpKey{6, 30303})
+		udp, _ := server.mapping(pcpKey{17, 30303})
+		if tcp != nil && udp != nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("mappings not recreated after server restart")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestPCPIPv6(t *testing.T) {
+	var (
+		extIP  = net.ParseIP("2001:db8::1")
+		server = startFakePCP(t, "udp6", "[::1]:0", extIP)
+		client = server.client()
+	)
+	if _, err := client.AddMapping("TCP", 30303, 30303, "test", 10*time.Minute); err != nil {
+		t.Fatalf("AddMapping failed: %v", err)
+	}
+	ip, err := client.ExternalIP()
+	if err != nil {
+		t.Fatalf("ExternalIP failed: %v", err)
+	}
+	if !ip.Equal(extIP) {
+		t.Errorf("wrong external IP %v", ip)
+	}
+}
+
+func TestPCPErrors(t *testing.T) {
+	server := startFakePCP(t, "udp4", "127.0.0.1:0", net.IP{33, 44, 55, 66})
+
+	// Another client's mapping of the same port can't be changed.
+	if _, err := server.client().AddMapping("TCP", 30303, 30303, "test", time.Minute); err != nil {
+		t.Fatalf("AddMapping failed: %v", err)
+	}
+	_, err := server.client().AddMapping("TCP", 30303, 30303, "test", time.Minute)
+	if !errors.Is(err, pcpError(2)) {
+		t.Fatalf("wrong error: %v", err)
+	}
+	if err.Error() != "PCP error NOT_AUTHORIZED" {
+		t.Fatalf("wrong error message: %q", err)
+	}
+
+	// A server which doesn't respond times out.
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IP{127, 0, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	silent := newPCP(net.IP{127, 0, 0, 1}, conn.LocalAddr().(*net.UDPAddr).Port)
+	if err := silent.announce(1); err == nil {
+		t.Fatal("no error for silent server")
+	}
+}
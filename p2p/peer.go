@@ -166,6 +166,9 @@ type Peer struct {
 	// events receives message send / receive events if set
 	// events 如果设置，则接收消息发送/接收事件
 	events *event.Feed
+	// reputation tracks the peer's reputation score if set
+	// reputation 如果设置，则跟踪对等方的信誉分数
+	reputation *reputation
 	// 用于测试
 	testPipe *MsgPipeRW // for testing
 }
@@ -295,6 +298,12 @@ func (p *Peer) Inbound() bool {
 	return p.rw.is(inboundConn) // 检查是否为入站
 }
 
+// Trusted returns true if the peer is a trusted peer
+// Trusted 如果对等方是受信任的对等方，则返回 true
+func (p *Peer) Trusted() bool {
+	return p.rw.is(trustedConn)
+}
+
 func newPeer(log log.Logger, conn *conn, protocols []Protocol) *Peer {
 	protomap := matchProtocols(protocols, conn.caps, conn)
 	p := &Peer{
@@ -425,9 +434,11 @@ func (p *Peer) handle(msg Msg) error {
 		if err != nil {
 			return fmt.Errorf("msg code out of range: %v", msg.Code) // 如果超出范围则返回错误
 		}
+		proto.traffic.ingress[msg.Code-proto.offset].add(msg.meterSize, msg.Size)
 		if metrics.Enabled() { // 如果启用了指标
 			m := fmt.Sprintf("%s/%s/%d/%#02x", ingressMeterName, proto.Name, proto.Version, msg.Code-proto.offset)
 			metrics.GetOrRegisterMeter(m, nil).Mark(int64(msg.meterSize))
+			metrics.GetOrRegisterMeter(m+uncompressedMeterSuffix, nil).Mark(int64(msg.Size))
 			metrics.GetOrRegisterMeter(m+"/packets", nil).Mark(1)
 		}
 		select {
@@ -493,7 +504,7 @@ outer:
 				}
 				// Assign the new match
 				// 分配新的匹配
-				result[cap.Name] = &protoRW{Protocol: proto, offset: offset, in: make(chan Msg), w: rw}
+				result[cap.Name] = &protoRW{Protocol: proto, offset: offset, in: make(chan Msg), w: rw, traffic: newProtoTraffic(proto.Length)}
 				offset += proto.Length
 				continue outer
 			}
@@ -549,6 +560,9 @@ type protoRW struct {
 	werr   chan<- error // for write results 用于写入结果
 	offset uint64
 	w      MsgWriter
+
+	traffic *protoTraffic // per-message traffic counters 每条消息的流量计数器
+	serve   *serveLimiter // serving bandwidth limit, nil if unlimited 服务带宽限制，如果不受限制则为 nil
 }
 
 func (rw *protoRW) WriteMsg(msg Msg) (err error) {
@@ -557,7 +571,15 @@ func (rw *protoRW) WriteMsg(msg Msg) (err error) {
 	}
 	msg.meterCap = rw.cap()
 	msg.meterCode = msg.Code
+	msg.meterEgress = &rw.traffic.egress[msg.Code]
 
+	// Served data waits for the bandwidth limit before taking the write slot.
+	// 提供的数据在获取写入槽之前等待带宽限制。
+	if rw.serve != nil && rw.isServeMessage(msg.Code) {
+		if err := rw.serve.wait(int(msg.Size), rw.closed); err != nil {
+			return err
+		}
+	}
 	msg.Code += rw.offset
 
 	select {
@@ -612,6 +634,10 @@ type PeerInfo struct {
 		Trusted       bool   `json:"trusted"`
 		Static        bool   `json:"static"`
 	} `json:"network"`
+	// 对等方的信誉分数
+	Reputation float64 `json:"reputation"` // Reputation score of the peer
+	// 每个协议的流量统计
+	Traffic map[string]*ProtocolTraffic `json:"traffic"` // Traffic statistics by protocol
 	// 子协议特定的元数据字段
 	Protocols map[string]interface{} `json:"protocols"` // Sub-protocol specific metadata fields
 }
@@ -642,6 +668,8 @@ func (p *Peer) Info() *PeerInfo {
 	info.Network.Inbound = p.rw.is(inboundConn)          // 设置入站标志
 	info.Network.Trusted = p.rw.is(trustedConn)          // 设置受信任标志
 	info.Network.Static = p.rw.is(staticDialedConn)      // 设置静态标志
+	info.Reputation = p.Reputation()                     // 设置信誉分数
+	info.Traffic = p.Traffic()                           // 设置流量统计
 
 	// Gather all the running protocol infos
 	// 收集所有正在运行的协议信息
@@ -85,7 +85,7 @@ func newNode(id enode.ID, addr string) *enode.Node {
 	return enode.SignNull(&r, id)
 }
 
-func testPeer(protos []Protocol) (func(), *conn, *Peer, <-chan error) {
+func testPeer(protos []Protocol, setup ...func(*Peer)) (func(), *conn, *Peer, <-chan error) {
 	var (
 		fd1, fd2   = net.Pipe()
 		key1, key2 = newkey(), newkey()
@@ -101,6 +101,9 @@ func testPeer(protos []Protocol) (func(), *conn, *Peer, <-chan error) {
 	}
 
 	peer := newPeer(log.Root(), c1, protos)
+	for _, fn := range setup {
+		fn(peer)
+	}
 	errc := make(chan error, 1)
 	go func() {
 		_, err := peer.run()
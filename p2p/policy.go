@@ -0,0 +1,570 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/p2p/netutil"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// 对等方策略 (Peer Policy)
+//
+// 对等方策略是一个有序的允许/拒绝规则列表，外加一个默认动作。第一个匹配的规则决定结果。
+// 规则可以匹配远程 IP 的 CIDR、节点 ID、连接方向以及节点记录（ENR）中的字段。
+// 策略在接受入站连接时、拨号前以及两次握手之后进行评估。在较早的阶段，某些条件（例如客户端名称）
+// 还无法确定，只有当结果必然是拒绝时才会拒绝连接。
+// 策略可以从 JSON 文件加载，文件更改时会自动重新加载；通过 RPC 添加的规则优先于文件中的规则，并在重新加载后保留。
+
+const (
+	// policyReloadInterval is how often the policy file is checked for changes.
+	// policyReloadInterval 是检查策略文件更改的频率。
+	policyReloadInterval = 5 * time.Second
+
+	policyAllow = "allow"
+	policyDeny  = "deny"
+
+	policyInbound  = "inbound"
+	policyOutbound = "outbound"
+)
+
+// PeerRule is a rule of the peer policy. All conditions which are set must match
+// for the rule to apply.
+//
+// PeerRule 是对等方策略的一条规则。所有已设置的条件都必须匹配，规则才会生效。
+type PeerRule struct {
+	Name      string `json:"name,omitempty"`
+	Action    string `json:"action"`              // "allow" or "deny"
+	CIDR      string `json:"cidr,omitempty"`      // network of the remote IP // 远程 IP 所在的网络
+	ID        string `json:"id,omitempty"`        // hex node ID // 十六进制节点 ID
+	Direction string `json:"direction,omitempty"` // "inbound" or "outbound"
+	ForkHash  string `json:"forkHash,omitempty"`  // fork hash of the "eth" ENR entry // "eth" ENR 条目的分叉哈希
+	Client    string `json:"client,omitempty"`    // prefix of the client name, case-insensitive // 客户端名称前缀，不区分大小写
+	ENRKey    string `json:"enrKey,omitempty"`    // key which must be present in the ENR // ENR 中必须存在的键
+}
+
+// PeerRuleInfo is a rule of the peer policy with the number of connections it
+// decided.
+//
+// PeerRuleInfo 是对等方策略的一条规则及其决定的连接数量。
+type PeerRuleInfo struct {
+	PeerRule
+	Source  string `json:"source"` // "file" or "rpc"
+	Matches uint64 `json:"matches"`
+}
+
+// PeerPolicyInfo describes the peer policy.
+// PeerPolicyInfo 描述对等方策略。
+type PeerPolicyInfo struct {
+	File           string         `json:"file,omitempty"`
+	Default        string         `json:"default"`
+	DefaultMatches uint64         `json:"defaultMatches"`
+	Rules          []PeerRuleInfo `json:"rules"`
+}
+
+// peerPolicyFile is the format of the policy file.
+// peerPolicyFile 是策略文件的格式。
+type peerPolicyFile struct {
+	Default string     `json:"default"`
+	Rules   []PeerRule `json:"rules"`
+}
+
+// policyRule is a parsed PeerRule.
+// policyRule 是已解析的 PeerRule。
+type policyRule struct {
+	PeerRule
+	source  string
+	allow   bool
+	prefix  netip.Prefix
+	id      *enode.ID
+	inbound *bool
+	fork    *[4]byte
+	client  string
+	matches atomic.Uint64
+}
+
+func newPolicyRule(r PeerRule, source string) (*policyRule, error) {
+	pr := &policyRule{PeerRule: r, source: source}
+	switch r.Action {
+	case policyAllow:
+		pr.allow = true
+	case policyDeny:
+	default:
+		return nil, fmt.Errorf("invalid action %q", r.Action)
+	}
+	if r.CIDR != "" {
+		prefix, err := netip.ParsePrefix(r.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr: %v", err)
+		}
+		pr.prefix = prefix.Masked()
+	}
+	if r.ID != "" {
+		id, err := enode.ParseID(r.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id: %v", err)
+		}
+		pr.id = &id
+	}
+	switch r.Direction {
+	case "":
+	case policyInbound, policyOutbound:
+		inbound := r.Direction == policyInbound
+		pr.inbound = &inbound
+	default:
+		return nil, fmt.Errorf("invalid direction %q", r.Direction)
+	}
+	if r.ForkHash != "" {
+		b, err := hexutil.Decode(r.ForkHash)
+		if err != nil || len(b) != 4 {
+			return nil, fmt.Errorf("invalid fork hash %q", r.ForkHash)
+		}
+		pr.fork = new([4]byte)
+		copy(pr.fork[:], b)
+	}
+	pr.client = strings.ToLower(r.Client)
+	return pr, nil
+}
+
+// policyTarget holds what is known about a connection at the time the policy
+// is evaluated.
+//
+// policyTarget 保存评估策略时关于连接的已知信息。
+type policyTarget struct {
+	ip      netip.Addr  // remote IP, invalid if unknown // 远程 IP，未知时无效
+	id      *enode.ID   // node ID, nil before the encryption handshake // 节点 ID，加密握手前为 nil
+	node    *enode.Node // node record, nil if unknown // 节点记录，未知时为 nil
+	inbound bool
+	name    string // devp2p client name // devp2p 客户端名称
+	final   bool   // set after the protocol handshake, when everything is known // 协议握手后设置，此时一切已知
+}
+
+// matchResult is the outcome of matching a rule condition. Conditions which
+// can't be decided yet result in matchUnknown.
+//
+// matchResult 是匹配规则条件的结果。尚无法确定的条件结果为 matchUnknown。
+type matchResult int
+
+const (
+	matchNo matchResult = iota
+	matchYes
+	matchUnknown
+)
+
+func (m matchResult) and(other matchResult) matchResult {
+	if m == matchNo || other == matchNo {
+		return matchNo
+	}
+	if m == matchUnknown || other == matchUnknown {
+		return matchUnknown
+	}
+	return matchYes
+}
+
+func matchBool(ok bool) matchResult {
+	if ok {
+		return matchYes
+	}
+	return matchNo
+}
+
+// ethENREntry is the "eth" entry of a node record, decoded without depending on
+// package eth.
+//
+// ethENREntry 是节点记录的 "eth" 条目，在不依赖 eth 包的情况下解码。
+type ethENREntry struct {
+	ForkID struct {
+		Hash [4]byte
+		Next uint64
+	}
+	Rest []rlp.RawValue `rlp:"tail"`
+}
+
+func (ethENREntry) ENRKey() string { return "eth" }
+
+// clientENREntry is the "client" entry of a node record (EIP-7636).
+// clientENREntry 是节点记录的 "client" 条目（EIP-7636）。
+type clientENREntry struct {
+	Name string
+	Rest []rlp.RawValue `rlp:"tail"`
+}
+
+func (clientENREntry) ENRKey() string { return "client" }
+
+// match reports whether the rule applies to the target.
+// match 报告规则是否适用于目标。
+func (r *policyRule) match(t *policyTarget) matchResult {
+	m := matchYes
+	if r.prefix.IsValid() {
+		switch {
+		case t.ip.IsValid():
+			m = m.and(matchBool(r.prefix.Contains(t.ip.Unmap())))
+		case t.final:
+			m = matchNo
+		default:
+			m = m.and(matchUnknown)
+		}
+	}
+	if r.id != nil {
+		if t.id == nil {
+			m = m.and(matchUnknown)
+		} else {
+			m = m.and(matchBool(*t.id == *r.id))
+		}
+	}
+	if r.inbound != nil {
+		m = m.and(matchBool(*r.inbound == t.inbound))
+	}
+	if r.fork != nil {
+		var entry ethENREntry
+		switch {
+		case t.node != nil:
+			m = m.and(matchBool(t.node.Load(&entry) == nil && entry.ForkID.Hash == *r.fork))
+		case t.id == nil:
+			m = m.and(matchUnknown)
+		default:
+			m = matchNo
+		}
+	}
+	if r.ENRKey != "" {
+		switch {
+		case t.node != nil:
+			m = m.and(matchBool(t.node.Load(enr.WithEntry(r.ENRKey, new(rlp.RawValue))) == nil))
+		case t.id == nil:
+			m = m.and(matchUnknown)
+		default:
+			m = matchNo
+		}
+	}
+	if r.client != "" {
+		m = m.and(r.matchClient(t))
+	}
+	return m
+}
+
+// matchClient matches the client name of the devp2p handshake, or of the ENR
+// before the handshake.
+//
+// matchClient 匹配 devp2p 握手中的客户端名称，握手前则匹配 ENR 中的客户端名称。
+func (r *policyRule) matchClient(t *policyTarget) matchResult {
+	if t.final {
+		return matchBool(strings.HasPrefix(strings.ToLower(t.name), r.client))
+	}
+	var entry clientENREntry
+	if t.node != nil && t.node.Load(&entry) == nil {
+		return matchBool(strings.HasPrefix(strings.ToLower(entry.Name), r.client))
+	}
+	return matchUnknown
+}
+
+// peerPolicy is the rule set evaluated for peer connections.
+// peerPolicy 是为对等方连接评估的规则集。
+type peerPolicy struct {
+	file string
+
+	lock           sync.RWMutex
+	allow          bool          // default action // 默认动作
+	defaultMatches atomic.Uint64 // connections decided by the default action // 由默认动作决定的连接数
+	fileRules      []*policyRule
+	rpcRules       []*policyRule
+	fileStat       os.FileInfo // file state at last load, for change detection // 上次加载时的文件状态，用于检测更改
+}
+
+// newPeerPolicy creates a policy which allows all connections, loading the
+// rules from the file if one is given.
+//
+// newPeerPolicy 创建一个允许所有连接的策略；如果给定了文件，则从文件加载规则。
+func newPeerPolicy(file string) (*peerPolicy, error) {
+	p := &peerPolicy{file: file, allow: true}
+	if file != "" {
+		if _, err := p.reload(); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// reload reads the policy file if it changed since the last load. It returns
+// whether the policy was changed.
+//
+// reload 在策略文件自上次加载后发生更改时读取它。返回策略是否已更改。
+func (p *peerPolicy) reload() (bool, error) {
+	stat, err := os.Stat(p.file)
+	if err != nil {
+		return false, err
+	}
+	p.lock.RLock()
+	last := p.fileStat
+	p.lock.RUnlock()
+	if last != nil && stat.ModTime().Equal(last.ModTime()) && stat.Size() == last.Size() {
+		return false, nil
+	}
+	data, err := os.ReadFile(p.file)
+	if err != nil {
+		return false, err
+	}
+	dec, rules, err := parsePeerPolicy(data)
+	if err != nil {
+		// Remember the invalid file, so it isn't reported again until it
+		// changes. The previous rules stay active.
+		// 记住无效的文件，以便在其更改之前不会再次报告。先前的规则保持有效。
+		p.lock.Lock()
+		p.fileStat = stat
+		p.lock.Unlock()
+		return false, fmt.Errorf("invalid peer policy file %s: %v", p.file, err)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.allow = dec.Default != policyDeny
+	p.fileRules = rules
+	p.fileStat = stat
+	return true, nil
+}
+
+// parsePeerPolicy decodes the content of a policy file.
+// parsePeerPolicy 解码策略文件的内容。
+func parsePeerPolicy(data []byte) (*peerPolicyFile, []*policyRule, error) {
+	var (
+		dec   peerPolicyFile
+		rules []*policyRule
+	)
+	jd := json.NewDecoder(bytes.NewReader(data))
+	jd.DisallowUnknownFields()
+	if err := jd.Decode(&dec); err != nil {
+		return nil, nil, err
+	}
+	if dec.Default != "" && dec.Default != policyAllow && dec.Default != policyDeny {
+		return nil, nil, fmt.Errorf("invalid default action %q", dec.Default)
+	}
+	for i, r := range dec.Rules {
+		rule, err := newPolicyRule(r, "file")
+		if err != nil {
+			return nil, nil, fmt.Errorf("rule %d: %v", i, err)
+		}
+		rules = append(rules, rule)
+	}
+	return &dec, rules, nil
+}
+
+// add appends a rule. Rules added at runtime take precedence over the rules
+// of the policy file.
+//
+// add 追加一条规则。运行时添加的规则优先于策略文件中的规则。
+func (p *peerPolicy) add(r PeerRule) error {
+	rule, err := newPolicyRule(r, "rpc")
+	if err != nil {
+		return err
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.rpcRules = append(p.rpcRules, rule)
+	return nil
+}
+
+// evaluate returns the action of the policy for the target, together with the
+// match counter of the deciding rule. The counter is nil if the outcome is not
+// decided yet.
+//
+// evaluate 返回策略对目标的动作以及决定规则的匹配计数器。如果结果尚未确定，计数器为 nil。
+func (p *peerPolicy) evaluate(t *policyTarget) (bool, *atomic.Uint64) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	for _, rules := range [][]*policyRule{p.rpcRules, p.fileRules} {
+		for _, r := range rules {
+			switch r.match(t) {
+			case matchYes:
+				return r.allow, &r.matches
+			case matchUnknown:
+				// The rule may or may not apply. If it allows the connection,
+				// it can't be denied yet.
+				// 规则可能适用也可能不适用。如果它允许该连接，则暂时不能拒绝。
+				if r.allow {
+					return true, nil
+				}
+			}
+		}
+	}
+	return p.allow, &p.defaultMatches
+}
+
+// check evaluates the policy for the target, returning errPolicyDenied if the
+// connection must be rejected. Before the final stage, connections are only
+// rejected if every possible outcome denies them. Allowed connections are only
+// counted at the final stage.
+//
+// check 为目标评估策略，如果必须拒绝连接则返回 errPolicyDenied。在最终阶段之前，
+// 只有在所有可能的结果都拒绝连接时才会拒绝它。允许的连接仅在最终阶段计数。
+func (p *peerPolicy) check(t *policyTarget) error {
+	allow, counter := p.evaluate(t)
+	if counter != nil && (!allow || t.final) {
+		counter.Add(1)
+	}
+	if !allow {
+		return errPolicyDenied
+	}
+	return nil
+}
+
+// info returns the rules and their match counters.
+// info 返回规则及其匹配计数器。
+func (p *peerPolicy) info() *PeerPolicyInfo {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	info := &PeerPolicyInfo{File: p.file, Default: policyAllow, DefaultMatches: p.defaultMatches.Load(), Rules: []PeerRuleInfo{}}
+	if !p.allow {
+		info.Default = policyDeny
+	}
+	for _, rules := range [][]*policyRule{p.rpcRules, p.fileRules} {
+		for _, r := range rules {
+			info.Rules = append(info.Rules, PeerRuleInfo{PeerRule: r.PeerRule, Source: r.source, Matches: r.matches.Load()})
+		}
+	}
+	return info
+}
+
+// policyNode returns the most recent record of n known to the node database,
+// which is used to match ENR fields. Inbound connections don't have a record.
+//
+// policyNode 返回节点数据库中已知的 n 的最新记录，用于匹配 ENR 字段。入站连接没有记录。
+func (srv *Server) policyNode(n *enode.Node) *enode.Node {
+	if db := srv.nodedb.Node(n.ID()); db != nil && db.Seq() > n.Seq() {
+		return db
+	}
+	return n
+}
+
+// connPolicyTarget returns the policy target of a connection after the
+// encryption handshake.
+//
+// connPolicyTarget 返回加密握手后连接的策略目标。
+func (srv *Server) connPolicyTarget(c *conn) *policyTarget {
+	id := c.node.ID()
+	t := &policyTarget{
+		id:      &id,
+		node:    srv.policyNode(c.node),
+		inbound: c.is(inboundConn),
+		name:    c.name,
+	}
+	if c.fd != nil {
+		t.ip = netutil.AddrAddr(c.fd.RemoteAddr())
+	}
+	return t
+}
+
+// checkDialPolicy evaluates the peer policy for a dial candidate.
+// checkDialPolicy 为拨号候选节点评估对等方策略。
+func (srv *Server) checkDialPolicy(n *enode.Node) error {
+	id := n.ID()
+	return srv.policy.check(&policyTarget{ip: n.IPAddr(), id: &id, node: srv.policyNode(n)})
+}
+
+// AddPeerRule adds a rule to the peer policy. Connected peers which are denied
+// by the new policy are disconnected.
+//
+// AddPeerRule 向对等方策略添加一条规则。新策略拒绝的已连接对等方将被断开。
+func (srv *Server) AddPeerRule(rule PeerRule) error {
+	if srv.policy == nil {
+		return errServerStopped
+	}
+	if err := srv.policy.add(rule); err != nil {
+		return err
+	}
+	srv.log.Info("Added peer policy rule", "name", rule.Name, "action", rule.Action)
+	srv.applyPeerPolicy()
+	return nil
+}
+
+// PeerPolicy returns the rules of the peer policy and their match counters.
+// PeerPolicy 返回对等方策略的规则及其匹配计数器。
+func (srv *Server) PeerPolicy() *PeerPolicyInfo {
+	if srv.policy == nil {
+		return nil
+	}
+	return srv.policy.info()
+}
+
+// ReloadPeerPolicy re-reads the peer policy file if it has changed.
+// ReloadPeerPolicy 在对等方策略文件更改时重新读取它。
+func (srv *Server) ReloadPeerPolicy() error {
+	if srv.policy == nil || srv.policy.file == "" {
+		return nil
+	}
+	changed, err := srv.policy.reload()
+	if err != nil || !changed {
+		return err
+	}
+	srv.log.Info("Reloaded peer policy", "file", srv.policy.file)
+	srv.applyPeerPolicy()
+	return nil
+}
+
+// applyPeerPolicy disconnects the peers denied by the current policy and lets
+// the dialer reconsider the static nodes.
+//
+// applyPeerPolicy 断开当前策略拒绝的对等方，并让拨号器重新考虑静态节点。
+func (srv *Server) applyPeerPolicy() {
+	srv.doPeerOp(func(peers map[enode.ID]*Peer) {
+		for _, p := range peers {
+			// Connected peers were counted when they were added, so only
+			// denials are counted here.
+			// 已连接的对等方在添加时已被计数，因此这里只计算拒绝。
+			t := srv.connPolicyTarget(p.rw)
+			t.final = true
+			if allow, counter := srv.policy.evaluate(t); !allow {
+				counter.Add(1)
+				p.log.Debug("Disconnecting peer denied by policy")
+				p.Disconnect(DiscUselessPeer)
+			}
+		}
+	})
+	if srv.dialsched != nil {
+		srv.dialsched.policyChanged()
+	}
+}
+
+// policyLoop reloads the policy file when it changes.
+// policyLoop 在策略文件更改时重新加载它。
+func (srv *Server) policyLoop() {
+	defer srv.loopWG.Done()
+
+	ticker := time.NewTicker(policyReloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := srv.ReloadPeerPolicy(); err != nil {
+				srv.log.Warn("Failed to reload peer policy", "file", srv.policy.file, "err", err)
+			}
+		case <-srv.quit:
+			return
+		}
+	}
+}
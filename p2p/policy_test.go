@@ -0,0 +1,314 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"crypto/ecdsa"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+)
+
+// policyTestNode creates a node with the given "eth" fork hash and client name
+// in its record.
+func policyTestNode(t *testing.T, fork [4]byte, client string) *enode.Node {
+	var r enr.Record
+	entry := ethENREntry{}
+	entry.ForkID.Hash = fork
+	r.Set(entry)
+	if client != "" {
+		r.Set(clientENREntry{Name: client})
+	}
+	r.Set(enr.IPv4Addr(netip.MustParseAddr("10.0.0.1")))
+	r.Set(enr.TCP(30303))
+	if err := enode.SignV4(&r, newkey()); err != nil {
+		t.Fatal(err)
+	}
+	n, err := enode.New(enode.ValidSchemes, &r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestPeerPolicyCheck(t *testing.T) {
+	var (
+		geth  = policyTestNode(t, [4]byte{0xfc, 0x64, 0xec, 0x04}, "Geth")
+		other = policyTestNode(t, [4]byte{1, 2, 3, 4}, "")
+		gid   = geth.ID()
+		oid   = other.ID()
+		lan   = netip.MustParseAddr("10.0.0.1")
+		wan   = netip.MustParseAddr("8.8.8.8")
+	)
+	tests := []struct {
+		name    string
+		deny    bool // default action
+		rules   []PeerRule
+		target  policyTarget
+		wantErr bool
+	}{
+		{
+			name:   "empty policy allows",
+			target: policyTarget{ip: wan, final: true},
+		},
+		{
+			name:    "default deny",
+			deny:    true,
+			target:  policyTarget{ip: wan, final: true},
+			wantErr: true,
+		},
+		{
+			name:   "cidr allow",
+			deny:   true,
+			rules:  []PeerRule{{Action: "allow", CIDR: "10.0.0.0/8"}},
+			target: policyTarget{ip: lan, id: &gid, final: true},
+		},
+		{
+			name:    "cidr allow, other network",
+			deny:    true,
+			rules:   []PeerRule{{Action: "allow", CIDR: "10.0.0.0/8"}},
+			target:  policyTarget{ip: wan, id: &gid, final: true},
+			wantErr: true,
+		},
+		{
+			name:    "first match wins",
+			rules:   []PeerRule{{Action: "deny", ID: gid.String()}, {Action: "allow", CIDR: "10.0.0.0/8"}},
+			target:  policyTarget{ip: lan, id: &gid, final: true},
+			wantErr: true,
+		},
+		{
+			name:    "direction",
+			rules:   []PeerRule{{Action: "deny", Direction: "inbound"}},
+			target:  policyTarget{ip: wan, inbound: true, final: true},
+			wantErr: true,
+		},
+		{
+			name:   "direction, other direction",
+			rules:  []PeerRule{{Action: "deny", Direction: "inbound"}},
+			target: policyTarget{ip: wan, final: true},
+		},
+		{
+			name:   "fork hash",
+			deny:   true,
+			rules:  []PeerRule{{Action: "allow", ForkHash: "0xfc64ec04"}},
+			target: policyTarget{id: &gid, node: geth},
+		},
+		{
+			name:    "fork hash, other fork",
+			deny:    true,
+			rules:   []PeerRule{{Action: "allow", ForkHash: "0xfc64ec04"}},
+			target:  policyTarget{id: &oid, node: other},
+			wantErr: true,
+		},
+		{
+			name:    "enr key",
+			rules:   []PeerRule{{Action: "deny", ENRKey: "client"}},
+			target:  policyTarget{id: &gid, node: geth},
+			wantErr: true,
+		},
+		{
+			name:   "client from enr before handshake",
+			deny:   true,
+			rules:  []PeerRule{{Action: "allow", Client: "geth"}},
+			target: policyTarget{id: &gid, node: geth},
+		},
+		{
+			name:   "client unknown before handshake",
+			deny:   true,
+			rules:  []PeerRule{{Action: "allow", Client: "geth"}},
+			target: policyTarget{id: &oid, node: other},
+		},
+		{
+			name:    "client from handshake",
+			deny:    true,
+			rules:   []PeerRule{{Action: "allow", Client: "geth"}},
+			target:  policyTarget{id: &oid, node: other, name: "Nethermind/v1.0", final: true},
+			wantErr: true,
+		},
+		{
+			name:   "id unknown on accept",
+			deny:   true,
+			rules:  []PeerRule{{Action: "allow", ID: gid.String()}},
+			target: policyTarget{ip: wan, inbound: true},
+		},
+		{
+			name:    "cidr deny on accept",
+			rules:   []PeerRule{{Action: "deny", CIDR: "8.8.0.0/16"}, {Action: "allow", ID: gid.String()}},
+			target:  policyTarget{ip: wan, inbound: true},
+			wantErr: true,
+		},
+		{
+			name:   "unknown deny doesn't decide",
+			rules:  []PeerRule{{Action: "deny", Client: "geth"}},
+			target: policyTarget{id: &oid, node: other},
+		},
+	}
+	for _, test := range tests {
+		policy, _ := newPeerPolicy("")
+		policy.allow = !test.deny
+		for _, r := range test.rules {
+			if err := policy.add(r); err != nil {
+				t.Fatalf("%s: invalid rule: %v", test.name, err)
+			}
+		}
+		err := policy.check(&test.target)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: wrong result: have %v, want error %v", test.name, err, test.wantErr)
+		}
+	}
+}
+
+func TestPeerPolicyInvalidRule(t *testing.T) {
+	policy, _ := newPeerPolicy("")
+	for _, r := range []PeerRule{
+		{Action: "reject"},
+		{Action: "deny", CIDR: "10.0.0.1"},
+		{Action: "deny", ID: "0x1234"},
+		{Action: "deny", Direction: "both"},
+		{Action: "deny", ForkHash: "0x1234"},
+	} {
+		if err := policy.add(r); err == nil {
+			t.Errorf("no error for invalid rule %+v", r)
+		}
+	}
+}
+
+func TestPeerPolicyReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.json")
+	write := func(content string, mtime time.Time) {
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var (
+		now    = time.Now()
+		id     = enode.ID{1}
+		target = &policyTarget{ip: netip.MustParseAddr("10.0.0.1"), id: &id, final: true}
+	)
+	write(`{"default": "deny", "rules": [{"name": "lan", "action": "allow", "cidr": "10.0.0.0/8"}]}`, now)
+	policy, err := newPeerPolicy(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := policy.check(target); err != nil {
+		t.Fatalf("connection denied: %v", err)
+	}
+	if changed, err := policy.reload(); changed || err != nil {
+		t.Fatalf("unchanged file reloaded: changed %v, err %v", changed, err)
+	}
+
+	// Rules added at runtime take precedence and are kept across reloads.
+	if err := policy.add(PeerRule{Name: "ban", Action: "deny", ID: id.String()}); err != nil {
+		t.Fatal(err)
+	}
+	write(`{"default": "allow", "rules": []}`, now.Add(time.Second))
+	if changed, err := policy.reload(); !changed || err != nil {
+		t.Fatalf("changed file not reloaded: changed %v, err %v", changed, err)
+	}
+	if err := policy.check(target); err != errPolicyDenied {
+		t.Fatalf("wrong error: %v", err)
+	}
+	info := policy.info()
+	if info.Default != "allow" || len(info.Rules) != 1 || info.Rules[0].Name != "ban" || info.Rules[0].Matches != 1 {
+		t.Fatalf("wrong policy info: %+v", info)
+	}
+
+	// Invalid files are rejected and the previous rules stay active.
+	write(`{"default": "deny", "rules": [{"action": "deny", "cidr": "invalid"}]}`, now.Add(2*time.Second))
+	if _, err := policy.reload(); err == nil {
+		t.Fatal("no error for invalid policy file")
+	}
+	if info := policy.info(); info.Default != "allow" {
+		t.Fatalf("invalid policy file applied: %+v", info)
+	}
+	if _, err := policy.reload(); err != nil {
+		t.Fatalf("unchanged invalid file reported again: %v", err)
+	}
+}
+
+func TestServerPeerPolicy(t *testing.T) {
+	var (
+		srvkey    = newkey()
+		clientkey = newkey()
+		clientid  = enode.PubkeyToIDV4(&clientkey.PublicKey)
+	)
+	newTransport := func(name string) *setupTransport {
+		return &setupTransport{
+			pubkey: &clientkey.PublicKey,
+			phs: protoHandshake{
+				ID:   crypto.FromECDSAPub(&clientkey.PublicKey)[1:],
+				Name: name,
+			},
+		}
+	}
+	var tp *setupTransport
+	srv := &Server{
+		Config: Config{
+			PrivateKey:  srvkey,
+			MaxPeers:    10,
+			NoDial:      true,
+			NoDiscovery: true,
+			Logger:      testlog.Logger(t, log.LvlTrace),
+		},
+		newTransport: func(fd net.Conn, dialDest *ecdsa.PublicKey) transport { return tp },
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatalf("couldn't start server: %v", err)
+	}
+	defer srv.Stop()
+
+	// Connections denied by node ID are rejected before the protocol handshake.
+	if err := srv.AddPeerRule(PeerRule{Name: "id", Action: "deny", ID: clientid.String()}); err != nil {
+		t.Fatal(err)
+	}
+	tp = newTransport("test")
+	conn, _ := net.Pipe()
+	srv.SetupConn(conn, inboundConn, nil)
+	conn.Close()
+	if tp.closeErr != DiscUselessPeer || tp.calls != "doEncHandshake,close," {
+		t.Fatalf("wrong result: err %v, calls %q", tp.closeErr, tp.calls)
+	}
+
+	// Connections denied by client name are rejected after it.
+	srv.policy.rpcRules = nil
+	if err := srv.AddPeerRule(PeerRule{Name: "client", Action: "deny", Client: "bad"}); err != nil {
+		t.Fatal(err)
+	}
+	tp = newTransport("BadClient/v1.0.0")
+	conn, _ = net.Pipe()
+	srv.SetupConn(conn, inboundConn, nil)
+	conn.Close()
+	if tp.closeErr != DiscUselessPeer || tp.calls != "doEncHandshake,doProtoHandshake,close," {
+		t.Fatalf("wrong result: err %v, calls %q", tp.closeErr, tp.calls)
+	}
+	info := srv.PeerPolicy()
+	if len(info.Rules) != 1 || info.Rules[0].Matches != 1 {
+		t.Fatalf("wrong policy info: %+v", info)
+	}
+}
@@ -86,6 +86,14 @@ type Protocol struct {
 	// Attributes contains protocol specific information for the node record.
 	// Attributes 包含节点记录的协议特定信息。
 	Attributes []enr.Entry
+
+	// ServeMessages lists the message codes which carry served data, such as
+	// responses to chain data requests. Sending these messages counts against
+	// the serving bandwidth limit of the peer (Config.PeerServeLimit).
+	//
+	// ServeMessages 列出携带提供数据的消息代码，例如对链数据请求的响应。
+	// 发送这些消息会计入对等方的服务带宽限制（Config.PeerServeLimit）。
+	ServeMessages []uint64
 }
 
 // 返回协议的能力
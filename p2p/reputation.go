@@ -0,0 +1,281 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// 对等方信誉 (Peer Reputation)
+//
+// 协议处理程序将对等方的行为（无用响应、请求超时、无效区块、良好吞吐量）报告给服务器。
+// 每个事件按其权重调整对等方的分数，分数随时间向零衰减，因此过去的不良行为最终会被原谅。
+// 分数保存在内存中，并定期按节点 ID 持久化到节点数据库中，因此在重启后仍然有效。
+// 拨号器跳过信誉过低的候选节点并优先拨号信誉较高的节点；服务器在满员时驱逐信誉最低的对等方。
+
+const (
+	// reputationHalfLife is the time it takes for a reputation score to decay
+	// to half of its value.
+	// reputationHalfLife 是信誉分数衰减到其一半值所需的时间。
+	reputationHalfLife = time.Hour
+
+	// reputationLimit bounds the absolute value of a reputation score.
+	// reputationLimit 限制信誉分数的绝对值。
+	reputationLimit = 1000
+
+	// dialMinReputation is the score below which discovered nodes are not dialed.
+	// dialMinReputation 是低于该分数的已发现节点不会被拨号的阈值。
+	dialMinReputation = -100
+
+	// evictionMargin is how much the score of a new connection must exceed the
+	// score of the worst connected peer for that peer to be evicted when full.
+	// evictionMargin 是在满员时新连接的分数必须超过最差对等方分数的幅度，才能驱逐该对等方。
+	evictionMargin = 50
+
+	// reputationFlushInterval is how often changed scores are written to the
+	// node database.
+	// reputationFlushInterval 是将已更改的分数写入节点数据库的频率。
+	reputationFlushInterval = 5 * time.Minute
+
+	// reputationForgetScore is the absolute score below which unchanged scores
+	// are dropped from memory on flush.
+	// reputationForgetScore 是刷新时从内存中丢弃未更改分数的绝对分数阈值。
+	reputationForgetScore = 1
+)
+
+// ReputationEvent is a peer behaviour reported by a protocol handler.
+// ReputationEvent 是协议处理程序报告的对等方行为。
+type ReputationEvent int
+
+const (
+	// RepUselessResponse is reported when a peer sends data which can't be used.
+	// RepUselessResponse 在对等方发送无法使用的数据时报告。
+	RepUselessResponse ReputationEvent = iota
+
+	// RepTimeout is reported when a request to the peer times out.
+	// RepTimeout 在对对等方的请求超时时报告。
+	RepTimeout
+
+	// RepInvalidBlock is reported when a peer delivers an invalid block.
+	// RepInvalidBlock 在对等方交付无效区块时报告。
+	RepInvalidBlock
+
+	// RepGoodThroughput is reported when a peer serves a request quickly.
+	// RepGoodThroughput 在对等方快速处理请求时报告。
+	RepGoodThroughput
+)
+
+// reputationWeights is the score adjustment of each event.
+// reputationWeights 是每个事件的分数调整值。
+var reputationWeights = map[ReputationEvent]float64{
+	RepUselessResponse: -20,
+	RepTimeout:         -5,
+	RepInvalidBlock:    -100,
+	RepGoodThroughput:  1,
+}
+
+func (ev ReputationEvent) String() string {
+	switch ev {
+	case RepUselessResponse:
+		return "useless-response"
+	case RepTimeout:
+		return "timeout"
+	case RepInvalidBlock:
+		return "invalid-block"
+	case RepGoodThroughput:
+		return "good-throughput"
+	default:
+		return "unknown"
+	}
+}
+
+// reputation tracks the reputation scores of peers. Scores are loaded from the
+// node database on first use and kept in memory, changes are written back by
+// flush.
+//
+// reputation 跟踪对等方的信誉分数。分数在首次使用时从节点数据库加载并保存在内存中，更改由 flush 写回。
+type reputation struct {
+	db     *enode.DB
+	now    func() time.Time
+	scores map[enode.ID]*reputationScore
+	lock   sync.Mutex // protects scores // 保护 scores
+}
+
+// reputationScore is the score of a node at the time of its last update.
+// reputationScore 是节点在最后一次更新时的分数。
+type reputationScore struct {
+	score   float64
+	updated time.Time
+	dirty   bool // changed since last flush // 自上次刷新以来已更改
+}
+
+func newReputation(db *enode.DB) *reputation {
+	return &reputation{db: db, now: time.Now, scores: make(map[enode.ID]*reputationScore)}
+}
+
+// decay returns a score stored at the given time, decayed to now.
+// decay 返回在给定时间存储的分数衰减到现在的值。
+func decay(score float64, updated, now time.Time) float64 {
+	if score == 0 || !now.After(updated) {
+		return score
+	}
+	return score * math.Exp2(-float64(now.Sub(updated))/float64(reputationHalfLife))
+}
+
+// get returns the in-memory score of a node, loading it from the database if
+// it isn't cached yet. The caller must hold the lock.
+// get 返回节点的内存分数，如果尚未缓存则从数据库加载。调用方必须持有锁。
+func (r *reputation) get(id enode.ID) *reputationScore {
+	s, ok := r.scores[id]
+	if !ok {
+		s = new(reputationScore)
+		s.score, s.updated = r.db.Reputation(id)
+		r.scores[id] = s
+	}
+	return s
+}
+
+// score returns the current reputation score of a node.
+// score 返回节点当前的信誉分数。
+func (r *reputation) score(id enode.ID) float64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := r.get(id)
+	return decay(s.score, s.updated, r.now())
+}
+
+// report adjusts the score of a node by the weight of the event, returning
+// the new score.
+//
+// report 按事件权重调整节点的分数，并返回新分数。
+func (r *reputation) report(id enode.ID, ev ReputationEvent) float64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var (
+		now = r.now()
+		s   = r.get(id)
+	)
+	score := decay(s.score, s.updated, now) + reputationWeights[ev]
+	s.score = math.Max(-reputationLimit, math.Min(reputationLimit, score))
+	s.updated, s.dirty = now, true
+	return s.score
+}
+
+// flush writes the scores changed since the last flush to the database. Unchanged
+// scores which decayed close to zero are dropped from memory.
+//
+// flush 将自上次刷新以来更改的分数写入数据库。已衰减到接近零的未更改分数会从内存中丢弃。
+func (r *reputation) flush() {
+	r.lock.Lock()
+	var (
+		now     = r.now()
+		changed = make(map[enode.ID]reputationScore)
+	)
+	for id, s := range r.scores {
+		switch {
+		case s.dirty:
+			changed[id] = *s
+			s.dirty = false
+		case math.Abs(decay(s.score, s.updated, now)) < reputationForgetScore:
+			delete(r.scores, id)
+		}
+	}
+	r.lock.Unlock()
+
+	for id, s := range changed {
+		r.db.UpdateReputation(id, s.score, s.updated)
+	}
+}
+
+// Reputation returns the current reputation score of the given node.
+// Reputation 返回给定节点当前的信誉分数。
+func (srv *Server) Reputation(id enode.ID) float64 {
+	if srv.reputation == nil {
+		return 0
+	}
+	return srv.reputation.score(id)
+}
+
+// ReportPeer adjusts the reputation of the given node. Protocol handlers which
+// only know the ID of a peer can use it instead of Peer.Report.
+//
+// ReportPeer 调整给定节点的信誉。仅知道对等方 ID 的协议处理程序可以使用它代替 Peer.Report。
+func (srv *Server) ReportPeer(id enode.ID, ev ReputationEvent) {
+	if srv.reputation == nil {
+		return
+	}
+	score := srv.reputation.report(id, ev)
+	srv.log.Trace("Adjusted peer reputation", "id", id, "event", ev, "score", score)
+}
+
+// Report adjusts the reputation of the peer for the given behaviour.
+// Report 根据给定行为调整对等方的信誉。
+func (p *Peer) Report(ev ReputationEvent) {
+	if p.reputation == nil {
+		return
+	}
+	score := p.reputation.report(p.ID(), ev)
+	p.log.Trace("Adjusted peer reputation", "event", ev, "score", score)
+}
+
+// Reputation returns the current reputation score of the peer.
+// Reputation 返回对等方当前的信誉分数。
+func (p *Peer) Reputation() float64 {
+	if p.reputation == nil {
+		return 0
+	}
+	return p.reputation.score(p.ID())
+}
+
+// evictionVictim returns the peer to disconnect in favour of the connection c
+// when the peer set is full, or nil if no peer is worse enough than c. Trusted
+// and static peers are never evicted. If inboundOnly is set, only inbound peers
+// are considered.
+//
+// evictionVictim 返回在对等方集满员时为连接 c 让位而断开的对等方；如果没有对等方比 c 差得足够多，则返回 nil。
+// 受信任和静态对等方永远不会被驱逐。如果设置了 inboundOnly，则仅考虑入站对等方。
+func (srv *Server) evictionVictim(peers map[enode.ID]*Peer, c *conn, inboundOnly bool) *Peer {
+	if srv.reputation == nil {
+		return nil
+	}
+	var (
+		threshold = srv.reputation.score(c.node.ID()) - evictionMargin
+		victim    *Peer
+		worst     float64
+	)
+	for id, p := range peers {
+		if p.rw.is(trustedConn) || p.rw.is(staticDialedConn) {
+			continue
+		}
+		if inboundOnly && !p.rw.is(inboundConn) {
+			continue
+		}
+		if _, ok := srv.evicting[id]; ok {
+			continue
+		}
+		if score := srv.reputation.score(id); score < threshold && (victim == nil || score < worst) {
+			victim, worst = p, score
+		}
+	}
+	return victim
+}
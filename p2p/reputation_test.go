@@ -0,0 +1,176 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+func TestReputationScore(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+
+	var (
+		now = time.Unix(1700000000, 0)
+		rep = newReputation(db)
+		id  = enode.ID{1}
+	)
+	rep.now = func() time.Time { return now }
+
+	if score := rep.score(id); score != 0 {
+		t.Fatalf("unknown node has score %v", score)
+	}
+	rep.report(id, RepInvalidBlock)
+	rep.report(id, RepTimeout)
+	if score, want := rep.score(id), reputationWeights[RepInvalidBlock]+reputationWeights[RepTimeout]; score != want {
+		t.Fatalf("wrong score: have %v, want %v", score, want)
+	}
+	// The score halves every half-life.
+	now = now.Add(reputationHalfLife)
+	if score, want := rep.score(id), (reputationWeights[RepInvalidBlock]+reputationWeights[RepTimeout])/2; math.Abs(score-want) > 1e-9 {
+		t.Fatalf("wrong decayed score: have %v, want %v", score, want)
+	}
+	// Scores are bounded.
+	for i := 0; i < 100; i++ {
+		rep.report(id, RepInvalidBlock)
+	}
+	if score := rep.score(id); score != -reputationLimit {
+		t.Fatalf("score not bounded: have %v, want %v", score, -reputationLimit)
+	}
+}
+
+func TestReputationPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nodes")
+	db, err := enode.OpenDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		now = time.Now().Truncate(time.Millisecond)
+		rep = newReputation(db)
+		id  = enode.ID{2}
+	)
+	rep.now = func() time.Time { return now }
+	rep.report(id, RepUselessResponse)
+	if score, _ := db.Reputation(id); score != 0 {
+		t.Fatalf("score written before flush: %v", score)
+	}
+	rep.flush()
+	db.Close()
+
+	if db, err = enode.OpenDB(path); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	rep = newReputation(db)
+	rep.now = func() time.Time { return now }
+	if score, want := rep.score(id), reputationWeights[RepUselessResponse]; score != want {
+		t.Fatalf("wrong score after reopening: have %v, want %v", score, want)
+	}
+}
+
+func TestDialSchedLowReputation(t *testing.T) {
+	bad, good := newNode(enode.ID{1}, "127.0.0.1:30303"), newNode(enode.ID{2}, "127.0.0.2:30303")
+	scores := map[enode.ID]float64{bad.ID(): dialMinReputation - 1, good.ID(): 10}
+	d := &dialScheduler{dialConfig: dialConfig{
+		reputation: func(id enode.ID) float64 { return scores[id] },
+	}}
+	if err := d.checkReputation(bad); err != errLowReputation {
+		t.Fatalf("wrong error for low reputation node: %v", err)
+	}
+	if err := d.checkReputation(good); err != nil {
+		t.Fatalf("wrong error for good node: %v", err)
+	}
+}
+
+func TestReputationFlush(t *testing.T) {
+	db, _ := enode.OpenDB("")
+	defer db.Close()
+
+	var (
+		now  = time.Unix(1700000000, 0)
+		rep  = newReputation(db)
+		good = enode.ID{1}
+		bad  = enode.ID{2}
+	)
+	rep.now = func() time.Time { return now }
+	rep.report(good, RepGoodThroughput)
+	rep.report(bad, RepInvalidBlock)
+	rep.flush()
+	if score, _ := db.Reputation(bad); score != reputationWeights[RepInvalidBlock] {
+		t.Fatalf("wrong stored score: %v", score)
+	}
+	// Scores which decayed close to zero are dropped from memory, but are still
+	// served from the database.
+	now = now.Add(reputationHalfLife)
+	rep.flush()
+	if _, ok := rep.scores[good]; ok {
+		t.Fatal("decayed score kept in memory")
+	}
+	if _, ok := rep.scores[bad]; !ok {
+		t.Fatal("significant score dropped from memory")
+	}
+	if score, want := rep.score(good), reputationWeights[RepGoodThroughput]/2; math.Abs(score-want) > 1e-9 {
+		t.Fatalf("wrong score after reload: have %v, want %v", score, want)
+	}
+}
+
+// This test checks that discovered nodes waiting for a dial slot are dialed in
+// the order of their reputation.
+func TestDialSchedReputationOrder(t *testing.T) {
+	t.Parallel()
+
+	nodes := []*enode.Node{
+		newNode(uintID(0x01), "127.0.0.1:30303"),
+		newNode(uintID(0x02), "127.0.0.2:30303"),
+		newNode(uintID(0x03), "127.0.0.3:30303"),
+		newNode(uintID(0x04), "127.0.0.4:30303"),
+	}
+	scores := map[enode.ID]float64{nodes[1].ID(): -10, nodes[2].ID(): 20, nodes[3].ID(): 5}
+	config := dialConfig{
+		maxActiveDials: 1,
+		maxDialPeers:   1,
+		reputation:     func(id enode.ID) float64 { return scores[id] },
+	}
+	runDialTest(t, config, []dialTestRound{
+		{
+			discovered:   nodes[:1],
+			wantNewDials: nodes[:1],
+		},
+		// The dial slot is busy, the candidates are buffered.
+		{
+			discovered: nodes[1:],
+		},
+		{
+			failed:       []enode.ID{nodes[0].ID()},
+			wantNewDials: nodes[2:3],
+		},
+		{
+			failed:       []enode.ID{nodes[2].ID()},
+			wantNewDials: nodes[3:4],
+		},
+		{
+			failed:       []enode.ID{nodes[3].ID()},
+			wantNewDials: nodes[1:2],
+		},
+	})
+}
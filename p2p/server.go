@@ -158,6 +158,20 @@ type Config struct {
 	// 如果此选项设置为非 nil 值，则仅考虑与列表中包含的 IP 网络之一匹配的主机。
 	NetRestrict *netutil.Netlist `toml:",omitempty"`
 
+	// PeerPolicyFile is the path of a JSON file containing allow/deny rules for
+	// peer connections. The file is reloaded when it changes.
+	//
+	// PeerPolicyFile 是包含对等方连接允许/拒绝规则的 JSON 文件的路径。文件更改时会重新加载。
+	PeerPolicyFile string `toml:",omitempty"`
+
+	// PeerServeLimit is the maximum number of bytes per second of served data,
+	// such as snap and historical chain data, sent to a single peer. Trusted
+	// peers are exempt. Zero means no limit.
+	//
+	// PeerServeLimit 是每秒发送给单个对等方的提供数据（例如 snap 和历史链数据）的最大字节数。
+	// 受信任的对等方不受限制。零表示无限制。
+	PeerServeLimit int `toml:",omitempty"`
+
 	// NodeDatabase is the path to the database containing the previously seen
 	// live nodes in the network.
 	//
@@ -204,6 +218,18 @@ type Config struct {
 	// 如果 Dialer 设置为非 nil 值，则使用给定的 Dialer 拨号出站对等方连接。
 	Dialer NodeDialer `toml:"-"`
 
+	// If ListenFunc is set to a non-nil value, it is used instead of net.Listen
+	// to create the TCP listener. This is used by network simulations.
+	//
+	// 如果 ListenFunc 设置为非 nil 值，则使用它代替 net.Listen 创建 TCP 监听器。网络模拟使用此功能。
+	ListenFunc func(network, addr string) (net.Listener, error) `toml:"-"`
+
+	// If ListenUDPFunc is set to a non-nil value, it is used instead of
+	// net.ListenUDP to create the discovery socket.
+	//
+	// 如果 ListenUDPFunc 设置为非 nil 值，则使用它代替 net.ListenUDP 创建发现套接字。
+	ListenUDPFunc func(network string, addr *net.UDPAddr) (discover.UDPConn, error) `toml:"-"`
+
 	// If NoDial is true, the server will not dial any peers.
 	// 如果 NoDial 为 true，服务器将不拨号任何对等方。
 	NoDial bool `toml:",omitempty"`
@@ -217,7 +243,11 @@ type Config struct {
 	// Logger 是与 p2p.Server 一起使用的自定义记录器。
 	Logger log.Logger `toml:",omitempty"`
 
-	clock mclock.Clock
+	// Clock is the clock used for timers of the server and discovery. It defaults
+	// to the system clock.
+	//
+	// Clock 是服务器和发现的定时器使用的时钟，默认为系统时钟。
+	Clock mclock.Clock `toml:"-"`
 }
 
 // Server manages all peer connections.
@@ -243,12 +273,14 @@ type Server struct {
 	peerFeed     event.Feed
 	log          log.Logger
 
-	nodedb    *enode.DB
-	localnode *enode.LocalNode
-	discv4    *discover.UDPv4
-	discv5    *discover.UDPv5
-	discmix   *enode.FairMix
-	dialsched *dialScheduler
+	nodedb     *enode.DB
+	reputation *reputation
+	policy     *peerPolicy
+	localnode  *enode.LocalNode
+	discv4     *discover.UDPv4
+	discv5     *discover.UDPv5
+	discmix    *enode.FairMix
+	dialsched  *dialScheduler
 
 	// This is read by the NAT port mapping loop.
 	// NAT 端口映射循环读取此通道。
@@ -268,6 +300,7 @@ type Server struct {
 	// State of run loop and listenLoop.
 	// run 循环和 listenLoop 的状态。
 	inboundHistory expHeap
+	evicting       map[enode.ID]bool // peers disconnected to make room, value is inbound flag // 为腾出空间而断开的对等方，值为入站标志
 }
 
 type peerOpFunc func(map[enode.ID]*Peer)
@@ -525,7 +558,7 @@ func (srv *Server) Stop() {
 //
 // sharedUDPConn 实现共享连接。Write 将消息发送到底层连接，而 read 返回被发现无法处理并由主监听器发送到 unhandled 通道的消息。
 type sharedUDPConn struct {
-	*net.UDPConn
+	discover.UDPConn
 	unhandled chan discover.ReadPacket
 }
 
@@ -566,8 +599,8 @@ func (srv *Server) Start() (err error) {
 	if srv.log == nil {
 		srv.log = log.Root()
 	}
-	if srv.clock == nil {
-		srv.clock = mclock.System{}
+	if srv.Clock == nil {
+		srv.Clock = mclock.System{}
 	}
 	if srv.NoDial && srv.ListenAddr == "" {
 		srv.log.Warn("P2P server will be useless, neither dialing nor listening")
@@ -580,6 +613,9 @@ func (srv *Server) Start() (err error) {
 	if srv.newTransport == nil {
 		srv.newTransport = newRLPX
 	}
+	if srv.listenFunc == nil {
+		srv.listenFunc = srv.ListenFunc
+	}
 	if srv.listenFunc == nil {
 		srv.listenFunc = net.Listen
 	}
@@ -591,6 +627,10 @@ func (srv *Server) Start() (err error) {
 	srv.removetrusted = make(chan *enode.Node)
 	srv.peerOp = make(chan peerOpFunc)
 	srv.peerOpDone = make(chan struct{})
+	srv.evicting = make(map[enode.ID]bool)
+	if srv.policy, err = newPeerPolicy(srv.PeerPolicyFile); err != nil {
+		return err
+	}
 
 	if err := srv.setupLocalNode(); err != nil {
 		return err
@@ -609,6 +649,10 @@ func (srv *Server) Start() (err error) {
 
 	srv.loopWG.Add(1)
 	go srv.run()
+	if srv.PeerPolicyFile != "" {
+		srv.loopWG.Add(1)
+		go srv.policyLoop()
+	}
 	return nil
 }
 
@@ -629,6 +673,7 @@ func (srv *Server) setupLocalNode() error {
 		return err
 	}
 	srv.nodedb = db
+	srv.reputation = newReputation(db)
 	srv.localnode = enode.NewLocalNode(db, srv.PrivateKey)
 	srv.localnode.SetFallbackIP(net.IP{127, 0, 0, 1})
 	// TODO: check conflicts
@@ -674,6 +719,7 @@ func (srv *Server) setupDiscovery() error {
 			Bootnodes:   srv.BootstrapNodes,
 			Unhandled:   unhandled,
 			Log:         srv.log,
+			Clock:       srv.Clock,
 		}
 		ntab, err := discover.ListenV4(conn, srv.localnode, cfg)
 		if err != nil {
@@ -688,6 +734,7 @@ func (srv *Server) setupDiscovery() error {
 			NetRestrict: srv.NetRestrict,
 			Bootnodes:   srv.BootstrapNodesV5,
 			Log:         srv.log,
+			Clock:       srv.Clock,
 		}
 		srv.discv5, err = discover.ListenV5(sconn, srv.localnode, cfg)
 		if err != nil {
@@ -714,8 +761,10 @@ func (srv *Server) setupDialScheduler() {
 		maxActiveDials: srv.MaxPendingPeers,
 		log:            srv.Logger,
 		netRestrict:    srv.NetRestrict,
+		reputation:     srv.reputation.score,
+		policy:         srv.checkDialPolicy,
 		dialer:         srv.Dialer,
-		clock:          srv.clock,
+		clock:          srv.Clock,
 	}
 	if srv.discv4 != nil {
 		config.resolver = srv.discv4
@@ -777,7 +826,7 @@ func (srv *Server) setupListening() error {
 	return nil
 }
 
-func (srv *Server) setupUDPListening() (*net.UDPConn, error) {
+func (srv *Server) setupUDPListening() (discover.UDPConn, error) {
 	listenAddr := srv.ListenAddr
 
 	// Use an alternate listening address for UDP if
@@ -790,7 +839,13 @@ func (srv *Server) setupUDPListening() (*net.UDPConn, error) {
 	if err != nil {
 		return nil, err
 	}
-	conn, err := net.ListenUDP("udp", addr)
+	listenUDP := srv.ListenUDPFunc
+	if listenUDP == nil {
+		listenUDP = func(network string, addr *net.UDPAddr) (discover.UDPConn, error) {
+			return net.ListenUDP(network, addr)
+		}
+	}
+	conn, err := listenUDP("udp", addr)
 	if err != nil {
 		return nil, err
 	}
@@ -824,6 +879,7 @@ func (srv *Server) run() {
 	srv.log.Info("Started P2P networking", "self", srv.localnode.Node().URLv4())
 	defer srv.loopWG.Done()
 	defer srv.nodedb.Close()
+	defer srv.reputation.flush()
 	defer srv.discmix.Close()
 	defer srv.dialsched.stop()
 
@@ -831,7 +887,9 @@ func (srv *Server) run() {
 		peers        = make(map[enode.ID]*Peer)
 		inboundCount = 0
 		trusted      = make(map[enode.ID]bool, len(srv.TrustedNodes))
+		flush        = time.NewTicker(reputationFlushInterval)
 	)
+	defer flush.Stop()
 	// Put trusted nodes into a map to speed up checks.
 	// Trusted peers are loaded on startup or added via AddTrustedPeer RPC.
 	//
@@ -869,6 +927,11 @@ running:
 				p.rw.set(trustedConn, false)
 			}
 
+		case <-flush.C:
+			// Persist the reputation scores changed since the last flush.
+			// 持久化自上次刷新以来更改的信誉分数。
+			srv.reputation.flush()
+
 		case op := <-srv.peerOp:
 			// This channel is used by Peers and PeerCount.
 			// 此通道由 Peers 和 PeerCount 使用。
@@ -918,6 +981,7 @@ running:
 			// 对等方断开连接。
 			d := common.PrettyDuration(mclock.Now() - pd.created)
 			delete(peers, pd.ID())
+			delete(srv.evicting, pd.ID())
 			srv.log.Debug("Removing p2p peer", "peercount", len(peers), "id", pd.ID(), "duration", d, "req", pd.requested, "err", pd.err)
 			srv.dialsched.peerRemoved(pd.rw)
 			if pd.Inbound() {
@@ -957,11 +1021,20 @@ running:
 }
 
 func (srv *Server) postHandshakeChecks(peers map[enode.ID]*Peer, inboundCount int, c *conn) error {
+	// Peers which are being evicted don't count against the limits anymore.
+	// 正在被驱逐的对等方不再计入限制。
+	active, activeInbound := len(peers), inboundCount
+	for _, inbound := range srv.evicting {
+		active--
+		if inbound {
+			activeInbound--
+		}
+	}
 	switch {
-	case !c.is(trustedConn) && len(peers) >= srv.MaxPeers:
-		return DiscTooManyPeers
-	case !c.is(trustedConn) && c.is(inboundConn) && inboundCount >= srv.maxInboundConns():
-		return DiscTooManyPeers
+	case !c.is(trustedConn) && active >= srv.MaxPeers:
+		return srv.evictFor(peers, c)
+	case !c.is(trustedConn) && c.is(inboundConn) && activeInbound >= srv.maxInboundConns():
+		return srv.evictFor(peers, c)
 	case peers[c.node.ID()] != nil:
 		return DiscAlreadyConnected
 	case c.node.ID() == srv.localnode.ID():
@@ -971,12 +1044,44 @@ func (srv *Server) postHandshakeChecks(peers map[enode.ID]*Peer, inboundCount in
 	}
 }
 
+// evictFor disconnects the connected peer with the lowest reputation to make
+// room for c, if c's reputation is sufficiently higher. It returns
+// DiscTooManyPeers if no peer was evicted.
+//
+// evictFor 如果 c 的信誉足够高，则断开信誉最低的已连接对等方，为 c 腾出空间。
+// 如果没有驱逐任何对等方，则返回 DiscTooManyPeers。
+func (srv *Server) evictFor(peers map[enode.ID]*Peer, c *conn) error {
+	if id := c.node.ID(); peers[id] != nil || id == srv.localnode.ID() {
+		return DiscTooManyPeers
+	}
+	// Inbound connections can only replace inbound peers, because the inbound
+	// slots are limited separately.
+	// 入站连接只能替换入站对等方，因为入站槽位是单独限制的。
+	victim := srv.evictionVictim(peers, c, c.is(inboundConn))
+	if victim == nil {
+		return DiscTooManyPeers
+	}
+	srv.log.Debug("Evicting p2p peer", "id", victim.ID(), "score", victim.Reputation(), "for", c.node.ID())
+	srv.evicting[victim.ID()] = victim.Inbound()
+	victim.Disconnect(DiscTooManyPeers)
+	return nil
+}
+
 func (srv *Server) addPeerChecks(peers map[enode.ID]*Peer, inboundCount int, c *conn) error {
 	// Drop connections with no matching protocols.
 	// 丢弃没有匹配协议的连接。
 	if len(srv.Protocols) > 0 && countMatchingProtocols(srv.Protocols, c.caps) == 0 {
 		return DiscUselessPeer
 	}
+	// Apply the peer policy now that the client name is known. This runs on
+	// the main loop, so the peer can't miss a concurrent policy change.
+	// 现在客户端名称已知，应用对等方策略。此检查在主循环上运行，因此对等方不会错过并发的策略更改。
+	t := srv.connPolicyTarget(c)
+	t.final = true
+	if err := srv.policy.check(t); err != nil {
+		srv.log.Debug("Rejected peer by policy", "id", c.node.ID(), "name", c.name, "conn", c.flags)
+		return DiscUselessPeer
+	}
 	// Repeat the post-handshake checks because the
 	// peer set might have changed since those checks were performed.
 	// 重复握手后检查，因为自执行这些检查以来，对等方集可能已更改。
@@ -1067,9 +1172,12 @@ func (srv *Server) checkInboundConn(remoteIP netip.Addr) error {
 	if srv.NetRestrict != nil && !srv.NetRestrict.ContainsAddr(remoteIP) {
 		return errors.New("not in netrestrict list")
 	}
+	if err := srv.policy.check(&policyTarget{ip: remoteIP, inbound: true}); err != nil {
+		return err
+	}
 	// Reject Internet peers that try too often.
 	// 拒绝尝试过于频繁的 Internet 对等方。
-	now := srv.clock.Now()
+	now := srv.Clock.Now()
 	srv.inboundHistory.expire(now, nil)
 	if !netutil.AddrIsLAN(remoteIP) && srv.inboundHistory.contains(remoteIP.String()) {
 		return errors.New("too many attempts")
@@ -1136,6 +1244,10 @@ func (srv *Server) setupConn(c *conn, dialDest *enode.Node) error {
 		c.node = nodeFromConn(remotePubkey, c.fd)
 	}
 	clog := srv.log.New("id", c.node.ID(), "addr", c.fd.RemoteAddr(), "conn", c.flags)
+	if err := srv.policy.check(srv.connPolicyTarget(c)); err != nil {
+		clog.Trace("Rejected peer", "err", err)
+		return DiscUselessPeer
+	}
 	err = srv.checkpoint(c, srv.checkpointPostHandshake)
 	if err != nil {
 		clog.Trace("Rejected peer", "err", err)
@@ -1188,6 +1300,10 @@ func (srv *Server) checkpoint(c *conn, stage chan<- *conn) error {
 
 func (srv *Server) launchPeer(c *conn) *Peer {
 	p := newPeer(srv.log, c, srv.Protocols)
+	p.reputation = srv.reputation
+	if !c.is(trustedConn) {
+		p.setServeLimit(srv.PeerServeLimit)
+	}
 	if srv.EnableMsgEvents {
 		// If message events are enabled, pass the peerFeed
 		// to the peer.
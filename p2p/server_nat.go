@@ -110,11 +110,11 @@ func (srv *Server) portMappingLoop() {
 
 	var (
 		mappings  = make(map[string]*portMapping, 2) // 存储 TCP 和 UDP 的映射
-		refresh   = mclock.NewAlarm(srv.clock)       // 用于刷新映射的闹钟
-		extip     = mclock.NewAlarm(srv.clock)       // 用于检查外部 IP 的闹钟
+		refresh   = mclock.NewAlarm(srv.Clock)       // 用于刷新映射的闹钟
+		extip     = mclock.NewAlarm(srv.Clock)       // 用于检查外部 IP 的闹钟
 		lastExtIP net.IP                             // 上次已知的外部 IP
 	)
-	extip.Schedule(srv.clock.Now())
+	extip.Schedule(srv.Clock.Now())
 	defer func() {
 		refresh.Stop()
 		extip.Stop()
@@ -141,7 +141,7 @@ func (srv *Server) portMappingLoop() {
 		case <-extip.C():
 			// Check and update external IP periodically
 			// 定期检查并更新外部 IP
-			extip.Schedule(srv.clock.Now().Add(extipRetryInterval))
+			extip.Schedule(srv.Clock.Now().Add(extipRetryInterval))
 			ip, err := srv.NAT.ExternalIP()
 			if err != nil {
 				log.Debug("Couldn't get external IP", "err", err, "interface", srv.NAT) // 无法获取外部 IP
@@ -157,7 +157,7 @@ func (srv *Server) portMappingLoop() {
 			// Ensure port mappings are refreshed in case we have moved to a new network.
 			// 确保端口映射在网络更改时刷新。
 			for _, m := range mappings {
-				m.nextTime = srv.clock.Now()
+				m.nextTime = srv.Clock.Now()
 			}
 
 		case m := <-srv.portMappingRegister:
@@ -165,11 +165,11 @@ func (srv *Server) portMappingLoop() {
 				panic("unknown NAT protocol name: " + m.protocol) // 未知的 NAT 协议名称
 			}
 			mappings[m.protocol] = m
-			m.nextTime = srv.clock.Now()
+			m.nextTime = srv.Clock.Now()
 
 		case <-refresh.C():
 			for _, m := range mappings {
-				if srv.clock.Now() < m.nextTime {
+				if srv.Clock.Now() < m.nextTime {
 					continue
 				}
 
@@ -184,13 +184,13 @@ func (srv *Server) portMappingLoop() {
 				if err != nil {
 					log.Debug("Couldn't add port mapping", "err", err) // 无法添加端口映射
 					m.extPort = 0
-					m.nextTime = srv.clock.Now().Add(portMapRetryInterval)
+					m.nextTime = srv.Clock.Now().Add(portMapRetryInterval)
 					continue
 				}
 				// It was mapped!
 				// 映射成功！
 				m.extPort = int(p)
-				m.nextTime = srv.clock.Now().Add(portMapRefreshInterval)
+				m.nextTime = srv.Clock.Now().Add(portMapRefreshInterval)
 				if external != m.extPort {
 					log = newLogger(m.protocol, m.extPort, m.port)
 					log.Info("NAT mapped alternative port") // NAT 映射了替代端口
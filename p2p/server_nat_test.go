@@ -39,7 +39,7 @@ func TestServerPortMapping(t *testing.T) {
 			DiscAddr:   ":0",
 			NAT:        mockNAT,
 			Logger:     testlog.Logger(t, log.LvlTrace),
-			clock:      clock,
+			Clock:      clock,
 		},
 	}
 	err := srv.Start()
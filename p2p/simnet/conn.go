@@ -0,0 +1,231 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simnet
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// relayQueue is the number of chunks buffered in each direction of a stream.
+// relayQueue 是流的每个方向上缓冲的数据块数量。
+const relayQueue = 256
+
+// dialer implements p2p.NodeDialer for a simulated node.
+// dialer 为模拟节点实现 p2p.NodeDialer。
+type dialer struct {
+	n *Node
+}
+
+// Dial connects to the listener of the destination node.
+// Dial 连接到目标节点的监听器。
+func (d *dialer) Dial(ctx context.Context, dest *enode.Node) (net.Conn, error) {
+	nw := d.n.nw
+	nw.mu.Lock()
+	target := nw.byAddr[dest.IPAddr()]
+	var l *listener
+	if target != nil && dest.TCP() == nodePort {
+		l = target.listener
+	}
+	nw.mu.Unlock()
+
+	if l == nil {
+		return nil, errRefused
+	}
+	if nw.link(d.n.ID(), target.ID()).Down {
+		return nil, errUnreachable
+	}
+	s := nw.newStream(d.n, target)
+	select {
+	case l.conns <- s.right:
+		return s.left, nil
+	case <-l.closed:
+		s.close()
+		return nil, errRefused
+	case <-ctx.Done():
+		s.close()
+		return nil, ctx.Err()
+	}
+}
+
+// listener implements net.Listener for a simulated node.
+// listener 为模拟节点实现 net.Listener。
+type listener struct {
+	n         *Node
+	conns     chan net.Conn
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// listen creates the TCP listener of the node.
+// listen 创建节点的 TCP 监听器。
+func (n *Node) listen(network, addr string) (net.Listener, error) {
+	n.nw.mu.Lock()
+	defer n.nw.mu.Unlock()
+	if n.listener != nil {
+		return nil, &net.OpError{Op: "listen", Net: network, Addr: n.tcpAddr(), Err: errAddrInUse}
+	}
+	n.listener = &listener{n: n, conns: make(chan net.Conn), closed: make(chan struct{})}
+	return n.listener, nil
+}
+
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *listener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		l.n.nw.mu.Lock()
+		if l.n.listener == l {
+			l.n.listener = nil
+		}
+		l.n.nw.mu.Unlock()
+	})
+	return nil
+}
+
+func (l *listener) Addr() net.Addr {
+	return l.n.tcpAddr()
+}
+
+// stream is a connection between two nodes. Data written on either end is
+// relayed to the other end according to the link between the nodes.
+//
+// stream 是两个节点之间的连接。写入任一端的数据会根据节点之间的链路中继到另一端。
+type stream struct {
+	nw          *Network
+	key         linkKey
+	left, right net.Conn // endpoints of the dialer and the listener // 拨号方和监听方的端点
+	pipes       []net.Conn
+	closeOnce   sync.Once
+}
+
+// streamConn is an endpoint of a stream, which reports the node addresses.
+// streamConn 是流的一个端点，报告节点地址。
+type streamConn struct {
+	net.Conn
+	local, remote net.Addr
+}
+
+func (c *streamConn) LocalAddr() net.Addr  { return c.local }
+func (c *streamConn) RemoteAddr() net.Addr { return c.remote }
+
+// newStream creates a stream from node a to node b and starts relaying.
+// newStream 创建从节点 a 到节点 b 的流并开始中继。
+func (nw *Network) newStream(a, b *Node) *stream {
+	// Each endpoint is connected by a pipe to a relay, which forwards the
+	// data to the pipe of the other endpoint.
+	// 每个端点通过管道连接到中继，中继将数据转发到另一个端点的管道。
+	aConn, aRelay := net.Pipe()
+	bConn, bRelay := net.Pipe()
+	s := &stream{
+		nw:    nw,
+		key:   newLinkKey(a.ID(), b.ID()),
+		left:  &streamConn{Conn: aConn, local: a.tcpAddr(), remote: b.tcpAddr()},
+		right: &streamConn{Conn: bConn, local: b.tcpAddr(), remote: a.tcpAddr()},
+		pipes: []net.Conn{aConn, aRelay, bConn, bRelay},
+	}
+	nw.mu.Lock()
+	if nw.streams[s.key] == nil {
+		nw.streams[s.key] = make(map[*stream]struct{})
+	}
+	nw.streams[s.key][s] = struct{}{}
+	nw.mu.Unlock()
+
+	go s.relay(a.ID(), b.ID(), aRelay, bRelay)
+	go s.relay(a.ID(), b.ID(), bRelay, aRelay)
+	return s
+}
+
+// chunk is data in flight on a stream.
+// chunk 是流上正在传输的数据。
+type chunk struct {
+	data      []byte
+	deliverAt mclock.AbsTime
+}
+
+// relay forwards data read from src to dst. Data is delivered in order, after
+// the latency of the link.
+//
+// relay 将从 src 读取的数据转发到 dst。数据按顺序在链路延迟之后交付。
+func (s *stream) relay(a, b enode.ID, src, dst net.Conn) {
+	defer s.close()
+
+	queue := make(chan chunk, relayQueue)
+	go func() {
+		defer close(queue)
+		var last mclock.AbsTime
+		for {
+			buf := make([]byte, 4096)
+			n, err := src.Read(buf)
+			if err != nil {
+				return
+			}
+			link := s.nw.link(a, b)
+			if link.Down {
+				return
+			}
+			delay, ok := s.nw.delay(link)
+			if !ok {
+				delay += retransmitDelay
+			}
+			// Data must not overtake earlier data on the stream.
+			// 数据不得超过流上较早的数据。
+			at := s.nw.clock.Now().Add(delay)
+			if at < last {
+				at = last
+			}
+			last = at
+			queue <- chunk{data: buf[:n], deliverAt: at}
+		}
+	}()
+
+	for c := range queue {
+		if wait := c.deliverAt.Sub(s.nw.clock.Now()); wait > 0 {
+			<-s.nw.clock.After(wait)
+		}
+		if _, err := dst.Write(c.data); err != nil {
+			return
+		}
+	}
+}
+
+// close closes both ends of the stream.
+// close 关闭流的两端。
+func (s *stream) close() {
+	s.closeOnce.Do(func() {
+		for _, c := range s.pipes {
+			c.Close()
+		}
+		s.nw.mu.Lock()
+		delete(s.nw.streams[s.key], s)
+		if len(s.nw.streams[s.key]) == 0 {
+			delete(s.nw.streams, s.key)
+		}
+		s.nw.mu.Unlock()
+	})
+}
@@ -0,0 +1,455 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package simnet runs many p2p servers in a single process, connected through an
+// in-memory network with configurable links.
+//
+// Each simulated node is a regular p2p.Server with its own address. Stream
+// connections between nodes use in-memory pipes and discovery packets are
+// exchanged through in-memory UDP sockets. Links between nodes can delay, lose
+// or block traffic, and all timers of the network run on a common mclock.Clock,
+// so tests can use a simulated clock for deterministic scheduling.
+//
+// Package simnet 在单个进程中运行多个 p2p 服务器，通过具有可配置链路的内存网络连接。
+//
+// 每个模拟节点都是一个具有自己地址的常规 p2p.Server。节点之间的流连接使用内存管道，
+// 发现数据包通过内存 UDP 套接字交换。节点之间的链路可以延迟、丢失或阻断流量，
+// 网络的所有定时器都在一个公共的 mclock.Clock 上运行，因此测试可以使用模拟时钟进行确定性调度。
+package simnet
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/nat"
+)
+
+const (
+	// nodePort is the TCP and UDP port of all simulated nodes.
+	// nodePort 是所有模拟节点的 TCP 和 UDP 端口。
+	nodePort = 30303
+
+	// retransmitDelay is the extra delay of stream data lost on a link, which
+	// models the retransmission timeout of TCP.
+	// retransmitDelay 是链路上丢失的流数据的额外延迟，模拟 TCP 的重传超时。
+	retransmitDelay = 200 * time.Millisecond
+)
+
+var (
+	errUnreachable = errors.New("network unreachable")
+	errRefused     = errors.New("connection refused")
+	errAddrInUse   = errors.New("address already in use")
+)
+
+// Link describes the properties of the connection between two nodes.
+// Link 描述两个节点之间连接的属性。
+type Link struct {
+	Latency time.Duration // one-way delay // 单向延迟
+
+	// Loss is the probability that a packet is lost. Lost UDP packets are
+	// dropped, while lost stream data is delayed by the retransmission timeout.
+	//
+	// Loss 是数据包丢失的概率。丢失的 UDP 数据包会被丢弃，而丢失的流数据会延迟一个重传超时。
+	Loss float64
+
+	// Down blocks all traffic. Existing connections over the link are closed.
+	// Down 阻断所有流量。链路上的现有连接将被关闭。
+	Down bool
+}
+
+// Config is the configuration of a simulated network.
+// Config 是模拟网络的配置。
+type Config struct {
+	// Clock drives link latency and the timers of all servers. It defaults to
+	// the system clock. With a *mclock.Simulated, time only advances when the
+	// test runs the clock.
+	//
+	// Clock 驱动链路延迟和所有服务器的定时器，默认为系统时钟。使用 *mclock.Simulated 时，
+	// 时间只在测试运行时钟时前进。
+	Clock mclock.Clock
+
+	// Link is the default link between nodes.
+	// Link 是节点之间的默认链路。
+	Link Link
+
+	// Seed initializes the randomness of packet loss and random topologies.
+	// Seed 初始化数据包丢失和随机拓扑的随机性。
+	Seed int64
+
+	Logger log.Logger
+}
+
+// Event is a peer event observed by a simulated node.
+// Event 是模拟节点观察到的对等方事件。
+type Event struct {
+	Node enode.ID       // node which observed the event // 观察到事件的节点
+	Time mclock.AbsTime // network time of the event // 事件的网络时间
+	p2p.PeerEvent
+}
+
+// Network is an in-memory network of p2p servers.
+// Network 是 p2p 服务器的内存网络。
+type Network struct {
+	cfg   Config
+	clock mclock.Clock
+	feed  event.Feed
+
+	mu      sync.Mutex
+	rand    *rand.Rand
+	nodes   []*Node
+	byID    map[enode.ID]*Node
+	byAddr  map[netip.Addr]*Node
+	links   map[linkKey]Link
+	streams map[linkKey]map[*stream]struct{}
+}
+
+// linkKey identifies the link between two nodes, independent of direction.
+// linkKey 标识两个节点之间的链路，与方向无关。
+type linkKey [2]enode.ID
+
+func newLinkKey(a, b enode.ID) linkKey {
+	if string(a[:]) > string(b[:]) {
+		a, b = b, a
+	}
+	return linkKey{a, b}
+}
+
+// New creates a network.
+// New 创建一个网络。
+func New(cfg Config) *Network {
+	if cfg.Clock == nil {
+		cfg.Clock = mclock.System{}
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Root()
+	}
+	return &Network{
+		cfg:     cfg,
+		clock:   cfg.Clock,
+		rand:    rand.New(rand.NewSource(cfg.Seed)),
+		byID:    make(map[enode.ID]*Node),
+		byAddr:  make(map[netip.Addr]*Node),
+		links:   make(map[linkKey]Link),
+		streams: make(map[linkKey]map[*stream]struct{}),
+	}
+}
+
+// Clock returns the clock of the network.
+// Clock 返回网络的时钟。
+func (nw *Network) Clock() mclock.Clock {
+	return nw.clock
+}
+
+// Nodes returns all nodes in the order they were created.
+// Nodes 按创建顺序返回所有节点。
+func (nw *Network) Nodes() []*Node {
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+	return append([]*Node{}, nw.nodes...)
+}
+
+// SubscribeEvents subscribes to the peer events of all nodes, including the
+// messages sent and received by them.
+//
+// SubscribeEvents 订阅所有节点的对等方事件，包括它们发送和接收的消息。
+func (nw *Network) SubscribeEvents(ch chan<- *Event) event.Subscription {
+	return nw.feed.Subscribe(ch)
+}
+
+// SetLink sets the link between a and b. Setting a link down closes the
+// connections between the nodes.
+//
+// SetLink 设置 a 和 b 之间的链路。将链路设置为断开会关闭节点之间的连接。
+func (nw *Network) SetLink(a, b *Node, link Link) {
+	key := newLinkKey(a.ID(), b.ID())
+	nw.mu.Lock()
+	nw.links[key] = link
+	var cut []*stream
+	if link.Down {
+		for s := range nw.streams[key] {
+			cut = append(cut, s)
+		}
+	}
+	nw.mu.Unlock()
+
+	for _, s := range cut {
+		s.close()
+	}
+}
+
+// Partition takes down all links between the two groups of nodes.
+// Partition 断开两组节点之间的所有链路。
+func (nw *Network) Partition(group1, group2 []*Node) {
+	for _, a := range group1 {
+		for _, b := range group2 {
+			link := nw.link(a.ID(), b.ID())
+			link.Down = true
+			nw.SetLink(a, b, link)
+		}
+	}
+}
+
+// ResetLinks restores the default link between all nodes.
+// ResetLinks 恢复所有节点之间的默认链路。
+func (nw *Network) ResetLinks() {
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+	nw.links = make(map[linkKey]Link)
+}
+
+// link returns the link between the given nodes.
+// link 返回给定节点之间的链路。
+func (nw *Network) link(a, b enode.ID) Link {
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+	if link, ok := nw.links[newLinkKey(a, b)]; ok {
+		return link
+	}
+	return nw.cfg.Link
+}
+
+// delay returns the time it takes to transfer data over the link, or false if
+// the data is lost.
+//
+// delay 返回通过链路传输数据所需的时间；如果数据丢失，则返回 false。
+func (nw *Network) delay(link Link) (time.Duration, bool) {
+	if link.Loss > 0 {
+		nw.mu.Lock()
+		lost := nw.rand.Float64() < link.Loss
+		nw.mu.Unlock()
+		if lost {
+			return link.Latency, false
+		}
+	}
+	return link.Latency, true
+}
+
+// NodeConfig is the configuration of a simulated node.
+// NodeConfig 是模拟节点的配置。
+type NodeConfig struct {
+	Name       string
+	PrivateKey *ecdsa.PrivateKey // generated if nil // 如果为 nil 则生成
+	Protocols  []p2p.Protocol
+	MaxPeers   int // defaults to 50 // 默认为 50
+
+	// Discovery enables the v4 discovery protocol with the given bootstrap nodes.
+	// Discovery 启用 v4 发现协议，并使用给定的引导节点。
+	Discovery bool
+	Bootnodes []*enode.Node
+}
+
+// Node is a p2p server in the simulated network.
+// Node 是模拟网络中的 p2p 服务器。
+type Node struct {
+	Server *p2p.Server
+
+	nw       *Network
+	ip       netip.Addr
+	listener *listener
+	udp      *udpConn
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewNode adds a node to the network. The node's server is not started yet, so
+// its configuration can still be changed.
+//
+// NewNode 向网络添加一个节点。节点的服务器尚未启动，因此其配置仍可更改。
+func (nw *Network) NewNode(cfg NodeConfig) (*Node, error) {
+	key := cfg.PrivateKey
+	if key == nil {
+		var err error
+		if key, err = crypto.GenerateKey(); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.MaxPeers == 0 {
+		cfg.MaxPeers = 50
+	}
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+
+	// Nodes get consecutive addresses in 10.0.0.0/8.
+	// 节点在 10.0.0.0/8 中获得连续的地址。
+	i := len(nw.nodes) + 1
+	ip := netip.AddrFrom4([4]byte{10, byte(i >> 16), byte(i >> 8), byte(i)})
+	n := &Node{nw: nw, ip: ip, quit: make(chan struct{})}
+	n.Server = &p2p.Server{Config: p2p.Config{
+		PrivateKey:      key,
+		Name:            cfg.Name,
+		MaxPeers:        cfg.MaxPeers,
+		Protocols:       cfg.Protocols,
+		ListenAddr:      netip.AddrPortFrom(ip, nodePort).String(),
+		NoDiscovery:     !cfg.Discovery,
+		DiscoveryV4:     cfg.Discovery,
+		BootstrapNodes:  cfg.Bootnodes,
+		NAT:             nat.ExtIP(ip.AsSlice()),
+		Dialer:          &dialer{n},
+		ListenFunc:      n.listen,
+		ListenUDPFunc:   n.listenUDP,
+		EnableMsgEvents: true,
+		Clock:           nw.clock,
+		Logger:          nw.cfg.Logger.New("node", len(nw.nodes)),
+	}}
+	id := enode.PubkeyToIDV4(&key.PublicKey)
+	if nw.byID[id] != nil {
+		return nil, fmt.Errorf("node %v already exists", id)
+	}
+	nw.nodes = append(nw.nodes, n)
+	nw.byID[id] = n
+	nw.byAddr[ip] = n
+	return n, nil
+}
+
+// NewNodes adds count nodes with the same configuration and starts them.
+// NewNodes 添加 count 个具有相同配置的节点并启动它们。
+func (nw *Network) NewNodes(count int, cfg NodeConfig) ([]*Node, error) {
+	nodes := make([]*Node, 0, count)
+	for i := 0; i < count; i++ {
+		n, err := nw.NewNode(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := n.Start(); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes, nil
+}
+
+// Shutdown stops all nodes.
+// Shutdown 停止所有节点。
+func (nw *Network) Shutdown() {
+	for _, n := range nw.Nodes() {
+		n.Stop()
+	}
+}
+
+// ID returns the node ID.
+// ID 返回节点 ID。
+func (n *Node) ID() enode.ID {
+	return enode.PubkeyToIDV4(&n.Server.PrivateKey.PublicKey)
+}
+
+// Addr returns the IP address of the node.
+// Addr 返回节点的 IP 地址。
+func (n *Node) Addr() netip.Addr {
+	return n.ip
+}
+
+// Node returns the node record, which can be used to connect to it.
+// Node 返回节点记录，可用于连接该节点。
+func (n *Node) Node() *enode.Node {
+	return n.Server.Self()
+}
+
+// PeerCount returns the number of connected peers.
+// PeerCount 返回已连接对等方的数量。
+func (n *Node) PeerCount() int {
+	return n.Server.PeerCount()
+}
+
+// Start starts the server of the node.
+// Start 启动节点的服务器。
+func (n *Node) Start() error {
+	if err := n.Server.Start(); err != nil {
+		return err
+	}
+	events := make(chan *p2p.PeerEvent, 256)
+	sub := n.Server.SubscribeEvents(events)
+	n.wg.Add(1)
+	go n.forwardEvents(events, sub)
+	return nil
+}
+
+// Stop stops the server of the node. The node can't be restarted.
+// Stop 停止节点的服务器。节点无法重新启动。
+func (n *Node) Stop() {
+	select {
+	case <-n.quit:
+		return
+	default:
+		close(n.quit)
+	}
+	n.Server.Stop()
+	n.wg.Wait()
+}
+
+// forwardEvents publishes the events of the node on the network feed.
+// forwardEvents 将节点的事件发布到网络源上。
+func (n *Node) forwardEvents(events chan *p2p.PeerEvent, sub event.Subscription) {
+	defer n.wg.Done()
+	defer sub.Unsubscribe()
+
+	id := n.ID()
+	for {
+		select {
+		case ev := <-events:
+			n.nw.feed.Send(&Event{Node: id, Time: n.nw.clock.Now(), PeerEvent: *ev})
+		case <-sub.Err():
+			return
+		case <-n.quit:
+			return
+		}
+	}
+}
+
+// WaitPeers waits until every node has at least the given number of peers, or the timeout
+// (in wall clock time) expires.
+//
+// WaitPeers 等待直到每个节点至少有给定数量的对等方，或超时（以挂钟时间计）到期。
+func (nw *Network) WaitPeers(nodes []*Node, peers int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done := true
+		for _, n := range nodes {
+			if n.PeerCount() < peers {
+				done = false
+				break
+			}
+		}
+		if done {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.New("timeout waiting for peers")
+		}
+		if sim, ok := nw.clock.(*mclock.Simulated); ok {
+			sim.Run(10 * time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// tcpAddr returns the TCP address of the node.
+// tcpAddr 返回节点的 TCP 地址。
+func (n *Node) tcpAddr() *net.TCPAddr {
+	return net.TCPAddrFromAddrPort(netip.AddrPortFrom(n.ip, nodePort))
+}
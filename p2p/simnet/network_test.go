@@ -0,0 +1,271 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simnet
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/mclock"
+	"github.com/ethereum/go-ethereum/internal/testlog"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+// floodNode is the state of a simple flooding protocol, which relays every
+// announcement it hasn't seen before to all peers.
+type floodNode struct {
+	clock mclock.Clock
+
+	mu    sync.Mutex
+	peers map[enode.ID]p2p.MsgReadWriter
+	seen  map[uint64]mclock.AbsTime
+}
+
+func newFloodNode(clock mclock.Clock) *floodNode {
+	return &floodNode{
+		clock: clock,
+		peers: make(map[enode.ID]p2p.MsgReadWriter),
+		seen:  make(map[uint64]mclock.AbsTime),
+	}
+}
+
+func (f *floodNode) protocol() p2p.Protocol {
+	return p2p.Protocol{
+		Name:    "flood",
+		Version: 1,
+		Length:  1,
+		Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+			f.mu.Lock()
+			f.peers[p.ID()] = rw
+			f.mu.Unlock()
+			defer func() {
+				f.mu.Lock()
+				delete(f.peers, p.ID())
+				f.mu.Unlock()
+			}()
+			for {
+				msg, err := rw.ReadMsg()
+				if err != nil {
+					return err
+				}
+				var id uint64
+				if err := msg.Decode(&id); err != nil {
+					return err
+				}
+				f.announce(id)
+			}
+		},
+	}
+}
+
+// announce records the announcement and relays it if it is new.
+func (f *floodNode) announce(id uint64) {
+	f.mu.Lock()
+	if _, ok := f.seen[id]; ok {
+		f.mu.Unlock()
+		return
+	}
+	f.seen[id] = f.clock.Now()
+	peers := make([]p2p.MsgReadWriter, 0, len(f.peers))
+	for _, rw := range f.peers {
+		peers = append(peers, rw)
+	}
+	f.mu.Unlock()
+
+	for _, rw := range peers {
+		go p2p.Send(rw, 0, id)
+	}
+}
+
+func (f *floodNode) seenAt(id uint64) (mclock.AbsTime, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.seen[id]
+	return t, ok
+}
+
+// newFloodNetwork creates count started nodes running the flood protocol.
+func newFloodNetwork(t *testing.T, cfg Config, count int) (*Network, []*Node, []*floodNode) {
+	t.Helper()
+	cfg.Logger = testlog.Logger(t, log.LvlInfo)
+	net := New(cfg)
+	t.Cleanup(net.Shutdown)
+
+	var (
+		nodes = make([]*Node, count)
+		flood = make([]*floodNode, count)
+	)
+	for i := range nodes {
+		flood[i] = newFloodNode(net.Clock())
+		n, err := net.NewNode(NodeConfig{Protocols: []p2p.Protocol{flood[i].protocol()}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := n.Start(); err != nil {
+			t.Fatal(err)
+		}
+		nodes[i] = n
+	}
+	return net, nodes, flood
+}
+
+// waitFor polls cond until it is true, running the network clock if it is
+// simulated.
+func waitFor(t *testing.T, net *Network, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		if sim, ok := net.Clock().(*mclock.Simulated); ok {
+			sim.Run(10 * time.Millisecond)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestNetworkRing(t *testing.T) {
+	net, nodes, _ := newFloodNetwork(t, Config{}, 8)
+	net.ConnectRing(nodes)
+	if err := net.WaitPeers(nodes, 2, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	for i, n := range nodes {
+		if c := n.PeerCount(); c != 2 {
+			t.Errorf("node %d has %d peers, want 2", i, c)
+		}
+	}
+}
+
+func TestNetworkFlood(t *testing.T) {
+	net, nodes, flood := newFloodNetwork(t, Config{Seed: 1, Link: Link{Loss: 0.05}}, 20)
+
+	events := make(chan *Event, 4096)
+	sub := net.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	// The ring keeps the network connected.
+	net.ConnectRing(nodes)
+	net.ConnectRandom(nodes, 2)
+	if err := net.WaitPeers(nodes, 2, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	flood[0].announce(1)
+	waitFor(t, net, "announcement", func() bool {
+		for _, f := range flood {
+			if _, ok := f.seenAt(1); !ok {
+				return false
+			}
+		}
+		return true
+	})
+
+	// The message events show the announcement arriving at every other node.
+	received := make(map[enode.ID]bool)
+	for len(received) < len(nodes)-1 {
+		select {
+		case ev := <-events:
+			if ev.Type == p2p.PeerEventTypeMsgRecv && ev.Protocol == "flood" && ev.Node != nodes[0].ID() {
+				received[ev.Node] = true
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("missing message events, have %d", len(received))
+		}
+	}
+}
+
+func TestNetworkLatency(t *testing.T) {
+	var (
+		clock          = new(mclock.Simulated)
+		latency        = 100 * time.Millisecond
+		net, nodes, fl = newFloodNetwork(t, Config{Clock: clock, Link: Link{Latency: latency}}, 3)
+	)
+	net.ConnectChain(nodes)
+	if err := net.WaitPeers(nodes[1:2], 2, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	fl[0].announce(1)
+	waitFor(t, net, "announcement", func() bool {
+		_, ok := fl[2].seenAt(1)
+		return ok
+	})
+
+	// The announcement crossed two links.
+	sent, _ := fl[0].seenAt(1)
+	recv, _ := fl[2].seenAt(1)
+	if d := time.Duration(recv - sent); d < 2*latency || d > 2*latency+time.Second {
+		t.Fatalf("wrong propagation time %v", d)
+	}
+}
+
+func TestNetworkPartition(t *testing.T) {
+	var (
+		clock         = new(mclock.Simulated)
+		net, nodes, _ = newFloodNetwork(t, Config{Clock: clock}, 4)
+	)
+	net.ConnectFull(nodes)
+	if err := net.WaitPeers(nodes, 3, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cutting the links closes the connections between the groups.
+	net.Partition(nodes[:2], nodes[2:])
+	waitFor(t, net, "partition", func() bool {
+		for _, n := range nodes {
+			if n.PeerCount() != 1 {
+				return false
+			}
+		}
+		return true
+	})
+
+	// After healing, the static dials are retried once the dial history expires.
+	net.ResetLinks()
+	clock.Run(time.Minute)
+	if err := net.WaitPeers(nodes, 3, 10*time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNetworkDiscovery(t *testing.T) {
+	net := New(Config{Logger: testlog.Logger(t, log.LvlInfo)})
+	defer net.Shutdown()
+
+	boot, err := net.NewNode(NodeConfig{Discovery: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := boot.Start(); err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := net.NewNodes(8, NodeConfig{
+		MaxPeers:  10,
+		Discovery: true,
+		Bootnodes: []*enode.Node{boot.Node()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nodes only know the bootnode, so finding a second peer requires discovery.
+	if err := net.WaitPeers(nodes, 2, 20*time.Second); err != nil {
+		t.Fatal(err)
+	}
+}
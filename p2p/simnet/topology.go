@@ -0,0 +1,95 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simnet
+
+// Connect makes node a keep a connection to node b. The connection is
+// established asynchronously and redialed when it drops.
+//
+// Connect 使节点 a 保持与节点 b 的连接。连接是异步建立的，并在断开时重新拨号。
+func (nw *Network) Connect(a, b *Node) {
+	a.Server.AddPeer(b.Node())
+}
+
+// Disconnect removes the connection between a and b.
+// Disconnect 移除 a 和 b 之间的连接。
+func (nw *Network) Disconnect(a, b *Node) {
+	a.Server.RemovePeer(b.Node())
+	b.Server.RemovePeer(a.Node())
+}
+
+// ConnectChain connects the nodes in a line.
+// ConnectChain 将节点连接成一条线。
+func (nw *Network) ConnectChain(nodes []*Node) {
+	for i := 1; i < len(nodes); i++ {
+		nw.Connect(nodes[i-1], nodes[i])
+	}
+}
+
+// ConnectRing connects the nodes in a ring.
+// ConnectRing 将节点连接成一个环。
+func (nw *Network) ConnectRing(nodes []*Node) {
+	nw.ConnectChain(nodes)
+	if len(nodes) > 2 {
+		nw.Connect(nodes[len(nodes)-1], nodes[0])
+	}
+}
+
+// ConnectStar connects all nodes to the center node.
+// ConnectStar 将所有节点连接到中心节点。
+func (nw *Network) ConnectStar(center *Node, nodes []*Node) {
+	for _, n := range nodes {
+		if n != center {
+			nw.Connect(n, center)
+		}
+	}
+}
+
+// ConnectFull connects every pair of nodes.
+// ConnectFull 连接每一对节点。
+func (nw *Network) ConnectFull(nodes []*Node) {
+	for i := range nodes {
+		for j := i + 1; j < len(nodes); j++ {
+			nw.Connect(nodes[i], nodes[j])
+		}
+	}
+}
+
+// ConnectRandom connects each node to degree other nodes, chosen with the
+// randomness of the network seed. Nodes may end up with more than degree peers
+// because they also accept connections from others.
+//
+// ConnectRandom 将每个节点连接到 degree 个其他节点，这些节点使用网络种子的随机性选择。
+// 节点最终可能拥有多于 degree 个对等方，因为它们也接受来自其他节点的连接。
+func (nw *Network) ConnectRandom(nodes []*Node, degree int) {
+	degree = min(degree, len(nodes)-1)
+	for i, n := range nodes {
+		nw.mu.Lock()
+		perm := nw.rand.Perm(len(nodes))
+		nw.mu.Unlock()
+
+		count := 0
+		for _, j := range perm {
+			if count == degree {
+				break
+			}
+			if j != i {
+				nw.Connect(n, nodes[j])
+				count++
+			}
+		}
+	}
+}
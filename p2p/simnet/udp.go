@@ -0,0 +1,138 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package simnet
+
+import (
+	"net"
+	"net/netip"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+)
+
+// udpQueue is the number of packets buffered by a UDP socket. Further packets
+// are dropped, like on a real socket.
+//
+// udpQueue 是 UDP 套接字缓冲的数据包数量。更多的数据包将被丢弃，就像在真实套接字上一样。
+const udpQueue = 512
+
+// udpPacket is a datagram received by a node.
+// udpPacket 是节点接收到的数据报。
+type udpPacket struct {
+	data []byte
+	from netip.AddrPort
+}
+
+// udpConn implements discover.UDPConn for a simulated node.
+// udpConn 为模拟节点实现 discover.UDPConn。
+type udpConn struct {
+	n         *Node
+	inbox     chan udpPacket
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// listenUDP creates the discovery socket of the node.
+// listenUDP 创建节点的发现套接字。
+func (n *Node) listenUDP(network string, addr *net.UDPAddr) (discover.UDPConn, error) {
+	n.nw.mu.Lock()
+	defer n.nw.mu.Unlock()
+	if n.udp != nil {
+		return nil, &net.OpError{Op: "listen", Net: network, Addr: n.udpAddr(), Err: errAddrInUse}
+	}
+	n.udp = &udpConn{n: n, inbox: make(chan udpPacket, udpQueue), closed: make(chan struct{})}
+	return n.udp, nil
+}
+
+// udpAddr returns the UDP address of the node.
+// udpAddr 返回节点的 UDP 地址。
+func (n *Node) udpAddr() *net.UDPAddr {
+	return net.UDPAddrFromAddrPort(netip.AddrPortFrom(n.ip, nodePort))
+}
+
+func (c *udpConn) ReadFromUDPAddrPort(b []byte) (int, netip.AddrPort, error) {
+	select {
+	case p := <-c.inbox:
+		return copy(b, p.data), p.from, nil
+	case <-c.closed:
+		return 0, netip.AddrPort{}, net.ErrClosed
+	}
+}
+
+// WriteToUDPAddrPort sends a packet. Like on a real network, packets to unknown
+// destinations and packets lost on the link are dropped silently.
+//
+// WriteToUDPAddrPort 发送数据包。与真实网络一样，发往未知目标的数据包和在链路上丢失的数据包会被静默丢弃。
+func (c *udpConn) WriteToUDPAddrPort(b []byte, to netip.AddrPort) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, net.ErrClosed
+	default:
+	}
+	nw := c.n.nw
+	nw.mu.Lock()
+	var target *udpConn
+	if n := nw.byAddr[to.Addr().Unmap()]; n != nil && to.Port() == nodePort {
+		target = n.udp
+	}
+	nw.mu.Unlock()
+	if target == nil {
+		return len(b), nil
+	}
+
+	link := nw.link(c.n.ID(), target.n.ID())
+	if link.Down {
+		return len(b), nil
+	}
+	delay, ok := nw.delay(link)
+	if !ok {
+		return len(b), nil
+	}
+	p := udpPacket{data: append([]byte{}, b...), from: netip.AddrPortFrom(c.n.ip, nodePort)}
+	if delay <= 0 {
+		target.deliver(p)
+	} else {
+		nw.clock.AfterFunc(delay, func() { target.deliver(p) })
+	}
+	return len(b), nil
+}
+
+// deliver puts a packet into the inbox of the socket.
+// deliver 将数据包放入套接字的收件箱。
+func (c *udpConn) deliver(p udpPacket) {
+	select {
+	case c.inbox <- p:
+	case <-c.closed:
+	default:
+	}
+}
+
+func (c *udpConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.n.nw.mu.Lock()
+		if c.n.udp == c {
+			c.n.udp = nil
+		}
+		c.n.nw.mu.Unlock()
+	})
+	return nil
+}
+
+func (c *udpConn) LocalAddr() net.Addr {
+	return c.n.udpAddr()
+}
@@ -22,6 +22,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 )
@@ -83,6 +84,9 @@ type Tracker struct {
 	expire  *list.List          // Linked list tracking the expiration order / 跟踪到期顺序的链表
 	wake    *time.Timer         // Timer tracking the expiration of the next item / 跟踪下一项到期时间的计时器
 
+	lostFeed  event.Feed              // Feed notifying about the peers of expired requests / 通知过期请求的对等节点的订阅源
+	lostScope event.SubscriptionScope // Subscriptions to the lost feed / 过期订阅源的订阅
+
 	lock sync.Mutex // Lock protecting from concurrent updates / 保护并发更新的锁
 }
 
@@ -99,12 +103,27 @@ func New(protocol string, timeout time.Duration) *Tracker {
 	}
 }
 
+// SubscribeLost subscribes to the IDs of peers which failed to respond to a
+// request in time. Requests are tracked while there are subscriptions even if
+// metrics are disabled.
+//
+// SubscribeLost 订阅未能及时响应请求的对等节点 ID。只要存在订阅，即使禁用了指标，请求也会被跟踪。
+func (t *Tracker) SubscribeLost(ch chan<- string) event.Subscription {
+	return t.lostScope.Track(t.lostFeed.Subscribe(ch))
+}
+
+// enabled reports whether requests need to be tracked.
+// enabled 报告是否需要跟踪请求。
+func (t *Tracker) enabled() bool {
+	return metrics.Enabled() || t.lostScope.Count() > 0
+}
+
 // Track adds a network request to the tracker to wait for a response to arrive
 // or until the request it cancelled or times out.
 //
 // Track 将网络请求添加到跟踪器，以等待响应到达，或者直到请求被取消或超时。
 func (t *Tracker) Track(peer string, version uint, reqCode uint64, resCode uint64, id uint64) {
-	if !metrics.Enabled() { // 如果指标未启用，则返回
+	if !t.enabled() { // 如果既未启用指标也没有订阅，则返回
 		return
 	}
 	t.lock.Lock()         // 加锁
@@ -153,6 +172,14 @@ func (t *Tracker) Track(peer string, version uint, reqCode uint64, resCode uint6
 //
 // clean 在预设时间过去而第一个网络请求未收到响应时自动调用。
 func (t *Tracker) clean() {
+	// Notify the subscribers outside of the lock, the feed might block
+	// 在锁之外通知订阅者，订阅源可能会阻塞
+	var lost []string
+	defer func() {
+		for _, peer := range lost {
+			t.lostFeed.Send(peer)
+		}
+	}()
 	t.lock.Lock()         // 加锁
 	defer t.lock.Unlock() // 延迟解锁
 
@@ -180,6 +207,8 @@ func (t *Tracker) clean() {
 
 		m := fmt.Sprintf("%s/%s/%d/%#02x", lostMeterName, t.protocol, req.version, req.reqCode)
 		metrics.GetOrRegisterMeter(m, nil).Mark(1) // 标记丢失指标
+
+		lost = append(lost, req.peer)
 	}
 	t.schedule() // 重新调度
 }
@@ -199,7 +228,7 @@ func (t *Tracker) schedule() {
 // Fulfil fills a pending request, if any is available, reporting on various metrics.
 // Fulfil 填充一个待处理的请求（如果有的话），并报告各种指标。
 func (t *Tracker) Fulfil(peer string, version uint, code uint64, id uint64) {
-	if !metrics.Enabled() { // 如果指标未启用，则返回
+	if !t.enabled() { // 如果既未启用指标也没有订阅，则返回
 		return
 	}
 	t.lock.Lock()         // 加锁
@@ -0,0 +1,188 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// TrafficStats counts the messages sent or received in one direction.
+// TrafficStats 统计一个方向上发送或接收的消息。
+type TrafficStats struct {
+	Packets   uint64 `json:"packets"`
+	WireBytes uint64 `json:"wireBytes"` // Compressed size on the wire // 线路上的压缩大小
+	Bytes     uint64 `json:"bytes"`     // Uncompressed payload size // 未压缩的有效载荷大小
+}
+
+// MessageTraffic is the traffic of a single message code.
+// MessageTraffic 是单个消息代码的流量。
+type MessageTraffic struct {
+	Ingress TrafficStats `json:"ingress"`
+	Egress  TrafficStats `json:"egress"`
+}
+
+// ProtocolTraffic is the traffic of a protocol with a peer, in total and by
+// message code. Only message codes which were used are included.
+//
+// ProtocolTraffic 是与对等方之间某协议的流量，包括总计和按消息代码统计的流量。仅包含使用过的消息代码。
+type ProtocolTraffic struct {
+	MessageTraffic
+	Messages map[string]MessageTraffic `json:"messages"`
+}
+
+// trafficCounter counts messages in one direction.
+// trafficCounter 统计一个方向上的消息。
+type trafficCounter struct {
+	packets   atomic.Uint64
+	wireBytes atomic.Uint64
+	bytes     atomic.Uint64
+}
+
+func (c *trafficCounter) add(wireSize, size uint32) {
+	c.packets.Add(1)
+	c.wireBytes.Add(uint64(wireSize))
+	c.bytes.Add(uint64(size))
+}
+
+func (c *trafficCounter) stats() TrafficStats {
+	return TrafficStats{
+		Packets:   c.packets.Load(),
+		WireBytes: c.wireBytes.Load(),
+		Bytes:     c.bytes.Load(),
+	}
+}
+
+func (s *TrafficStats) add(o TrafficStats) {
+	s.Packets += o.Packets
+	s.WireBytes += o.WireBytes
+	s.Bytes += o.Bytes
+}
+
+// protoTraffic holds the counters of a running protocol, indexed by message code.
+// protoTraffic 保存正在运行的协议的计数器，按消息代码索引。
+type protoTraffic struct {
+	ingress []trafficCounter
+	egress  []trafficCounter
+}
+
+func newProtoTraffic(length uint64) *protoTraffic {
+	return &protoTraffic{
+		ingress: make([]trafficCounter, length),
+		egress:  make([]trafficCounter, length),
+	}
+}
+
+// info returns the traffic statistics of the protocol.
+// info 返回协议的流量统计。
+func (t *protoTraffic) info() *ProtocolTraffic {
+	info := &ProtocolTraffic{Messages: make(map[string]MessageTraffic)}
+	for code := range t.ingress {
+		m := MessageTraffic{Ingress: t.ingress[code].stats(), Egress: t.egress[code].stats()}
+		if m.Ingress.Packets == 0 && m.Egress.Packets == 0 {
+			continue
+		}
+		info.Ingress.add(m.Ingress)
+		info.Egress.add(m.Egress)
+		info.Messages[fmt.Sprintf("%#02x", code)] = m
+	}
+	return info
+}
+
+// Traffic returns the traffic with the peer for each running protocol, keyed by
+// protocol name and version.
+//
+// Traffic 返回与对等方之间每个正在运行的协议的流量，以协议名称和版本为键。
+func (p *Peer) Traffic() map[string]*ProtocolTraffic {
+	traffic := make(map[string]*ProtocolTraffic, len(p.running))
+	for _, proto := range p.running {
+		traffic[proto.cap().String()] = proto.traffic.info()
+	}
+	return traffic
+}
+
+// serveLimiter limits the bandwidth used for serving data to a peer.
+// serveLimiter 限制向对等方提供数据所使用的带宽。
+type serveLimiter struct {
+	limiter *rate.Limiter
+}
+
+// newServeLimiter creates a limiter allowing the given number of bytes per
+// second. It returns nil if the limit is zero.
+//
+// newServeLimiter 创建一个每秒允许给定字节数的限制器。如果限制为零，则返回 nil。
+func newServeLimiter(bytesPerSecond int) *serveLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &serveLimiter{limiter: rate.NewLimiter(rate.Limit(bytesPerSecond), bytesPerSecond)}
+}
+
+// wait blocks until size bytes may be sent. Messages larger than the burst size
+// are charged in several steps. It returns ErrShuttingDown if closed is closed
+// while waiting.
+//
+// wait 阻塞直到可以发送 size 字节。大于突发大小的消息分几步计费。
+// 如果在等待期间 closed 被关闭，则返回 ErrShuttingDown。
+func (l *serveLimiter) wait(size int, closed <-chan struct{}) error {
+	var waited time.Duration
+	for size > 0 {
+		n := min(size, l.limiter.Burst())
+		size -= n
+		r := l.limiter.ReserveN(time.Now(), n)
+		delay := r.Delay()
+		if delay == 0 {
+			continue
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+			waited += delay
+		case <-closed:
+			timer.Stop()
+			r.Cancel()
+			return ErrShuttingDown
+		}
+	}
+	if waited > 0 {
+		serveThrottleTimer.Update(waited)
+	}
+	return nil
+}
+
+// setServeLimit limits the bandwidth of served data sent to the peer. It must
+// be called before the peer is started.
+//
+// setServeLimit 限制发送给对等方的提供数据的带宽。必须在对等方启动之前调用。
+func (p *Peer) setServeLimit(bytesPerSecond int) {
+	limiter := newServeLimiter(bytesPerSecond)
+	for _, proto := range p.running {
+		proto.serve = limiter
+	}
+}
+
+// isServeMessage reports whether the message code of the protocol carries
+// served data.
+//
+// isServeMessage 报告协议的消息代码是否携带提供的数据。
+func (p Protocol) isServeMessage(code uint64) bool {
+	return slices.Contains(p.ServeMessages, code)
+}
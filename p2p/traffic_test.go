@@ -0,0 +1,133 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package p2p
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestPeerTraffic(t *testing.T) {
+	payload := bytes.Repeat([]byte{1}, 1000)
+	size, _, _ := rlp.EncodeToReader(payload)
+
+	done := make(chan struct{})
+	proto := Protocol{
+		Name:   "a",
+		Length: 5,
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			defer close(done)
+			for i := 0; i < 2; i++ {
+				if err := Send(rw, 1, payload); err != nil {
+					return err
+				}
+			}
+			return ExpectMsg(rw, 2, []uint{7})
+		},
+	}
+	closer, rw, peer, _ := testPeer([]Protocol{proto})
+	defer closer()
+
+	for i := 0; i < 2; i++ {
+		if err := ExpectMsg(rw, baseProtocolLength+1, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Send(rw, baseProtocolLength+2, []uint{7}); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+
+	traffic := peer.Traffic()["a/0"]
+	if traffic == nil {
+		t.Fatalf("no traffic for protocol: %v", peer.Traffic())
+	}
+	out := traffic.Messages["0x01"].Egress
+	if out.Packets != 2 || out.Bytes != 2*uint64(size) || out.WireBytes == 0 {
+		t.Errorf("wrong egress stats: %+v", out)
+	}
+	in := traffic.Messages["0x02"].Ingress
+	if in.Packets != 1 || in.Bytes == 0 || in.WireBytes == 0 {
+		t.Errorf("wrong ingress stats: %+v", in)
+	}
+	if traffic.Egress != out || traffic.Ingress != in || len(traffic.Messages) != 2 {
+		t.Errorf("wrong totals: %+v", traffic)
+	}
+}
+
+func TestServeLimiter(t *testing.T) {
+	l := newServeLimiter(1000)
+
+	// The burst is sent immediately, further data is delayed.
+	start := time.Now()
+	if err := l.wait(1000, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.wait(200, nil); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 150*time.Millisecond || d > time.Second {
+		t.Errorf("wrong delay %v", d)
+	}
+
+	// Waiting is aborted when the peer shuts down.
+	closed := make(chan struct{})
+	time.AfterFunc(50*time.Millisecond, func() { close(closed) })
+	if err := l.wait(5000, closed); err != ErrShuttingDown {
+		t.Fatalf("wrong error: %v", err)
+	}
+	if newServeLimiter(0) != nil {
+		t.Error("limiter created without limit")
+	}
+}
+
+func TestPeerServeLimit(t *testing.T) {
+	payload := bytes.Repeat([]byte{1}, 1000)
+	proto := Protocol{
+		Name:          "a",
+		Length:        5,
+		ServeMessages: []uint64{1},
+		Run: func(peer *Peer, rw MsgReadWriter) error {
+			for i := 0; i < 3; i++ {
+				if err := Send(rw, 1, payload); err != nil {
+					return err
+				}
+			}
+			return Send(rw, 2, payload)
+		},
+	}
+	// Three served messages exceed the limit of 2kB/s, and the unserved
+	// message is not limited.
+	closer, rw, _, _ := testPeer([]Protocol{proto}, func(p *Peer) { p.setServeLimit(2000) })
+	defer closer()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := ExpectMsg(rw, baseProtocolLength+1, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(start); d < 400*time.Millisecond {
+		t.Errorf("served messages not limited, took %v", d)
+	}
+	if err := ExpectMsg(rw, baseProtocolLength+2, payload); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -121,11 +121,15 @@ func (t *rlpxTransport) WriteMsg(msg Msg) error {
 	// Set metrics.
 	// 设置指标。
 	msg.meterSize = size
+	if msg.meterEgress != nil {
+		msg.meterEgress.add(msg.meterSize, msg.Size) // 记录对等方流量
+	}
 	if metrics.Enabled() && msg.meterCap.Name != "" { // don't meter non-subprotocol messages
 		// 如果指标启用且消息属于子协议，则记录
 		m := fmt.Sprintf("%s/%s/%d/%#02x", egressMeterName, msg.meterCap.Name, msg.meterCap.Version, msg.meterCode)
-		metrics.GetOrRegisterMeter(m, nil).Mark(int64(msg.meterSize)) // 记录数据量
-		metrics.GetOrRegisterMeter(m+"/packets", nil).Mark(1)         // 记录数据包计数
+		metrics.GetOrRegisterMeter(m, nil).Mark(int64(msg.meterSize))                    // 记录数据量
+		metrics.GetOrRegisterMeter(m+uncompressedMeterSuffix, nil).Mark(int64(msg.Size)) // 记录未压缩数据量
+		metrics.GetOrRegisterMeter(m+"/packets", nil).Mark(1)                            // 记录数据包计数
 	}
 	return nil
 }
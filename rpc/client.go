@@ -114,6 +114,23 @@ type Client struct {
 	// 配置字段
 	batchItemLimit       int // 批量请求中允许的最大条目数。为了防止客户端发送过大的批量请求导致服务器压力过大，通常会设置一个限制。
 	batchResponseMaxSize int // 批量响应的最大大小（字节数或其他单位）。限制批量响应的大小可以防止服务器返回过大的数据，影响客户端性能或网络带宽。
+	batchConcurrency     int // 批量请求中并发执行的调用的最大数量。
+
+	// server-side connection fields
+	// 服务端连接字段
+	callFilter    CallFilter      // 在执行每个方法调用之前被调用的过滤器
+	responseLimit int             // 单个响应的最大字节数，0 表示没有限制
+	recorder      *Recorder       // 记录处理的调用，nil 表示不记录
+	connCtx       context.Context // 连接的基础上下文，携带建立连接时的请求范围值
+
+	// pool is set for multi-endpoint clients, which delegate all requests to it
+	// pool 在多端点客户端中设置，所有请求都委托给它
+	pool *endpointPool
+
+	// subscription re-establishment settings
+	// 订阅重建设置
+	resubscribe bool                  // 连接丢失后是否重新建立订阅
+	onGap       func(SubscriptionGap) // 重新建立订阅后报告缺口的回调
 
 	// writeConn is used for writing to the connection on the caller's goroutine. It should
 	// only be accessed outside of dispatch, with the write lock held. The write lock is
@@ -153,9 +170,16 @@ type clientConn struct {
 // newClientConn 为给定的编解码器创建一个新的客户端连接。
 func (c *Client) newClientConn(conn ServerCodec) *clientConn {
 	ctx := context.Background()
+	if c.connCtx != nil {
+		ctx = c.connCtx // 使用建立连接时的上下文（例如 WebSocket 升级请求的上下文）。
+	}
 	ctx = context.WithValue(ctx, clientContextKey{}, c)                                             // 将当前 *Client 实例存入上下文。
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, conn.peerInfo())                             // 将连接的对端信息（PeerInfo）存入上下文。
 	handler := newHandler(ctx, conn, c.idgen, c.services, c.batchItemLimit, c.batchResponseMaxSize) // 创建处理程序，传入上下文、编解码器、ID 生成器、服务集合及批量限制参数。
+	handler.callFilter = c.callFilter
+	handler.responseLimit = c.responseLimit
+	handler.recorder = c.recorder
+	handler.batchConcurrency = c.batchConcurrency
 	return &clientConn{conn, handler}
 }
 
@@ -251,11 +275,13 @@ func DialContext(ctx context.Context, rawurl string) (*Client, error) {
 // The context is used to cancel or time out the initial connection establishment. It does
 // not affect subsequent interactions with the client.
 //
-// The client reconnects automatically when the connection is lost.
+// The client reconnects automatically when the connection is lost. If additional
+// endpoints are configured via WithEndpoints, the client is connected to all of
+// them and fails over between them.
 //
 // DialOptions 为给定的 URL 创建一个新的 RPC 客户端。你可以提供任何预定义的客户端选项来配置底层传输。
 // 上下文用于取消或超时初始连接建立。它不会影响与客户端的后续交互。
-// 客户端在连接丢失时会自动重连。
+// 客户端在连接丢失时会自动重连。如果通过 WithEndpoints 配置了额外的端点，客户端将连接到所有端点并在它们之间进行故障转移。
 func DialOptions(ctx context.Context, rawurl string, options ...ClientOption) (*Client, error) {
 	// 解析传入的 URL
 	u, err := url.Parse(rawurl)
@@ -269,6 +295,9 @@ func DialOptions(ctx context.Context, rawurl string, options ...ClientOption) (*
 	for _, opt := range options {
 		opt.applyOption(cfg)
 	}
+	if len(cfg.endpoints) > 0 {
+		return newPoolClient(ctx, append([]string{rawurl}, cfg.endpoints...), cfg, options)
+	}
 
 	var reconnect reconnectFunc
 	// 根据 URL 方案选择不同的传输方式
@@ -326,6 +355,13 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig)
 		idgen:                cfg.idgen,              // ID 生成器
 		batchItemLimit:       cfg.batchItemLimit,     // 批处理请求项限制
 		batchResponseMaxSize: cfg.batchResponseLimit, // 批处理响应最大大小
+		batchConcurrency:     cfg.batchConcurrency,   // 批处理并发调用数量
+		callFilter:           cfg.callFilter,         // 调用过滤器
+		responseLimit:        cfg.responseLimit,      // 单个响应的大小限制
+		recorder:             cfg.recorder,           // 调用记录器
+		connCtx:              cfg.connCtx,            // 连接上下文
+		resubscribe:          cfg.resubscribe,        // 是否重新建立订阅
+		onGap:                cfg.onGap,              // 订阅缺口回调
 		writeConn:            conn,                   // 写入连接
 		close:                make(chan struct{}),    // 关闭信号通道
 		closing:              make(chan struct{}),    // 正在关闭信号通道
@@ -360,6 +396,9 @@ func initClient(conn ServerCodec, services *serviceRegistry, cfg *clientConfig)
 // RegisterName 在给定名称下为给定的接收者类型创建服务。如果接收者上的方法不符合作为 RPC 方法或订阅的条件，则返回错误。
 // 否则，将创建一个新服务并添加到此客户端提供给服务器的服务集合中。
 func (c *Client) RegisterName(name string, receiver interface{}) error {
+	if c.pool != nil {
+		return c.pool.registerName(name, receiver)
+	}
 	return c.services.registerName(name, receiver)
 }
 
@@ -384,6 +423,10 @@ func (c *Client) SupportedModules() (map[string]string, error) {
 // Close closes the client, aborting any in-flight requests.
 // Close 关闭客户端，中止任何正在进行的请求。
 func (c *Client) Close() {
+	if c.pool != nil {
+		c.pool.close()
+		return
+	}
 	if c.isHTTP {
 		return
 	}
@@ -401,6 +444,10 @@ func (c *Client) Close() {
 // SetHeader 为客户端的请求添加自定义 HTTP 头。
 // 此方法仅适用于使用 HTTP 的客户端，对于使用其他传输方式的客户端没有任何效果。
 func (c *Client) SetHeader(key, value string) {
+	if c.pool != nil {
+		c.pool.setHeader(key, value)
+		return
+	}
 	if !c.isHTTP {
 		return
 	}
@@ -436,6 +483,9 @@ func (c *Client) CallContext(ctx context.Context, result interface{}, method str
 	if result != nil && reflect.TypeOf(result).Kind() != reflect.Ptr {
 		return fmt.Errorf("call result parameter must be pointer or nil interface: %v", result)
 	}
+	if c.pool != nil {
+		return c.pool.call(ctx, result, method, args...)
+	}
 	msg, err := c.newMessage(method, args...)
 	if err != nil {
 		return err
@@ -512,6 +562,9 @@ func (c *Client) BatchCall(b []BatchElem) error {
 //
 // 用于将多个 JSON-RPC 请求作为一个批次发送，并等待服务器返回所有响应。
 func (c *Client) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	if c.pool != nil {
+		return c.pool.batchCall(ctx, b)
+	}
 	var (
 		msgs = make([]*jsonrpcMessage, len(b))
 		byID = make(map[string]int, len(b)) // byID（ID 到索引的映射）
@@ -590,6 +643,9 @@ func (c *Client) BatchCallContext(ctx context.Context, b []BatchElem) error {
 // Notify 发送一个通知，即一个不期望响应的方法调用。
 // 用于发送不需要响应的 JSON-RPC 通知。它是 go-ethereum RPC 客户端中实现单向通信的便捷接口，适用于不需要服务器确认的场景。
 func (c *Client) Notify(ctx context.Context, method string, args ...interface{}) error {
+	if c.pool != nil {
+		return c.pool.notify(ctx, method, args...)
+	}
 	op := new(requestOp) // 用于注册到分发循环。
 	msg, err := c.newMessage(method, args...)
 	if err != nil {
@@ -650,10 +706,21 @@ func (c *Client) Subscribe(ctx context.Context, namespace string, channel interf
 	if chanVal.IsNil() {
 		panic("channel given to Subscribe must not be nil")
 	}
+	if c.pool != nil {
+		return c.pool.subscribe(ctx, c, namespace, chanVal, args...)
+	}
 	if c.isHTTP { // HTTP 不支持订阅。
 		return nil, ErrNotificationsUnsupported
 	}
+	if c.resubscribe {
+		return c.subscribeResilient(ctx, namespace, chanVal, args...)
+	}
+	return c.subscribe(ctx, namespace, chanVal, args...)
+}
 
+// subscribe registers a subscription on the current connection.
+// subscribe 在当前连接上注册一个订阅。
+func (c *Client) subscribe(ctx context.Context, namespace string, chanVal reflect.Value, args ...interface{}) (*ClientSubscription, error) {
 	// 构造订阅消息
 	// 方法名：namespace + subscribeMethodSuffix（如 eth_subscribe）
 	msg, err := c.newMessage(namespace+subscribeMethodSuffix, args...)
@@ -689,6 +756,9 @@ func (c *Client) Subscribe(ctx context.Context, namespace string, channel interf
 // 当返回 false 时，Subscribe 及相关方法将返回 ErrNotificationsUnsupported。
 // 用于检查客户端的传输协议是否支持订阅功能。
 func (c *Client) SupportsSubscriptions() bool {
+	if c.pool != nil {
+		return c.pool.supportsSubscriptions()
+	}
 	return !c.isHTTP
 }
 
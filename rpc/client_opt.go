@@ -17,7 +17,9 @@
 package rpc
 
 import (
+	"context"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -46,6 +48,26 @@ type clientConfig struct {
 	idgen              func() ID // 用于生成 RPC 请求 ID 的函数
 	batchItemLimit     int       // 批量 RPC 请求中允许的最大条目数
 	batchResponseLimit int       // 批量 RPC 响应中允许的最大条目数
+	batchConcurrency   int       // 批量 RPC 请求中并发执行的调用的最大数量
+
+	// Server-side connection options
+	// 服务端连接选项
+	callFilter    CallFilter      // 在每个方法调用之前调用的过滤器
+	responseLimit int             // 单个响应的最大字节数
+	recorder      *Recorder       // 记录处理的调用
+	connCtx       context.Context // 连接的基础上下文
+
+	// Multi-endpoint options
+	// 多端点选项
+	endpoints      []string        // 除拨号 URL 之外的其他后端端点
+	balancing      BalancingPolicy // 在健康后端之间路由调用的策略
+	healthInterval time.Duration   // 后端健康检查的间隔
+	maxHeadLag     uint64          // 后端落后于最高头部区块的最大区块数
+
+	// Subscription options
+	// 订阅选项
+	resubscribe bool                  // 连接丢失后是否重新建立订阅
+	onGap       func(SubscriptionGap) // 重新建立订阅后调用，报告可能错过的通知
 }
 
 func (cfg *clientConfig) initHeaders() {
@@ -179,3 +201,68 @@ func WithBatchResponseSizeLimit(sizeLimit int) ClientOption {
 		cfg.batchResponseLimit = sizeLimit
 	})
 }
+
+// WithEndpoints configures additional endpoint URLs the client is connected to
+// alongside the dialed one. Calls are routed to a healthy backend according to
+// the balancing policy, idempotent reads are retried on another backend when one
+// fails, and subscriptions are pinned to a single backend, transparently moving
+// to another one if it goes away.
+//
+// Note that filters created via eth_newFilter live on a single backend, so
+// subscriptions should be used instead when connecting to multiple endpoints.
+//
+// WithEndpoints 配置客户端除拨号 URL 之外还要连接的其他端点 URL。调用根据均衡策略路由到健康的后端，
+// 当某个后端失败时，幂等的读取调用会在另一个后端上重试，订阅被固定到单个后端，并在该后端消失时透明地迁移到另一个后端。
+//
+// 注意，通过 eth_newFilter 创建的过滤器只存在于单个后端上，因此连接多个端点时应改用订阅。
+func WithEndpoints(urls ...string) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.endpoints = append(cfg.endpoints, urls...)
+	})
+}
+
+// WithBalancingPolicy configures how calls are distributed among the healthy
+// backends of a multi-endpoint client. The default is RoundRobin.
+//
+// WithBalancingPolicy 配置多端点客户端的调用如何在健康的后端之间分配。默认为 RoundRobin。
+func WithBalancingPolicy(policy BalancingPolicy) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.balancing = policy
+	})
+}
+
+// WithHealthCheck configures the health checking of a multi-endpoint client. The
+// backends are probed via eth_blockNumber at the given interval, and ones whose
+// head block is more than maxHeadLag blocks behind the best backend are avoided.
+//
+// WithHealthCheck 配置多端点客户端的健康检查。后端按给定的间隔通过 eth_blockNumber 进行探测，
+// 头部区块落后于最佳后端超过 maxHeadLag 个区块的后端将被避开。
+func WithHealthCheck(interval time.Duration, maxHeadLag uint64) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.healthInterval = interval
+		cfg.maxHeadLag = maxHeadLag
+	})
+}
+
+// WithResubscribe makes the client re-establish its subscriptions with their
+// original arguments when the connection is lost, delivering the notifications
+// on the same channel. Notifications sent while disconnected are lost, so the
+// optional hook is called with the last notification seen before the gap after
+// the subscription is re-established, allowing the caller to backfill. The hook
+// runs on the delivery path of the subscription and should return quickly.
+//
+// Subscriptions fail with an error only if the server rejects the resubscription.
+// Multi-endpoint clients always move subscriptions to another backend, but the
+// hook is honoured there too.
+//
+// WithResubscribe 使客户端在连接丢失时使用原始参数重新建立其订阅，并在同一通道上交付通知。
+// 断开连接期间发送的通知会丢失，因此在订阅重新建立后，会使用缺口之前看到的最后一个通知调用可选的回调，
+// 以便调用方回填。该回调在订阅的交付路径上运行，应快速返回。
+//
+// 只有当服务器拒绝重新订阅时，订阅才会因错误而失败。多端点客户端总是会将订阅迁移到另一个后端，但回调在那里同样生效。
+func WithResubscribe(onGap func(SubscriptionGap)) ClientOption {
+	return optionFunc(func(cfg *clientConfig) {
+		cfg.resubscribe = true
+		cfg.onGap = onGap
+	})
+}
@@ -0,0 +1,549 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// 多端点客户端 (Multi-endpoint Client)
+//
+// 使用 WithEndpoints 拨号时，返回的 Client 不直接持有连接，而是将调用委托给一组后端客户端。
+// 后端定期通过 eth_blockNumber 进行健康检查：调用失败或头部区块落后过多的后端被视为不健康。
+// 调用按均衡策略路由到健康的后端，幂等的读取在传输失败时在下一个后端上重试；订阅被固定到一个后端，
+// 该后端的订阅失败后会在另一个后端上透明地重新订阅。
+
+// BalancingPolicy selects how a multi-endpoint client distributes calls among
+// its healthy backends.
+// BalancingPolicy 选择多端点客户端如何在其健康的后端之间分配调用。
+type BalancingPolicy int
+
+const (
+	// RoundRobin rotates calls evenly across the healthy backends.
+	// RoundRobin 在健康的后端之间均匀轮换调用。
+	RoundRobin BalancingPolicy = iota
+
+	// LowestLatency sends calls to the healthy backend with the lowest measured
+	// health check latency, using the others as fallbacks.
+	// LowestLatency 将调用发送到健康检查延迟最低的健康后端，其他后端作为备用。
+	LowestLatency
+)
+
+const (
+	defaultHealthInterval = 5 * time.Second // 默认的健康检查间隔
+	defaultMaxHeadLag     = 2               // 默认允许后端落后的最大区块数
+	latencyWeight         = 0.2             // 延迟指数移动平均中新样本的权重
+)
+
+// errNoEndpoint is returned if no backend of a multi-endpoint client is available.
+// errNoEndpoint 在多端点客户端没有可用后端时返回。
+var errNoEndpoint = errors.New("no RPC endpoint available")
+
+// idempotentPrefixes are the method name prefixes of read-only calls, which are
+// safe to retry on another backend.
+// idempotentPrefixes 是只读调用的方法名前缀，可以安全地在另一个后端上重试。
+var idempotentPrefixes = []string{
+	"eth_get", "eth_call", "eth_estimateGas", "eth_createAccessList", "eth_simulateV1",
+	"eth_blockNumber", "eth_chainId", "eth_syncing", "eth_protocolVersion",
+	"eth_gasPrice", "eth_maxPriorityFeePerGas", "eth_feeHistory", "eth_blobBaseFee",
+	"net_", "web3_", "rpc_",
+}
+
+// isIdempotent reports whether a method only reads state and may be retried.
+// Filter polling is excluded as filters only exist on the backend creating them.
+// isIdempotent 报告方法是否只读取状态并且可以重试。过滤器轮询被排除在外，因为过滤器只存在于创建它们的后端上。
+func isIdempotent(method string) bool {
+	if strings.HasPrefix(method, "eth_getFilter") {
+		return false
+	}
+	for _, prefix := range idempotentPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTransportError reports whether an error was caused by the backend being
+// unreachable or broken, rather than the call itself being rejected.
+// isTransportError 报告错误是否是由于后端不可达或故障引起的，而不是调用本身被拒绝。
+func isTransportError(err error) bool {
+	var (
+		rpcErr    Error
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &rpcErr), errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return false
+	case errors.Is(err, ErrNoResult), errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+	return true
+}
+
+// poolBackend is a single endpoint of a multi-endpoint client.
+// poolBackend 是多端点客户端的单个端点。
+type poolBackend struct {
+	url string
+
+	lock    sync.Mutex
+	client  *Client       // 后端的客户端，断开连接时为 nil
+	healthy bool          // 上一次健康检查是否通过（并且之后没有失败）
+	latency time.Duration // 健康检查延迟的指数移动平均
+}
+
+// conn returns the client of the backend, or nil if it's not connected.
+// conn 返回后端的客户端，如果未连接则返回 nil。
+func (b *poolBackend) conn() *Client {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.client
+}
+
+// endpointPool distributes the requests of a multi-endpoint client among its
+// backends and keeps track of their health.
+// endpointPool 在多端点客户端的后端之间分配请求，并跟踪它们的健康状况。
+type endpointPool struct {
+	backends []*poolBackend
+	policy   BalancingPolicy
+	interval time.Duration
+	maxLag   uint64
+	onGap    func(SubscriptionGap)
+	dial     func(ctx context.Context, url string) (*Client, error)
+
+	next      atomic.Uint32 // 轮询计数器
+	confLock  sync.Mutex    // 保护后端的设置，使新连接的后端不会错过设置
+	headers   http.Header   // 通过 SetHeader 设置的 HTTP 头
+	services  []poolService // 通过 RegisterName 注册的服务
+	quit      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// poolService is a service registered on all the backends of a pool.
+// poolService 是在池的所有后端上注册的服务。
+type poolService struct {
+	name     string
+	receiver interface{}
+}
+
+// newPoolClient dials all the given endpoints and creates a client distributing
+// requests among them. It fails only if none of the endpoints can be reached,
+// the others are retried during health checks.
+// newPoolClient 拨号所有给定的端点，并创建一个在它们之间分配请求的客户端。
+// 只有在所有端点都无法访问时才会失败，其他端点会在健康检查期间重试。
+func newPoolClient(ctx context.Context, urls []string, cfg *clientConfig, options []ClientOption) (*Client, error) {
+	// Backends are dialed with the same options, minus the extra endpoints
+	// 后端使用相同的选项拨号，但不包括额外的端点
+	options = append(options, optionFunc(func(cfg *clientConfig) { cfg.endpoints = nil }))
+
+	p := &endpointPool{
+		policy:   cfg.balancing,
+		interval: cfg.healthInterval,
+		maxLag:   cfg.maxHeadLag,
+		onGap:    cfg.onGap,
+		dial: func(ctx context.Context, url string) (*Client, error) {
+			return DialOptions(ctx, url, options...)
+		},
+		headers: make(http.Header),
+		quit:    make(chan struct{}),
+	}
+	if p.interval == 0 {
+		p.interval, p.maxLag = defaultHealthInterval, defaultMaxHeadLag
+	}
+	for _, url := range urls {
+		p.backends = append(p.backends, &poolBackend{url: url})
+	}
+	// Connect to and probe all the backends before starting
+	// 在启动之前连接并探测所有后端
+	p.check(ctx)
+
+	var connected bool
+	for _, b := range p.backends {
+		if b.conn() != nil {
+			connected = true
+		}
+	}
+	if !connected {
+		return nil, errNoEndpoint
+	}
+	p.wg.Add(1)
+	go p.loop()
+
+	c := &Client{
+		pool:     p,
+		idgen:    randomIDGenerator(),
+		services: new(serviceRegistry),
+	}
+	return c, nil
+}
+
+// loop periodically checks the health of the backends.
+// loop 定期检查后端的健康状况。
+func (p *endpointPool) loop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+			p.check(ctx)
+			cancel()
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// check probes all the backends concurrently, reconnecting the disconnected
+// ones, and marks them healthy if they respond and their head block is recent.
+// check 并发探测所有后端，重新连接断开的后端，如果它们有响应并且头部区块足够新，则将它们标记为健康。
+func (p *endpointPool) check(ctx context.Context) {
+	var (
+		wg    sync.WaitGroup
+		heads = make([]*uint64, len(p.backends))
+		alive = make([]bool, len(p.backends))
+	)
+	for i, b := range p.backends {
+		wg.Add(1)
+		go func(i int, b *poolBackend) {
+			defer wg.Done()
+			alive[i], heads[i] = p.probe(ctx, b)
+		}(i, b)
+	}
+	wg.Wait()
+
+	// Backends not reporting a head (non-eth servers) are judged by liveness only
+	// 不报告头部区块的后端（非 eth 服务器）仅根据存活状态判断
+	var best uint64
+	for i := range p.backends {
+		if alive[i] && heads[i] != nil && *heads[i] > best {
+			best = *heads[i]
+		}
+	}
+	for i, b := range p.backends {
+		healthy := alive[i] && (heads[i] == nil || *heads[i]+p.maxLag >= best)
+
+		b.lock.Lock()
+		if b.healthy != healthy {
+			log.Debug("RPC endpoint health changed", "url", b.url, "healthy", healthy)
+		}
+		b.healthy = healthy
+		b.lock.Unlock()
+	}
+}
+
+// probe connects the backend if needed and queries its head block, measuring
+// the latency of the call.
+// probe 在需要时连接后端并查询其头部区块，同时测量调用的延迟。
+func (p *endpointPool) probe(ctx context.Context, b *poolBackend) (alive bool, head *uint64) {
+	client := b.conn()
+	if client == nil {
+		var err error
+		if client, err = p.dial(ctx, b.url); err != nil {
+			log.Debug("Failed to connect RPC endpoint", "url", b.url, "err", err)
+			return false, nil
+		}
+		if err = p.connect(b, client); err != nil {
+			log.Debug("Failed to configure RPC endpoint", "url", b.url, "err", err)
+			client.Close()
+			return false, nil
+		}
+	}
+	var (
+		number hexutil.Uint64
+		start  = time.Now()
+		err    = client.CallContext(ctx, &number, "eth_blockNumber")
+		rtt    = time.Since(start)
+	)
+	if err != nil && !errors.Is(err, ErrNotificationsUnsupported) {
+		// ErrNotificationsUnsupported matches all "method not found" errors
+		// ErrNotificationsUnsupported 匹配所有"方法未找到"错误
+		log.Debug("RPC endpoint health check failed", "url", b.url, "err", err)
+		return false, nil
+	}
+	b.lock.Lock()
+	if b.latency == 0 {
+		b.latency = rtt
+	} else {
+		b.latency = time.Duration((1-latencyWeight)*float64(b.latency) + latencyWeight*float64(rtt))
+	}
+	b.lock.Unlock()
+
+	if err != nil {
+		return true, nil
+	}
+	head = new(uint64)
+	*head = uint64(number)
+	return true, head
+}
+
+// fail marks a backend unhealthy after a transport error, until the next health
+// check succeeds.
+// fail 在发生传输错误后将后端标记为不健康，直到下一次健康检查成功。
+func (p *endpointPool) fail(b *poolBackend, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.healthy {
+		log.Debug("RPC endpoint failed", "url", b.url, "err", err)
+	}
+	b.healthy = false
+}
+
+// candidates returns the connected backends in the order they should be tried.
+// If none is healthy, all connected ones are returned as a last resort.
+// candidates 按应尝试的顺序返回已连接的后端。如果没有健康的后端，则返回所有已连接的后端作为最后的手段。
+func (p *endpointPool) candidates() []*poolBackend {
+	var (
+		healthy   []*poolBackend
+		connected []*poolBackend
+		latencies = make(map[*poolBackend]time.Duration)
+	)
+	for _, b := range p.backends {
+		b.lock.Lock()
+		if b.client != nil {
+			connected = append(connected, b)
+			if b.healthy {
+				healthy = append(healthy, b)
+			}
+			latencies[b] = b.latency
+		}
+		b.lock.Unlock()
+	}
+	list := healthy
+	if len(list) == 0 {
+		list = connected
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	switch p.policy {
+	case LowestLatency:
+		sort.SliceStable(list, func(i, j int) bool { return latencies[list[i]] < latencies[list[j]] })
+	default:
+		offset := int(p.next.Add(1)-1) % len(list)
+		list = append(list[offset:], list[:offset]...)
+	}
+	return list
+}
+
+// call performs a call on the first available backend, moving on to the next one
+// on transport errors if the method is idempotent.
+// call 在第一个可用的后端上执行调用，如果方法是幂等的，则在传输错误时转到下一个后端。
+func (p *endpointPool) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	retry := isIdempotent(method)
+
+	err := errNoEndpoint
+	for _, b := range p.candidates() {
+		if err = b.conn().CallContext(ctx, result, method, args...); err == nil || !isTransportError(err) || ctx.Err() != nil {
+			return err
+		}
+		p.fail(b, err)
+		if !retry {
+			break
+		}
+	}
+	return err
+}
+
+// batchCall sends a batch to the first available backend. On transport errors,
+// the batch is retried on the next backend if it only contains idempotent calls.
+// batchCall 将批处理发送到第一个可用的后端。发生传输错误时，如果批处理只包含幂等调用，则在下一个后端上重试。
+func (p *endpointPool) batchCall(ctx context.Context, b []BatchElem) error {
+	retry := true
+	for _, elem := range b {
+		retry = retry && isIdempotent(elem.Method)
+	}
+	err := errNoEndpoint
+	for _, backend := range p.candidates() {
+		if err = backend.conn().BatchCallContext(ctx, b); err == nil || !isTransportError(err) || ctx.Err() != nil {
+			return err
+		}
+		p.fail(backend, err)
+		if !retry {
+			break
+		}
+	}
+	return err
+}
+
+// notify sends a notification to the first available backend.
+// notify 将通知发送到第一个可用的后端。
+func (p *endpointPool) notify(ctx context.Context, method string, args ...interface{}) error {
+	candidates := p.candidates()
+	if len(candidates) == 0 {
+		return errNoEndpoint
+	}
+	err := candidates[0].conn().Notify(ctx, method, args...)
+	if err != nil && isTransportError(err) {
+		p.fail(candidates[0], err)
+	}
+	return err
+}
+
+// subscribe creates a subscription pinned to one of the backends, which is
+// re-established on another backend whenever it fails.
+// subscribe 创建一个固定到某个后端的订阅，每当它失败时会在另一个后端上重新建立。
+func (p *endpointPool) subscribe(ctx context.Context, c *Client, namespace string, channel reflect.Value, args ...interface{}) (*ClientSubscription, error) {
+	var backend *poolBackend // 当前承载订阅的后端，仅由中继 goroutine 在初始订阅之后访问
+	relay := &subscriptionRelay{
+		subscribe: func(ctx context.Context) (sub *ClientSubscription, in chan json.RawMessage, err error) {
+			backend, sub, in, err = p.subscribeBackend(ctx, namespace, args)
+			return sub, in, err
+		},
+		failed: func(err error) { p.fail(backend, err) },
+		onGap:  p.onGap,
+		quit:   p.quit,
+	}
+	sub, inner, in, err := newSubscriptionRelay(ctx, c, namespace, channel, args, relay)
+	if err != nil {
+		return nil, err
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		relay.run(inner, in)
+	}()
+	return sub, nil
+}
+
+// subscribeBackend subscribes on the first backend supporting subscriptions.
+// subscribeBackend 在第一个支持订阅的后端上进行订阅。
+func (p *endpointPool) subscribeBackend(ctx context.Context, namespace string, args []interface{}) (*poolBackend, *ClientSubscription, chan json.RawMessage, error) {
+	candidates := p.candidates()
+	if len(candidates) == 0 {
+		return nil, nil, nil, errNoEndpoint
+	}
+	err := error(ErrNotificationsUnsupported)
+	for _, b := range candidates {
+		client := b.conn()
+		if !client.SupportsSubscriptions() {
+			continue
+		}
+		in := make(chan json.RawMessage)
+		sub, serr := client.subscribe(ctx, namespace, reflect.ValueOf(in), args...)
+		if serr == nil {
+			return b, sub, in, nil
+		}
+		if err = serr; !isTransportError(err) || ctx.Err() != nil {
+			break
+		}
+		p.fail(b, err)
+	}
+	return nil, nil, nil, err
+}
+
+// connect applies the headers and services set on the pool to a newly dialed
+// client and makes it the client of the backend.
+// connect 将池上设置的头和服务应用到新拨号的客户端，并将其设为后端的客户端。
+func (p *endpointPool) connect(b *poolBackend, client *Client) error {
+	p.confLock.Lock()
+	defer p.confLock.Unlock()
+
+	for key, values := range p.headers {
+		client.SetHeader(key, values[0])
+	}
+	for _, svc := range p.services {
+		if err := client.RegisterName(svc.name, svc.receiver); err != nil {
+			return err
+		}
+	}
+	b.lock.Lock()
+	b.client = client
+	b.lock.Unlock()
+	return nil
+}
+
+// setHeader sets an HTTP header on all the backends, including the ones
+// connected later.
+// setHeader 在所有后端上设置 HTTP 头，包括之后连接的后端。
+func (p *endpointPool) setHeader(key, value string) {
+	p.confLock.Lock()
+	defer p.confLock.Unlock()
+
+	p.headers.Set(key, value)
+	for _, b := range p.backends {
+		if client := b.conn(); client != nil {
+			client.SetHeader(key, value)
+		}
+	}
+}
+
+// registerName registers a service on all the backends, including the ones
+// connected later.
+// registerName 在所有后端上注册服务，包括之后连接的后端。
+func (p *endpointPool) registerName(name string, receiver interface{}) error {
+	p.confLock.Lock()
+	defer p.confLock.Unlock()
+
+	// Check the service even if no backend is connected right now
+	// 即使当前没有已连接的后端，也检查服务是否有效
+	if err := new(serviceRegistry).registerName(name, receiver); err != nil {
+		return err
+	}
+	for _, b := range p.backends {
+		if client := b.conn(); client != nil {
+			if err := client.RegisterName(name, receiver); err != nil {
+				return err
+			}
+		}
+	}
+	p.services = append(p.services, poolService{name, receiver})
+	return nil
+}
+
+// supportsSubscriptions reports whether any backend supports subscriptions.
+// supportsSubscriptions 报告是否有任何后端支持订阅。
+func (p *endpointPool) supportsSubscriptions() bool {
+	for _, b := range p.backends {
+		if client := b.conn(); client != nil && client.SupportsSubscriptions() {
+			return true
+		}
+	}
+	return false
+}
+
+// close stops the health checks and subscriptions, and closes all backends.
+// close 停止健康检查和订阅，并关闭所有后端。
+func (p *endpointPool) close() {
+	p.closeOnce.Do(func() {
+		close(p.quit)
+		p.wg.Wait()
+
+		for _, b := range p.backends {
+			if client := b.conn(); client != nil {
+				client.Close()
+			}
+		}
+	})
+}
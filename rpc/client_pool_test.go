@@ -0,0 +1,285 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// poolTestService is an eth-like backend of a multi-endpoint client.
+type poolTestService struct {
+	head  uint64
+	calls atomic.Int32
+}
+
+func (s *poolTestService) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.head)
+}
+
+func (s *poolTestService) Send() int32 {
+	return s.calls.Add(1)
+}
+
+func (s *poolTestService) GetBalance() int32 {
+	return s.calls.Add(1)
+}
+
+// Ticks sends increasing numbers until unsubscribed.
+func (s *poolTestService) Ticks(ctx context.Context) (*Subscription, error) {
+	notifier, _ := NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-time.After(5 * time.Millisecond):
+				notifier.Notify(sub.ID, i)
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func newPoolTestBackend(t *testing.T, head uint64, ws bool) (*Server, *poolTestService, *httptest.Server, string) {
+	service := &poolTestService{head: head}
+	server := NewServer()
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatal(err)
+	}
+	var httpsrv *httptest.Server
+	if ws {
+		httpsrv = httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	} else {
+		httpsrv = httptest.NewServer(server)
+	}
+	t.Cleanup(func() {
+		httpsrv.Close()
+		server.Stop()
+	})
+	url := httpsrv.URL
+	if ws {
+		url = "ws:" + strings.TrimPrefix(url, "http:")
+	}
+	return server, service, httpsrv, url
+}
+
+func TestClientPoolFailover(t *testing.T) {
+	t.Parallel()
+
+	_, s1, srv1, url1 := newPoolTestBackend(t, 100, false)
+	_, s2, _, url2 := newPoolTestBackend(t, 100, false)
+	_, s3, _, url3 := newPoolTestBackend(t, 50, false) // stale head
+
+	client, err := DialOptions(context.Background(), url1, WithEndpoints(url2, url3), WithHealthCheck(time.Hour, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Calls must be spread across the fresh backends only
+	for i := 0; i < 10; i++ {
+		if err := client.Call(nil, "eth_getBalance"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if s1.calls.Load() != 5 || s2.calls.Load() != 5 || s3.calls.Load() != 0 {
+		t.Fatalf("wrong call distribution: %d %d %d", s1.calls.Load(), s2.calls.Load(), s3.calls.Load())
+	}
+	// Take down a backend, reads must be retried on the other one
+	srv1.Close()
+	for i := 0; i < 4; i++ {
+		if err := client.Call(nil, "eth_getBalance"); err != nil {
+			t.Fatalf("read not retried: %v", err)
+		}
+	}
+	if s2.calls.Load() != 9 {
+		t.Fatalf("wrong number of calls on remaining backend: %d", s2.calls.Load())
+	}
+	// Application errors must not be retried
+	if err := client.Call(nil, "eth_getMissing"); err == nil || isTransportError(err) {
+		t.Fatalf("expected method error, got %v", err)
+	}
+}
+
+func TestClientPoolNoRetryWrites(t *testing.T) {
+	t.Parallel()
+
+	_, _, srv1, url1 := newPoolTestBackend(t, 1, false)
+	_, s2, _, url2 := newPoolTestBackend(t, 1, false)
+
+	client, err := DialOptions(context.Background(), url1, WithEndpoints(url2), WithBalancingPolicy(LowestLatency), WithHealthCheck(time.Hour, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Force the first backend to be preferred, then take it down
+	client.pool.backends[0].latency, client.pool.backends[1].latency = time.Millisecond, time.Second
+	srv1.Close()
+
+	if err := client.Call(nil, "eth_send"); err == nil {
+		t.Fatal("expected write to fail without retry")
+	}
+	if s2.calls.Load() != 0 {
+		t.Fatal("write was retried on another backend")
+	}
+	// The failed backend is avoided from now on
+	if err := client.Call(nil, "eth_send"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientPoolResubscribe(t *testing.T) {
+	t.Parallel()
+
+	server1, _, _, url1 := newPoolTestBackend(t, 1, true)
+	_, _, _, url2 := newPoolTestBackend(t, 1, true)
+
+	client, err := DialOptions(context.Background(), url1, WithEndpoints(url2), WithBalancingPolicy(LowestLatency), WithHealthCheck(time.Hour, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.pool.backends[0].latency, client.pool.backends[1].latency = time.Millisecond, time.Second
+
+	ch := make(chan int)
+	sub, err := client.EthSubscribe(context.Background(), ch, "ticks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	<-ch
+	// Kill the pinned backend, notifications must resume from the other one
+	server1.Stop()
+
+	timeout := time.After(5 * time.Second)
+	for restarted := false; !restarted; {
+		select {
+		case v := <-ch:
+			restarted = v == 0
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-timeout:
+			t.Fatal("subscription not moved to other backend")
+		}
+	}
+}
+
+// Tests that headers and services set on the client apply to backends connected
+// after they were set.
+func TestClientPoolSettingsOnReconnect(t *testing.T) {
+	t.Parallel()
+
+	_, _, _, url1 := newPoolTestBackend(t, 1, false)
+	server2 := NewServer()
+	server2.RegisterName("eth", &poolTestService{head: 1})
+	var header atomic.Value
+	srv2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header.Store(r.Header.Get("X-Test"))
+		server2.ServeHTTP(w, r)
+	}))
+	defer srv2.Close()
+	defer server2.Stop()
+
+	client, err := DialOptions(context.Background(), url1, WithEndpoints(srv2.URL), WithHealthCheck(time.Hour, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Disconnect the second backend, then change the settings
+	backend := client.pool.backends[1]
+	backend.conn().Close()
+	backend.lock.Lock()
+	backend.client = nil
+	backend.lock.Unlock()
+
+	client.SetHeader("X-Test", "value")
+	if err := client.RegisterName("test", new(poolTestService)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.RegisterName("invalid", 1); err == nil {
+		t.Fatal("invalid service registered")
+	}
+	// The reconnected backend gets the settings
+	client.pool.check(context.Background())
+	conn := backend.conn()
+	if conn == nil {
+		t.Fatal("backend not reconnected")
+	}
+	if h, _ := header.Load().(string); h != "value" {
+		t.Fatalf("header not set on reconnected backend: %q", h)
+	}
+	conn.services.mu.Lock()
+	_, ok := conn.services.services["test"]
+	conn.services.mu.Unlock()
+	if !ok {
+		t.Fatal("service not registered on reconnected backend")
+	}
+}
+
+// Tests that batches rejected by a backend don't mark it as failed.
+func TestClientPoolBatchRPCError(t *testing.T) {
+	t.Parallel()
+
+	// The first backend rejects all batches with a single error response
+	server1 := NewServer()
+	server1.RegisterName("eth", &poolTestService{head: 1})
+	srv1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if bytes.HasPrefix(body, []byte("[")) {
+			w.Header().Set("content-type", "application/json")
+			io.WriteString(w, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch too large"}}`)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		server1.ServeHTTP(w, r)
+	}))
+	defer srv1.Close()
+	defer server1.Stop()
+	_, s2, _, url2 := newPoolTestBackend(t, 1, false)
+
+	client, err := DialOptions(context.Background(), srv1.URL, WithEndpoints(url2), WithBalancingPolicy(LowestLatency), WithHealthCheck(time.Hour, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	client.pool.backends[0].latency, client.pool.backends[1].latency = time.Millisecond, time.Second
+
+	batch := []BatchElem{{Method: "eth_getBalance", Result: new(int32)}, {Method: "eth_getBalance", Result: new(int32)}}
+	if err := client.BatchCall(batch); err == nil {
+		t.Fatal("expected batch to be rejected")
+	}
+	if !client.pool.backends[0].healthy {
+		t.Fatal("backend marked failed after rejected batch")
+	}
+	if s2.calls.Load() != 0 {
+		t.Fatal("rejected batch was retried on another backend")
+	}
+}
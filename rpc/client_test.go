@@ -909,6 +909,81 @@ func TestClientReconnect(t *testing.T) {
 	}
 }
 
+func TestClientResubscribe(t *testing.T) {
+	t.Parallel()
+
+	// Track the websocket connections, so they can be dropped.
+	var (
+		mu    sync.Mutex
+		conns []net.Conn
+	)
+	server := NewServer()
+	defer server.Stop()
+	server.RegisterName("eth", new(poolTestService))
+	httpsrv := httptest.NewUnstartedServer(server.WebsocketHandler([]string{"*"}))
+	httpsrv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateHijacked {
+			mu.Lock()
+			conns = append(conns, c)
+			mu.Unlock()
+		}
+	}
+	httpsrv.Start()
+	defer httpsrv.Close()
+
+	gaps := make(chan SubscriptionGap, 1)
+	client, err := DialOptions(context.Background(), "ws:"+strings.TrimPrefix(httpsrv.URL, "http:"), WithResubscribe(func(gap SubscriptionGap) {
+		gaps <- gap
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ch := make(chan int)
+	sub, err := client.EthSubscribe(context.Background(), ch, "ticks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-ch
+	<-ch
+
+	// Drop the connection, the subscription must be re-established on the same channel.
+	mu.Lock()
+	for _, c := range conns {
+		c.Close()
+	}
+	mu.Unlock()
+
+	timeout := time.After(10 * time.Second)
+	for restarted := false; !restarted; {
+		select {
+		case v := <-ch:
+			restarted = v == 0
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-timeout:
+			t.Fatal("subscription not re-established")
+		}
+	}
+	select {
+	case gap := <-gaps:
+		if gap.Namespace != "eth" || len(gap.Args) != 1 || gap.Args[0] != "ticks" {
+			t.Fatalf("wrong gap subscription: %s %v", gap.Namespace, gap.Args)
+		}
+		if gap.Last == nil || gap.Err == nil {
+			t.Fatalf("gap lacks last notification or error: %+v", gap)
+		}
+	default:
+		t.Fatal("gap not reported")
+	}
+	// Unsubscribing must still work after the subscription moved.
+	sub.Unsubscribe()
+	if _, ok := <-sub.Err(); ok {
+		t.Fatal("error channel not closed after unsubscribe")
+	}
+}
+
 func httpTestClient(srv *Server, transport string, fl *flakeyListener) (*Client, *httptest.Server) {
 	// Create the HTTP server.
 	var hs *httptest.Server
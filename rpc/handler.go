@@ -28,6 +28,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -90,6 +91,13 @@ type handler struct {
 	batchRequestLimit    int  // 允许在单个批量请求中包含的最大请求数。
 	batchResponseMaxSize int  //  允许为单个批量响应生成的最大大小。
 
+	callFilter    CallFilter // 在执行每个方法调用之前调用的过滤器，返回错误时拒绝调用。
+	responseLimit int        // 单个响应的最大大小（字节），0 表示没有限制。
+	recorder      *Recorder  // 记录处理的调用，nil 表示不记录。
+
+	batchConcurrency int           // 连接上并发执行的批处理调用的最大数量，0 或 1 表示按顺序执行。
+	batchSlots       chan struct{} // 连接的批处理工作槽位，在第一个批处理时创建。
+
 	subLock    sync.Mutex           // 用于保护对 serverSubs map 的并发访问。
 	serverSubs map[ID]*Subscription // 存储了服务器端已建立的订阅。
 }
@@ -128,41 +136,63 @@ func newHandler(connCtx context.Context, conn jsonWriter, idgen func() ID, reg *
 //
 // batchCallBuffer 管理批量调用期间正在处理的调用消息及其响应。
 // 调用需要在处理 goroutine 和超时触发 goroutine 之间同步。
+//
+// Calls of a batch may be executed concurrently and finish in any order. Responses
+// are kept at the position of their call, so they are written in request order. The
+// response size limit is applied to the responses in request order as well, which
+// makes the outcome independent of the order of completion.
+//
+// 批处理中的调用可能并发执行并以任意顺序完成。响应保存在其调用的位置上，因此按请求顺序写入。
+// 响应大小限制同样按请求顺序应用于响应，使结果与完成顺序无关。
 type batchCallBuffer struct {
 	mutex sync.Mutex
 	calls []*jsonrpcMessage // 批量调用中的消息列表
-	resp  []*jsonrpcMessage // 批量调用的响应列表
+	resp  []*jsonrpcMessage // 批量调用的响应列表，与 calls 一一对应
+	done  []bool            // 指示对应的调用是否已完成
 	wrote bool              // 指示响应是否已写入
-}
 
-// nextCall returns the next unprocessed message.
-// nextCall 返回下一个未处理的消息。
-func (b *batchCallBuffer) nextCall() *jsonrpcMessage {
-	b.mutex.Lock()
-	defer b.mutex.Unlock()
+	sizeLimit int // 批量响应的最大大小，0 表示没有限制
+	size      int // 已计入的响应大小
+	counted   int // 已计入大小的调用数量（按请求顺序）
+	cutoff    int // 从此位置开始的调用以错误响应
+}
 
-	if len(b.calls) == 0 {
-		return nil
+func newBatchCallBuffer(calls []*jsonrpcMessage, sizeLimit int) *batchCallBuffer {
+	return &batchCallBuffer{
+		calls:     calls,
+		resp:      make([]*jsonrpcMessage, len(calls)),
+		done:      make([]bool, len(calls)),
+		sizeLimit: sizeLimit,
+		cutoff:    len(calls),
 	}
-	// The popping happens in `pushAnswer`. The in progress call is kept
-	// so we can return an error for it in case of timeout.
-	// 弹出操作发生在 `pushResponse` 中。正在处理的调用被保留，
-	// 这样在超时的情况下我们可以为其返回错误。
-	msg := b.calls[0] // 表示下一个待处理的消息
-	return msg
 }
 
-// pushResponse adds the response to last call returned by nextCall.
-// pushResponse 将响应添加到 nextCall 返回的最后一个调用。
-// 将一个调用的响应添加到 b.resp 列表中，并将对应的调用从 b.calls 列表中移除。
-func (b *batchCallBuffer) pushResponse(answer *jsonrpcMessage) {
+// pushResponse adds the response of the i'th call. It reports whether the batch
+// response size limit is exceeded by the responses up to and including the first
+// unfinished call.
+//
+// pushResponse 添加第 i 个调用的响应。它报告直到第一个未完成调用为止的响应是否超过了批量响应大小限制。
+func (b *batchCallBuffer) pushResponse(i int, answer *jsonrpcMessage) bool {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	if answer != nil {
-		b.resp = append(b.resp, answer)
+	if b.wrote {
+		return false // too late, the call was answered with an error
 	}
-	b.calls = b.calls[1:]
+	b.resp[i], b.done[i] = answer, true
+	for b.counted < b.cutoff && b.done[b.counted] {
+		if resp := b.resp[b.counted]; resp != nil {
+			b.size += len(resp.Result)
+		}
+		b.counted++
+		if b.sizeLimit != 0 && b.size > b.sizeLimit {
+			// The responses after this one are dropped, even if they are done
+			// 此响应之后的响应将被丢弃，即使它们已完成
+			b.cutoff = b.counted
+			return true
+		}
+	}
+	return false
 }
 
 // write sends the responses.
@@ -173,7 +203,7 @@ func (b *batchCallBuffer) write(ctx context.Context, conn jsonWriter) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	b.doWrite(ctx, conn, false)
+	b.doWrite(ctx, conn, nil)
 }
 
 // respondWithError sends the responses added so far. For the remaining unanswered call
@@ -185,25 +215,35 @@ func (b *batchCallBuffer) respondWithError(ctx context.Context, conn jsonWriter,
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	for _, msg := range b.calls {
-		if !msg.isNotification() { // 如果消息不是通知（notification，即没有 ID 的请求），则调用 msg.errorResponse(err) 为其创建一个包含错误的响应，并添加到 b.resp 列表中。
-			b.resp = append(b.resp, msg.errorResponse(err))
-		}
-	}
-	b.doWrite(ctx, conn, true)
+	b.doWrite(ctx, conn, err)
 }
 
-// doWrite actually writes the response.
+// doWrite actually writes the response. Calls which are unfinished or past the
+// size limit cutoff are answered with err.
 // This assumes b.mutex is held.
-// doWrite 实际写入响应。
+//
+// doWrite 实际写入响应。未完成或超过大小限制截止位置的调用以 err 响应。
 // 这假定 b.mutex 已被持有
-func (b *batchCallBuffer) doWrite(ctx context.Context, conn jsonWriter, isErrorResponse bool) {
+func (b *batchCallBuffer) doWrite(ctx context.Context, conn jsonWriter, err error) {
 	if b.wrote {
 		return
 	}
 	b.wrote = true // can only write once  只能写入一次
-	if len(b.resp) > 0 {
-		conn.writeJSON(ctx, b.resp, isErrorResponse)
+
+	resp := make([]*jsonrpcMessage, 0, len(b.calls))
+	for i, msg := range b.calls {
+		switch {
+		case b.done[i] && i < b.cutoff:
+			if b.resp[i] != nil {
+				resp = append(resp, b.resp[i])
+			}
+		case err != nil && !msg.isNotification():
+			// 如果消息不是通知（notification，即没有 ID 的请求），则为其创建一个包含错误的响应
+			resp = append(resp, msg.errorResponse(err))
+		}
+	}
+	if len(resp) > 0 {
+		conn.writeJSON(ctx, resp, err != nil)
 	}
 }
 
@@ -240,18 +280,36 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 		return
 	}
 
+	// Batches are executed sequentially unless concurrency was enabled, as calls
+	// may depend on the side effects of the earlier ones. The worker slots of
+	// concurrent batches are shared by all batches of the connection. This is safe
+	// to set up here because batches are dispatched by a single goroutine.
+	// 除非启用了并发，否则批处理按顺序执行，因为调用可能依赖于之前调用的副作用。
+	// 并发批处理的工作槽位由连接的所有批处理共享。在这里设置是安全的，因为批处理由单个 goroutine 分派。
+	concurrent := h.batchConcurrency > 1
+	if concurrent && h.batchSlots == nil {
+		h.batchSlots = make(chan struct{}, h.batchConcurrency)
+	}
+
 	// Process calls on a goroutine because they may block indefinitely:
 	// 在一个 goroutine 中处理调用，因为它们可能会无限期地阻塞：
 	h.startCallProc(func(cp *callProc) {
 		var (
+			start      = time.Now()
 			timer      *time.Timer
 			cancel     context.CancelFunc
-			callBuffer = &batchCallBuffer{calls: calls, resp: make([]*jsonrpcMessage, 0, len(calls))}
+			callBuffer = newBatchCallBuffer(calls, h.batchResponseMaxSize)
 		)
 
 		cp.ctx, cancel = context.WithCancel(cp.ctx)
 		defer cancel()
 
+		// Trace the batch as a whole, the calls are traced as its children
+		// 将整个批处理作为一个整体进行追踪，调用作为其子 span 被追踪
+		var span *tracing.Span
+		cp.ctx, span = tracing.StartServer(cp.ctx, "rpc.batch", tracing.Int("rpc.batch.size", len(calls)))
+		defer span.End()
+
 		// Cancel the request context after timeout and send an error response. Since the
 		// currently-running method might not return immediately on timeout, we must wait
 		// for the timeout concurrently with processing the request.
@@ -265,31 +323,69 @@ func (h *handler) handleBatch(msgs []*jsonrpcMessage) {
 			})
 		}
 
-		responseBytes := 0
-		for {
-			// No need to handle rest of calls if timed out.
-			// 如果超时，则无需处理其余调用。
-			if cp.ctx.Err() != nil {
-				break
+		// Start the calls in request order, concurrent ones each on a worker slot.
+		// Every call gets its own callProc, so subscriptions created by concurrent
+		// calls don't race.
+		// 按请求顺序启动调用，并发调用各占用一个工作槽位。每个调用都有自己的 callProc，
+		// 因此并发调用创建的订阅不会产生竞争。
+		var (
+			wg    sync.WaitGroup
+			procs = make([]*callProc, len(calls))
+		)
+		run := func(i int, msg *jsonrpcMessage) {
+			resp := h.handleCallMsg(procs[i], msg)
+			if resp != nil && resp.stream != nil {
+				// Batch responses are written as a whole, encode the stream here so
+				// its size counts towards the batch limit.
+				// 批量响应作为整体写入，在此编码流，使其大小计入批量限制。
+				resp = resp.materialize()
 			}
-			msg := callBuffer.nextCall()
-			if msg == nil {
-				break
+			if callBuffer.pushResponse(i, resp) {
+				cancel()
+				err := &internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge}
+				callBuffer.respondWithError(cp.ctx, h.conn, err)
 			}
-			resp := h.handleCallMsg(cp, msg)
-			callBuffer.pushResponse(resp)
-			if resp != nil && h.batchResponseMaxSize != 0 {
-				responseBytes += len(resp.Result)
-				if responseBytes > h.batchResponseMaxSize {
-					err := &internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge}
-					callBuffer.respondWithError(cp.ctx, h.conn, err)
+		}
+	dispatch:
+		for i, msg := range calls {
+			if !concurrent {
+				// No need to handle rest of calls if timed out.
+				// 如果超时，则无需处理其余调用。
+				if cp.ctx.Err() != nil {
 					break
 				}
+				procs[i] = &callProc{ctx: cp.ctx}
+				run(i, msg)
+				continue
+			}
+			select {
+			case h.batchSlots <- struct{}{}:
+			case <-cp.ctx.Done():
+				break dispatch
+			}
+			if cp.ctx.Err() != nil {
+				<-h.batchSlots
+				break
 			}
+			procs[i] = &callProc{ctx: cp.ctx}
+			wg.Add(1)
+			go func(i int, msg *jsonrpcMessage) {
+				defer wg.Done()
+				defer func() { <-h.batchSlots }()
+				run(i, msg)
+			}(i, msg)
 		}
+		wg.Wait()
 		if timer != nil {
 			timer.Stop()
 		}
+		for _, proc := range procs {
+			if proc != nil {
+				cp.notifiers = append(cp.notifiers, proc.notifiers...)
+			}
+		}
+		rpcBatchSizeHistogram.Update(int64(len(calls)))
+		rpcBatchTimer.UpdateSince(start)
 
 		h.addSubscriptions(cp.notifiers)
 		callBuffer.write(cp.ctx, h.conn)
@@ -361,6 +457,10 @@ func (h *handler) handleNonBatchCall(cp *callProc, msg *jsonrpcMessage) {
 		responded.Do(func() {
 			h.conn.writeJSON(cp.ctx, answer, false)
 		})
+		// The span of a streamed result is ended while writing it, unless the
+		// response was never written because the call timed out
+		// 流式结果的 span 在写入时结束，除非调用超时导致响应从未写入
+		answer.finishSpan(nil)
 	}
 	for _, n := range cp.notifiers {
 		n.activate()
@@ -553,12 +653,20 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 	start := time.Now() // 记录处理开始时间
 	switch {
 	case msg.isNotification(): // 如果是通知消息（没有 ID）
-		h.handleCall(ctx, msg)                                           // 处理调用（但不返回响应）
+		h.handleCall(ctx, msg).finishSpan(nil)                           // 处理调用（但不返回响应）
 		h.log.Debug("Served "+msg.Method, "duration", time.Since(start)) // 记录调试日志，包含方法名和处理时长
 		return nil                                                       // 通知消息没有响应
 
 	case msg.isCall(): // 如果是调用消息（有 ID）
-		resp := h.handleCall(ctx, msg)                                                    // 处理调用并获取响应
+		resp := h.handleCall(ctx, msg) // 处理调用并获取响应
+		if h.recorder != nil {
+			// Streams can only be produced once, encode them for the recording
+			// 流只能产生一次，为记录对其进行编码
+			if resp.stream != nil {
+				resp = resp.materialize()
+			}
+			h.recorder.record(ctx.ctx, msg, resp, start)
+		}
 		var logctx []any                                                                  // 创建一个用于日志上下文的切片
 		logctx = append(logctx, "reqid", idForLog{msg.ID}, "duration", time.Since(start)) // 添加请求 ID 和处理时长到日志上下文
 		if resp.Error != nil {                                                            // 如果响应包含错误
@@ -583,6 +691,11 @@ func (h *handler) handleCallMsg(ctx *callProc, msg *jsonrpcMessage) *jsonrpcMess
 // handleCall processes method calls.
 // handleCall 处理方法调用。
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage {
+	if h.callFilter != nil { // 如果设置了调用过滤器，先检查是否允许该调用
+		if err := h.callFilter(cp.ctx, msg.Method); err != nil {
+			return msg.errorResponse(err)
+		}
+	}
 	if msg.isSubscribe() { // 如果是订阅请求
 		return h.handleSubscribe(cp, msg) // 调用 handleSubscribe 处理
 	}
@@ -600,8 +713,22 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage) *jsonrpcMessage
 	if err != nil {
 		return msg.errorResponse(&invalidParamsError{err.Error()})
 	}
-	start := time.Now()                             // 记录方法调用的开始时间
-	answer := h.runMethod(cp.ctx, msg, callb, args) // 执行方法
+	ctx, span := tracing.StartServer(cp.ctx, msg.Method, tracing.String("rpc.system", "jsonrpc"), tracing.String("rpc.method", msg.Method))
+
+	start := time.Now()                          // 记录方法调用的开始时间
+	answer := h.runMethod(ctx, msg, callb, args) // 执行方法
+	switch {
+	case answer.stream != nil:
+		// Streamed results are produced while writing the response, which is
+		// where most of the work happens. The span is ended once it's done.
+		// 流式结果在写入响应时生成，大部分工作都在此时完成。span 在完成后结束。
+		answer.span = span
+	case answer.Error != nil:
+		span.SetError(answer.Error)
+		span.End()
+	default:
+		span.End()
+	}
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
@@ -681,7 +808,15 @@ func (h *handler) runMethod(ctx context.Context, msg *jsonrpcMessage, callb *cal
 	if err != nil {
 		return msg.errorResponse(err) // 返回包含错误的 JSON-RPC 响应
 	}
-	return msg.response(result) // 返回包含结果的 JSON-RPC 响应
+	resp := msg.response(result) // 创建包含结果的 JSON-RPC 响应
+	if resp.stream != nil {
+		// The size limit of streamed results is enforced while encoding
+		// 流式结果的大小限制在编码时执行
+		resp.stream.limit = h.responseLimit
+	} else if h.responseLimit > 0 && len(resp.Result) > h.responseLimit {
+		return msg.errorResponse(errResponseTooLarge)
+	}
+	return resp
 }
 
 // 以太坊的 JSON-RPC API 提供了订阅功能，允许客户端订阅例如新的区块头、日志等事件。
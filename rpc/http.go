@@ -30,6 +30,8 @@ import (
 	"strconv"
 	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum/internal/tracing"
 )
 
 const (
@@ -310,6 +312,8 @@ type httpServerConn struct {
 	io.Reader
 	io.Writer
 	r *http.Request
+
+	aborted bool // set if a streamed response failed midway 如果流式响应中途失败则设置
 }
 
 // 用于将 HTTP 连接封装为一个符合 ServerCodec 接口的对象，以便在以太坊节点中处理 JSON-RPC 请求。
@@ -368,7 +372,15 @@ func (s *Server) newHTTPServerConn(r *http.Request, w http.ResponseWriter) Serve
 	dec := json.NewDecoder(conn)
 	dec.UseNumber()
 
-	return NewFuncCodec(conn, encoder, dec.Decode)
+	codec := NewFuncCodec(conn, encoder, dec.Decode).(*jsonCodec)
+	codec.stream = func(write func(io.Writer) error) error {
+		return write(w)
+	}
+	// A partially written response can't be completed, the request is aborted by
+	// ServeHTTP once the handler returns.
+	// 部分写入的响应无法完成，请求将在处理程序返回后由 ServeHTTP 中止。
+	codec.onAbort = func() { conn.aborted = true }
+	return codec
 }
 
 // Close does nothing and always returns nil.
@@ -413,6 +425,8 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	ctx = context.WithValue(ctx, peerInfoContextKey{}, connInfo)
 
+	ctx = contextWithTraceparent(ctx, r)
+
 	// All checks passed, create a codec that reads directly from the request body
 	// until EOF, writes the response to w, and orders the server to process a
 	// single request.
@@ -422,6 +436,13 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	codec := s.newHTTPServerConn(r, w)
 	defer codec.close()
 	s.serveSingleRequest(ctx, codec)
+
+	// Abort the connection if a streamed response failed midway, so the client
+	// doesn't mistake the truncated output for a complete response.
+	// 如果流式响应中途失败则中止连接，使客户端不会将截断的输出误认为完整的响应。
+	if codec.(*jsonCodec).conn.(*httpServerConn).aborted {
+		panic(http.ErrAbortHandler)
+	}
 }
 
 // validateRequest returns a non-zero response code and error message if the
@@ -495,3 +516,15 @@ func ContextRequestTimeout(ctx context.Context) (time.Duration, bool) {
 
 	return timeout, hasTimeout
 }
+
+// contextWithTraceparent continues the trace of the caller if it propagated one
+// in the traceparent header of the request.
+// contextWithTraceparent 如果调用方在请求的 traceparent 头中传播了追踪上下文，则延续其追踪。
+func contextWithTraceparent(ctx context.Context, r *http.Request) context.Context {
+	if header := r.Header.Get("traceparent"); header != "" {
+		if sc, err := tracing.ParseTraceparent(header); err == nil {
+			ctx = tracing.ContextWithRemoteSpan(ctx, sc)
+		}
+	}
+	return ctx
+}
@@ -27,6 +27,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/ethereum/go-ethereum/internal/tracing"
 )
 
 // 用于统一 RPC 服务的方法命名规则和默认超时设置。
@@ -100,6 +102,9 @@ type jsonrpcMessage struct {
 	Params  json.RawMessage `json:"params,omitempty"`  // 请求或通知的参数，未解析的 JSON 数据。在请求和通知中存在，响应中不存在。
 	Error   *jsonError      `json:"error,omitempty"`   // 错误对象。在错误响应中存在，其他情况不存在。
 	Result  json.RawMessage `json:"result,omitempty"`  // 成功响应的结果，未解析的 JSON 数据。在成功响应中存在，其他情况不存在。
+
+	stream *Stream       // result encoded while writing, set instead of Result 在写入时编码的结果，代替 Result 设置
+	span   *tracing.Span // span of a streamed call, ended once the result was produced 流式调用的 span，在结果生成后结束
 }
 
 // 判断消息是否为 JSON-RPC 通知。
@@ -163,6 +168,9 @@ func (msg *jsonrpcMessage) errorResponse(err error) *jsonrpcMessage {
 }
 
 func (msg *jsonrpcMessage) response(result interface{}) *jsonrpcMessage {
+	if stream, ok := result.(*Stream); ok {
+		return &jsonrpcMessage{Version: vsn, ID: msg.ID, stream: stream}
+	}
 	enc, err := json.Marshal(result)
 	if err != nil {
 		return msg.errorResponse(&internalServerError{errcodeMarshalError, err.Error()})
@@ -240,10 +248,18 @@ type jsonCodec struct {
 	encMu   sync.Mutex       // guards the encoder          保护编码器
 	encode  encodeFunc       // encoder to allow multiple transports 编码器，允许使用多种传输方式
 	conn    deadlineCloser
+
+	stream  streamFunc // writes streamed responses, nil if unsupported 写入流式响应，不支持时为 nil
+	onAbort func()     // aborts the connection after a failed stream, defaults to close 在流失败后中止连接，默认为关闭
 }
 
 type encodeFunc = func(v interface{}, isErrorResponse bool) error
 
+// streamFunc opens the writer of a single outgoing message and passes it to the
+// write function, completing the message after it returns.
+// streamFunc 打开单个传出消息的写入器并将其传递给 write 函数，在其返回后完成该消息。
+type streamFunc = func(write func(w io.Writer) error) error
+
 type decodeFunc = func(v interface{}) error
 
 // NewFuncCodec creates a codec which uses the given functions to read and write. If conn
@@ -278,7 +294,11 @@ func NewCodec(conn Conn) ServerCodec {
 	encode := func(v interface{}, isErrorResponse bool) error {
 		return enc.Encode(v)
 	}
-	return NewFuncCodec(conn, encode, dec.Decode)
+	codec := NewFuncCodec(conn, encode, dec.Decode).(*jsonCodec)
+	codec.stream = func(write func(io.Writer) error) error {
+		return write(conn)
+	}
+	return codec
 }
 
 func (c *jsonCodec) peerInfo() PeerInfo {
@@ -321,6 +341,15 @@ func (c *jsonCodec) writeJSON(ctx context.Context, v interface{}, isErrorRespons
 		deadline = time.Now().Add(defaultWriteTimeout)
 	}
 	c.conn.SetWriteDeadline(deadline)
+
+	// Streamed results are encoded while writing if the transport supports it
+	// 如果传输支持，流式结果在写入时进行编码
+	if msg, ok := v.(*jsonrpcMessage); ok && msg.stream != nil {
+		if c.stream != nil {
+			return c.writeStream(msg)
+		}
+		v = msg.materialize()
+	}
 	return c.encode(v, isErrorResponse)
 }
 
@@ -33,6 +33,9 @@ var (
 	serveTimeHistName = "rpc/duration"
 
 	rpcServingTimer = metrics.NewRegisteredTimer("rpc/duration/all", nil) // 所有 RPC 请求的服务时间计时器
+
+	rpcBatchTimer         = metrics.NewRegisteredTimer("rpc/batch/duration", nil)                                         // 批处理请求的执行时间计时器
+	rpcBatchSizeHistogram = metrics.NewRegisteredHistogram("rpc/batch/size", nil, metrics.NewExpDecaySample(1028, 0.015)) // 批处理请求中调用数量的直方图
 )
 
 // updateServeTimeHistogram tracks the serving time of a remote RPC call.
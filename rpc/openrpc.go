@@ -0,0 +1,330 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/internal/version"
+)
+
+// OpenRPC 服务发现 (OpenRPC Service Discovery)
+//
+// rpc_discover 通过反射已注册的服务接收者，生成描述服务器所暴露方法的 OpenRPC 文档：方法名、参数和返回值的
+// Go 类型被映射为 JSON Schema，订阅通过 "x-subscription" 扩展字段标记。由于每个端点的服务器只注册了在该端点
+// 启用的模块（见 node.RegisterApis），文档自然只包含该端点可用的方法。
+
+// openrpcVersion is the version of the OpenRPC specification the generated
+// documents conform to.
+// openrpcVersion 是生成的文档所遵循的 OpenRPC 规范版本。
+const openrpcVersion = "1.2.6"
+
+// OpenRPCDocument is a service description in the OpenRPC format.
+// OpenRPCDocument 是 OpenRPC 格式的服务描述。
+type OpenRPCDocument struct {
+	OpenRPC string `json:"openrpc"`
+	Info    struct {
+		Title   string `json:"title"`
+		Version string `json:"version"`
+	} `json:"info"`
+	Methods    []*OpenRPCMethod `json:"methods"`
+	Components struct {
+		Schemas map[string]*JSONSchema `json:"schemas,omitempty"`
+	} `json:"components"`
+}
+
+// OpenRPCMethod describes a single callable method, or a subscription.
+// OpenRPCMethod 描述单个可调用方法或订阅。
+type OpenRPCMethod struct {
+	Name         string                      `json:"name"`
+	Summary      string                      `json:"summary,omitempty"`
+	Params       []*OpenRPCContentDescriptor `json:"params"`
+	Result       *OpenRPCContentDescriptor   `json:"result"`
+	Subscription *OpenRPCSubscription        `json:"x-subscription,omitempty"`
+}
+
+// OpenRPCSubscription marks a method entry as a subscription, which is created
+// via the subscribe method of its namespace rather than called directly.
+// OpenRPCSubscription 将方法条目标记为订阅，订阅通过其命名空间的 subscribe 方法创建，而不是直接调用。
+type OpenRPCSubscription struct {
+	Subscribe   string `json:"subscribe"`   // Method creating the subscription, e.g. eth_subscribe // 创建订阅的方法
+	Unsubscribe string `json:"unsubscribe"` // Method cancelling the subscription, e.g. eth_unsubscribe // 取消订阅的方法
+	Name        string `json:"name"`        // Subscription name passed as the first parameter // 作为第一个参数传递的订阅名称
+}
+
+// OpenRPCContentDescriptor describes a parameter or result of a method.
+// OpenRPCContentDescriptor 描述方法的参数或结果。
+type OpenRPCContentDescriptor struct {
+	Name     string      `json:"name"`
+	Required bool        `json:"required,omitempty"`
+	Schema   *JSONSchema `json:"schema"`
+}
+
+// JSONSchema is the subset of JSON Schema used to describe Go types.
+// JSONSchema 是用于描述 Go 类型的 JSON Schema 子集。
+type JSONSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// uintPattern matches hex encoded quantities without leading zeroes.
+	// uintPattern 匹配没有前导零的十六进制编码数量。
+	uintPattern = "^0x(0|[1-9a-f][0-9a-f]*)$"
+
+	// knownSchemas are the schemas of types with a custom encoding, which can't
+	// be derived via reflection.
+	// knownSchemas 是具有自定义编码的类型的 schema，无法通过反射推导。
+	knownSchemas = map[reflect.Type]JSONSchema{
+		reflect.TypeOf(common.Address{}):    {Title: "address", Type: "string", Pattern: "^0x[0-9a-fA-F]{40}$"},
+		reflect.TypeOf(common.Hash{}):       {Title: "hash", Type: "string", Pattern: "^0x[0-9a-f]{64}$"},
+		reflect.TypeOf(hexutil.Big{}):       {Title: "uint", Type: "string", Pattern: uintPattern},
+		reflect.TypeOf(hexutil.Uint64(0)):   {Title: "uint64", Type: "string", Pattern: uintPattern},
+		reflect.TypeOf(hexutil.Uint(0)):     {Title: "uint", Type: "string", Pattern: uintPattern},
+		reflect.TypeOf(hexutil.Bytes{}):     {Title: "bytes", Type: "string", Pattern: "^0x([0-9a-f][0-9a-f])*$"},
+		reflect.TypeOf(big.Int{}):           {Title: "integer", Type: "integer"},
+		reflect.TypeOf(json.RawMessage{}):   {},
+		reflect.TypeOf(BlockNumber(0)):      {Title: "blockNumberOrTag", Type: "string", Pattern: "^(0x(0|[1-9a-f][0-9a-f]*)|earliest|latest|safe|finalized|pending)$"},
+		reflect.TypeOf(BlockNumberOrHash{}): {Title: "blockNumberOrHashOrTag"},
+		reflect.TypeOf(ID("")):              {Title: "subscriptionId", Type: "string"},
+	}
+
+	// schemaNameInvalid matches the characters not permitted in component names.
+	// schemaNameInvalid 匹配组件名称中不允许的字符。
+	schemaNameInvalid = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// Discover returns an OpenRPC document describing all the methods and
+// subscriptions served by the server.
+// Discover 返回描述服务器提供的所有方法和订阅的 OpenRPC 文档。
+func (s *RPCService) Discover() *OpenRPCDocument {
+	s.server.services.mu.Lock()
+	defer s.server.services.mu.Unlock()
+
+	doc := &OpenRPCDocument{OpenRPC: openrpcVersion}
+	doc.Info.Title = "Go Ethereum JSON-RPC API"
+	doc.Info.Version = version.WithMeta
+	doc.Methods = []*OpenRPCMethod{}
+
+	gen := &schemaGenerator{schemas: make(map[string]*JSONSchema), names: make(map[reflect.Type]string)}
+
+	namespaces := make([]string, 0, len(s.server.services.services))
+	for name := range s.server.services.services {
+		namespaces = append(namespaces, name)
+	}
+	sort.Strings(namespaces)
+
+	for _, namespace := range namespaces {
+		svc := s.server.services.services[namespace]
+		for _, name := range sortedCallbacks(svc.callbacks) {
+			method := gen.method(svc.callbacks[name])
+			method.Name = namespace + serviceMethodSeparator + name
+			doc.Methods = append(doc.Methods, method)
+		}
+		// Subscriptions take the subscription name as their first parameter,
+		// which is documented by the marker instead.
+		// 订阅将订阅名称作为第一个参数，改由标记来记录。
+		for _, name := range sortedCallbacks(svc.subscriptions) {
+			method := gen.method(svc.subscriptions[name])
+			method.Name = namespace + serviceMethodSeparator + name
+			method.Summary = fmt.Sprintf("Subscription, created by calling %s%s%s with %q as the first parameter.", namespace, serviceMethodSeparator, subscribeMethodSuffix[1:], name)
+			method.Subscription = &OpenRPCSubscription{
+				Subscribe:   namespace + subscribeMethodSuffix,
+				Unsubscribe: namespace + unsubscribeMethodSuffix,
+				Name:        name,
+			}
+			doc.Methods = append(doc.Methods, method)
+		}
+	}
+	doc.Components.Schemas = gen.schemas
+	return doc
+}
+
+// sortedCallbacks returns the names of the callbacks in alphabetical order.
+// sortedCallbacks 按字母顺序返回回调的名称。
+func sortedCallbacks(callbacks map[string]*callback) []string {
+	names := make([]string, 0, len(callbacks))
+	for name := range callbacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// schemaGenerator maps Go types to JSON schemas, collecting the named struct
+// types as reusable components.
+// schemaGenerator 将 Go 类型映射为 JSON schema，并将命名的结构体类型收集为可复用的组件。
+type schemaGenerator struct {
+	schemas map[string]*JSONSchema // 组件名称到 schema 的映射
+	names   map[reflect.Type]string
+}
+
+// method describes the parameters and result of a callback.
+// method 描述回调的参数和结果。
+func (g *schemaGenerator) method(cb *callback) *OpenRPCMethod {
+	method := &OpenRPCMethod{Params: []*OpenRPCContentDescriptor{}}
+
+	// Trailing pointer arguments may be omitted by the caller
+	// 调用方可以省略尾部的指针参数
+	args := cb.argTypes
+	optional := len(args)
+	for optional > 0 && args[optional-1].Kind() == reflect.Ptr {
+		optional--
+	}
+	for i, arg := range args {
+		method.Params = append(method.Params, &OpenRPCContentDescriptor{
+			Name:     fmt.Sprintf("param%d", i+1),
+			Required: i < optional,
+			Schema:   g.schema(arg),
+		})
+	}
+	// The result is the first non-error return value, if any
+	// 结果是第一个非 error 的返回值（如果有）
+	result := &OpenRPCContentDescriptor{Name: "result", Schema: &JSONSchema{Type: "null"}}
+	if cb.isSubscribe {
+		result.Schema = g.schema(reflect.TypeOf(ID("")))
+	} else if fntype := cb.fn.Type(); fntype.NumOut() > 0 && cb.errPos != 0 {
+		result.Schema = g.schema(fntype.Out(0))
+	}
+	method.Result = result
+	return method
+}
+
+// schema returns the JSON schema of a Go type.
+// schema 返回 Go 类型的 JSON schema。
+func (g *schemaGenerator) schema(typ reflect.Type) *JSONSchema {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if known, ok := knownSchemas[typ]; ok {
+		return &known
+	}
+	// Types with custom encodings can only be described by their name
+	// 具有自定义编码的类型只能通过其名称来描述
+	if typ.Implements(jsonMarshalerType) || reflect.PointerTo(typ).Implements(jsonMarshalerType) {
+		return &JSONSchema{Title: typ.String()}
+	}
+	if typ.Implements(textMarshalerType) || reflect.PointerTo(typ).Implements(textMarshalerType) {
+		return &JSONSchema{Title: typ.String(), Type: "string"}
+	}
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string"} // base64 encoded by encoding/json
+		}
+		return &JSONSchema{Type: "array", Items: g.schema(typ.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.schema(typ.Elem())}
+	case reflect.Struct:
+		if typ.Name() == "" {
+			return g.object(typ)
+		}
+		return &JSONSchema{Ref: "#/components/schemas/" + g.component(typ)}
+	default:
+		// Interfaces (and anything else) may hold arbitrary values
+		// 接口（以及其他任何类型）可以持有任意值
+		return &JSONSchema{}
+	}
+}
+
+// component registers a named struct type as a reusable schema, returning its
+// component name.
+// component 将命名结构体类型注册为可复用的 schema，并返回其组件名称。
+func (g *schemaGenerator) component(typ reflect.Type) string {
+	if name, ok := g.names[typ]; ok {
+		return name
+	}
+	name := schemaNameInvalid.ReplaceAllString(typ.String(), "_")
+	for base, i := name, 2; g.schemas[name] != nil; i++ {
+		name = fmt.Sprintf("%s%d", base, i) // same name from different packages
+	}
+	// Reserve the name before descending, so recursive types terminate
+	// 在递归下降之前预留名称，以便递归类型能够终止
+	g.names[typ] = name
+	g.schemas[name] = &JSONSchema{}
+	*g.schemas[name] = *g.object(typ)
+	return name
+}
+
+// object describes the fields of a struct as encoded by encoding/json.
+// object 描述由 encoding/json 编码的结构体字段。
+func (g *schemaGenerator) object(typ reflect.Type) *JSONSchema {
+	schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+	g.fields(typ, schema)
+	return schema
+}
+
+// fields adds the encoded fields of a struct to an object schema, flattening
+// untagged embedded structs.
+// fields 将结构体的编码字段添加到对象 schema 中，展开未标记的嵌入结构体。
+func (g *schemaGenerator) fields(typ reflect.Type, schema *JSONSchema) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.fields(embedded, schema)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = g.schema(field.Type)
+		if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// 请求记录 (Request Recording)
+//
+// 为了重现问题，服务器可以将处理的每个方法调用连同其响应、时间和传输方式记录到文件中。
+// 记录是 JSON 行格式，每行一个 RecordEntry；当文件超过大小限制时会轮换，最多保留配置数量的旧文件。
+// 记录的调用可以使用 ReadRecording 读取，并针对另一个节点重放。
+// 注意：启用记录时，流式结果会在内存中完整编码，以便记录。
+
+// RecordEntry is a single recorded method call.
+// RecordEntry 是一个被记录的方法调用。
+type RecordEntry struct {
+	Time      time.Time       `json:"time"`      // Time the call was received // 收到调用的时间
+	Duration  time.Duration   `json:"duration"`  // Time taken to serve the call // 处理调用所花费的时间
+	Transport string          `json:"transport"` // Transport of the call, e.g. "http" or "ws" // 调用的传输方式
+	Request   json.RawMessage `json:"request"`   // The JSON-RPC request // JSON-RPC 请求
+	Response  json.RawMessage `json:"response"`  // The JSON-RPC response // JSON-RPC 响应
+}
+
+// Recorder writes the calls served by a server to a rotating file.
+// Recorder 将服务器处理的调用写入轮换文件。
+type Recorder struct {
+	path     string
+	maxSize  int64 // 单个文件的最大大小，0 表示不轮换
+	maxFiles int   // 保留的轮换文件数量
+
+	lock sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewRecorder creates a recorder appending to the file at path. Once the file
+// grows beyond maxSize bytes, it is renamed to path.1 (shifting older files up to
+// path.<maxFiles>) and a new file is started. A zero maxSize disables rotation.
+//
+// NewRecorder 创建一个追加写入 path 文件的记录器。当文件超过 maxSize 字节时，它会被重命名为 path.1
+// （较旧的文件依次移动到 path.<maxFiles>），并开始一个新文件。maxSize 为零时禁用轮换。
+func NewRecorder(path string, maxSize int64, maxFiles int) (*Recorder, error) {
+	r := &Recorder{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// open opens the current recording file for appending.
+// open 打开当前记录文件以进行追加。
+func (r *Recorder) open() error {
+	file, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	r.file, r.size = file, stat.Size()
+	return nil
+}
+
+// rotate closes the current file, shifts the existing files and opens a new one.
+// rotate 关闭当前文件，移动现有文件并打开一个新文件。
+func (r *Recorder) rotate() error {
+	r.file.Close()
+
+	if r.maxFiles > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxFiles))
+		for i := r.maxFiles - 1; i > 0; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		if err := os.Rename(r.path, r.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// record writes a served call to the recording.
+// record 将已处理的调用写入记录。
+func (r *Recorder) record(ctx context.Context, req, resp *jsonrpcMessage, start time.Time) {
+	entry := RecordEntry{
+		Time:      start,
+		Duration:  time.Since(start),
+		Transport: PeerInfoFromContext(ctx).Transport,
+	}
+	var err error
+	if entry.Request, err = json.Marshal(req); err != nil {
+		return
+	}
+	if entry.Response, err = json.Marshal(resp); err != nil {
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.file == nil {
+		return // closed
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(line)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			log.Warn("Failed to rotate RPC recording", "path", r.path, "err", err)
+			r.file = nil
+			return
+		}
+	}
+	// Entries are written unbuffered, so the recording is complete up to the
+	// last call even if the process dies.
+	// 条目以无缓冲方式写入，这样即使进程终止，记录也完整到最后一个调用。
+	if _, err := r.file.Write(line); err != nil {
+		log.Warn("Failed to write RPC recording", "path", r.path, "err", err)
+		return
+	}
+	r.size += int64(len(line))
+}
+
+// Close closes the recording file.
+// Close 关闭记录文件。
+func (r *Recorder) Close() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// ReadRecording decodes the entries of a recording.
+// ReadRecording 解码记录中的条目。
+func ReadRecording(in io.Reader) ([]*RecordEntry, error) {
+	var (
+		entries []*RecordEntry
+		dec     = json.NewDecoder(in)
+	)
+	for {
+		entry := new(RecordEntry)
+		if err := dec.Decode(entry); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	recorder, err := NewRecorder(path, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestServer()
+	server.SetRecorder(recorder)
+	client := DialInProc(server)
+
+	var res string
+	if err := client.Call(&res, "test_repeat", "x", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Call(nil, "test_returnError"); err == nil {
+		t.Fatal("expected error")
+	}
+	client.Close()
+	server.Stop()
+	recorder.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	entries, err := ReadRecording(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("wrong number of entries: %d", len(entries))
+	}
+	var (
+		req  jsonrpcMessage
+		resp jsonrpcMessage
+	)
+	json.Unmarshal(entries[0].Request, &req)
+	json.Unmarshal(entries[0].Response, &resp)
+	if req.Method != "test_repeat" || string(req.Params) != `["x",3]` {
+		t.Errorf("wrong recorded request: %s", entries[0].Request)
+	}
+	if string(resp.Result) != `"xxx"` {
+		t.Errorf("wrong recorded response: %s", entries[0].Response)
+	}
+	if entries[0].Transport != "ipc" { // in-process connections use the IPC codec
+		t.Errorf("wrong transport: %q", entries[0].Transport)
+	}
+	if !strings.Contains(string(entries[1].Response), `"error"`) {
+		t.Errorf("recorded response has no error: %s", entries[1].Response)
+	}
+}
+
+func TestRecorderRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "calls.jsonl")
+	recorder, err := NewRecorder(path, 200, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newTestServer()
+	server.SetRecorder(recorder)
+	client := DialInProc(server)
+	defer client.Close()
+	defer server.Stop()
+
+	for i := 0; i < 10; i++ {
+		if err := client.Call(nil, "test_repeat", "x", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	recorder.Close()
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		if _, err := os.Stat(name); err != nil {
+			t.Errorf("missing recording file: %v", err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("too many rotated files kept")
+	}
+}
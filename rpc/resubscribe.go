@@ -0,0 +1,177 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// 订阅自动重建 (Automatic Resubscription)
+//
+// 可重建的订阅由两层组成：交给调用方的外层 ClientSubscription，以及在连接上实际建立的内层订阅。
+// 中继 goroutine 将内层订阅的通知转发到外层订阅；当内层订阅因连接丢失而失败时，中继使用原始参数重新订阅，
+// 并继续在同一个通道上交付结果。由于断开期间的通知会丢失，重新订阅成功后会通过回调报告缺口，以便调用方回填。
+
+const (
+	resubscribeBackoff    = time.Second      // 第一次重新订阅尝试之前的等待时间
+	maxResubscribeBackoff = 30 * time.Second // 重新订阅尝试之间的最长等待时间
+)
+
+// SubscriptionGap describes a period during which notifications of a subscription
+// may have been missed, because it had to be re-established.
+// SubscriptionGap 描述了订阅因需要重新建立而可能错过通知的一段时间。
+type SubscriptionGap struct {
+	Namespace string          // Namespace of the subscription, e.g. "eth" // 订阅的命名空间
+	Args      []interface{}   // Arguments the subscription was created with // 创建订阅时使用的参数
+	Last      json.RawMessage // Last notification delivered before the gap, nil if none // 缺口之前交付的最后一个通知
+	Lost      time.Time       // Time the subscription was lost // 订阅丢失的时间
+	Err       error           // Error that ended the previous subscription // 结束之前订阅的错误
+}
+
+// subscriptionRelay forwards the notifications of an inner subscription to the
+// outer one handed out to the caller, re-establishing the inner subscription
+// whenever it fails.
+// subscriptionRelay 将内层订阅的通知转发到交给调用方的外层订阅，每当内层订阅失败时重新建立它。
+type subscriptionRelay struct {
+	sub       *ClientSubscription // 交给调用方的外层订阅
+	namespace string
+	args      []interface{}
+
+	subscribe func(ctx context.Context) (*ClientSubscription, chan json.RawMessage, error) // 建立内层订阅
+	failed    func(err error)                                                              // 内层订阅失败时调用（可选）
+	onGap     func(SubscriptionGap)                                                        // 重新订阅后报告缺口（可选）
+	quit      <-chan struct{}                                                              // 在底层客户端关闭时关闭
+}
+
+// newSubscriptionRelay creates the outer subscription of a relay and establishes
+// the initial inner subscription.
+// newSubscriptionRelay 创建中继的外层订阅，并建立初始的内层订阅。
+func newSubscriptionRelay(ctx context.Context, c *Client, namespace string, channel reflect.Value, args []interface{}, relay *subscriptionRelay) (*ClientSubscription, *ClientSubscription, chan json.RawMessage, error) {
+	inner, in, err := relay.subscribe(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	relay.sub = newClientSubscription(c, namespace, channel)
+	relay.sub.relayed = true
+	relay.namespace, relay.args = namespace, args
+	go relay.sub.run()
+	return relay.sub, inner, in, nil
+}
+
+// run is the forwarding loop of the relay.
+// run 是中继的转发循环。
+func (r *subscriptionRelay) run(inner *ClientSubscription, in chan json.RawMessage) {
+	var last json.RawMessage
+	for {
+		select {
+		case msg := <-in:
+			if !r.sub.deliver(msg) {
+				inner.Unsubscribe()
+				return
+			}
+			last = msg
+
+		case err := <-inner.Err():
+			if err == nil {
+				// The underlying client was closed
+				// 底层客户端已关闭
+				r.sub.close(ErrClientQuit)
+				return
+			}
+			if r.failed != nil {
+				r.failed(err)
+			}
+			lost := time.Now()
+			log.Debug("RPC subscription lost, resubscribing", "namespace", r.namespace, "err", err)
+
+			var ok bool
+			if inner, in, ok = r.resubscribe(); !ok {
+				return
+			}
+			log.Debug("RPC subscription re-established", "namespace", r.namespace, "gap", time.Since(lost))
+			if r.onGap != nil {
+				r.onGap(SubscriptionGap{Namespace: r.namespace, Args: r.args, Last: last, Lost: lost, Err: err})
+			}
+
+		case <-r.sub.forwardDone:
+			inner.Unsubscribe()
+			return
+
+		case <-r.quit:
+			r.sub.close(ErrClientQuit)
+			return
+		}
+	}
+}
+
+// resubscribe keeps trying to re-establish the inner subscription with backoff,
+// until it succeeds, the outer subscription ends or the request is rejected.
+// resubscribe 以退避方式持续尝试重新建立内层订阅，直到成功、外层订阅结束或请求被拒绝。
+func (r *subscriptionRelay) resubscribe() (*ClientSubscription, chan json.RawMessage, bool) {
+	backoff := resubscribeBackoff
+	for {
+		select {
+		case <-time.After(backoff):
+		case <-r.sub.forwardDone:
+			return nil, nil, false
+		case <-r.quit:
+			r.sub.close(ErrClientQuit)
+			return nil, nil, false
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), subscribeTimeout)
+		inner, in, err := r.subscribe(ctx)
+		cancel()
+		if err == nil {
+			return inner, in, true
+		}
+		if !isTransportError(err) && !errors.Is(err, context.DeadlineExceeded) {
+			r.sub.close(err)
+			return nil, nil, false
+		}
+		log.Trace("RPC resubscription failed", "namespace", r.namespace, "err", err)
+		if backoff *= 2; backoff > maxResubscribeBackoff {
+			backoff = maxResubscribeBackoff
+		}
+	}
+}
+
+// subscribeResilient creates a subscription which is re-established after the
+// connection of the client is lost.
+// subscribeResilient 创建一个在客户端连接丢失后会重新建立的订阅。
+func (c *Client) subscribeResilient(ctx context.Context, namespace string, channel reflect.Value, args ...interface{}) (*ClientSubscription, error) {
+	relay := &subscriptionRelay{
+		subscribe: func(ctx context.Context) (*ClientSubscription, chan json.RawMessage, error) {
+			in := make(chan json.RawMessage)
+			sub, err := c.subscribe(ctx, namespace, reflect.ValueOf(in), args...)
+			return sub, in, err
+		},
+		onGap: c.onGap,
+		quit:  c.closing,
+	}
+	sub, inner, in, err := newSubscriptionRelay(ctx, c, namespace, channel, args, relay)
+	if err != nil {
+		return nil, err
+	}
+	go relay.run(inner, in)
+	return sub, nil
+}
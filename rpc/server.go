@@ -24,6 +24,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/ethereum/go-ethereum/internal/tracing"
 	"github.com/ethereum/go-ethereum/log"
 )
 
@@ -68,8 +69,21 @@ type Server struct {
 	batchItemLimit     int                      // 限制批量请求中允许的最大条目数。
 	batchResponseLimit int                      // 限制批量响应的最大条目数。
 	httpBodyLimit      int                      // 限制 HTTP 请求体的最大字节数。
+	callFilter         CallFilter               // 在执行每个方法调用之前调用的过滤器。
+	responseLimit      int                      // 限制单个响应的最大字节数。
+	recorder           *Recorder                // 记录处理的调用。
+	batchConcurrency   int                      // 每个连接上并发执行的批处理调用的最大数量。
+	tracer             *tracing.Tracer          // 记录处理的调用的追踪 span，为 nil 时禁用。
 }
 
+// CallFilter is invoked before every method call served by a server, with the
+// context of the call and the name of the method. If it returns an error, the
+// call is rejected and the error is returned to the client.
+//
+// CallFilter 在服务器处理的每个方法调用之前被调用，参数为调用的上下文和方法名。
+// 如果它返回错误，则拒绝该调用，并将错误返回给客户端。
+type CallFilter func(ctx context.Context, method string) error
+
 // NewServer creates a new server instance with no registered handlers.
 // NewServer 创建一个没有注册处理程序的新服务器实例。
 func NewServer() *Server {
@@ -103,6 +117,26 @@ func (s *Server) SetBatchLimits(itemLimit, maxResponseSize int) {
 	s.batchResponseLimit = maxResponseSize
 }
 
+// SetBatchConcurrency sets the maximum number of calls of batch requests executed
+// concurrently on a single connection. Responses are always returned in request
+// order. By default, or with a limit of one, batches are executed sequentially.
+// Only enable concurrency if clients don't rely on the calls of a batch taking
+// effect in order.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+//
+// SetBatchConcurrency 设置单个连接上批处理请求中并发执行的调用的最大数量。响应总是按请求顺序返回。
+// 默认情况下或限制为 1 时，批处理按顺序执行。只有在客户端不依赖批处理中的调用按顺序生效时才启用并发。
+//
+// 此方法应在通过 ServeCodec、ServeHTTP、ServeListener 等处理任何请求之前调用。
+func (s *Server) SetBatchConcurrency(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	s.batchConcurrency = limit
+}
+
 // SetHTTPBodyLimit sets the size limit for HTTP requests.
 //
 // This method should be called before processing any requests via ServeHTTP.
@@ -114,6 +148,64 @@ func (s *Server) SetHTTPBodyLimit(limit int) {
 	s.httpBodyLimit = limit
 }
 
+// SetCallFilter installs a filter which is consulted before every method call,
+// e.g. to enforce rate limits.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+//
+// SetCallFilter 设置一个在每个方法调用之前被检查的过滤器，例如用于实施速率限制。
+//
+// 此方法应在通过 ServeCodec、ServeHTTP、ServeListener 等处理任何请求之前调用。
+func (s *Server) SetCallFilter(filter CallFilter) {
+	s.callFilter = filter
+}
+
+// SetResponseSizeLimit sets the maximum size of a single response in bytes. Large
+// results are checked while they are being encoded, and calls exceeding the limit
+// fail with an error instead. A zero or negative limit means no limit.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+//
+// SetResponseSizeLimit 设置单个响应的最大字节数。大型结果在编码时被检查，超过限制的调用将以错误失败。
+// 零或负值表示没有限制。
+//
+// 此方法应在通过 ServeCodec、ServeHTTP、ServeListener 等处理任何请求之前调用。
+func (s *Server) SetResponseSizeLimit(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+	s.responseLimit = limit
+}
+
+// SetRecorder makes the server record all method calls it serves, along with
+// their responses, to the given recorder. Streamed results are encoded in memory
+// while recording is enabled.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+//
+// SetRecorder 使服务器将其处理的所有方法调用及其响应记录到给定的记录器中。启用记录时，流式结果在内存中编码。
+//
+// 此方法应在通过 ServeCodec、ServeHTTP、ServeListener 等处理任何请求之前调用。
+func (s *Server) SetRecorder(recorder *Recorder) {
+	s.recorder = recorder
+}
+
+// SetTracer makes the server record trace spans of the calls it serves with the
+// given tracer. Servers without a tracer don't record any spans.
+//
+// This method should be called before processing any requests via ServeCodec, ServeHTTP,
+// ServeListener etc.
+//
+// SetTracer 使服务器使用给定的追踪器记录其处理的调用的追踪 span。没有追踪器的服务器不记录任何 span。
+//
+// 此方法应在通过 ServeCodec、ServeHTTP、ServeListener 等处理任何请求之前调用。
+func (s *Server) SetTracer(tracer *tracing.Tracer) {
+	s.tracer = tracer
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either an RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -146,6 +238,13 @@ func (s *Server) RegisterName(name string, receiver interface{}) error {
 //
 // 从 codec 读取 RPC 请求，处理并返回响应，阻塞直到连接关闭或服务器停止。
 func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
+	s.serveCodec(context.Background(), codec)
+}
+
+// serveCodec serves the codec like ServeCodec, deriving the context of all
+// calls from the given connection context.
+// serveCodec 与 ServeCodec 一样服务于编解码器，所有调用的上下文都派生自给定的连接上下文。
+func (s *Server) serveCodec(ctx context.Context, codec ServerCodec) {
 	defer codec.close()
 
 	if !s.trackCodec(codec) { // 调用 trackCodec 注册 codec，若失败（服务器已停止），直接返回。
@@ -153,10 +252,18 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	}
 	defer s.untrackCodec(codec) // 确保退出时移除 codec 的跟踪。
 
+	if s.tracer != nil {
+		ctx = tracing.ContextWithTracer(ctx, s.tracer) // 使用服务器的追踪器记录连接上的调用
+	}
 	cfg := &clientConfig{ // 初始化客户端配置，包括 ID 生成器和批量限制。
 		idgen:              s.idgen,
 		batchItemLimit:     s.batchItemLimit,
 		batchResponseLimit: s.batchResponseLimit,
+		callFilter:         s.callFilter,
+		responseLimit:      s.responseLimit,
+		recorder:           s.recorder,
+		batchConcurrency:   s.batchConcurrency,
+		connCtx:            ctx,
 	}
 	c := initClient(codec, &s.services, cfg)
 	<-codec.closed() // 等待 codec 的关闭信号
@@ -201,10 +308,17 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec) {
 		return
 	}
 
+	if s.tracer != nil {
+		ctx = tracing.ContextWithTracer(ctx, s.tracer) // 使用服务器的追踪器记录请求中的调用
+	}
 	// 创建请求处理器，传入上下文、编解码器、ID 生成器、服务集合及批量限制参数。
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.batchItemLimit, s.batchResponseLimit)
 	h.allowSubscribe = false   // 禁用订阅功能。
 	defer h.close(io.EOF, nil) // 确保处理器在函数结束时关闭。
+	h.callFilter = s.callFilter
+	h.responseLimit = s.responseLimit
+	h.recorder = s.recorder
+	h.batchConcurrency = s.batchConcurrency
 
 	reqs, batch, err := codec.readBatch() // 从 codec 读取请求，可能为批量请求。
 	if err != nil {
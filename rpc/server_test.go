@@ -19,6 +19,7 @@ package rpc
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"io"
 	"net"
 	"os"
@@ -202,3 +203,156 @@ func TestServerBatchResponseSizeLimit(t *testing.T) {
 		}
 	}
 }
+
+func TestServerBatchConcurrency(t *testing.T) {
+	t.Parallel()
+
+	const (
+		calls = 8
+		sleep = 100 * time.Millisecond
+	)
+	run := func(concurrency int) time.Duration {
+		server := newTestServer()
+		defer server.Stop()
+		server.SetBatchConcurrency(concurrency)
+		client := DialInProc(server)
+		defer client.Close()
+
+		// The earlier calls take longer, so they complete last if run concurrently.
+		var batch []BatchElem
+		for i := 0; i < calls; i++ {
+			batch = append(batch,
+				BatchElem{Method: "test_sleep", Args: []any{time.Duration(calls-i) * sleep / calls}, Result: new(any)},
+				BatchElem{Method: "test_repeat", Args: []any{"x", i}, Result: new(string)},
+			)
+		}
+		start := time.Now()
+		if err := client.BatchCall(batch); err != nil {
+			t.Fatal("error sending batch:", err)
+		}
+		elapsed := time.Since(start)
+		for i, elem := range batch {
+			if elem.Error != nil {
+				t.Fatalf("batch elem %d has unexpected error: %v", i, elem.Error)
+			}
+			if i%2 == 1 {
+				if want := strings.Repeat("x", i/2); *elem.Result.(*string) != want {
+					t.Errorf("batch elem %d has wrong result: %q, want %q", i, *elem.Result.(*string), want)
+				}
+			}
+		}
+		return elapsed
+	}
+	// Sequential execution, the default, sleeps for the sum of all durations.
+	for _, concurrency := range []int{0, 1} {
+		if elapsed := run(concurrency); elapsed < sleep*(calls+1)/2 {
+			t.Errorf("sequential batch too fast with concurrency %d: %v", concurrency, elapsed)
+		}
+	}
+	// Concurrent execution only takes about as long as the longest call.
+	if elapsed := run(2 * calls); elapsed > sleep*(calls+1)/4 {
+		t.Errorf("concurrent batch too slow: %v", elapsed)
+	}
+}
+
+func TestServerCallFilter(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+
+	var calls []string
+	server.SetCallFilter(func(ctx context.Context, method string) error {
+		calls = append(calls, method)
+		if len(calls) > 2 {
+			return testError{}
+		}
+		return nil
+	})
+	var (
+		batch  []BatchElem
+		client = DialInProc(server)
+	)
+	for i := 0; i < 4; i++ {
+		batch = append(batch, BatchElem{
+			Method: "test_echo",
+			Args:   []any{"x", 1},
+			Result: new(echoResult),
+		})
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal("error sending batch:", err)
+	}
+	for i := range batch {
+		// We expect the first two calls to pass the filter, the rest to be rejected.
+		if i < 2 {
+			if batch[i].Error != nil {
+				t.Fatalf("batch elem %d has unexpected error: %v", i, batch[i].Error)
+			}
+			continue
+		}
+		re, ok := batch[i].Error.(Error)
+		if !ok {
+			t.Fatalf("batch elem %d has wrong error: %v", i, batch[i].Error)
+		}
+		if re.ErrorCode() != (testError{}).ErrorCode() {
+			t.Errorf("batch elem %d wrong error code, have %d want %d", i, re.ErrorCode(), testError{}.ErrorCode())
+		}
+	}
+	if len(calls) != 4 || calls[0] != "test_echo" {
+		t.Fatalf("wrong filtered calls: %v", calls)
+	}
+}
+
+func TestServerDiscover(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer()
+	defer server.Stop()
+
+	client := DialInProc(server)
+	defer client.Close()
+
+	var doc OpenRPCDocument
+	if err := client.Call(&doc, "rpc_discover"); err != nil {
+		t.Fatal(err)
+	}
+	methods := make(map[string]*OpenRPCMethod)
+	for _, m := range doc.Methods {
+		methods[m.Name] = m
+	}
+	for _, name := range []string{"rpc_modules", "rpc_discover", "test_echo", "nftest_someSubscription"} {
+		if methods[name] == nil {
+			t.Fatalf("method %s missing from document", name)
+		}
+	}
+	// Check the parameters and result of a regular method
+	echo := methods["test_echo"]
+	if len(echo.Params) != 3 {
+		t.Fatalf("wrong number of test_echo params: %d", len(echo.Params))
+	}
+	if echo.Params[0].Schema.Type != "string" || echo.Params[1].Schema.Type != "integer" {
+		t.Errorf("wrong test_echo param schemas: %+v %+v", echo.Params[0].Schema, echo.Params[1].Schema)
+	}
+	if !echo.Params[0].Required || !echo.Params[1].Required || echo.Params[2].Required {
+		t.Errorf("wrong test_echo param requirements")
+	}
+	if echo.Result.Schema.Ref != "#/components/schemas/rpc.echoResult" {
+		t.Fatalf("wrong test_echo result schema: %+v", echo.Result.Schema)
+	}
+	result := doc.Components.Schemas["rpc.echoResult"]
+	if result == nil || len(result.Properties) != 3 || result.Properties["Int"].Type != "integer" {
+		t.Fatalf("wrong echoResult schema: %+v", result)
+	}
+	if methods["test_echo"].Subscription != nil {
+		t.Error("test_echo marked as subscription")
+	}
+	// Check the subscription marker
+	sub := methods["nftest_someSubscription"]
+	if sub.Subscription == nil || sub.Subscription.Subscribe != "nftest_subscribe" || sub.Subscription.Name != "someSubscription" {
+		t.Fatalf("wrong subscription marker: %+v", sub.Subscription)
+	}
+	if len(sub.Params) != 2 {
+		t.Fatalf("wrong number of nftest_someSubscription params: %d", len(sub.Params))
+	}
+}
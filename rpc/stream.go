@@ -0,0 +1,287 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// 流式结果编码 (Streaming Result Encoding)
+//
+// 大型结果（例如区块追踪、日志、账户转储）如果先完整编码到内存中再写出，可能导致节点内存耗尽。
+// 流式结果在写入连接时逐个元素编码：方法可以返回 *Stream 以增量地产生元素。其他结果（包括切片）作为整体编码。
+// 响应大小限制在流式写入过程中执行：如果在任何数据发送之前超出限制，客户端将收到正常的错误响应；
+// 如果部分响应已经发送，连接将被中止，因为 JSON-RPC 无法在结果中途报告错误。
+
+const (
+	// streamBufferSize is the amount of encoded output buffered before it is
+	// written to the connection. Errors occurring before the first write are
+	// reported as regular error responses.
+	// streamBufferSize 是在写入连接之前缓冲的编码输出量。在第一次写入之前发生的错误将作为常规错误响应报告。
+	streamBufferSize = 64 * 1024
+)
+
+// errStreamAborted is returned when a streamed response fails after a part of it
+// was already sent, and the connection had to be aborted.
+// errStreamAborted 在流式响应的一部分已经发送后失败、必须中止连接时返回。
+var errStreamAborted = errors.New("streamed response aborted")
+
+// Stream is a method result which is encoded incrementally while being sent,
+// rather than being held in memory as a whole. The producer function is invoked
+// once, while the response is written, and must emit the elements through the
+// given encoder. Nested streams may be emitted as elements.
+//
+// Stream 是一种方法结果，它在发送时被增量编码，而不是作为整体保存在内存中。生产者函数在写入响应时被调用一次，
+// 并且必须通过给定的编码器发出元素。嵌套的流可以作为元素发出。
+type Stream struct {
+	object  bool
+	produce func(enc *StreamEncoder) error
+	limit   int // 响应大小限制，由处理程序设置
+}
+
+// ArrayStream creates a streamed result encoded as a JSON array, whose elements
+// are emitted via StreamEncoder.Encode.
+// ArrayStream 创建一个编码为 JSON 数组的流式结果，其元素通过 StreamEncoder.Encode 发出。
+func ArrayStream(produce func(enc *StreamEncoder) error) *Stream {
+	return &Stream{produce: produce}
+}
+
+// ObjectStream creates a streamed result encoded as a JSON object, whose fields
+// are emitted via StreamEncoder.EncodeField.
+// ObjectStream 创建一个编码为 JSON 对象的流式结果，其字段通过 StreamEncoder.EncodeField 发出。
+func ObjectStream(produce func(enc *StreamEncoder) error) *Stream {
+	return &Stream{object: true, produce: produce}
+}
+
+// MarshalJSON encodes the stream into memory, for transports which can't write
+// it incrementally.
+// MarshalJSON 将流编码到内存中，用于无法增量写入的传输方式。
+func (s *Stream) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := s.encode(&limitWriter{w: &buf, limit: s.limit}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// encode runs the producer, writing the encoded stream to w.
+// encode 运行生产者，将编码后的流写入 w。
+func (s *Stream) encode(w io.Writer) error {
+	open, close := "[", "]"
+	if s.object {
+		open, close = "{", "}"
+	}
+	if _, err := io.WriteString(w, open); err != nil {
+		return err
+	}
+	if err := s.produce(&StreamEncoder{w: w, object: s.object}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, close)
+	return err
+}
+
+// StreamEncoder emits the elements of a streamed result. Once an error has been
+// returned, the producer should stop and return it.
+// StreamEncoder 发出流式结果的元素。一旦返回错误，生产者应停止并返回该错误。
+type StreamEncoder struct {
+	w      io.Writer
+	object bool
+	count  int
+	err    error
+}
+
+// Encode appends an element to a streamed array.
+// Encode 将一个元素追加到流式数组中。
+func (e *StreamEncoder) Encode(v any) error {
+	if e.object {
+		return errors.New("Encode called on object stream")
+	}
+	return e.emit("", v)
+}
+
+// EncodeField appends a field to a streamed object.
+// EncodeField 将一个字段追加到流式对象中。
+func (e *StreamEncoder) EncodeField(key string, v any) error {
+	if !e.object {
+		return errors.New("EncodeField called on array stream")
+	}
+	return e.emit(key, v)
+}
+
+func (e *StreamEncoder) emit(key string, v any) error {
+	if e.err != nil {
+		return e.err
+	}
+	if e.count > 0 {
+		if _, e.err = io.WriteString(e.w, ","); e.err != nil {
+			return e.err
+		}
+	}
+	e.count++
+	if e.object {
+		var enc []byte
+		if enc, e.err = json.Marshal(key); e.err == nil {
+			enc = append(enc, ':')
+			_, e.err = e.w.Write(enc)
+		}
+		if e.err != nil {
+			return e.err
+		}
+	}
+	if s, ok := v.(*Stream); ok {
+		e.err = s.encode(e.w)
+		return e.err
+	}
+	var enc []byte
+	if enc, e.err = json.Marshal(v); e.err == nil {
+		_, e.err = e.w.Write(enc)
+	}
+	return e.err
+}
+
+// errResponseTooLarge is returned when a response exceeds the size limit.
+// errResponseTooLarge 在响应超过大小限制时返回。
+var errResponseTooLarge = &internalServerError{errcodeResponseTooLarge, errMsgResponseTooLarge}
+
+// limitWriter fails writes exceeding the response size limit.
+// limitWriter 使超过响应大小限制的写入失败。
+type limitWriter struct {
+	w       io.Writer
+	limit   int // 0 表示没有限制
+	written int
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if w.limit > 0 && w.written+len(p) > w.limit {
+		return 0, errResponseTooLarge
+	}
+	w.written += len(p)
+	return w.w.Write(p)
+}
+
+// flushTracker records whether anything was written to the connection.
+// flushTracker 记录是否有任何内容被写入连接。
+type flushTracker struct {
+	w       io.Writer
+	flushed bool
+}
+
+func (w *flushTracker) Write(p []byte) (int, error) {
+	w.flushed = true
+	return w.w.Write(p)
+}
+
+// materialize encodes a streamed response into memory. Failures are turned into
+// an error response.
+// materialize 将流式响应编码到内存中。失败会被转换为错误响应。
+func (msg *jsonrpcMessage) materialize() *jsonrpcMessage {
+	enc, err := msg.stream.MarshalJSON()
+	msg.finishSpan(err)
+	if err != nil {
+		return msg.streamErrorResponse(err)
+	}
+	return &jsonrpcMessage{Version: vsn, ID: msg.ID, Result: enc}
+}
+
+// finishSpan ends the trace span of a streamed call once its result was produced,
+// recording the error of the stream or of writing it. It does nothing for other
+// responses, their spans are ended when the call returns.
+// finishSpan 在流式调用的结果生成后结束其追踪 span，并记录流或写入的错误。
+// 对其他响应不做任何操作，它们的 span 在调用返回时结束。
+func (msg *jsonrpcMessage) finishSpan(err error) {
+	if msg == nil || msg.span == nil {
+		return
+	}
+	if err != nil {
+		msg.span.SetError(err)
+	}
+	msg.span.End()
+	msg.span = nil
+}
+
+// streamErrorResponse creates the error response of a failed stream. Encoding
+// failures are reported like marshalling errors of regular results.
+// streamErrorResponse 创建失败流的错误响应。编码失败的报告方式与常规结果的序列化错误相同。
+func (msg *jsonrpcMessage) streamErrorResponse(err error) *jsonrpcMessage {
+	var (
+		marshalerErr *json.MarshalerError
+		typeErr      *json.UnsupportedTypeError
+		valueErr     *json.UnsupportedValueError
+	)
+	if errors.As(err, &marshalerErr) || errors.As(err, &typeErr) || errors.As(err, &valueErr) {
+		err = &internalServerError{errcodeMarshalError, err.Error()}
+	}
+	return msg.errorResponse(err)
+}
+
+// writeStream writes a response with a streamed result to the connection. If the
+// stream fails before anything was sent, an error response is written instead.
+// Otherwise the connection is aborted, as the response can't be completed.
+// writeStream 将带有流式结果的响应写入连接。如果流在发送任何内容之前失败，则改为写入错误响应。
+// 否则连接将被中止，因为响应无法完成。
+func (c *jsonCodec) writeStream(msg *jsonrpcMessage) error {
+	var (
+		tracker *flushTracker
+		err     error
+	)
+	err = c.stream(func(w io.Writer) error {
+		tracker = &flushTracker{w: w}
+		buf := bufio.NewWriterSize(tracker, streamBufferSize)
+		lw := &limitWriter{w: buf, limit: msg.stream.limit}
+
+		// The envelope is written by hand, matching the encoding of jsonrpcMessage
+		// 信封是手动写入的，与 jsonrpcMessage 的编码相匹配
+		id := msg.ID
+		if id == nil {
+			id = null
+		}
+		if _, err := io.WriteString(lw, `{"jsonrpc":"`+vsn+`","id":`); err != nil {
+			return err
+		}
+		if _, err := lw.Write(id); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(lw, `,"result":`); err != nil {
+			return err
+		}
+		if err := msg.stream.encode(lw); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(lw, "}\n"); err != nil {
+			return err
+		}
+		return buf.Flush()
+	})
+	msg.finishSpan(err)
+	if err == nil {
+		return nil
+	}
+	if tracker == nil || !tracker.flushed {
+		return c.encode(msg.streamErrorResponse(err), true)
+	}
+	if c.onAbort != nil {
+		c.onAbort()
+	} else {
+		c.close()
+	}
+	return errStreamAborted
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type streamTestService struct{}
+
+type streamTestItem struct {
+	N    int    `json:"n"`
+	Text string `json:"text"`
+}
+
+func streamTestItems(n int) []streamTestItem {
+	items := make([]streamTestItem, n)
+	for i := range items {
+		items[i] = streamTestItem{N: i, Text: strings.Repeat("x", i%10)}
+	}
+	return items
+}
+
+// Items returns an array stream, producing the items one by one.
+func (s *streamTestService) Items(n int) *Stream {
+	return ArrayStream(func(enc *StreamEncoder) error {
+		for _, item := range streamTestItems(n) {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Slice returns a slice, which is encoded as a whole.
+func (s *streamTestService) Slice(n int) []streamTestItem {
+	return streamTestItems(n)
+}
+
+// Dump returns an object stream with a nested array stream.
+func (s *streamTestService) Dump(n int) *Stream {
+	return ObjectStream(func(enc *StreamEncoder) error {
+		items := ArrayStream(func(enc *StreamEncoder) error {
+			for i := 0; i < n; i++ {
+				if err := enc.Encode(streamTestItem{N: i, Text: strings.Repeat("y", i%10)}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err := enc.EncodeField("items", items); err != nil {
+			return err
+		}
+		return enc.EncodeField("count", n)
+	})
+}
+
+// Fail returns a stream which fails after emitting n elements.
+func (s *streamTestService) Fail(n int) *Stream {
+	return ArrayStream(func(enc *StreamEncoder) error {
+		for i := 0; i < n; i++ {
+			if err := enc.Encode(i); err != nil {
+				return err
+			}
+		}
+		return errors.New("producer failed")
+	})
+}
+
+func newStreamTestServer(t *testing.T, limit int) *Server {
+	server := NewServer()
+	if err := server.RegisterName("stream", new(streamTestService)); err != nil {
+		t.Fatal(err)
+	}
+	server.SetResponseSizeLimit(limit)
+	t.Cleanup(server.Stop)
+	return server
+}
+
+type streamDump struct {
+	Items []streamTestItem `json:"items"`
+	Count int              `json:"count"`
+}
+
+func TestStreamResults(t *testing.T) {
+	t.Parallel()
+
+	server := newStreamTestServer(t, 0)
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+	wssrv := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer wssrv.Close()
+
+	clients := map[string]*Client{"inproc": DialInProc(server)}
+	for name, url := range map[string]string{"http": httpsrv.URL, "ws": "ws:" + strings.TrimPrefix(wssrv.URL, "http:")} {
+		client, err := Dial(url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clients[name] = client
+	}
+	want := streamTestItems(20000) // larger than the stream buffer
+	for name, client := range clients {
+		defer client.Close()
+
+		var items []streamTestItem
+		if err := client.Call(&items, "stream_items", len(want)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(items, want) {
+			t.Fatalf("%s: wrong streamed items", name)
+		}
+		var slice []streamTestItem
+		if err := client.Call(&slice, "stream_slice", len(want)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(slice, want) {
+			t.Fatalf("%s: wrong slice", name)
+		}
+		var dump streamDump
+		if err := client.Call(&dump, "stream_dump", len(want)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if dump.Count != len(want) || len(dump.Items) != len(want) || dump.Items[7].Text != "yyyyyyy" {
+			t.Fatalf("%s: wrong streamed object", name)
+		}
+		// Streams in batches are encoded as a whole
+		batch := []BatchElem{
+			{Method: "stream_items", Args: []interface{}{3}, Result: new([]streamTestItem)},
+			{Method: "stream_dump", Args: []interface{}{3}, Result: new(streamDump)},
+		}
+		if err := client.BatchCall(batch); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, elem := range batch {
+			if elem.Error != nil {
+				t.Fatalf("%s: batch error: %v", name, elem.Error)
+			}
+		}
+		if d := batch[1].Result.(*streamDump); d.Count != 3 || len(d.Items) != 3 {
+			t.Fatalf("%s: wrong batch result: %+v", name, d)
+		}
+	}
+}
+
+func TestStreamResponseLimit(t *testing.T) {
+	t.Parallel()
+
+	server := newStreamTestServer(t, 4096)
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	client, err := Dial(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Small results pass
+	var items []streamTestItem
+	if err := client.Call(&items, "stream_items", 10); err != nil {
+		t.Fatal(err)
+	}
+	// Exceeding the limit before anything was sent yields an error response
+	for _, method := range []string{"stream_items", "stream_dump", "stream_slice"} {
+		err := client.Call(nil, method, 1000)
+		var rpcErr Error
+		if !errors.As(err, &rpcErr) || rpcErr.ErrorCode() != errcodeResponseTooLarge {
+			t.Fatalf("%s: expected response too large error, got %v", method, err)
+		}
+	}
+	// Failing streams are reported as errors
+	if err := client.Call(nil, "stream_fail", 10); err == nil || err.Error() != "producer failed" {
+		t.Fatalf("expected producer error, got %v", err)
+	}
+}
+
+func TestStreamAbort(t *testing.T) {
+	t.Parallel()
+
+	server := newStreamTestServer(t, 0)
+	httpsrv := httptest.NewServer(server)
+	defer httpsrv.Close()
+
+	client, err := Dial(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// A failure after the first chunk was flushed aborts the response, the client
+	// must not see a truncated result.
+	var result json.RawMessage
+	err = client.CallContext(context.Background(), &result, "stream_fail", 100000)
+	if err == nil {
+		t.Fatal("expected aborted response to fail")
+	}
+	var rpcErr Error
+	if errors.As(err, &rpcErr) {
+		t.Fatalf("expected transport error, got %v", err)
+	}
+	// The server keeps working afterwards
+	var items []streamTestItem
+	if err := client.Call(&items, "stream_items", 3); err != nil || len(items) != 3 {
+		t.Fatalf("call after abort failed: %v", err)
+	}
+}
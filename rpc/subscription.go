@@ -270,6 +270,7 @@ type ClientSubscription struct {
 	channel   reflect.Value // 客户端用于接收通知的 Go 通道
 	namespace string        // 订阅的命名空间（例如 "eth"）
 	subid     string        // 从服务器接收到的订阅 ID
+	relayed   bool          // 由中继从内层订阅提供，服务端订阅由中继取消
 
 	// The in channel receives notification values from client dispatcher.
 	// in 通道从客户端分发器接收通知值。
@@ -486,6 +487,9 @@ func (sub *ClientSubscription) unmarshal(result json.RawMessage) (interface{}, e
 // requestUnsubscribe() 方法正是实现了这个过程。它构造了 eth_unsubscribe 方法名（通过拼接命名空间和后缀），并使用订阅的 ID 作为参数发起 RPC 调用。
 // 使用 context.WithTimeout 可以防止取消订阅操作因为网络问题或其他原因而无限期地阻塞。如果在指定的时间内没有收到服务器的响应，上下文会超时，CallContext 方法会返回一个错误。
 func (sub *ClientSubscription) requestUnsubscribe() error {
+	if sub.relayed {
+		return nil // the inner subscription is cancelled by the relay
+	}
 	var result interface{}
 	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
 	defer cancel()
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -67,7 +68,12 @@ func (s *Server) WebsocketHandler(allowedOrigins []string) http.Handler {
 			return
 		}
 		codec := newWebsocketCodec(conn, r.Host, r.Header, wsDefaultReadLimit)
-		s.ServeCodec(codec, 0)
+		// Keep the values of the upgrade request (e.g. set by HTTP middlewares)
+		// available to the calls on the connection. Calls continue the trace
+		// propagated by the upgrade request.
+		// 保留升级请求的值（例如由 HTTP 中间件设置的值），供连接上的调用使用。调用延续升级请求传播的追踪。
+		ctx := contextWithTraceparent(context.WithoutCancel(r.Context()), r)
+		s.serveCodec(ctx, codec)
 	})
 }
 
@@ -329,8 +335,23 @@ func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header, readL
 	encode := func(v interface{}, isErrorResponse bool) error {
 		return conn.WriteJSON(v) // 使用 WebSocket 连接的 WriteJSON 方法进行编码
 	}
+	// Streamed responses are written as a single message, which is only opened
+	// once the first chunk of output is ready.
+	// 流式响应作为单个消息写入，该消息仅在第一块输出准备好后才打开。
+	stream := func(write func(io.Writer) error) error {
+		w := &wsMessageWriter{conn: conn}
+		err := write(w)
+		if w.w != nil {
+			if cerr := w.w.Close(); err == nil {
+				err = cerr
+			}
+		}
+		return err
+	}
+	jsonCodec := NewFuncCodec(conn, encode, conn.ReadJSON).(*jsonCodec)
+	jsonCodec.stream = stream
 	wc := &websocketCodec{
-		jsonCodec:    NewFuncCodec(conn, encode, conn.ReadJSON).(*jsonCodec), // 创建并嵌入 jsonCodec，使用 WebSocket 连接的 ReadJSON 方法进行解码
+		jsonCodec:    jsonCodec, // 嵌入 jsonCodec，使用 WebSocket 连接的 ReadJSON 方法进行解码
 		conn:         conn,
 		pingReset:    make(chan struct{}, 1),
 		pongReceived: make(chan struct{}),
@@ -358,6 +379,23 @@ func newWebsocketCodec(conn *websocket.Conn, host string, req http.Header, readL
 	return wc
 }
 
+// wsMessageWriter opens a websocket text message on the first write.
+// wsMessageWriter 在第一次写入时打开一个 websocket 文本消息。
+type wsMessageWriter struct {
+	conn *websocket.Conn
+	w    io.WriteCloser
+}
+
+func (w *wsMessageWriter) Write(p []byte) (int, error) {
+	if w.w == nil {
+		var err error
+		if w.w, err = w.conn.NextWriter(websocket.TextMessage); err != nil {
+			return 0, err
+		}
+	}
+	return w.w.Write(p)
+}
+
 func (wc *websocketCodec) close() {
 	wc.jsonCodec.close() // 关闭底层的 jsonCodec
 	wc.wg.Wait()         // 等待 pingLoop Goroutine 结束